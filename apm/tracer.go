@@ -28,6 +28,12 @@ func (t *tracer) Trace(ctx context.Context, name string, fn func(context.Context
 	iSpan.SetStatus(SpanCodeOk, "")
 }
 
+// NewNoopTracer returns a tracer that runs traced functions without recording
+// spans, for tools that run outside the server
+func NewNoopTracer() ITracer {
+	return &mockTracer{}
+}
+
 type MockTracer struct {
 	*mockTracer
 }
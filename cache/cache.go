@@ -6,9 +6,13 @@ import (
 	"github.com/coopersmall/subswag/apm"
 	apitokencache "github.com/coopersmall/subswag/cache/apitoken"
 	chatsessionscache "github.com/coopersmall/subswag/cache/chatsessions"
+	lobbycache "github.com/coopersmall/subswag/cache/lobby"
+	spectatorscache "github.com/coopersmall/subswag/cache/spectators"
 	userscache "github.com/coopersmall/subswag/cache/users"
 	"github.com/coopersmall/subswag/domain/apitoken"
 	chatsessionsdomain "github.com/coopersmall/subswag/domain/chatsession"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/lobby"
 	"github.com/coopersmall/subswag/domain/user"
 	usersdomain "github.com/coopersmall/subswag/domain/user"
 
@@ -19,12 +23,16 @@ import (
 type ICache interface {
 	APITokensCache(userId user.UserID) IAPITokenCache
 	ChatSessionsCache() IChatSessionsCache
+	LobbyCache() ILobbyCache
+	SpectatorsCache() ISpectatorsCache
 	UsersCache() IUsersCache
 }
 
 type Cache struct {
 	apitokensCache    func(userId user.UserID) IAPITokenCache
 	chatSessionsCache func() IChatSessionsCache
+	lobbyCache        func() ILobbyCache
+	spectatorsCache   func() ISpectatorsCache
 	usersCache        func() IUsersCache
 }
 
@@ -47,6 +55,20 @@ func GetCaches(
 			gateway.RedisCacheGateway(),
 		)
 	}
+	lobbyCache := func() ILobbyCache {
+		return lobbycache.NewLobbyCache(
+			env.GetLogger("lobby-cache"),
+			env.GetTracer("lobby-cache"),
+			gateway.RedisCacheGateway(),
+		)
+	}
+	spectatorsCache := func() ISpectatorsCache {
+		return spectatorscache.NewSpectatorsCache(
+			env.GetLogger("spectators-cache"),
+			env.GetTracer("spectators-cache"),
+			gateway.RedisCacheGateway(),
+		)
+	}
 	usersCache := func() IUsersCache {
 		return userscache.NewUsersCache(
 			env.GetLogger("users-cache"),
@@ -58,6 +80,8 @@ func GetCaches(
 	return &Cache{
 		apitokensCache:    apitokensCache,
 		chatSessionsCache: chatSessionsCache,
+		lobbyCache:        lobbyCache,
+		spectatorsCache:   spectatorsCache,
 		usersCache:        usersCache,
 	}
 }
@@ -70,6 +94,14 @@ func (c *Cache) ChatSessionsCache() IChatSessionsCache {
 	return c.chatSessionsCache()
 }
 
+func (c *Cache) LobbyCache() ILobbyCache {
+	return c.lobbyCache()
+}
+
+func (c *Cache) SpectatorsCache() ISpectatorsCache {
+	return c.spectatorsCache()
+}
+
 func (c *Cache) UsersCache() IUsersCache {
 	return c.usersCache()
 }
@@ -110,6 +142,23 @@ type IChatSessionsCache interface {
 	) error
 }
 
+type ILobbyCache interface {
+	UpdateQueue(ctx context.Context, update func(*lobby.Queue) error) error
+	GetQueue(ctx context.Context) (*lobby.Queue, error)
+	GetMatch(ctx context.Context, userId user.UserID) (*lobby.QueueStatus, error)
+	SetMatch(ctx context.Context, userId user.UserID, status *lobby.QueueStatus) error
+	DeleteMatch(ctx context.Context, userId user.UserID) error
+	GetLobby(ctx context.Context, inviteCode string) (*lobby.Lobby, error)
+	CreateLobby(ctx context.Context, l *lobby.Lobby) error
+	UpdateLobby(ctx context.Context, inviteCode string, update func(*lobby.Lobby) error) (*lobby.Lobby, error)
+	DeleteLobby(ctx context.Context, inviteCode string) error
+}
+
+type ISpectatorsCache interface {
+	GetSpectators(ctx context.Context, gameStateId game.GameStateID) (*game.Spectators, error)
+	UpdateSpectators(ctx context.Context, gameStateId game.GameStateID, update func(*game.Spectators) error) error
+}
+
 type IAPITokenCache interface {
 	Get(
 		ctx context.Context,
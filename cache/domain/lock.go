@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/coopersmall/subswag/gateways"
+	"github.com/coopersmall/subswag/utils"
+)
+
+const (
+	lockTTL      = 5 * time.Second
+	lockWait     = 2 * time.Second
+	lockInterval = 25 * time.Millisecond
+)
+
+// Lock spins on a short-lived key until it is acquired and returns the
+// function that releases it. The TTL releases the lock if its holder dies.
+func Lock(
+	ctx context.Context,
+	logger utils.ILogger,
+	gateway gateways.ICacheGateway,
+	key string,
+) (func(), error) {
+	deadline := time.Now().Add(lockWait)
+	for {
+		ok, err := gateway.SetIfAbsent(ctx, key, []byte("1"), lockTTL)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return func() {
+				if err := gateway.Delete(ctx, key); err != nil {
+					logger.Error(ctx, "failed to release lock", err, map[string]any{"key": key})
+				}
+			}, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, utils.NewUnableToHandleError("timed out waiting for lock")
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockInterval):
+		}
+	}
+}
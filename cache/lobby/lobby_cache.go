@@ -0,0 +1,171 @@
+package lobby
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/coopersmall/subswag/apm"
+	cachedomain "github.com/coopersmall/subswag/cache/domain"
+	"github.com/coopersmall/subswag/domain/lobby"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/gateways"
+	"github.com/coopersmall/subswag/utils"
+)
+
+const (
+	queueKey     = "matchmaking/queue"
+	queueLockKey = "matchmaking/queue/lock"
+	matchKey     = "matchmaking/matches"
+	lobbiesKey   = "lobbies"
+
+	matchTTL = 10 * time.Minute
+	lobbyTTL = 30 * time.Minute
+)
+
+// LobbyCache keeps the matchmaking queue, match results and private lobbies
+// in Redis so that every API instance shares them
+type LobbyCache struct {
+	logger  utils.ILogger
+	tracer  apm.ITracer
+	gateway gateways.ICacheGateway
+}
+
+func NewLobbyCache(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	gateway gateways.ICacheGateway,
+) *LobbyCache {
+	return &LobbyCache{
+		logger:  logger,
+		tracer:  tracer,
+		gateway: gateway,
+	}
+}
+
+// UpdateQueue runs update on the queue while holding the queue lock and saves
+// the result if update succeeds
+func (c *LobbyCache) UpdateQueue(ctx context.Context, update func(*lobby.Queue) error) error {
+	var err error
+	c.tracer.Trace(ctx, "lobby-cache.update-queue", func(ctx context.Context, span apm.ISpan) error {
+		var unlock func()
+		unlock, err = c.lock(ctx, cachedomain.NamespaceKey(queueLockKey))
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		queue := &lobby.Queue{}
+		if err = c.get(ctx, cachedomain.NamespaceKey(queueKey), queue); err != nil {
+			return err
+		}
+		if err = update(queue); err != nil {
+			return err
+		}
+		err = c.set(ctx, cachedomain.NamespaceKey(queueKey), queue, 0)
+		return err
+	})
+	return err
+}
+
+func (c *LobbyCache) GetQueue(ctx context.Context) (*lobby.Queue, error) {
+	queue := &lobby.Queue{}
+	return queue, c.get(ctx, cachedomain.NamespaceKey(queueKey), queue)
+}
+
+// GetMatch returns the game the player was last matched into, or nil
+func (c *LobbyCache) GetMatch(ctx context.Context, userId user.UserID) (*lobby.QueueStatus, error) {
+	var status *lobby.QueueStatus
+	return status, c.get(ctx, cachedomain.UserIDKey(userId, matchKey), &status)
+}
+
+func (c *LobbyCache) SetMatch(ctx context.Context, userId user.UserID, status *lobby.QueueStatus) error {
+	return c.set(ctx, cachedomain.UserIDKey(userId, matchKey), status, matchTTL)
+}
+
+func (c *LobbyCache) DeleteMatch(ctx context.Context, userId user.UserID) error {
+	return c.gateway.Delete(ctx, cachedomain.UserIDKey(userId, matchKey))
+}
+
+// GetLobby returns the lobby for the invite code, or nil
+func (c *LobbyCache) GetLobby(ctx context.Context, inviteCode string) (*lobby.Lobby, error) {
+	var found *lobby.Lobby
+	return found, c.get(ctx, lobbyKey(inviteCode), &found)
+}
+
+// CreateLobby saves a new lobby, failing if its invite code is taken
+func (c *LobbyCache) CreateLobby(ctx context.Context, l *lobby.Lobby) error {
+	bytes, err := json.Marshal(l)
+	if err != nil {
+		return utils.NewJSONMarshError("failed to marshal lobby", err)
+	}
+	ok, err := c.gateway.SetIfAbsent(ctx, lobbyKey(l.InviteCode), bytes, lobbyTTL)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return utils.NewAlreadyExistsError("invite code is taken")
+	}
+	return nil
+}
+
+// UpdateLobby runs update on the lobby while holding its lock and saves the
+// result if update succeeds
+func (c *LobbyCache) UpdateLobby(
+	ctx context.Context,
+	inviteCode string,
+	update func(*lobby.Lobby) error,
+) (*lobby.Lobby, error) {
+	unlock, err := c.lock(ctx, lobbyKey(inviteCode)+"/lock")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	found, err := c.GetLobby(ctx, inviteCode)
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, utils.NewNotFoundError("lobby not found")
+	}
+	if err := update(found); err != nil {
+		return nil, err
+	}
+	return found, c.set(ctx, lobbyKey(inviteCode), found, lobbyTTL)
+}
+
+func (c *LobbyCache) DeleteLobby(ctx context.Context, inviteCode string) error {
+	return c.gateway.Delete(ctx, lobbyKey(inviteCode))
+}
+
+func (c *LobbyCache) lock(ctx context.Context, key string) (func(), error) {
+	return cachedomain.Lock(ctx, c.logger, c.gateway, key)
+}
+
+func (c *LobbyCache) get(ctx context.Context, key string, v any) error {
+	found, err := c.gateway.Get(ctx, key)
+	if err != nil {
+		return utils.NewInternalError("failed to get item", err)
+	}
+	if len(found) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(found, v); err != nil {
+		return utils.NewJSONMarshError("failed to unmarshal item", err)
+	}
+	return nil
+}
+
+func (c *LobbyCache) set(ctx context.Context, key string, v any, ttl time.Duration) error {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return utils.NewJSONMarshError("failed to marshal item", err)
+	}
+	return c.gateway.Set(ctx, key, bytes, ttl)
+}
+
+func lobbyKey(inviteCode string) string {
+	return cachedomain.NamespaceKey(lobbiesKey + "/" + strings.ToUpper(inviteCode))
+}
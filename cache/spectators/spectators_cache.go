@@ -0,0 +1,97 @@
+package spectators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/coopersmall/subswag/apm"
+	cachedomain "github.com/coopersmall/subswag/cache/domain"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/gateways"
+	"github.com/coopersmall/subswag/utils"
+)
+
+const (
+	spectatorsKey = "games/%d/spectators"
+
+	// Outlives every lease so an abandoned game's spectators are cleaned up
+	spectatorsTTL = 2 * game.SpectatorLeaseTTL
+)
+
+// SpectatorsCache keeps each game's spectator leases in Redis so that the
+// count covers spectators connected to every API instance
+type SpectatorsCache struct {
+	logger  utils.ILogger
+	tracer  apm.ITracer
+	gateway gateways.ICacheGateway
+}
+
+func NewSpectatorsCache(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	gateway gateways.ICacheGateway,
+) *SpectatorsCache {
+	return &SpectatorsCache{
+		logger:  logger,
+		tracer:  tracer,
+		gateway: gateway,
+	}
+}
+
+// GetSpectators returns the game's spectators, empty if nobody is watching
+func (c *SpectatorsCache) GetSpectators(ctx context.Context, gameStateId game.GameStateID) (*game.Spectators, error) {
+	spectators := &game.Spectators{}
+	found, err := c.gateway.Get(ctx, spectatorsKeyFor(gameStateId))
+	if err != nil {
+		return nil, utils.NewInternalError("failed to get spectators", err)
+	}
+	if len(found) == 0 {
+		return spectators, nil
+	}
+	if err := json.Unmarshal(found, spectators); err != nil {
+		return nil, utils.NewJSONMarshError("failed to unmarshal spectators", err)
+	}
+	return spectators, nil
+}
+
+// UpdateSpectators runs update on the game's spectators while holding their
+// lock and saves the result if update succeeds
+func (c *SpectatorsCache) UpdateSpectators(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	update func(*game.Spectators) error,
+) error {
+	var err error
+	c.tracer.Trace(ctx, "spectators-cache.update-spectators", func(ctx context.Context, span apm.ISpan) error {
+		key := spectatorsKeyFor(gameStateId)
+		var unlock func()
+		unlock, err = cachedomain.Lock(ctx, c.logger, c.gateway, key+"/lock")
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		var spectators *game.Spectators
+		spectators, err = c.GetSpectators(ctx, gameStateId)
+		if err != nil {
+			return err
+		}
+		if err = update(spectators); err != nil {
+			return err
+		}
+		var bytes []byte
+		bytes, err = json.Marshal(spectators)
+		if err != nil {
+			err = utils.NewJSONMarshError("failed to marshal spectators", err)
+			return err
+		}
+		err = c.gateway.Set(ctx, key, bytes, spectatorsTTL)
+		return err
+	})
+	return err
+}
+
+func spectatorsKeyFor(gameStateId game.GameStateID) string {
+	return cachedomain.NamespaceKey(fmt.Sprintf(spectatorsKey, gameStateId))
+}
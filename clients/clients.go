@@ -145,10 +145,12 @@ type IHttpClient interface {
 type IRedisClient interface {
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+	SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error)
 	Del(ctx context.Context, key string) error
 	FlushAll(ctx context.Context) error
 	XAdd(ctx context.Context, stream string, value map[string]any) (string, error)
 	XReadGroup(ctx context.Context, args *redis.XReadGroupArgs) ([]redis.XStream, error)
+	XRead(ctx context.Context, args *redis.XReadArgs) ([]redis.XStream, error)
 	XGroupCreateConsumer(ctx context.Context, key, group, consumer string) error
 	XGroupCreateMkStream(ctx context.Context, key string, consumerGroup string) error
 	XAck(ctx context.Context, key string, consumerGroup string, ids ...string) error
@@ -56,6 +56,16 @@ func (r *RedisClient) Set(ctx context.Context, key string, value string, ttl tim
 	return nil
 }
 
+// SetNX sets the key only if it does not already exist, reporting whether it
+// was set
+func (r *RedisClient) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	ok, err := r.client.SetNX(ctx, key, value, ttl).Result()
+	if err != nil {
+		return false, err
+	}
+	return ok, nil
+}
+
 func (r *RedisClient) Del(ctx context.Context, key string) error {
 	err := r.client.Del(ctx, key).Err()
 	if err != nil {
@@ -111,6 +121,25 @@ func (r *RedisClient) XReadGroup(
 	return result, nil
 }
 
+func (r *RedisClient) XRead(
+	ctx context.Context,
+	args *XReadArgs,
+) ([]XStream, error) {
+	streamsData, err := r.client.XRead(ctx, (*redis.XReadArgs)(args)).Result()
+	if err == redis.Nil {
+		// No messages available
+		return []XStream{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := make([]XStream, len(streamsData))
+	for i, streamData := range streamsData {
+		result[i] = XStream(streamData)
+	}
+	return result, nil
+}
+
 func (r *RedisClient) XGroupCreateMkStream(
 	ctx context.Context,
 	stream, group string,
@@ -139,6 +168,7 @@ func (r *RedisClient) Close() error {
 }
 
 type XReadGroupArgs redis.XReadGroupArgs
+type XReadArgs redis.XReadArgs
 type XStream redis.XStream
 
 func IsRedisNil(err error) bool {
@@ -0,0 +1,119 @@
+// Command lintcards checks every card in the catalog for effects that would
+// never fire or do not make sense, and exits with status 1 if any card has
+// an error.
+//
+//	go run ./cmd/subswag/lintcards
+//	go run ./cmd/subswag/lintcards -file core_set.yaml -format yaml -warnings=false
+//
+// Cards are loaded from the database configured in the environment, or from
+// a card set file in the format the admin import takes.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/env"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/joho/godotenv"
+)
+
+type options struct {
+	file     string
+	format   string
+	warnings bool
+	json     bool
+}
+
+func main() {
+	opts := options{}
+	flag.StringVar(&opts.file, "file", "", "card set file to lint, instead of the database")
+	flag.StringVar(&opts.format, "format", string(card.CardSetFormatJSON), "card set file format, json or yaml")
+	flag.BoolVar(&opts.warnings, "warnings", true, "report warnings as well as errors")
+	flag.BoolVar(&opts.json, "json", false, "write the problems as JSON")
+	flag.Parse()
+
+	failed, err := run(context.Background(), opts, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// run lints the cards and reports whether any card has an error
+func run(ctx context.Context, opts options, w io.Writer) (bool, error) {
+	load := loadFromDB
+	if opts.file != "" {
+		load = loadFromFile
+	}
+	cards, err := load(ctx, opts)
+	if err != nil {
+		return false, err
+	}
+
+	schema := game.EffectSnapshotSchema()
+	problems := []card.EffectLintProblem{}
+	failed := false
+	for _, c := range cards {
+		if err := card.ValidateCard(c); err != nil {
+			problems = append(problems, card.EffectLintProblem{
+				CardID:   c.GetID(),
+				Severity: booleanexpression.LintSeverityError,
+				Message:  utils.GetErrorMessage(err),
+			})
+		}
+		problems = append(problems, card.LintCard(c, schema)...)
+	}
+
+	reported := problems[:0]
+	for _, problem := range problems {
+		if problem.Severity == booleanexpression.LintSeverityError {
+			failed = true
+		} else if !opts.warnings {
+			continue
+		}
+		reported = append(reported, problem)
+	}
+
+	if opts.json {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return failed, encoder.Encode(reported)
+	}
+	for _, problem := range reported {
+		fmt.Fprintln(w, problem)
+	}
+	fmt.Fprintf(w, "%d cards checked, %d problems\n", len(cards), len(reported))
+	return failed, nil
+}
+
+func loadFromDB(ctx context.Context, opts options) ([]card.Card, error) {
+	godotenv.Load()
+	e := env.MustGetEnv(env.MustGetEnvVars(env.Opts...))
+	defer e.Shutdown()
+	repos, closeRepos := e.GetRepos()
+	defer closeRepos()
+	return repos.CardsRepo().All(ctx)
+}
+
+func loadFromFile(ctx context.Context, opts options) ([]card.Card, error) {
+	data, err := os.ReadFile(opts.file)
+	if err != nil {
+		return nil, err
+	}
+	set, err := card.ParseCardSet(data, card.CardSetFormat(opts.format))
+	if err != nil {
+		return nil, err
+	}
+	return set.Cards, nil
+}
@@ -0,0 +1,166 @@
+// Command simulate plays games between two decks in memory and reports how
+// they fared, for balance testing cards before they ship.
+//
+//	go run ./cmd/subswag/simulate -deck1 12 -deck2 34 -owner 56 -games 5000
+//	go run ./cmd/subswag/simulate -file testdata.json -deck1 1 -deck2 2 -format csv
+//
+// Cards and decks are loaded from the database configured in the
+// environment, or from a JSON file of the form {"cards": [...], "decks": [...]}.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain/bot"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/env"
+	"github.com/coopersmall/subswag/services/simulation"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/joho/godotenv"
+)
+
+type options struct {
+	games  int
+	seed   int64
+	decks  [2]int64
+	bots   [2]string
+	rules  string
+	owner  int64
+	file   string
+	format string
+	out    string
+}
+
+func main() {
+	opts := options{}
+	flag.IntVar(&opts.games, "games", 1000, "number of games to play")
+	flag.Int64Var(&opts.seed, "seed", 1, "seed of the first game, game i uses seed+i")
+	flag.Int64Var(&opts.decks[0], "deck1", 0, "ID of the first deck")
+	flag.Int64Var(&opts.decks[1], "deck2", 0, "ID of the second deck")
+	flag.StringVar(&opts.bots[0], "bot1", string(bot.DifficultyMedium), "bot difficulty playing the first deck")
+	flag.StringVar(&opts.bots[1], "bot2", string(bot.DifficultyMedium), "bot difficulty playing the second deck")
+	flag.StringVar(&opts.rules, "rules", string(game.RulesPresetClassic), "rules preset, classic, blitz or big_board")
+	flag.Int64Var(&opts.owner, "owner", 0, "user who owns the decks, when loading from the database")
+	flag.StringVar(&opts.file, "file", "", "JSON file of cards and decks, instead of the database")
+	flag.StringVar(&opts.format, "format", "json", "report format, json or csv")
+	flag.StringVar(&opts.out, "out", "", "file to write the report to, stdout if empty")
+	flag.Parse()
+
+	if err := run(context.Background(), opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, opts options) error {
+	if opts.games <= 0 {
+		return fmt.Errorf("games must be positive")
+	}
+	if opts.format != "json" && opts.format != "csv" {
+		return fmt.Errorf("unknown format %q", opts.format)
+	}
+
+	load := loadFromDB
+	if opts.file != "" {
+		load = loadFromFile
+	}
+	cards, decks, err := load(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	simulator, err := simulation.NewSimulator(
+		utils.NewLogger("simulate", utils.WithWriter(os.Stderr)),
+		apm.NewNoopTracer(),
+		cards,
+		decks,
+		[2]bot.Difficulty{bot.Difficulty(opts.bots[0]), bot.Difficulty(opts.bots[1])},
+		game.RulesPreset(opts.rules),
+	)
+	if err != nil {
+		return err
+	}
+	report, err := simulator.Run(ctx, opts.games, opts.seed)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if opts.out != "" {
+		f, err := os.Create(opts.out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if opts.format == "csv" {
+		return report.WriteCSV(w)
+	}
+	return report.WriteJSON(w)
+}
+
+func loadFromDB(ctx context.Context, opts options) ([]card.Card, [2]*card.SerializableDeck, error) {
+	var decks [2]*card.SerializableDeck
+	if opts.owner == 0 {
+		return nil, decks, fmt.Errorf("owner is required when loading from the database")
+	}
+	godotenv.Load()
+	e := env.MustGetEnv(env.MustGetEnvVars(env.Opts...))
+	defer e.Shutdown()
+	repos, closeRepos := e.GetRepos()
+	defer closeRepos()
+
+	cards, err := repos.CardsRepo().All(ctx)
+	if err != nil {
+		return nil, decks, err
+	}
+	for i, deckId := range opts.decks {
+		decks[i], err = repos.DecksRepo(user.UserID(opts.owner)).Get(ctx, card.SerializableDeckID(deckId))
+		if err != nil {
+			return nil, decks, fmt.Errorf("failed to load deck %d: %w", deckId, err)
+		}
+	}
+	return cards, decks, nil
+}
+
+func loadFromFile(ctx context.Context, opts options) ([]card.Card, [2]*card.SerializableDeck, error) {
+	var decks [2]*card.SerializableDeck
+	data, err := os.ReadFile(opts.file)
+	if err != nil {
+		return nil, decks, err
+	}
+	var file struct {
+		Cards []json.RawMessage        `json:"cards"`
+		Decks []*card.SerializableDeck `json:"decks"`
+	}
+	if err := utils.Unmarshal(data, &file); err != nil {
+		return nil, decks, err
+	}
+
+	cards := make([]card.Card, len(file.Cards))
+	for i, raw := range file.Cards {
+		if cards[i], err = card.UnmarshalCard(raw); err != nil {
+			return nil, decks, err
+		}
+	}
+	for i, deckId := range opts.decks {
+		for _, deck := range file.Decks {
+			if int64(deck.ID) == deckId {
+				decks[i] = deck
+			}
+		}
+		if decks[i] == nil {
+			return nil, decks, fmt.Errorf("deck %d is not in %s", deckId, opts.file)
+		}
+	}
+	return cards, decks, nil
+}
@@ -26,6 +26,14 @@ type Card struct {
 	Data      json.RawMessage
 }
 
+type CardVersion struct {
+	CardID    int64
+	Version   int32
+	CreatedAt time.Time
+	Type      string
+	Data      json.RawMessage
+}
+
 type ChatSession struct {
 	ID        int64
 	UserID    int64
@@ -56,6 +64,7 @@ type GameState struct {
 	ID        int64
 	CreatedAt time.Time
 	UpdatedAt sql.NullTime
+	Version   int64
 	Data      json.RawMessage
 }
 
@@ -67,6 +76,14 @@ type GameStateVersion struct {
 	Data        json.RawMessage
 }
 
+type GameTimer struct {
+	GameStateID int64
+	RoundNumber int32
+	Deadline    time.Time
+	LockedUntil sql.NullTime
+	CreatedAt   time.Time
+}
+
 type Integration struct {
 	ID        int64
 	Type      string
@@ -75,6 +92,31 @@ type Integration struct {
 	Data      json.RawMessage
 }
 
+type MatchResult struct {
+	GameStateID int64
+	Player1ID   int64
+	Player2ID   int64
+	CreatedAt   time.Time
+	UpdatedAt   sql.NullTime
+	Data        json.RawMessage
+}
+
+type PackOpening struct {
+	ID         int64
+	UserID     int64
+	PackTypeID int64
+	Sequence   int32
+	CreatedAt  time.Time
+	Data       json.RawMessage
+}
+
+type PackType struct {
+	ID        int64
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+	Data      json.RawMessage
+}
+
 type RateLimit struct {
 	ID        int64
 	UserID    int64
@@ -97,3 +139,21 @@ type User struct {
 	UpdatedAt sql.NullTime
 	Data      json.RawMessage
 }
+
+type UserCard struct {
+	ID        int64
+	UserID    int64
+	CardID    int64
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+	Data      json.RawMessage
+}
+
+type UserRating struct {
+	UserID    int64
+	Rating    float64
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+	Version   int64
+	Data      json.RawMessage
+}
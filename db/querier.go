@@ -11,16 +11,29 @@ import (
 type ISharedQueriesReadOnly interface {
 	GetCard(ctx context.Context, id int64) (Card, error)
 	GetAllCards(ctx context.Context) ([]Card, error)
+	GetCardsByIDs(ctx context.Context, dollar_1 []int64) ([]Card, error)
+	GetCardIDsByRarity(ctx context.Context, dollar_1 []string) ([]GetCardIDsByRarityRow, error)
+	GetCardVersion(ctx context.Context, arg GetCardVersionParams) (CardVersion, error)
+	GetPackType(ctx context.Context, id int64) (PackType, error)
+	GetAllPackTypes(ctx context.Context) ([]PackType, error)
 	GetUser(ctx context.Context, id int64) (User, error)
 	GetAllUsers(ctx context.Context) ([]User, error)
 	GetGameState(ctx context.Context, id int64) (GameState, error)
 	GetAllGameStates(ctx context.Context) ([]GameState, error)
 	GetGameStateVersion(ctx context.Context, id int64) (GameStateVersion, error)
 	GetAllGameStateVersions(ctx context.Context) ([]GameStateVersion, error)
-	GetGameStateVersionsByGameStateID(ctx context.Context, id int64) ([]GameStateVersion, error)
+	GetGameStateVersionByNumber(ctx context.Context, arg GetGameStateVersionByNumberParams) (GameStateVersion, error)
+	GetGameStateVersionsPage(ctx context.Context, arg GetGameStateVersionsPageParams) ([]GameStateVersion, error)
 	GetLatestGameStateVersionByGameStateID(ctx context.Context, id int64) (GameStateVersion, error)
+	GetLatestGameStateVersionBefore(ctx context.Context, arg GetLatestGameStateVersionBeforeParams) (GetLatestGameStateVersionBeforeRow, error)
+	GetGameStateVersionsBetween(ctx context.Context, arg GetGameStateVersionsBetweenParams) ([]GameStateVersion, error)
 	GetIntegration(ctx context.Context, id int64) (Integration, error)
 	GetAllIntegrations(ctx context.Context) ([]Integration, error)
+	GetUserRating(ctx context.Context, userID int64) (UserRating, error)
+	GetTopUserRatings(ctx context.Context, arg GetTopUserRatingsParams) ([]UserRating, error)
+	GetUserRatingsByUserIDs(ctx context.Context, dollar_1 []int64) ([]UserRating, error)
+	GetMatchResult(ctx context.Context, gameStateID int64) (MatchResult, error)
+	GetMatchResultsByUserID(ctx context.Context, arg GetMatchResultsByUserIDParams) ([]MatchResult, error)
 }
 
 // Shared Queries - Read Write
@@ -32,10 +45,21 @@ type ISharedQueriesReadWrite interface {
 	CreateCard(ctx context.Context, arg CreateCardParams) (sql.Result, error)
 	UpdateCard(ctx context.Context, arg UpdateCardParams) (sql.Result, error)
 	DeleteCard(ctx context.Context, id int64) (sql.Result, error)
+	CreateCardVersion(ctx context.Context, arg CreateCardVersionParams) (sql.Result, error)
+	SaveCards(ctx context.Context, arg SaveCardsParams) (sql.Result, error)
+	CreatePackType(ctx context.Context, arg CreatePackTypeParams) (sql.Result, error)
+	UpdatePackType(ctx context.Context, arg UpdatePackTypeParams) (sql.Result, error)
+	DeletePackType(ctx context.Context, id int64) (sql.Result, error)
 	CreateGameState(ctx context.Context, arg CreateGameStateParams) (sql.Result, error)
 	UpdateGameState(ctx context.Context, arg UpdateGameStateParams) (sql.Result, error)
 	DeleteGameState(ctx context.Context, id int64) (sql.Result, error)
 	CreateGameStateVersion(ctx context.Context, arg CreateGameStateVersionParams) (sql.Result, error)
+	UpsertGameTimer(ctx context.Context, arg UpsertGameTimerParams) (sql.Result, error)
+	ClaimDueGameTimers(ctx context.Context, arg ClaimDueGameTimersParams) ([]GameTimer, error)
+	DeleteGameTimer(ctx context.Context, arg DeleteGameTimerParams) (sql.Result, error)
+	CreateUserRating(ctx context.Context, arg CreateUserRatingParams) (sql.Result, error)
+	CreateMatchResult(ctx context.Context, arg CreateMatchResultParams) (sql.Result, error)
+	RecordMatchResult(ctx context.Context, arg RecordMatchResultParams) (sql.Result, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (sql.Result, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (sql.Result, error)
 	DeleteUser(ctx context.Context, id int64) (sql.Result, error)
@@ -52,7 +76,12 @@ type IStandardQueriesReadOnly interface {
 	GetChatSessionItemsBySessionID(ctx context.Context, arg GetChatSessionItemsBySessionIDParams) ([]ChatSessionItem, error)
 	GetAllChatSessionItems(ctx context.Context) ([]ChatSessionItem, error)
 	GetDeck(ctx context.Context, arg GetDeckParams) (Deck, error)
-	GetAllDecks(ctx context.Context) ([]Deck, error)
+	GetAllDecks(ctx context.Context, userID int64) ([]Deck, error)
+	GetUserCard(ctx context.Context, arg GetUserCardParams) (UserCard, error)
+	GetAllUserCards(ctx context.Context, userID int64) ([]UserCard, error)
+	GetPackOpening(ctx context.Context, arg GetPackOpeningParams) (PackOpening, error)
+	GetAllPackOpenings(ctx context.Context, userID int64) ([]PackOpening, error)
+	GetLatestPackOpening(ctx context.Context, arg GetLatestPackOpeningParams) (PackOpening, error)
 	GetSecret(ctx context.Context, arg GetSecretParams) (Secret, error)
 	GetAllSecrets(ctx context.Context) ([]Secret, error)
 	GetRateLimit(ctx context.Context, arg GetRateLimitParams) (RateLimit, error)
@@ -75,6 +104,12 @@ type IStandardQueriesReadWrite interface {
 	CreateDeck(ctx context.Context, arg CreateDeckParams) (sql.Result, error)
 	UpdateDeck(ctx context.Context, arg UpdateDeckParams) (sql.Result, error)
 	DeleteDeck(ctx context.Context, arg DeleteDeckParams) (sql.Result, error)
+	CreateUserCard(ctx context.Context, arg CreateUserCardParams) (sql.Result, error)
+	UpdateUserCard(ctx context.Context, arg UpdateUserCardParams) (sql.Result, error)
+	DeleteUserCard(ctx context.Context, arg DeleteUserCardParams) (sql.Result, error)
+	CreateUserCards(ctx context.Context, arg CreateUserCardsParams) ([]CreateUserCardsRow, error)
+	RevokeUserCards(ctx context.Context, arg RevokeUserCardsParams) ([]RevokeUserCardsRow, error)
+	CreatePackOpening(ctx context.Context, arg CreatePackOpeningParams) ([]CreatePackOpeningRow, error)
 	CreateSecret(ctx context.Context, arg CreateSecretParams) (sql.Result, error)
 	UpdateSecret(ctx context.Context, arg UpdateSecretParams) (sql.Result, error)
 	DeleteSecret(ctx context.Context, arg DeleteSecretParams) (sql.Result, error)
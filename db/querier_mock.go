@@ -46,6 +46,31 @@ func (m *MockSharedQueriesReadOnly) GetAllCards(ctx context.Context) ([]Card, er
 	return args.Get(0).([]Card), args.Error(1)
 }
 
+func (m *MockSharedQueriesReadOnly) GetCardsByIDs(ctx context.Context, dollar_1 []int64) ([]Card, error) {
+	args := m.Called(ctx, dollar_1)
+	return args.Get(0).([]Card), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadOnly) GetCardIDsByRarity(ctx context.Context, dollar_1 []string) ([]GetCardIDsByRarityRow, error) {
+	args := m.Called(ctx, dollar_1)
+	return args.Get(0).([]GetCardIDsByRarityRow), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadOnly) GetCardVersion(ctx context.Context, arg GetCardVersionParams) (CardVersion, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(CardVersion), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadOnly) GetPackType(ctx context.Context, id int64) (PackType, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(PackType), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadOnly) GetAllPackTypes(ctx context.Context) ([]PackType, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]PackType), args.Error(1)
+}
+
 func (m *MockSharedQueriesReadOnly) GetUser(ctx context.Context, id int64) (User, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(User), args.Error(1)
@@ -76,8 +101,13 @@ func (m *MockSharedQueriesReadOnly) GetAllGameStateVersions(ctx context.Context)
 	return args.Get(0).([]GameStateVersion), args.Error(1)
 }
 
-func (m *MockSharedQueriesReadOnly) GetGameStateVersionsByGameStateID(ctx context.Context, id int64) ([]GameStateVersion, error) {
-	args := m.Called(ctx, id)
+func (m *MockSharedQueriesReadOnly) GetGameStateVersionByNumber(ctx context.Context, arg GetGameStateVersionByNumberParams) (GameStateVersion, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(GameStateVersion), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadOnly) GetGameStateVersionsPage(ctx context.Context, arg GetGameStateVersionsPageParams) ([]GameStateVersion, error) {
+	args := m.Called(ctx, arg)
 	return args.Get(0).([]GameStateVersion), args.Error(1)
 }
 
@@ -86,6 +116,41 @@ func (m *MockSharedQueriesReadOnly) GetLatestGameStateVersionByGameStateID(ctx c
 	return args.Get(0).(GameStateVersion), args.Error(1)
 }
 
+func (m *MockSharedQueriesReadOnly) GetLatestGameStateVersionBefore(ctx context.Context, arg GetLatestGameStateVersionBeforeParams) (GetLatestGameStateVersionBeforeRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(GetLatestGameStateVersionBeforeRow), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadOnly) GetGameStateVersionsBetween(ctx context.Context, arg GetGameStateVersionsBetweenParams) ([]GameStateVersion, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]GameStateVersion), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadOnly) GetUserRating(ctx context.Context, userID int64) (UserRating, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(UserRating), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadOnly) GetTopUserRatings(ctx context.Context, arg GetTopUserRatingsParams) ([]UserRating, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]UserRating), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadOnly) GetUserRatingsByUserIDs(ctx context.Context, dollar_1 []int64) ([]UserRating, error) {
+	args := m.Called(ctx, dollar_1)
+	return args.Get(0).([]UserRating), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadOnly) GetMatchResult(ctx context.Context, gameStateID int64) (MatchResult, error) {
+	args := m.Called(ctx, gameStateID)
+	return args.Get(0).(MatchResult), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadOnly) GetMatchResultsByUserID(ctx context.Context, arg GetMatchResultsByUserIDParams) ([]MatchResult, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]MatchResult), args.Error(1)
+}
+
 type MockSharedQueriesReadWrite struct {
 	MockSharedQueriesReadOnly
 }
@@ -95,6 +160,31 @@ func (m *MockSharedQueriesReadWrite) CreateCard(ctx context.Context, arg CreateC
 	return args.Get(0).(sql.Result), args.Error(1)
 }
 
+func (m *MockSharedQueriesReadWrite) CreateCardVersion(ctx context.Context, arg CreateCardVersionParams) (sql.Result, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadWrite) SaveCards(ctx context.Context, arg SaveCardsParams) (sql.Result, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadWrite) CreatePackType(ctx context.Context, arg CreatePackTypeParams) (sql.Result, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadWrite) UpdatePackType(ctx context.Context, arg UpdatePackTypeParams) (sql.Result, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadWrite) DeletePackType(ctx context.Context, id int64) (sql.Result, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
 func (m *MockSharedQueriesReadWrite) UpdateCard(ctx context.Context, arg UpdateCardParams) (sql.Result, error) {
 	args := m.Called(ctx, arg)
 	return args.Get(0).(sql.Result), args.Error(1)
@@ -125,6 +215,36 @@ func (m *MockSharedQueriesReadWrite) CreateGameStateVersion(ctx context.Context,
 	return args.Get(0).(sql.Result), args.Error(1)
 }
 
+func (m *MockSharedQueriesReadWrite) UpsertGameTimer(ctx context.Context, arg UpsertGameTimerParams) (sql.Result, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadWrite) ClaimDueGameTimers(ctx context.Context, arg ClaimDueGameTimersParams) ([]GameTimer, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]GameTimer), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadWrite) DeleteGameTimer(ctx context.Context, arg DeleteGameTimerParams) (sql.Result, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadWrite) CreateUserRating(ctx context.Context, arg CreateUserRatingParams) (sql.Result, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadWrite) CreateMatchResult(ctx context.Context, arg CreateMatchResultParams) (sql.Result, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *MockSharedQueriesReadWrite) RecordMatchResult(ctx context.Context, arg RecordMatchResultParams) (sql.Result, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
 func (m *MockSharedQueriesReadWrite) CreateUser(ctx context.Context, arg CreateUserParams) (sql.Result, error) {
 	args := m.Called(ctx, arg)
 	return args.Get(0).(sql.Result), args.Error(1)
@@ -189,11 +309,36 @@ func (m *MockStandardQueriesReadOnly) GetDeck(ctx context.Context, arg GetDeckPa
 	return args.Get(0).(Deck), args.Error(1)
 }
 
-func (m *MockStandardQueriesReadOnly) GetAllDecks(ctx context.Context) ([]Deck, error) {
-	args := m.Called(ctx)
+func (m *MockStandardQueriesReadOnly) GetAllDecks(ctx context.Context, userID int64) ([]Deck, error) {
+	args := m.Called(ctx, userID)
 	return args.Get(0).([]Deck), args.Error(1)
 }
 
+func (m *MockStandardQueriesReadOnly) GetUserCard(ctx context.Context, arg GetUserCardParams) (UserCard, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(UserCard), args.Error(1)
+}
+
+func (m *MockStandardQueriesReadOnly) GetAllUserCards(ctx context.Context, userID int64) ([]UserCard, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]UserCard), args.Error(1)
+}
+
+func (m *MockStandardQueriesReadOnly) GetPackOpening(ctx context.Context, arg GetPackOpeningParams) (PackOpening, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(PackOpening), args.Error(1)
+}
+
+func (m *MockStandardQueriesReadOnly) GetAllPackOpenings(ctx context.Context, userID int64) ([]PackOpening, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]PackOpening), args.Error(1)
+}
+
+func (m *MockStandardQueriesReadOnly) GetLatestPackOpening(ctx context.Context, arg GetLatestPackOpeningParams) (PackOpening, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(PackOpening), args.Error(1)
+}
+
 func (m *MockStandardQueriesReadOnly) GetSecret(ctx context.Context, arg GetSecretParams) (Secret, error) {
 	args := m.Called(ctx, arg)
 	return args.Get(0).(Secret), args.Error(1)
@@ -283,6 +428,36 @@ func (m *MockStandardQueriesReadWrite) DeleteDeck(ctx context.Context, arg Delet
 	return args.Get(0).(sql.Result), args.Error(1)
 }
 
+func (m *MockStandardQueriesReadWrite) CreateUserCard(ctx context.Context, arg CreateUserCardParams) (sql.Result, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *MockStandardQueriesReadWrite) UpdateUserCard(ctx context.Context, arg UpdateUserCardParams) (sql.Result, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *MockStandardQueriesReadWrite) DeleteUserCard(ctx context.Context, arg DeleteUserCardParams) (sql.Result, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *MockStandardQueriesReadWrite) CreateUserCards(ctx context.Context, arg CreateUserCardsParams) ([]CreateUserCardsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]CreateUserCardsRow), args.Error(1)
+}
+
+func (m *MockStandardQueriesReadWrite) RevokeUserCards(ctx context.Context, arg RevokeUserCardsParams) ([]RevokeUserCardsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]RevokeUserCardsRow), args.Error(1)
+}
+
+func (m *MockStandardQueriesReadWrite) CreatePackOpening(ctx context.Context, arg CreatePackOpeningParams) ([]CreatePackOpeningRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]CreatePackOpeningRow), args.Error(1)
+}
+
 func (m *MockStandardQueriesReadWrite) CreateSecret(ctx context.Context, arg CreateSecretParams) (sql.Result, error) {
 	args := m.Called(ctx, arg)
 	return args.Get(0).(sql.Result), args.Error(1)
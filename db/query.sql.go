@@ -10,7 +10,58 @@ import (
 	"database/sql"
 	"encoding/json"
 	"time"
+
+	"github.com/lib/pq"
+)
+
+const claimDueGameTimers = `-- name: ClaimDueGameTimers :many
+UPDATE game_timers
+SET locked_until = $2
+WHERE game_state_id IN (
+    SELECT game_state_id
+    FROM game_timers
+    WHERE deadline <= $1 AND (locked_until IS NULL OR locked_until <= $1)
+    ORDER BY deadline
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
 )
+RETURNING game_state_id, round_number, deadline, locked_until, created_at
+`
+
+type ClaimDueGameTimersParams struct {
+	Deadline    time.Time
+	LockedUntil sql.NullTime
+	Limit       int32
+}
+
+func (q *Queries) ClaimDueGameTimers(ctx context.Context, arg ClaimDueGameTimersParams) ([]GameTimer, error) {
+	rows, err := q.db.QueryContext(ctx, claimDueGameTimers, arg.Deadline, arg.LockedUntil, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GameTimer
+	for rows.Next() {
+		var i GameTimer
+		if err := rows.Scan(
+			&i.GameStateID,
+			&i.RoundNumber,
+			&i.Deadline,
+			&i.LockedUntil,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
 
 const createAPIToken = `-- name: CreateAPIToken :execresult
 
@@ -60,6 +111,33 @@ func (q *Queries) CreateCard(ctx context.Context, arg CreateCardParams) (sql.Res
 	)
 }
 
+const createCardVersion = `-- name: CreateCardVersion :execresult
+
+INSERT INTO card_versions (card_id, version, created_at, type, data)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (card_id, version) DO NOTHING
+RETURNING card_id, version, created_at, type, data
+`
+
+type CreateCardVersionParams struct {
+	CardID    int64
+	Version   int32
+	CreatedAt time.Time
+	Type      string
+	Data      json.RawMessage
+}
+
+// Card Versions
+func (q *Queries) CreateCardVersion(ctx context.Context, arg CreateCardVersionParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createCardVersion,
+		arg.CardID,
+		arg.Version,
+		arg.CreatedAt,
+		arg.Type,
+		arg.Data,
+	)
+}
+
 const createChatSession = `-- name: CreateChatSession :execresult
 
 INSERT INTO chat_sessions (id, user_id, created_at, data)
@@ -140,7 +218,7 @@ const createGameState = `-- name: CreateGameState :execresult
 
 INSERT INTO game_states (id, created_at, data)
 VALUES ($1, $2, $3)
-RETURNING id, created_at, updated_at, data
+RETURNING id, created_at, updated_at, version, data
 `
 
 type CreateGameStateParams struct {
@@ -202,6 +280,126 @@ func (q *Queries) CreateIntegration(ctx context.Context, arg CreateIntegrationPa
 	)
 }
 
+const createMatchResult = `-- name: CreateMatchResult :execresult
+
+INSERT INTO match_results (game_state_id, player1_id, player2_id, created_at, data)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (game_state_id) DO NOTHING
+`
+
+type CreateMatchResultParams struct {
+	GameStateID int64
+	Player1ID   int64
+	Player2ID   int64
+	CreatedAt   time.Time
+	Data        json.RawMessage
+}
+
+// Match Results
+func (q *Queries) CreateMatchResult(ctx context.Context, arg CreateMatchResultParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createMatchResult,
+		arg.GameStateID,
+		arg.Player1ID,
+		arg.Player2ID,
+		arg.CreatedAt,
+		arg.Data,
+	)
+}
+
+const createPackOpening = `-- name: CreatePackOpening :many
+
+WITH opening AS (
+    INSERT INTO pack_openings (id, user_id, pack_type_id, sequence, created_at, data)
+    VALUES ($1, $2, $3, $4, $5, $6)
+    RETURNING id
+), inserted AS (
+    INSERT INTO user_cards (id, user_id, card_id, created_at, data)
+    SELECT copies.id, $2, copies.card_id, $5, copies.data::jsonb
+    FROM opening, unnest($7::bigint[], $8::bigint[], $9::text[]) AS copies (id, card_id, data)
+    RETURNING card_id
+), added AS (
+    SELECT card_id, count(*) AS copies
+    FROM inserted
+    GROUP BY card_id
+)
+SELECT added.card_id, added.copies, (added.copies + (
+    SELECT count(*)
+    FROM user_cards
+    WHERE user_cards.user_id = $2 AND user_cards.card_id = added.card_id
+))::bigint AS owned
+FROM added
+`
+
+type CreatePackOpeningParams struct {
+	ID          int64
+	UserID      int64
+	PackTypeID  int64
+	Sequence    int32
+	CreatedAt   time.Time
+	Data        json.RawMessage
+	UserCardIDs []int64
+	CardIDs     []int64
+	CardData    []string
+}
+
+type CreatePackOpeningRow struct {
+	CardID int64
+	Copies int64
+	Owned  int64
+}
+
+// Pack Openings
+func (q *Queries) CreatePackOpening(ctx context.Context, arg CreatePackOpeningParams) ([]CreatePackOpeningRow, error) {
+	rows, err := q.db.QueryContext(ctx, createPackOpening,
+		arg.ID,
+		arg.UserID,
+		arg.PackTypeID,
+		arg.Sequence,
+		arg.CreatedAt,
+		arg.Data,
+		pq.Array(arg.UserCardIDs),
+		pq.Array(arg.CardIDs),
+		pq.Array(arg.CardData),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CreatePackOpeningRow
+	for rows.Next() {
+		var i CreatePackOpeningRow
+		if err := rows.Scan(&i.CardID, &i.Copies, &i.Owned); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createPackType = `-- name: CreatePackType :execresult
+
+INSERT INTO pack_types (id, created_at, data)
+VALUES ($1, $2, $3)
+RETURNING id, created_at, updated_at, data
+`
+
+type CreatePackTypeParams struct {
+	ID        int64
+	CreatedAt time.Time
+	Data      json.RawMessage
+}
+
+// Pack Types
+func (q *Queries) CreatePackType(ctx context.Context, arg CreatePackTypeParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createPackType, arg.ID, arg.CreatedAt, arg.Data)
+}
+
 const createRateLimit = `-- name: CreateRateLimit :execresult
 
 INSERT INTO rate_limits (id, user_id, created_at, data)
@@ -268,6 +466,117 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (sql.Res
 	return q.db.ExecContext(ctx, createUser, arg.ID, arg.CreatedAt, arg.Data)
 }
 
+const createUserCard = `-- name: CreateUserCard :execresult
+
+INSERT INTO user_cards (id, user_id, card_id, created_at, data)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, card_id, created_at, data
+`
+
+type CreateUserCardParams struct {
+	ID        int64
+	UserID    int64
+	CardID    int64
+	CreatedAt time.Time
+	Data      json.RawMessage
+}
+
+const createUserCards = `-- name: CreateUserCards :many
+WITH inserted AS (
+    INSERT INTO user_cards (id, user_id, card_id, created_at, data)
+    SELECT copies.id, $2, copies.card_id, $4, copies.data::jsonb
+    FROM unnest($1::bigint[], $3::bigint[], $5::text[]) AS copies (id, card_id, data)
+    RETURNING card_id
+), added AS (
+    SELECT card_id, count(*) AS copies
+    FROM inserted
+    GROUP BY card_id
+)
+SELECT added.card_id, (added.copies + (
+    SELECT count(*)
+    FROM user_cards
+    WHERE user_cards.user_id = $2 AND user_cards.card_id = added.card_id
+))::bigint AS owned
+FROM added
+`
+
+type CreateUserCardsParams struct {
+	IDs       []int64
+	UserID    int64
+	CardIDs   []int64
+	CreatedAt time.Time
+	Data      []string
+}
+
+type CreateUserCardsRow struct {
+	CardID int64
+	Owned  int64
+}
+
+func (q *Queries) CreateUserCards(ctx context.Context, arg CreateUserCardsParams) ([]CreateUserCardsRow, error) {
+	rows, err := q.db.QueryContext(ctx, createUserCards,
+		pq.Array(arg.IDs),
+		arg.UserID,
+		pq.Array(arg.CardIDs),
+		arg.CreatedAt,
+		pq.Array(arg.Data),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CreateUserCardsRow
+	for rows.Next() {
+		var i CreateUserCardsRow
+		if err := rows.Scan(&i.CardID, &i.Owned); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// User Cards
+func (q *Queries) CreateUserCard(ctx context.Context, arg CreateUserCardParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createUserCard,
+		arg.ID,
+		arg.UserID,
+		arg.CardID,
+		arg.CreatedAt,
+		arg.Data,
+	)
+}
+
+const createUserRating = `-- name: CreateUserRating :execresult
+
+INSERT INTO user_ratings (user_id, rating, created_at, data)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO NOTHING
+`
+
+type CreateUserRatingParams struct {
+	UserID    int64
+	Rating    float64
+	CreatedAt time.Time
+	Data      json.RawMessage
+}
+
+// User Ratings
+func (q *Queries) CreateUserRating(ctx context.Context, arg CreateUserRatingParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createUserRating,
+		arg.UserID,
+		arg.Rating,
+		arg.CreatedAt,
+		arg.Data,
+	)
+}
+
 const deleteAPIToken = `-- name: DeleteAPIToken :execresult
 DELETE FROM api_tokens
 WHERE id = $1 AND user_id = $2
@@ -355,13 +664,27 @@ func (q *Queries) DeleteDeck(ctx context.Context, arg DeleteDeckParams) (sql.Res
 const deleteGameState = `-- name: DeleteGameState :execresult
 DELETE FROM game_states
 WHERE id = $1
-RETURNING id, created_at, updated_at, data
+RETURNING id, created_at, updated_at, version, data
 `
 
 func (q *Queries) DeleteGameState(ctx context.Context, id int64) (sql.Result, error) {
 	return q.db.ExecContext(ctx, deleteGameState, id)
 }
 
+const deleteGameTimer = `-- name: DeleteGameTimer :execresult
+DELETE FROM game_timers
+WHERE game_state_id = $1 AND round_number = $2
+`
+
+type DeleteGameTimerParams struct {
+	GameStateID int64
+	RoundNumber int32
+}
+
+func (q *Queries) DeleteGameTimer(ctx context.Context, arg DeleteGameTimerParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteGameTimer, arg.GameStateID, arg.RoundNumber)
+}
+
 const deleteIntegration = `-- name: DeleteIntegration :execresult
 DELETE FROM integrations
 WHERE id = $1
@@ -372,6 +695,16 @@ func (q *Queries) DeleteIntegration(ctx context.Context, id int64) (sql.Result,
 	return q.db.ExecContext(ctx, deleteIntegration, id)
 }
 
+const deletePackType = `-- name: DeletePackType :execresult
+DELETE FROM pack_types
+WHERE id = $1
+RETURNING id, created_at, updated_at, data
+`
+
+func (q *Queries) DeletePackType(ctx context.Context, id int64) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deletePackType, id)
+}
+
 const deleteRateLimit = `-- name: DeleteRateLimit :execresult
 DELETE FROM rate_limits
 WHERE id = $1 AND user_id = $2
@@ -412,6 +745,20 @@ func (q *Queries) DeleteUser(ctx context.Context, id int64) (sql.Result, error)
 	return q.db.ExecContext(ctx, deleteUser, id)
 }
 
+const deleteUserCard = `-- name: DeleteUserCard :execresult
+DELETE FROM user_cards
+WHERE id = $1 AND user_id = $2
+`
+
+type DeleteUserCardParams struct {
+	ID     int64
+	UserID int64
+}
+
+func (q *Queries) DeleteUserCard(ctx context.Context, arg DeleteUserCardParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteUserCard, arg.ID, arg.UserID)
+}
+
 const getAPIToken = `-- name: GetAPIToken :one
 SELECT id, user_id, created_at, updated_at, data
 FROM api_tokens
@@ -581,11 +928,12 @@ func (q *Queries) GetAllChatSessions(ctx context.Context) ([]ChatSession, error)
 const getAllDecks = `-- name: GetAllDecks :many
 SELECT id, user_id, created_at, updated_at, data
 FROM decks
+WHERE user_id = $1
 ORDER BY created_at DESC
 `
 
-func (q *Queries) GetAllDecks(ctx context.Context) ([]Deck, error) {
-	rows, err := q.db.QueryContext(ctx, getAllDecks)
+func (q *Queries) GetAllDecks(ctx context.Context, userID int64) ([]Deck, error) {
+	rows, err := q.db.QueryContext(ctx, getAllDecks, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -649,7 +997,7 @@ func (q *Queries) GetAllGameStateVersions(ctx context.Context) ([]GameStateVersi
 }
 
 const getAllGameStates = `-- name: GetAllGameStates :many
-SELECT id, created_at, updated_at, data
+SELECT id, created_at, updated_at, version, data
 FROM game_states
 ORDER BY created_at DESC
 `
@@ -667,6 +1015,7 @@ func (q *Queries) GetAllGameStates(ctx context.Context) ([]GameState, error) {
 			&i.ID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
 			&i.Data,
 		); err != nil {
 			return nil, err
@@ -717,6 +1066,77 @@ func (q *Queries) GetAllIntegrations(ctx context.Context) ([]Integration, error)
 	return items, nil
 }
 
+const getAllPackOpenings = `-- name: GetAllPackOpenings :many
+SELECT id, user_id, pack_type_id, sequence, created_at, data
+FROM pack_openings
+WHERE user_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetAllPackOpenings(ctx context.Context, userID int64) ([]PackOpening, error) {
+	rows, err := q.db.QueryContext(ctx, getAllPackOpenings, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PackOpening
+	for rows.Next() {
+		var i PackOpening
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.PackTypeID,
+			&i.Sequence,
+			&i.CreatedAt,
+			&i.Data,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllPackTypes = `-- name: GetAllPackTypes :many
+SELECT id, created_at, updated_at, data
+FROM pack_types
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetAllPackTypes(ctx context.Context) ([]PackType, error) {
+	rows, err := q.db.QueryContext(ctx, getAllPackTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PackType
+	for rows.Next() {
+		var i PackType
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Data,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAllRateLimits = `-- name: GetAllRateLimits :many
 SELECT id, user_id, created_at, updated_at, data
 FROM rate_limits
@@ -787,23 +1207,26 @@ func (q *Queries) GetAllSecrets(ctx context.Context) ([]Secret, error) {
 	return items, nil
 }
 
-const getAllUsers = `-- name: GetAllUsers :many
-SELECT id, created_at, updated_at, data
-FROM users
+const getAllUserCards = `-- name: GetAllUserCards :many
+SELECT id, user_id, card_id, created_at, updated_at, data
+FROM user_cards
+WHERE user_id = $1
 ORDER BY created_at DESC
 `
 
-func (q *Queries) GetAllUsers(ctx context.Context) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, getAllUsers)
+func (q *Queries) GetAllUserCards(ctx context.Context, userID int64) ([]UserCard, error) {
+	rows, err := q.db.QueryContext(ctx, getAllUserCards, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []User
+	var items []UserCard
 	for rows.Next() {
-		var i User
+		var i UserCard
 		if err := rows.Scan(
 			&i.ID,
+			&i.UserID,
+			&i.CardID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Data,
@@ -821,9 +1244,43 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
-const getCard = `-- name: GetCard :one
-SELECT id, created_at, updated_at, type, data
-FROM cards
+const getAllUsers = `-- name: GetAllUsers :many
+SELECT id, created_at, updated_at, data
+FROM users
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetAllUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getAllUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Data,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCard = `-- name: GetCard :one
+SELECT id, created_at, updated_at, type, data
+FROM cards
 WHERE id = $1
 `
 
@@ -840,6 +1297,101 @@ func (q *Queries) GetCard(ctx context.Context, id int64) (Card, error) {
 	return i, err
 }
 
+const getCardIDsByRarity = `-- name: GetCardIDsByRarity :many
+SELECT id, (data->>'rarity')::text AS rarity
+FROM cards
+WHERE data->>'rarity' = ANY($1::text[])
+ORDER BY id
+`
+
+type GetCardIDsByRarityRow struct {
+	ID     int64
+	Rarity string
+}
+
+func (q *Queries) GetCardIDsByRarity(ctx context.Context, dollar_1 []string) ([]GetCardIDsByRarityRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCardIDsByRarity, pq.Array(dollar_1))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCardIDsByRarityRow
+	for rows.Next() {
+		var i GetCardIDsByRarityRow
+		if err := rows.Scan(&i.ID, &i.Rarity); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCardVersion = `-- name: GetCardVersion :one
+SELECT card_id, version, created_at, type, data
+FROM card_versions
+WHERE card_id = $1 AND version = $2
+`
+
+type GetCardVersionParams struct {
+	CardID  int64
+	Version int32
+}
+
+func (q *Queries) GetCardVersion(ctx context.Context, arg GetCardVersionParams) (CardVersion, error) {
+	row := q.db.QueryRowContext(ctx, getCardVersion, arg.CardID, arg.Version)
+	var i CardVersion
+	err := row.Scan(
+		&i.CardID,
+		&i.Version,
+		&i.CreatedAt,
+		&i.Type,
+		&i.Data,
+	)
+	return i, err
+}
+
+const getCardsByIDs = `-- name: GetCardsByIDs :many
+SELECT id, created_at, updated_at, type, data
+FROM cards
+WHERE id = ANY($1::bigint[])
+ORDER BY id
+`
+
+func (q *Queries) GetCardsByIDs(ctx context.Context, dollar_1 []int64) ([]Card, error) {
+	rows, err := q.db.QueryContext(ctx, getCardsByIDs, pq.Array(dollar_1))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Card
+	for rows.Next() {
+		var i Card
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Type,
+			&i.Data,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getChatSession = `-- name: GetChatSession :one
 SELECT id, user_id, created_at, updated_at, data
 FROM chat_sessions
@@ -994,7 +1546,7 @@ func (q *Queries) GetDeck(ctx context.Context, arg GetDeckParams) (Deck, error)
 }
 
 const getGameState = `-- name: GetGameState :one
-SELECT id, created_at, updated_at, data
+SELECT id, created_at, updated_at, version, data
 FROM game_states
 WHERE id = $1
 `
@@ -1006,6 +1558,7 @@ func (q *Queries) GetGameState(ctx context.Context, id int64) (GameState, error)
 		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
 		&i.Data,
 	)
 	return i, err
@@ -1030,15 +1583,91 @@ func (q *Queries) GetGameStateVersion(ctx context.Context, id int64) (GameStateV
 	return i, err
 }
 
-const getGameStateVersionsByGameStateID = `-- name: GetGameStateVersionsByGameStateID :many
+const getGameStateVersionByNumber = `-- name: GetGameStateVersionByNumber :one
 SELECT id, game_state_id, created_at, updated_at, data
 FROM game_state_versions
 WHERE game_state_id = $1
-ORDER BY created_at DESC
+ORDER BY created_at
+OFFSET $2
+LIMIT 1
+`
+
+type GetGameStateVersionByNumberParams struct {
+	GameStateID int64
+	Offset      int64
+}
+
+func (q *Queries) GetGameStateVersionByNumber(ctx context.Context, arg GetGameStateVersionByNumberParams) (GameStateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getGameStateVersionByNumber, arg.GameStateID, arg.Offset)
+	var i GameStateVersion
+	err := row.Scan(
+		&i.ID,
+		&i.GameStateID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Data,
+	)
+	return i, err
+}
+
+const getGameStateVersionsBetween = `-- name: GetGameStateVersionsBetween :many
+SELECT id, game_state_id, created_at, updated_at, data
+FROM game_state_versions
+WHERE game_state_id = $1 AND created_at > $2 AND created_at <= $3
+ORDER BY created_at
 `
 
-func (q *Queries) GetGameStateVersionsByGameStateID(ctx context.Context, gameStateID int64) ([]GameStateVersion, error) {
-	rows, err := q.db.QueryContext(ctx, getGameStateVersionsByGameStateID, gameStateID)
+type GetGameStateVersionsBetweenParams struct {
+	GameStateID int64
+	CreatedAt   time.Time
+	CreatedAt_2 time.Time
+}
+
+func (q *Queries) GetGameStateVersionsBetween(ctx context.Context, arg GetGameStateVersionsBetweenParams) ([]GameStateVersion, error) {
+	rows, err := q.db.QueryContext(ctx, getGameStateVersionsBetween, arg.GameStateID, arg.CreatedAt, arg.CreatedAt_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GameStateVersion
+	for rows.Next() {
+		var i GameStateVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.GameStateID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Data,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGameStateVersionsPage = `-- name: GetGameStateVersionsPage :many
+SELECT id, game_state_id, created_at, updated_at, data
+FROM game_state_versions
+WHERE game_state_id = $1
+ORDER BY created_at
+LIMIT $2 OFFSET $3
+`
+
+type GetGameStateVersionsPageParams struct {
+	GameStateID int64
+	Limit       int32
+	Offset      int32
+}
+
+func (q *Queries) GetGameStateVersionsPage(ctx context.Context, arg GetGameStateVersionsPageParams) ([]GameStateVersion, error) {
+	rows, err := q.db.QueryContext(ctx, getGameStateVersionsPage, arg.GameStateID, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -1106,6 +1735,176 @@ func (q *Queries) GetLatestGameStateVersionByGameStateID(ctx context.Context, ga
 	return i, err
 }
 
+const getLatestGameStateVersionBefore = `-- name: GetLatestGameStateVersionBefore :one
+SELECT id, game_state_id, created_at, updated_at, data, count(*) OVER () AS total
+FROM game_state_versions
+WHERE game_state_id = $1 AND created_at <= $2
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+type GetLatestGameStateVersionBeforeParams struct {
+	GameStateID int64
+	CreatedAt   time.Time
+}
+
+type GetLatestGameStateVersionBeforeRow struct {
+	ID          int64
+	GameStateID int64
+	CreatedAt   time.Time
+	UpdatedAt   sql.NullTime
+	Data        json.RawMessage
+	Total       int64
+}
+
+func (q *Queries) GetLatestGameStateVersionBefore(ctx context.Context, arg GetLatestGameStateVersionBeforeParams) (GetLatestGameStateVersionBeforeRow, error) {
+	row := q.db.QueryRowContext(ctx, getLatestGameStateVersionBefore, arg.GameStateID, arg.CreatedAt)
+	var i GetLatestGameStateVersionBeforeRow
+	err := row.Scan(
+		&i.ID,
+		&i.GameStateID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Data,
+		&i.Total,
+	)
+	return i, err
+}
+
+const getLatestPackOpening = `-- name: GetLatestPackOpening :one
+SELECT id, user_id, pack_type_id, sequence, created_at, data
+FROM pack_openings
+WHERE user_id = $1 AND pack_type_id = $2
+ORDER BY sequence DESC
+LIMIT 1
+`
+
+type GetLatestPackOpeningParams struct {
+	UserID     int64
+	PackTypeID int64
+}
+
+func (q *Queries) GetLatestPackOpening(ctx context.Context, arg GetLatestPackOpeningParams) (PackOpening, error) {
+	row := q.db.QueryRowContext(ctx, getLatestPackOpening, arg.UserID, arg.PackTypeID)
+	var i PackOpening
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PackTypeID,
+		&i.Sequence,
+		&i.CreatedAt,
+		&i.Data,
+	)
+	return i, err
+}
+
+const getMatchResult = `-- name: GetMatchResult :one
+SELECT game_state_id, player1_id, player2_id, created_at, updated_at, data
+FROM match_results
+WHERE game_state_id = $1
+`
+
+func (q *Queries) GetMatchResult(ctx context.Context, gameStateID int64) (MatchResult, error) {
+	row := q.db.QueryRowContext(ctx, getMatchResult, gameStateID)
+	var i MatchResult
+	err := row.Scan(
+		&i.GameStateID,
+		&i.Player1ID,
+		&i.Player2ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Data,
+	)
+	return i, err
+}
+
+const getMatchResultsByUserID = `-- name: GetMatchResultsByUserID :many
+SELECT game_state_id, player1_id, player2_id, created_at, updated_at, data
+FROM match_results
+WHERE player1_id = $1 OR player2_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type GetMatchResultsByUserIDParams struct {
+	Player1ID int64
+	Limit     int32
+	Offset    int32
+}
+
+func (q *Queries) GetMatchResultsByUserID(ctx context.Context, arg GetMatchResultsByUserIDParams) ([]MatchResult, error) {
+	rows, err := q.db.QueryContext(ctx, getMatchResultsByUserID, arg.Player1ID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MatchResult
+	for rows.Next() {
+		var i MatchResult
+		if err := rows.Scan(
+			&i.GameStateID,
+			&i.Player1ID,
+			&i.Player2ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Data,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPackOpening = `-- name: GetPackOpening :one
+SELECT id, user_id, pack_type_id, sequence, created_at, data
+FROM pack_openings
+WHERE id = $1 AND user_id = $2
+`
+
+type GetPackOpeningParams struct {
+	ID     int64
+	UserID int64
+}
+
+func (q *Queries) GetPackOpening(ctx context.Context, arg GetPackOpeningParams) (PackOpening, error) {
+	row := q.db.QueryRowContext(ctx, getPackOpening, arg.ID, arg.UserID)
+	var i PackOpening
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PackTypeID,
+		&i.Sequence,
+		&i.CreatedAt,
+		&i.Data,
+	)
+	return i, err
+}
+
+const getPackType = `-- name: GetPackType :one
+SELECT id, created_at, updated_at, data
+FROM pack_types
+WHERE id = $1
+`
+
+func (q *Queries) GetPackType(ctx context.Context, id int64) (PackType, error) {
+	row := q.db.QueryRowContext(ctx, getPackType, id)
+	var i PackType
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Data,
+	)
+	return i, err
+}
+
 const getRateLimit = `-- name: GetRateLimit :one
 SELECT id, user_id, created_at, updated_at, data
 FROM rate_limits
@@ -1154,6 +1953,49 @@ func (q *Queries) GetSecret(ctx context.Context, arg GetSecretParams) (Secret, e
 	return i, err
 }
 
+const getTopUserRatings = `-- name: GetTopUserRatings :many
+SELECT user_id, rating, created_at, updated_at, version, data
+FROM user_ratings
+WHERE version > 0
+ORDER BY rating DESC, user_id
+LIMIT $1 OFFSET $2
+`
+
+type GetTopUserRatingsParams struct {
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) GetTopUserRatings(ctx context.Context, arg GetTopUserRatingsParams) ([]UserRating, error) {
+	rows, err := q.db.QueryContext(ctx, getTopUserRatings, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserRating
+	for rows.Next() {
+		var i UserRating
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Rating,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.Data,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUser = `-- name: GetUser :one
 SELECT id, created_at, updated_at, data
 FROM users
@@ -1172,6 +2014,270 @@ func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
 	return i, err
 }
 
+const getUserCard = `-- name: GetUserCard :one
+SELECT id, user_id, card_id, created_at, updated_at, data
+FROM user_cards
+WHERE id = $1 AND user_id = $2
+`
+
+type GetUserCardParams struct {
+	ID     int64
+	UserID int64
+}
+
+func (q *Queries) GetUserCard(ctx context.Context, arg GetUserCardParams) (UserCard, error) {
+	row := q.db.QueryRowContext(ctx, getUserCard, arg.ID, arg.UserID)
+	var i UserCard
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CardID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Data,
+	)
+	return i, err
+}
+
+const getUserRating = `-- name: GetUserRating :one
+SELECT user_id, rating, created_at, updated_at, version, data
+FROM user_ratings
+WHERE user_id = $1
+`
+
+func (q *Queries) GetUserRating(ctx context.Context, userID int64) (UserRating, error) {
+	row := q.db.QueryRowContext(ctx, getUserRating, userID)
+	var i UserRating
+	err := row.Scan(
+		&i.UserID,
+		&i.Rating,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.Data,
+	)
+	return i, err
+}
+
+const getUserRatingsByUserIDs = `-- name: GetUserRatingsByUserIDs :many
+SELECT user_id, rating, created_at, updated_at, version, data
+FROM user_ratings
+WHERE user_id = ANY($1::bigint[]) AND version > 0
+ORDER BY rating DESC, user_id
+`
+
+func (q *Queries) GetUserRatingsByUserIDs(ctx context.Context, dollar_1 []int64) ([]UserRating, error) {
+	rows, err := q.db.QueryContext(ctx, getUserRatingsByUserIDs, pq.Array(dollar_1))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserRating
+	for rows.Next() {
+		var i UserRating
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Rating,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.Data,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordMatchResult = `-- name: RecordMatchResult :execresult
+WITH current AS (
+    SELECT user_id, version
+    FROM user_ratings
+    WHERE user_id IN ($2, $3)
+    ORDER BY user_id
+    FOR UPDATE
+), result AS (
+    INSERT INTO match_results (game_state_id, player1_id, player2_id, created_at, data)
+    SELECT $1, $2, $3, $4, $5
+    WHERE (
+        SELECT count(*)
+        FROM current
+        WHERE (user_id = $2 AND version = $8) OR (user_id = $3 AND version = $11)
+    ) = 2
+    ON CONFLICT (game_state_id) DO NOTHING
+    RETURNING game_state_id
+), played AS (
+    UPDATE decks
+    SET updated_at = $4, data = decks.data || jsonb_build_object(
+        'games_played', COALESCE((decks.data->>'games_played')::int, 0) + 1,
+        'games_won', COALESCE((decks.data->>'games_won')::int, 0) + played_decks.won::int
+    )
+    FROM result, (VALUES ($2::bigint, $12::bigint, $13::boolean), ($3::bigint, $14::bigint, $15::boolean)) AS played_decks (user_id, deck_id, won)
+    WHERE decks.id = played_decks.deck_id AND decks.user_id = played_decks.user_id
+    RETURNING decks.id
+)
+UPDATE user_ratings
+SET rating = ratings.rating, updated_at = $4, data = ratings.data, version = user_ratings.version + 1
+FROM result, (VALUES ($2::bigint, $6::double precision, $7::jsonb), ($3::bigint, $9::double precision, $10::jsonb)) AS ratings (user_id, rating, data)
+WHERE user_ratings.user_id = ratings.user_id
+`
+
+type RecordMatchResultParams struct {
+	GameStateID    int64
+	Player1ID      int64
+	Player2ID      int64
+	CreatedAt      time.Time
+	Data           json.RawMessage
+	Player1Rating  float64
+	Player1Data    json.RawMessage
+	Player1Version int64
+	Player2Rating  float64
+	Player2Data    json.RawMessage
+	Player2Version int64
+	Player1DeckID  int64
+	Player1Won     bool
+	Player2DeckID  int64
+	Player2Won     bool
+}
+
+func (q *Queries) RecordMatchResult(ctx context.Context, arg RecordMatchResultParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, recordMatchResult,
+		arg.GameStateID,
+		arg.Player1ID,
+		arg.Player2ID,
+		arg.CreatedAt,
+		arg.Data,
+		arg.Player1Rating,
+		arg.Player1Data,
+		arg.Player1Version,
+		arg.Player2Rating,
+		arg.Player2Data,
+		arg.Player2Version,
+		arg.Player1DeckID,
+		arg.Player1Won,
+		arg.Player2DeckID,
+		arg.Player2Won,
+	)
+}
+
+const revokeUserCards = `-- name: RevokeUserCards :many
+WITH wanted AS (
+    SELECT card_id, count(*) AS copies
+    FROM unnest($2::bigint[]) AS wanted (card_id)
+    GROUP BY card_id
+), locked AS (
+    SELECT id, card_id, created_at
+    FROM user_cards
+    WHERE user_id = $1 AND card_id IN (SELECT card_id FROM wanted)
+    ORDER BY id
+    FOR UPDATE
+), ranked AS (
+    SELECT id, card_id, row_number() OVER (PARTITION BY card_id ORDER BY created_at DESC, id DESC) AS newest
+    FROM locked
+), have AS (
+    SELECT wanted.card_id, wanted.copies, count(locked.id) AS owned
+    FROM wanted
+    LEFT JOIN locked ON locked.card_id = wanted.card_id
+    GROUP BY wanted.card_id, wanted.copies
+), enough AS (
+    SELECT COALESCE(bool_and(owned >= copies), false) AS revoked
+    FROM have
+), deleted AS (
+    DELETE FROM user_cards
+    USING ranked, have, enough
+    WHERE enough.revoked
+        AND user_cards.id = ranked.id
+        AND ranked.card_id = have.card_id
+        AND ranked.newest <= have.copies
+    RETURNING user_cards.id
+)
+SELECT have.card_id, have.copies, have.owned, enough.revoked
+FROM have, enough
+ORDER BY have.card_id
+`
+
+type RevokeUserCardsParams struct {
+	UserID  int64
+	CardIDs []int64
+}
+
+type RevokeUserCardsRow struct {
+	CardID  int64
+	Copies  int64
+	Owned   int64
+	Revoked bool
+}
+
+func (q *Queries) RevokeUserCards(ctx context.Context, arg RevokeUserCardsParams) ([]RevokeUserCardsRow, error) {
+	rows, err := q.db.QueryContext(ctx, revokeUserCards, arg.UserID, pq.Array(arg.CardIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RevokeUserCardsRow
+	for rows.Next() {
+		var i RevokeUserCardsRow
+		if err := rows.Scan(
+			&i.CardID,
+			&i.Copies,
+			&i.Owned,
+			&i.Revoked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const saveCards = `-- name: SaveCards :execresult
+WITH incoming AS (
+    SELECT *
+    FROM unnest($1::bigint[], $2::int[], $3::text[], $4::text[]) AS incoming (id, version, type, data)
+), versions AS (
+    INSERT INTO card_versions (card_id, version, created_at, type, data)
+    SELECT id, version, $5, type, data::jsonb
+    FROM incoming
+    ON CONFLICT (card_id, version) DO NOTHING
+)
+INSERT INTO cards (id, created_at, type, data)
+SELECT id, $5, type, data::jsonb
+FROM incoming
+ON CONFLICT (id) DO UPDATE
+SET updated_at = EXCLUDED.created_at, type = EXCLUDED.type, data = EXCLUDED.data
+`
+
+type SaveCardsParams struct {
+	IDs       []int64
+	Versions  []int64
+	Types     []string
+	Data      []string
+	CreatedAt time.Time
+}
+
+func (q *Queries) SaveCards(ctx context.Context, arg SaveCardsParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, saveCards,
+		pq.Array(arg.IDs),
+		pq.Array(arg.Versions),
+		pq.Array(arg.Types),
+		pq.Array(arg.Data),
+		arg.CreatedAt,
+	)
+}
+
 const updateAPIToken = `-- name: UpdateAPIToken :execresult
 UPDATE api_tokens
 SET updated_at = $3, data = $4
@@ -1293,19 +2399,25 @@ func (q *Queries) UpdateDeck(ctx context.Context, arg UpdateDeckParams) (sql.Res
 
 const updateGameState = `-- name: UpdateGameState :execresult
 UPDATE game_states
-SET updated_at = $2, data = $3
-WHERE id = $1
-RETURNING id, created_at, updated_at, data
+SET updated_at = $2, data = $3, version = version + 1
+WHERE id = $1 AND version = $4
+RETURNING id, created_at, updated_at, version, data
 `
 
 type UpdateGameStateParams struct {
 	ID        int64
 	UpdatedAt sql.NullTime
 	Data      json.RawMessage
+	Version   int64
 }
 
 func (q *Queries) UpdateGameState(ctx context.Context, arg UpdateGameStateParams) (sql.Result, error) {
-	return q.db.ExecContext(ctx, updateGameState, arg.ID, arg.UpdatedAt, arg.Data)
+	return q.db.ExecContext(ctx, updateGameState,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.Data,
+		arg.Version,
+	)
 }
 
 const updateIntegration = `-- name: UpdateIntegration :execresult
@@ -1325,6 +2437,23 @@ func (q *Queries) UpdateIntegration(ctx context.Context, arg UpdateIntegrationPa
 	return q.db.ExecContext(ctx, updateIntegration, arg.ID, arg.UpdatedAt, arg.Data)
 }
 
+const updatePackType = `-- name: UpdatePackType :execresult
+UPDATE pack_types
+SET updated_at = $2, data = $3
+WHERE id = $1
+RETURNING id, created_at, updated_at, data
+`
+
+type UpdatePackTypeParams struct {
+	ID        int64
+	UpdatedAt sql.NullTime
+	Data      json.RawMessage
+}
+
+func (q *Queries) UpdatePackType(ctx context.Context, arg UpdatePackTypeParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updatePackType, arg.ID, arg.UpdatedAt, arg.Data)
+}
+
 const updateRateLimit = `-- name: UpdateRateLimit :execresult
 UPDATE rate_limits
 SET updated_at = $3, data = $4
@@ -1387,3 +2516,51 @@ type UpdateUserParams struct {
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (sql.Result, error) {
 	return q.db.ExecContext(ctx, updateUser, arg.ID, arg.UpdatedAt, arg.Data)
 }
+
+const updateUserCard = `-- name: UpdateUserCard :execresult
+UPDATE user_cards
+SET updated_at = $3, data = $4
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, card_id, created_at, updated_at, data
+`
+
+type UpdateUserCardParams struct {
+	ID        int64
+	UserID    int64
+	UpdatedAt sql.NullTime
+	Data      json.RawMessage
+}
+
+func (q *Queries) UpdateUserCard(ctx context.Context, arg UpdateUserCardParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateUserCard,
+		arg.ID,
+		arg.UserID,
+		arg.UpdatedAt,
+		arg.Data,
+	)
+}
+
+const upsertGameTimer = `-- name: UpsertGameTimer :execresult
+
+INSERT INTO game_timers (game_state_id, round_number, deadline, created_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (game_state_id) DO UPDATE
+SET round_number = EXCLUDED.round_number, deadline = EXCLUDED.deadline, locked_until = NULL
+`
+
+type UpsertGameTimerParams struct {
+	GameStateID int64
+	RoundNumber int32
+	Deadline    time.Time
+	CreatedAt   time.Time
+}
+
+// Game Timers
+func (q *Queries) UpsertGameTimer(ctx context.Context, arg UpsertGameTimerParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, upsertGameTimer,
+		arg.GameStateID,
+		arg.RoundNumber,
+		arg.Deadline,
+		arg.CreatedAt,
+	)
+}
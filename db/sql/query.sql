@@ -191,26 +191,26 @@ ORDER BY created_at DESC;
 -- name: CreateGameState :execresult
 INSERT INTO game_states (id, created_at, data)
 VALUES ($1, $2, $3)
-RETURNING id, created_at, updated_at, data;
+RETURNING id, created_at, updated_at, version, data;
 
 -- name: UpdateGameState :execresult
 UPDATE game_states
-SET updated_at = $2, data = $3
-WHERE id = $1
-RETURNING id, created_at, updated_at, data;
+SET updated_at = $2, data = $3, version = version + 1
+WHERE id = $1 AND version = $4
+RETURNING id, created_at, updated_at, version, data;
 
 -- name: DeleteGameState :execresult
 DELETE FROM game_states
 WHERE id = $1
-RETURNING id, created_at, updated_at, data;
+RETURNING id, created_at, updated_at, version, data;
 
 -- name: GetGameState :one
-SELECT id, created_at, updated_at, data
+SELECT id, created_at, updated_at, version, data
 FROM game_states
 WHERE id = $1;
 
 -- name: GetAllGameStates :many
-SELECT id, created_at, updated_at, data
+SELECT id, created_at, updated_at, version, data
 FROM game_states
 ORDER BY created_at DESC;
 
@@ -231,11 +231,20 @@ SELECT id, game_state_id, created_at, updated_at, data
 FROM game_state_versions
 ORDER BY created_at DESC;
 
--- name: GetGameStateVersionsByGameStateID :many
+-- name: GetGameStateVersionByNumber :one
 SELECT id, game_state_id, created_at, updated_at, data
 FROM game_state_versions
 WHERE game_state_id = $1
-ORDER BY created_at DESC;
+ORDER BY created_at
+OFFSET $2
+LIMIT 1;
+
+-- name: GetGameStateVersionsPage :many
+SELECT id, game_state_id, created_at, updated_at, data
+FROM game_state_versions
+WHERE game_state_id = $1
+ORDER BY created_at
+LIMIT $2 OFFSET $3;
 
 -- name: GetLatestGameStateVersionByGameStateID :one
 SELECT id, game_state_id, created_at, updated_at, data
@@ -244,6 +253,120 @@ WHERE game_state_id = $1
 ORDER BY created_at DESC
 LIMIT 1;
 
+-- name: GetLatestGameStateVersionBefore :one
+SELECT id, game_state_id, created_at, updated_at, data, count(*) OVER () AS total
+FROM game_state_versions
+WHERE game_state_id = $1 AND created_at <= $2
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: GetGameStateVersionsBetween :many
+SELECT id, game_state_id, created_at, updated_at, data
+FROM game_state_versions
+WHERE game_state_id = $1 AND created_at > $2 AND created_at <= $3
+ORDER BY created_at;
+
+-- Game Timers
+
+-- name: UpsertGameTimer :execresult
+INSERT INTO game_timers (game_state_id, round_number, deadline, created_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (game_state_id) DO UPDATE
+SET round_number = EXCLUDED.round_number, deadline = EXCLUDED.deadline, locked_until = NULL;
+
+-- name: ClaimDueGameTimers :many
+UPDATE game_timers
+SET locked_until = $2
+WHERE game_state_id IN (
+    SELECT game_state_id
+    FROM game_timers
+    WHERE deadline <= $1 AND (locked_until IS NULL OR locked_until <= $1)
+    ORDER BY deadline
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING game_state_id, round_number, deadline, locked_until, created_at;
+
+-- name: DeleteGameTimer :execresult
+DELETE FROM game_timers
+WHERE game_state_id = $1 AND round_number = $2;
+
+-- User Ratings
+
+-- name: CreateUserRating :execresult
+INSERT INTO user_ratings (user_id, rating, created_at, data)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO NOTHING;
+
+-- name: GetUserRating :one
+SELECT user_id, rating, created_at, updated_at, version, data
+FROM user_ratings
+WHERE user_id = $1;
+
+-- name: GetTopUserRatings :many
+SELECT user_id, rating, created_at, updated_at, version, data
+FROM user_ratings
+WHERE version > 0
+ORDER BY rating DESC, user_id
+LIMIT $1 OFFSET $2;
+
+-- name: GetUserRatingsByUserIDs :many
+SELECT user_id, rating, created_at, updated_at, version, data
+FROM user_ratings
+WHERE user_id = ANY($1::bigint[]) AND version > 0
+ORDER BY rating DESC, user_id;
+
+-- Match Results
+
+-- name: CreateMatchResult :execresult
+INSERT INTO match_results (game_state_id, player1_id, player2_id, created_at, data)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (game_state_id) DO NOTHING;
+
+-- name: RecordMatchResult :execresult
+WITH current AS (
+    SELECT user_id, version
+    FROM user_ratings
+    WHERE user_id IN ($2, $3)
+    ORDER BY user_id
+    FOR UPDATE
+), result AS (
+    INSERT INTO match_results (game_state_id, player1_id, player2_id, created_at, data)
+    SELECT $1, $2, $3, $4, $5
+    WHERE (
+        SELECT count(*)
+        FROM current
+        WHERE (user_id = $2 AND version = $8) OR (user_id = $3 AND version = $11)
+    ) = 2
+    ON CONFLICT (game_state_id) DO NOTHING
+    RETURNING game_state_id
+), played AS (
+    UPDATE decks
+    SET updated_at = $4, data = decks.data || jsonb_build_object(
+        'games_played', COALESCE((decks.data->>'games_played')::int, 0) + 1,
+        'games_won', COALESCE((decks.data->>'games_won')::int, 0) + played_decks.won::int
+    )
+    FROM result, (VALUES ($2::bigint, $12::bigint, $13::boolean), ($3::bigint, $14::bigint, $15::boolean)) AS played_decks (user_id, deck_id, won)
+    WHERE decks.id = played_decks.deck_id AND decks.user_id = played_decks.user_id
+    RETURNING decks.id
+)
+UPDATE user_ratings
+SET rating = ratings.rating, updated_at = $4, data = ratings.data, version = user_ratings.version + 1
+FROM result, (VALUES ($2::bigint, $6::double precision, $7::jsonb), ($3::bigint, $9::double precision, $10::jsonb)) AS ratings (user_id, rating, data)
+WHERE user_ratings.user_id = ratings.user_id;
+
+-- name: GetMatchResult :one
+SELECT game_state_id, player1_id, player2_id, created_at, updated_at, data
+FROM match_results
+WHERE game_state_id = $1;
+
+-- name: GetMatchResultsByUserID :many
+SELECT game_state_id, player1_id, player2_id, created_at, updated_at, data
+FROM match_results
+WHERE player1_id = $1 OR player2_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
 -- Cards
 
 -- name: CreateCard :execresult
@@ -272,6 +395,47 @@ SELECT id, created_at, updated_at, type, data
 FROM cards
 ORDER BY created_at DESC;
 
+-- name: GetCardIDsByRarity :many
+SELECT id, (data->>'rarity')::text AS rarity
+FROM cards
+WHERE data->>'rarity' = ANY($1::text[])
+ORDER BY id;
+
+-- name: GetCardsByIDs :many
+SELECT id, created_at, updated_at, type, data
+FROM cards
+WHERE id = ANY($1::bigint[])
+ORDER BY id;
+
+-- name: SaveCards :execresult
+WITH incoming AS (
+    SELECT *
+    FROM unnest($1::bigint[], $2::int[], $3::text[], $4::text[]) AS incoming (id, version, type, data)
+), versions AS (
+    INSERT INTO card_versions (card_id, version, created_at, type, data)
+    SELECT id, version, $5, type, data::jsonb
+    FROM incoming
+    ON CONFLICT (card_id, version) DO NOTHING
+)
+INSERT INTO cards (id, created_at, type, data)
+SELECT id, $5, type, data::jsonb
+FROM incoming
+ON CONFLICT (id) DO UPDATE
+SET updated_at = EXCLUDED.created_at, type = EXCLUDED.type, data = EXCLUDED.data;
+
+-- Card Versions
+
+-- name: CreateCardVersion :execresult
+INSERT INTO card_versions (card_id, version, created_at, type, data)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (card_id, version) DO NOTHING
+RETURNING card_id, version, created_at, type, data;
+
+-- name: GetCardVersion :one
+SELECT card_id, version, created_at, type, data
+FROM card_versions
+WHERE card_id = $1 AND version = $2;
+
 -- Decks
 
 -- name: CreateDeck :execresult
@@ -297,8 +461,160 @@ WHERE id = $1 AND user_id = $2;
 -- name: GetAllDecks :many
 SELECT id, user_id, created_at, updated_at, data
 FROM decks
+WHERE user_id = $1
 ORDER BY created_at DESC;
 
+-- User Cards
+
+-- name: CreateUserCard :execresult
+INSERT INTO user_cards (id, user_id, card_id, created_at, data)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, card_id, created_at, data;
+
+-- name: UpdateUserCard :execresult
+UPDATE user_cards
+SET updated_at = $3, data = $4
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, card_id, created_at, updated_at, data;
+
+-- name: DeleteUserCard :execresult
+DELETE FROM user_cards
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetUserCard :one
+SELECT id, user_id, card_id, created_at, updated_at, data
+FROM user_cards
+WHERE id = $1 AND user_id = $2;
+
+-- name: CreateUserCards :many
+WITH inserted AS (
+    INSERT INTO user_cards (id, user_id, card_id, created_at, data)
+    SELECT copies.id, $2, copies.card_id, $4, copies.data::jsonb
+    FROM unnest($1::bigint[], $3::bigint[], $5::text[]) AS copies (id, card_id, data)
+    RETURNING card_id
+), added AS (
+    SELECT card_id, count(*) AS copies
+    FROM inserted
+    GROUP BY card_id
+)
+SELECT added.card_id, (added.copies + (
+    SELECT count(*)
+    FROM user_cards
+    WHERE user_cards.user_id = $2 AND user_cards.card_id = added.card_id
+))::bigint AS owned
+FROM added;
+
+-- name: RevokeUserCards :many
+WITH wanted AS (
+    SELECT card_id, count(*) AS copies
+    FROM unnest($2::bigint[]) AS wanted (card_id)
+    GROUP BY card_id
+), locked AS (
+    SELECT id, card_id, created_at
+    FROM user_cards
+    WHERE user_id = $1 AND card_id IN (SELECT card_id FROM wanted)
+    ORDER BY id
+    FOR UPDATE
+), ranked AS (
+    SELECT id, card_id, row_number() OVER (PARTITION BY card_id ORDER BY created_at DESC, id DESC) AS newest
+    FROM locked
+), have AS (
+    SELECT wanted.card_id, wanted.copies, count(locked.id) AS owned
+    FROM wanted
+    LEFT JOIN locked ON locked.card_id = wanted.card_id
+    GROUP BY wanted.card_id, wanted.copies
+), enough AS (
+    SELECT COALESCE(bool_and(owned >= copies), false) AS revoked
+    FROM have
+), deleted AS (
+    DELETE FROM user_cards
+    USING ranked, have, enough
+    WHERE enough.revoked
+        AND user_cards.id = ranked.id
+        AND ranked.card_id = have.card_id
+        AND ranked.newest <= have.copies
+    RETURNING user_cards.id
+)
+SELECT have.card_id, have.copies, have.owned, enough.revoked
+FROM have, enough
+ORDER BY have.card_id;
+
+-- name: GetAllUserCards :many
+SELECT id, user_id, card_id, created_at, updated_at, data
+FROM user_cards
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- Pack Types
+
+-- name: CreatePackType :execresult
+INSERT INTO pack_types (id, created_at, data)
+VALUES ($1, $2, $3)
+RETURNING id, created_at, updated_at, data;
+
+-- name: UpdatePackType :execresult
+UPDATE pack_types
+SET updated_at = $2, data = $3
+WHERE id = $1
+RETURNING id, created_at, updated_at, data;
+
+-- name: DeletePackType :execresult
+DELETE FROM pack_types
+WHERE id = $1
+RETURNING id, created_at, updated_at, data;
+
+-- name: GetPackType :one
+SELECT id, created_at, updated_at, data
+FROM pack_types
+WHERE id = $1;
+
+-- name: GetAllPackTypes :many
+SELECT id, created_at, updated_at, data
+FROM pack_types
+ORDER BY created_at DESC;
+
+-- Pack Openings
+
+-- name: CreatePackOpening :many
+WITH opening AS (
+    INSERT INTO pack_openings (id, user_id, pack_type_id, sequence, created_at, data)
+    VALUES ($1, $2, $3, $4, $5, $6)
+    RETURNING id
+), inserted AS (
+    INSERT INTO user_cards (id, user_id, card_id, created_at, data)
+    SELECT copies.id, $2, copies.card_id, $5, copies.data::jsonb
+    FROM opening, unnest($7::bigint[], $8::bigint[], $9::text[]) AS copies (id, card_id, data)
+    RETURNING card_id
+), added AS (
+    SELECT card_id, count(*) AS copies
+    FROM inserted
+    GROUP BY card_id
+)
+SELECT added.card_id, added.copies, (added.copies + (
+    SELECT count(*)
+    FROM user_cards
+    WHERE user_cards.user_id = $2 AND user_cards.card_id = added.card_id
+))::bigint AS owned
+FROM added;
+
+-- name: GetPackOpening :one
+SELECT id, user_id, pack_type_id, sequence, created_at, data
+FROM pack_openings
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetAllPackOpenings :many
+SELECT id, user_id, pack_type_id, sequence, created_at, data
+FROM pack_openings
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: GetLatestPackOpening :one
+SELECT id, user_id, pack_type_id, sequence, created_at, data
+FROM pack_openings
+WHERE user_id = $1 AND pack_type_id = $2
+ORDER BY sequence DESC
+LIMIT 1;
+
 
 -- Integrations
 
@@ -4,6 +4,14 @@ DROP INDEX IF EXISTS chat_sessions_user_id_idx;
 DROP INDEX IF EXISTS rate_limits_user_id_idx;
 DROP INDEX IF EXISTS api_tokens_user_id_idx;
 DROP INDEX IF EXISTS secrets_user_id_idx;
+DROP INDEX IF EXISTS game_timers_deadline_idx;
+DROP INDEX IF EXISTS game_state_versions_game_state_id_created_at_idx;
+DROP INDEX IF EXISTS user_ratings_rating_idx;
+DROP INDEX IF EXISTS match_results_player1_id_idx;
+DROP INDEX IF EXISTS match_results_player2_id_idx;
+DROP INDEX IF EXISTS user_cards_user_id_card_id_idx;
+DROP INDEX IF EXISTS pack_openings_user_id_pack_type_id_sequence_idx;
+DROP INDEX IF EXISTS cards_rarity_idx;
 
 DROP TABLE IF EXISTS rate_limits;
 DROP TABLE IF EXISTS api_tokens;
@@ -12,9 +20,16 @@ DROP TABLE IF EXISTS secrets;
 DROP TABLE IF EXISTS chat_session_items;
 DROP TABLE IF EXISTS chat_sessions;
 DROP TABLE IF EXISTS decks;
+DROP TABLE IF EXISTS user_cards;
+DROP TABLE IF EXISTS pack_openings;
+DROP TABLE IF EXISTS match_results;
+DROP TABLE IF EXISTS user_ratings;
 
 DROP TABLE IF EXISTS users;
 DROP TABLE IF EXISTS cards;
+DROP TABLE IF EXISTS card_versions;
+DROP TABLE IF EXISTS pack_types;
+DROP TABLE IF EXISTS game_timers;
 DROP TABLE IF EXISTS game_state_versions;
 DROP TABLE IF EXISTS game_states;
 
@@ -29,6 +44,7 @@ CREATE TABLE game_states (
     ID BIGINT PRIMARY KEY,
     CREATED_AT TIMESTAMPTZ NOT NULL,
     UPDATED_AT TIMESTAMPTZ,
+    VERSION BIGINT NOT NULL DEFAULT 0,
     DATA JSONB NOT NULL
 );
 
@@ -41,6 +57,19 @@ CREATE TABLE game_state_versions (
     FOREIGN KEY (GAME_STATE_ID) REFERENCES game_states(ID) ON DELETE CASCADE
 );
 
+CREATE INDEX game_state_versions_game_state_id_created_at_idx ON game_state_versions (GAME_STATE_ID, CREATED_AT);
+
+CREATE TABLE game_timers (
+    GAME_STATE_ID BIGINT PRIMARY KEY,
+    ROUND_NUMBER INT NOT NULL,
+    DEADLINE TIMESTAMPTZ NOT NULL,
+    LOCKED_UNTIL TIMESTAMPTZ,
+    CREATED_AT TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (GAME_STATE_ID) REFERENCES game_states(ID) ON DELETE CASCADE
+);
+
+CREATE INDEX game_timers_deadline_idx ON game_timers (DEADLINE);
+
 CREATE TABLE cards (
     ID BIGINT PRIMARY KEY,
     CREATED_AT TIMESTAMPTZ NOT NULL,
@@ -49,6 +78,19 @@ CREATE TABLE cards (
     DATA JSONB NOT NULL
 );
 
+CREATE INDEX cards_rarity_idx ON cards ((DATA->>'rarity'));
+
+-- Every version of every card, kept after the card is deleted so games in
+-- progress can still be played with the cards they started with
+CREATE TABLE card_versions (
+    CARD_ID BIGINT NOT NULL,
+    VERSION INT NOT NULL,
+    CREATED_AT TIMESTAMPTZ NOT NULL,
+    TYPE VARCHAR(255) NOT NULL,
+    DATA JSONB NOT NULL,
+    PRIMARY KEY (CARD_ID, VERSION)
+);
+
 CREATE TABLE decks (
     ID BIGINT PRIMARY KEY,
     USER_ID BIGINT NOT NULL,
@@ -60,6 +102,64 @@ CREATE TABLE decks (
 
 CREATE INDEX decks_user_id_idx ON decks (USER_ID);
 
+CREATE TABLE user_cards (
+    ID BIGINT PRIMARY KEY,
+    USER_ID BIGINT NOT NULL,
+    CARD_ID BIGINT NOT NULL,
+    CREATED_AT TIMESTAMPTZ NOT NULL,
+    UPDATED_AT TIMESTAMPTZ,
+    DATA JSONB NOT NULL,
+    FOREIGN KEY (USER_ID) REFERENCES users(ID) ON DELETE CASCADE
+);
+
+CREATE INDEX user_cards_user_id_card_id_idx ON user_cards (USER_ID, CARD_ID);
+
+CREATE TABLE pack_types (
+    ID BIGINT PRIMARY KEY,
+    CREATED_AT TIMESTAMPTZ NOT NULL,
+    UPDATED_AT TIMESTAMPTZ,
+    DATA JSONB NOT NULL
+);
+
+-- The audit log of every pack opened. Openings are never changed and are
+-- kept after the user or pack type is deleted. A user's openings of a pack
+-- type are numbered, so two packs opened at once cannot share a pity count.
+CREATE TABLE pack_openings (
+    ID BIGINT PRIMARY KEY,
+    USER_ID BIGINT NOT NULL,
+    PACK_TYPE_ID BIGINT NOT NULL,
+    SEQUENCE INT NOT NULL,
+    CREATED_AT TIMESTAMPTZ NOT NULL,
+    DATA JSONB NOT NULL
+);
+
+CREATE UNIQUE INDEX pack_openings_user_id_pack_type_id_sequence_idx ON pack_openings (USER_ID, PACK_TYPE_ID, SEQUENCE);
+
+CREATE TABLE user_ratings (
+    USER_ID BIGINT PRIMARY KEY,
+    RATING DOUBLE PRECISION NOT NULL,
+    CREATED_AT TIMESTAMPTZ NOT NULL,
+    UPDATED_AT TIMESTAMPTZ,
+    VERSION BIGINT NOT NULL DEFAULT 0,
+    DATA JSONB NOT NULL,
+    FOREIGN KEY (USER_ID) REFERENCES users(ID) ON DELETE CASCADE
+);
+
+CREATE INDEX user_ratings_rating_idx ON user_ratings (RATING DESC);
+
+CREATE TABLE match_results (
+    GAME_STATE_ID BIGINT PRIMARY KEY,
+    PLAYER1_ID BIGINT NOT NULL,
+    PLAYER2_ID BIGINT NOT NULL,
+    CREATED_AT TIMESTAMPTZ NOT NULL,
+    UPDATED_AT TIMESTAMPTZ,
+    DATA JSONB NOT NULL,
+    FOREIGN KEY (GAME_STATE_ID) REFERENCES game_states(ID) ON DELETE CASCADE
+);
+
+CREATE INDEX match_results_player1_id_idx ON match_results (PLAYER1_ID);
+CREATE INDEX match_results_player2_id_idx ON match_results (PLAYER2_ID);
+
 CREATE TABLE secrets (
     ID BIGINT,
     USER_ID BIGINT NOT NULL,
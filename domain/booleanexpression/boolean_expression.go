@@ -6,6 +6,8 @@ type BooleanOperator string
 const (
 	OperatorAND BooleanOperator = "AND"
 	OperatorOR  BooleanOperator = "OR"
+	OperatorNOT BooleanOperator = "NOT" // Takes exactly one condition
+	OperatorXOR BooleanOperator = "XOR" // True when an odd number of conditions are true
 )
 
 // Returns either a boolean expression or a condition operator
@@ -12,6 +12,17 @@ const (
 	OperatorGreaterEqual Operator = ">="
 	OperatorLessThan     Operator = "<"
 	OperatorLessEqual    Operator = "<="
+
+	// String operators
+	OperatorStartsWith      Operator = "starts_with"
+	OperatorEndsWith        Operator = "ends_with"
+	OperatorStringContains  Operator = "contains"
+	OperatorMatches         Operator = "matches" // Regular expression match
+	OperatorEqualIgnoreCase Operator = "equals_ignore_case"
+
+	// Numeric operators that read ExpectedValues
+	OperatorBetween Operator = "between" // Inclusive of both bounds
+	OperatorIn      Operator = "in"
 )
 
 // ConditionType represents the type of condition
@@ -22,6 +33,11 @@ const (
 	ConditionTypeString  ConditionType = "string"
 	ConditionTypeBoolean ConditionType = "boolean"
 	ConditionTypeArray   ConditionType = "array"
+
+	ConditionTypeArrayLength ConditionType = "array_length"
+	ConditionTypeArrayMatch  ConditionType = "array_match"
+	ConditionTypeExists      ConditionType = "exists"
+	ConditionTypePath        ConditionType = "path"
 )
 
 // Condition represents a base condition interface
@@ -36,6 +52,7 @@ type NumericCondition struct {
 	ActualJSONPath utils.JSONPathQuery `json:"actual_json_path"`
 	Operator       Operator            `json:"operator"`
 	ExpectedValue  float64             `json:"expected_value"`
+	ExpectedValues []float64           `json:"expected_values,omitempty"` // The bounds for between, the options for in
 }
 
 func (n NumericCondition) IsBooleanExpressionOrCondition() {}
@@ -109,6 +126,90 @@ func (a ArrayCondition) GetActualJSONPath() utils.JSONPathQuery {
 	return a.ActualJSONPath
 }
 
+// ArrayLengthCondition compares the length of an array with a number. A
+// missing array has a length of 0.
+type ArrayLengthCondition struct {
+	Type           ConditionType       `json:"type"`
+	ActualJSONPath utils.JSONPathQuery `json:"actual_json_path"`
+	Operator       Operator            `json:"operator"`
+	ExpectedValue  float64             `json:"expected_value"`
+}
+
+func (a ArrayLengthCondition) IsBooleanExpressionOrCondition() {}
+
+func (a ArrayLengthCondition) GetType() ConditionType {
+	return a.Type
+}
+
+func (a ArrayLengthCondition) GetActualJSONPath() utils.JSONPathQuery {
+	return a.ActualJSONPath
+}
+
+type ArrayMatchOperator string
+
+const (
+	OperatorAny ArrayMatchOperator = "any"
+	OperatorAll ArrayMatchOperator = "all"
+)
+
+// ThisJSONPath refers to the data being evaluated as a whole, such as the
+// element in an ArrayMatchCondition
+const ThisJSONPath utils.JSONPathQuery = "@this"
+
+// ArrayMatchCondition evaluates Expression against each element of an
+// array. Paths in Expression are relative to the element.
+type ArrayMatchCondition struct {
+	Type           ConditionType       `json:"type"`
+	ActualJSONPath utils.JSONPathQuery `json:"actual_json_path"`
+	Operator       ArrayMatchOperator  `json:"operator"`
+	Expression     BooleanExpression   `json:"expression"`
+}
+
+func (a ArrayMatchCondition) IsBooleanExpressionOrCondition() {}
+
+func (a ArrayMatchCondition) GetType() ConditionType {
+	return a.Type
+}
+
+func (a ArrayMatchCondition) GetActualJSONPath() utils.JSONPathQuery {
+	return a.ActualJSONPath
+}
+
+// ExistsCondition is true when the JSON path matches a value, even null
+type ExistsCondition struct {
+	Type           ConditionType       `json:"type"`
+	ActualJSONPath utils.JSONPathQuery `json:"actual_json_path"`
+}
+
+func (e ExistsCondition) IsBooleanExpressionOrCondition() {}
+
+func (e ExistsCondition) GetType() ConditionType {
+	return e.Type
+}
+
+func (e ExistsCondition) GetActualJSONPath() utils.JSONPathQuery {
+	return e.ActualJSONPath
+}
+
+// PathCondition compares the values at two JSON paths. Numbers can be
+// ordered; any two values can be compared with == and !=.
+type PathCondition struct {
+	Type             ConditionType       `json:"type"`
+	ActualJSONPath   utils.JSONPathQuery `json:"actual_json_path"`
+	Operator         Operator            `json:"operator"`
+	ExpectedJSONPath utils.JSONPathQuery `json:"expected_json_path"`
+}
+
+func (p PathCondition) IsBooleanExpressionOrCondition() {}
+
+func (p PathCondition) GetType() ConditionType {
+	return p.Type
+}
+
+func (p PathCondition) GetActualJSONPath() utils.JSONPathQuery {
+	return p.ActualJSONPath
+}
+
 // NewNumericCondition creates a new NumericCondition
 func NewNumericCondition(jsonPath utils.JSONPathQuery, operator Operator, expectedValue float64) NumericCondition {
 	return NumericCondition{
@@ -146,3 +247,59 @@ func NewArrayCondition(jsonPath utils.JSONPathQuery, operator ArryConditionOpera
 		ExpectedValue:  expectedValue,
 	}
 }
+
+// NewBetweenCondition creates a NumericCondition that holds when the value is
+// between min and max inclusive
+func NewBetweenCondition(jsonPath utils.JSONPathQuery, min, max float64) NumericCondition {
+	return NumericCondition{
+		Type:           ConditionTypeNumeric,
+		ActualJSONPath: jsonPath,
+		Operator:       OperatorBetween,
+		ExpectedValues: []float64{min, max},
+	}
+}
+
+// NewInCondition creates a NumericCondition that holds when the value is one
+// of values
+func NewInCondition(jsonPath utils.JSONPathQuery, values ...float64) NumericCondition {
+	return NumericCondition{
+		Type:           ConditionTypeNumeric,
+		ActualJSONPath: jsonPath,
+		Operator:       OperatorIn,
+		ExpectedValues: values,
+	}
+}
+
+func NewArrayLengthCondition(jsonPath utils.JSONPathQuery, operator Operator, expectedValue float64) ArrayLengthCondition {
+	return ArrayLengthCondition{
+		Type:           ConditionTypeArrayLength,
+		ActualJSONPath: jsonPath,
+		Operator:       operator,
+		ExpectedValue:  expectedValue,
+	}
+}
+
+func NewArrayMatchCondition(jsonPath utils.JSONPathQuery, operator ArrayMatchOperator, expression BooleanExpression) ArrayMatchCondition {
+	return ArrayMatchCondition{
+		Type:           ConditionTypeArrayMatch,
+		ActualJSONPath: jsonPath,
+		Operator:       operator,
+		Expression:     expression,
+	}
+}
+
+func NewExistsCondition(jsonPath utils.JSONPathQuery) ExistsCondition {
+	return ExistsCondition{
+		Type:           ConditionTypeExists,
+		ActualJSONPath: jsonPath,
+	}
+}
+
+func NewPathCondition(jsonPath utils.JSONPathQuery, operator Operator, expectedJSONPath utils.JSONPathQuery) PathCondition {
+	return PathCondition{
+		Type:             ConditionTypePath,
+		ActualJSONPath:   jsonPath,
+		Operator:         operator,
+		ExpectedJSONPath: expectedJSONPath,
+	}
+}
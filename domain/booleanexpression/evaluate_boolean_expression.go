@@ -3,6 +3,9 @@ package booleanexpression
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/coopersmall/subswag/utils"
 )
@@ -24,6 +27,10 @@ func EvaluateBooleanExpression(expression BooleanExpression, data any) (bool, er
 		return evaluateAND(expression.Conditions, data)
 	case OperatorOR:
 		return evaluateOR(expression.Conditions, data)
+	case OperatorNOT:
+		return evaluateNOT(expression.Conditions, data)
+	case OperatorXOR:
+		return evaluateXOR(expression.Conditions, data)
 	default:
 		return false, &EvaluationError{
 			Message: "unsupported operator",
@@ -60,6 +67,31 @@ func evaluateOR(conditions []IsBooleanExpressionOrCondition, data any) (bool, er
 	return false, nil
 }
 
+func evaluateNOT(conditions []IsBooleanExpressionOrCondition, data any) (bool, error) {
+	if len(conditions) != 1 {
+		return false, &EvaluationError{
+			Message: "NOT takes exactly one condition",
+			Context: map[string]any{
+				"conditions": len(conditions),
+			},
+		}
+	}
+	result, err := evaluateConditionOrExpression(conditions[0], data)
+	return !result, err
+}
+
+func evaluateXOR(conditions []IsBooleanExpressionOrCondition, data any) (bool, error) {
+	result := false
+	for _, condition := range conditions {
+		r, err := evaluateConditionOrExpression(condition, data)
+		if err != nil {
+			return false, err
+		}
+		result = result != r
+	}
+	return result, nil
+}
+
 func evaluateConditionOrExpression(conditionOrExpression IsBooleanExpressionOrCondition, data any) (bool, error) {
 	switch c := conditionOrExpression.(type) {
 	case NumericCondition:
@@ -70,6 +102,15 @@ func evaluateConditionOrExpression(conditionOrExpression IsBooleanExpressionOrCo
 		return evaluateBooleanCondition(c, data)
 	case ArrayCondition:
 		return evaluateArrayCondition(c, data)
+	case ArrayLengthCondition:
+		return evaluateArrayLengthCondition(c, data)
+	case ArrayMatchCondition:
+		return evaluateArrayMatchCondition(c, data)
+	case ExistsCondition:
+		_, exists, err := utils.LookupJSONPathQuery(c.ActualJSONPath, data)
+		return exists, err
+	case PathCondition:
+		return evaluatePathCondition(c, data)
 	case BooleanExpression:
 		return EvaluateBooleanExpression(c, data)
 	default:
@@ -87,6 +128,20 @@ func evaluateNumericCondition(condition NumericCondition, data any) (bool, error
 	if err != nil {
 		return false, err
 	}
+	switch condition.Operator {
+	case OperatorBetween:
+		if len(condition.ExpectedValues) != 2 {
+			return false, &EvaluationError{
+				Message: "between needs a lower and an upper bound",
+				Context: map[string]any{
+					"expected_values": condition.ExpectedValues,
+				},
+			}
+		}
+		return actual >= condition.ExpectedValues[0] && actual <= condition.ExpectedValues[1], nil
+	case OperatorIn:
+		return slices.Contains(condition.ExpectedValues, actual), nil
+	}
 	return evaluateOperator(condition.Operator, actual, condition.ExpectedValue), nil
 }
 
@@ -95,6 +150,27 @@ func evaluateStringCondition(condition StringCondition, data any) (bool, error)
 	if err != nil {
 		return false, err
 	}
+	switch condition.Operator {
+	case OperatorStartsWith:
+		return strings.HasPrefix(actual, condition.ExpectedValue), nil
+	case OperatorEndsWith:
+		return strings.HasSuffix(actual, condition.ExpectedValue), nil
+	case OperatorStringContains:
+		return strings.Contains(actual, condition.ExpectedValue), nil
+	case OperatorEqualIgnoreCase:
+		return strings.EqualFold(actual, condition.ExpectedValue), nil
+	case OperatorMatches:
+		pattern, err := regexp.Compile(condition.ExpectedValue)
+		if err != nil {
+			return false, &EvaluationError{
+				Message: "invalid regular expression",
+				Context: map[string]any{
+					"pattern": condition.ExpectedValue,
+				},
+			}
+		}
+		return pattern.MatchString(actual), nil
+	}
 	return evaluateOperator(condition.Operator, actual, condition.ExpectedValue), nil
 }
 
@@ -131,6 +207,98 @@ func evaluateArrayCondition(condition ArrayCondition, data any) (bool, error) {
 	}
 }
 
+func evaluateArrayLengthCondition(condition ArrayLengthCondition, data any) (bool, error) {
+	actual, exists, err := utils.LookupJSONPathQuery(condition.ActualJSONPath, data)
+	if err != nil {
+		return false, err
+	}
+	length := 0
+	if exists {
+		arr, ok := actual.([]any)
+		if !ok {
+			return false, fmt.Errorf("value is not an array")
+		}
+		length = len(arr)
+	}
+	return evaluateOperator(condition.Operator, float64(length), condition.ExpectedValue), nil
+}
+
+func evaluateArrayMatchCondition(condition ArrayMatchCondition, data any) (bool, error) {
+	actual, exists, err := utils.LookupJSONPathQuery(condition.ActualJSONPath, data)
+	if err != nil {
+		return false, err
+	}
+	var elements []any
+	if exists {
+		arr, ok := actual.([]any)
+		if !ok {
+			return false, fmt.Errorf("value is not an array")
+		}
+		elements = arr
+	}
+
+	for _, element := range elements {
+		result, err := EvaluateBooleanExpression(condition.Expression, element)
+		if err != nil {
+			return false, err
+		}
+		switch condition.Operator {
+		case OperatorAny:
+			if result {
+				return true, nil
+			}
+		case OperatorAll:
+			if !result {
+				return false, nil
+			}
+		}
+	}
+
+	switch condition.Operator {
+	case OperatorAny:
+		return false, nil
+	case OperatorAll:
+		return true, nil
+	default:
+		return false, &EvaluationError{
+			Message: "unsupported array match operator",
+			Context: map[string]any{
+				"operator": condition.Operator,
+			},
+		}
+	}
+}
+
+func evaluatePathCondition(condition PathCondition, data any) (bool, error) {
+	actual, err := utils.EvaluateJSONPathQuery(condition.ActualJSONPath, data)
+	if err != nil {
+		return false, err
+	}
+	expected, err := utils.EvaluateJSONPathQuery(condition.ExpectedJSONPath, data)
+	if err != nil {
+		return false, err
+	}
+
+	switch condition.Operator {
+	case OperatorEqual:
+		return equalValues(actual, expected), nil
+	case OperatorNotEqual:
+		return !equalValues(actual, expected), nil
+	}
+	a, aOk := actual.(float64)
+	e, eOk := expected.(float64)
+	if !aOk || !eOk {
+		return false, &EvaluationError{
+			Message: "only numbers can be ordered",
+			Context: map[string]any{
+				"actual":   actual,
+				"expected": expected,
+			},
+		}
+	}
+	return evaluateOperator(condition.Operator, a, e), nil
+}
+
 func evaluateOperator(operator Operator, actual, expected any) bool {
 	switch operator {
 	case OperatorEqual:
@@ -303,3 +303,76 @@ func (s *BooleanExpressionTestSuite) TestBooleanConditions() {
 		}
 	})
 }
+
+func (s *BooleanExpressionTestSuite) TestRicherOperators() {
+	data := map[string]any{
+		"name":  "Sir Lancelot",
+		"round": 4,
+		"hand":  []any{map[string]any{"value": 3, "suite": "hearts"}, map[string]any{"value": 12, "suite": "spades"}},
+		"empty": []any{},
+		"self":  map[string]any{"points": 7, "suite": "hearts"},
+		"opponent": map[string]any{
+			"points": 5,
+			"suite":  "Hearts",
+		},
+		"nothing": nil,
+	}
+
+	s.Run("should evaluate every operator against the data", func() {
+		tests := map[string]bool{
+			`not $.round == 4`:                                 false,
+			`$.round == 4 xor $.round > 1`:                     false,
+			`$.round == 4 xor $.round > 1 xor $.round < 10`:    true,
+			`$.name starts_with "Sir"`:                         true,
+			`$.name ends_with "lot"`:                           true,
+			`$.name contains "Lance"`:                          true,
+			`$.name matches "^Sir [A-Z][a-z]+$"`:               true,
+			`$.self.suite equals_ignore_case "HEARTS"`:         true,
+			`$.round between [4, 6]`:                           true,
+			`$.round between [5, 6]`:                           false,
+			`$.round in [1, 4]`:                                true,
+			`$.round in [1, 2]`:                                false,
+			`length($.hand) == 2`:                              true,
+			`length($.empty) == 0`:                             true,
+			`length($.missing) == 0`:                           true,
+			`exists($.nothing)`:                                true,
+			`exists($.missing)`:                                false,
+			`any($.hand, $.value > 10)`:                        true,
+			`all($.hand, $.value > 10)`:                        false,
+			`all($.empty, $.value > 10)`:                       true,
+			`any($.missing, $.value > 10)`:                     false,
+			`any($.hand, $.suite == "spades" and $.value > 3)`: true,
+			`$.self.points > $.opponent.points`:                true,
+			`$.self.suite == $.opponent.suite`:                 false,
+			`$.self.suite != $.opponent.suite`:                 true,
+		}
+		for input, expected := range tests {
+			expression, err := booleanexpression.Parse(input)
+			s.Require().NoError(err, input)
+			result, err := booleanexpression.EvaluateBooleanExpression(expression, data)
+			s.Require().NoError(err, input)
+			assert.Equal(s.T(), expected, result, input)
+		}
+	})
+
+	s.Run("should match elements that are plain values", func() {
+		expression, err := booleanexpression.Parse(`any($.values, $ >= 10) and all($.values, $ > 0)`)
+		s.Require().NoError(err)
+		result, err := booleanexpression.EvaluateBooleanExpression(expression, map[string]any{"values": []any{1, 5, 10}})
+		s.Require().NoError(err)
+		assert.True(s.T(), result)
+	})
+
+	s.Run("should fail on values that cannot be compared", func() {
+		for _, input := range []string{
+			`$.self.suite > $.opponent.suite`,
+			`length($.name) > 1`,
+			`any($.name, $ == "S")`,
+		} {
+			expression, err := booleanexpression.Parse(input)
+			s.Require().NoError(err, input)
+			_, err = booleanexpression.EvaluateBooleanExpression(expression, data)
+			assert.Error(s.T(), err, input)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package booleanexpression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/coopersmall/subswag/utils"
+)
+
+// Format writes an expression in the syntax Parse reads, with keywords in
+// lower case. Nested expressions are always parenthesized. An empty
+// expression is written as "".
+func Format(expression BooleanExpression) (string, error) {
+	if expression.Operator == "" && len(expression.Conditions) == 0 {
+		return "", nil
+	}
+	return formatExpression(expression, false)
+}
+
+func formatExpression(expression BooleanExpression, nested bool) (string, error) {
+	if len(expression.Conditions) == 0 {
+		return "", utils.NewInvalidArgumentError(fmt.Sprintf("%s has no conditions", expression.Operator))
+	}
+	switch expression.Operator {
+	case OperatorNOT:
+		if len(expression.Conditions) != 1 {
+			return "", utils.NewInvalidArgumentError("NOT takes exactly one condition")
+		}
+		condition, err := formatConditionOrExpression(expression.Conditions[0])
+		if err != nil {
+			return "", err
+		}
+		return "not " + condition, nil
+	case OperatorAND, OperatorOR, OperatorXOR:
+	default:
+		return "", utils.NewInvalidArgumentError(fmt.Sprintf("unsupported operator %q", expression.Operator))
+	}
+
+	terms := make([]string, 0, len(expression.Conditions))
+	for _, condition := range expression.Conditions {
+		term, err := formatConditionOrExpression(condition)
+		if err != nil {
+			return "", err
+		}
+		terms = append(terms, term)
+	}
+	formatted := strings.Join(terms, " "+strings.ToLower(string(expression.Operator))+" ")
+	if nested {
+		return "(" + formatted + ")", nil
+	}
+	return formatted, nil
+}
+
+func formatConditionOrExpression(conditionOrExpression IsBooleanExpressionOrCondition) (string, error) {
+	switch c := conditionOrExpression.(type) {
+	case BooleanExpression:
+		return formatExpression(c, true)
+	case NumericCondition:
+		switch c.Operator {
+		case OperatorBetween, OperatorIn:
+			values := make([]any, 0, len(c.ExpectedValues))
+			for _, value := range c.ExpectedValues {
+				values = append(values, value)
+			}
+			return formatComparison(c.ActualJSONPath, string(c.Operator), values)
+		}
+		return formatComparison(c.ActualJSONPath, string(c.Operator), c.ExpectedValue)
+	case StringCondition:
+		return formatComparison(c.ActualJSONPath, string(c.Operator), c.ExpectedValue)
+	case BooleanCondition:
+		return formatComparison(c.ActualJSONPath, string(c.Operator), c.ExpectedValue)
+	case ArrayCondition:
+		return formatComparison(c.ActualJSONPath, string(c.Operator), c.ExpectedValue)
+	case ArrayLengthCondition:
+		path, err := formatCheckedPath(c.ActualJSONPath)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("length(%s) %s %s", path, c.Operator, formatNumber(c.ExpectedValue)), nil
+	case ArrayMatchCondition:
+		path, err := formatCheckedPath(c.ActualJSONPath)
+		if err != nil {
+			return "", err
+		}
+		expression, err := formatExpression(c.Expression, false)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s(%s, %s)", c.Operator, path, expression), nil
+	case ExistsCondition:
+		path, err := formatCheckedPath(c.ActualJSONPath)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("exists(%s)", path), nil
+	case PathCondition:
+		path, err := formatCheckedPath(c.ActualJSONPath)
+		if err != nil {
+			return "", err
+		}
+		expected, err := formatCheckedPath(c.ExpectedJSONPath)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s %s %s", path, c.Operator, expected), nil
+	}
+	return "", utils.NewInvalidArgumentError(fmt.Sprintf("unsupported condition type %T", conditionOrExpression))
+}
+
+func formatComparison(path utils.JSONPathQuery, operator string, expected any) (string, error) {
+	formattedPath, err := formatCheckedPath(path)
+	if err != nil {
+		return "", err
+	}
+	value, err := formatValue(expected)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s %s", formattedPath, operator, value), nil
+}
+
+func formatValue(value any) (string, error) {
+	switch v := value.(type) {
+	case float64:
+		return formatNumber(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case string:
+		return strconv.Quote(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if _, ok := item.([]any); ok {
+				return "", utils.NewInvalidArgumentError("lists cannot be nested")
+			}
+			formatted, err := formatValue(item)
+			if err != nil {
+				return "", err
+			}
+			values = append(values, formatted)
+		}
+		return "[" + strings.Join(values, ", ") + "]", nil
+	}
+	return "", utils.NewInvalidArgumentError(fmt.Sprintf("value %v cannot be written in an expression", value))
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatPath(path utils.JSONPathQuery) string {
+	if path == ThisJSONPath {
+		return "$"
+	}
+	return "$." + string(path)
+}
+
+// formatCheckedPath formats path, refusing paths that Parse could not read
+// back such as ones with gjson modifiers or escapes
+func formatCheckedPath(path utils.JSONPathQuery) (string, error) {
+	if path != ThisJSONPath {
+		for _, segment := range strings.Split(string(path), ".") {
+			if segment == "" || strings.IndexFunc(segment, func(r rune) bool { return !isPathRune(r) }) >= 0 {
+				return "", utils.NewInvalidArgumentError(fmt.Sprintf("path %q cannot be written in an expression", path))
+			}
+		}
+	}
+	return formatPath(path), nil
+}
@@ -0,0 +1,48 @@
+package booleanexpression_test
+
+import (
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *BooleanExpressionTestSuite) TestFormat() {
+	s.Run("should round trip through Parse", func() {
+		inputs := []string{
+			`$.self.points < 5`,
+			`($.a == 1 and $.b != "x") or not ($.c == true xor $.d == false)`,
+			`not $.revealed == true and $.hand not_contains [1, "two", true]`,
+			`$.name starts_with "Sir" or $.name matches "^[a-z]+\\d$" or $.suite equals_ignore_case "HEARTS"`,
+			`$.round between [2, 5.5] and $.round in [-1, 3]`,
+			`length($.hand) >= 3 and exists($.card.face) and $.self.points > $.opponent.points`,
+			`any($.hand, $ > 10) or all($.cards, $.suite == "hearts" or $.value < 3)`,
+		}
+		for _, input := range inputs {
+			expression, err := booleanexpression.Parse(input)
+			require.NoError(s.T(), err, input)
+			formatted, err := booleanexpression.Format(expression)
+			require.NoError(s.T(), err, input)
+			assert.Equal(s.T(), input, formatted)
+
+			reparsed, err := booleanexpression.Parse(formatted)
+			require.NoError(s.T(), err, input)
+			assert.Equal(s.T(), expression, reparsed, input)
+		}
+	})
+
+	s.Run("should write an empty expression as nothing", func() {
+		formatted, err := booleanexpression.Format(booleanexpression.BooleanExpression{})
+		require.NoError(s.T(), err)
+		assert.Empty(s.T(), formatted)
+	})
+
+	s.Run("should reject expressions Parse could not read back", func() {
+		_, err := booleanexpression.Format(booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+			booleanexpression.NewNumericCondition("friends.#(age>45)", booleanexpression.OperatorEqual, 1),
+		))
+		assert.ErrorContains(s.T(), err, `path "friends.#(age>45)" cannot be written`)
+
+		_, err = booleanexpression.Format(booleanexpression.NewBooleanExpression(booleanexpression.OperatorNOT))
+		assert.ErrorContains(s.T(), err, "NOT has no conditions")
+	})
+}
@@ -0,0 +1,151 @@
+package booleanexpression
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/coopersmall/subswag/utils"
+)
+
+// UnmarshalJSON decodes each entry of conditions by its "type", falling back
+// to a nested expression when an entry has no type but a boolean operator
+func (b *BooleanExpression) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Operator   BooleanOperator   `json:"operator"`
+		Conditions []json.RawMessage `json:"conditions"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	b.Operator = raw.Operator
+	b.Conditions = nil
+	if raw.Conditions != nil {
+		b.Conditions = make([]IsBooleanExpressionOrCondition, 0, len(raw.Conditions))
+	}
+	for i, rawCondition := range raw.Conditions {
+		condition, err := UnmarshalConditionOrExpression(rawCondition)
+		if err != nil {
+			return utils.NewInvalidArgumentError(fmt.Sprintf("conditions[%d]: %s", i, utils.GetErrorMessage(err)))
+		}
+		b.Conditions = append(b.Conditions, condition)
+	}
+	return nil
+}
+
+// UnmarshalConditionOrExpression decodes a single condition or nested
+// expression
+func UnmarshalConditionOrExpression(data []byte) (IsBooleanExpressionOrCondition, error) {
+	var head struct {
+		Type     ConditionType `json:"type"`
+		Operator string        `json:"operator"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, utils.NewInvalidArgumentError("condition must be an object", err)
+	}
+
+	switch head.Type {
+	case ConditionTypeNumeric:
+		return unmarshalCondition[NumericCondition](data)
+	case ConditionTypeString:
+		return unmarshalCondition[StringCondition](data)
+	case ConditionTypeBoolean:
+		return unmarshalCondition[BooleanCondition](data)
+	case ConditionTypeArray:
+		return unmarshalCondition[ArrayCondition](data)
+	case ConditionTypeArrayLength:
+		return unmarshalCondition[ArrayLengthCondition](data)
+	case ConditionTypeArrayMatch:
+		return unmarshalCondition[ArrayMatchCondition](data)
+	case ConditionTypeExists:
+		return unmarshalCondition[ExistsCondition](data)
+	case ConditionTypePath:
+		return unmarshalCondition[PathCondition](data)
+	case "":
+		switch BooleanOperator(head.Operator) {
+		case OperatorAND, OperatorOR, OperatorNOT, OperatorXOR:
+			var expression BooleanExpression
+			if err := json.Unmarshal(data, &expression); err != nil {
+				return nil, err
+			}
+			return expression, nil
+		}
+		return nil, utils.NewInvalidArgumentError(fmt.Sprintf("condition with operator %q has no type", head.Operator))
+	}
+	return nil, utils.NewInvalidArgumentError(fmt.Sprintf("unknown condition type %q", head.Type))
+}
+
+func unmarshalCondition[C IsBooleanExpressionOrCondition](data []byte) (IsBooleanExpressionOrCondition, error) {
+	var condition C
+	if err := json.Unmarshal(data, &condition); err != nil {
+		return nil, utils.NewInvalidArgumentError("invalid condition", err)
+	}
+	return condition, nil
+}
+
+// The conditions fill in a missing type when encoded so that they can always
+// be decoded again
+
+func (n NumericCondition) MarshalJSON() ([]byte, error) {
+	type numericCondition NumericCondition
+	if n.Type == "" {
+		n.Type = ConditionTypeNumeric
+	}
+	return json.Marshal(numericCondition(n))
+}
+
+func (s StringCondition) MarshalJSON() ([]byte, error) {
+	type stringCondition StringCondition
+	if s.Type == "" {
+		s.Type = ConditionTypeString
+	}
+	return json.Marshal(stringCondition(s))
+}
+
+func (b BooleanCondition) MarshalJSON() ([]byte, error) {
+	type booleanCondition BooleanCondition
+	if b.Type == "" {
+		b.Type = ConditionTypeBoolean
+	}
+	return json.Marshal(booleanCondition(b))
+}
+
+func (a ArrayCondition) MarshalJSON() ([]byte, error) {
+	type arrayCondition ArrayCondition
+	if a.Type == "" {
+		a.Type = ConditionTypeArray
+	}
+	return json.Marshal(arrayCondition(a))
+}
+
+func (a ArrayLengthCondition) MarshalJSON() ([]byte, error) {
+	type arrayLengthCondition ArrayLengthCondition
+	if a.Type == "" {
+		a.Type = ConditionTypeArrayLength
+	}
+	return json.Marshal(arrayLengthCondition(a))
+}
+
+func (a ArrayMatchCondition) MarshalJSON() ([]byte, error) {
+	type arrayMatchCondition ArrayMatchCondition
+	if a.Type == "" {
+		a.Type = ConditionTypeArrayMatch
+	}
+	return json.Marshal(arrayMatchCondition(a))
+}
+
+func (e ExistsCondition) MarshalJSON() ([]byte, error) {
+	type existsCondition ExistsCondition
+	if e.Type == "" {
+		e.Type = ConditionTypeExists
+	}
+	return json.Marshal(existsCondition(e))
+}
+
+func (p PathCondition) MarshalJSON() ([]byte, error) {
+	type pathCondition PathCondition
+	if p.Type == "" {
+		p.Type = ConditionTypePath
+	}
+	return json.Marshal(pathCondition(p))
+}
@@ -0,0 +1,67 @@
+package booleanexpression_test
+
+import (
+	"encoding/json"
+
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *BooleanExpressionTestSuite) TestJSON() {
+	s.Run("should round trip every condition kind and nested expressions", func() {
+		expression := booleanexpression.NewBooleanExpression(booleanexpression.OperatorOR,
+			booleanexpression.NewNumericCondition("self.points", booleanexpression.OperatorLessThan, 5),
+			booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+				booleanexpression.NewStringCondition("trigger", booleanexpression.OperatorEqual, "war"),
+				booleanexpression.NewBooleanCondition("self.has_drawn", booleanexpression.OperatorNotEqual, true),
+				booleanexpression.NewArrayCondition("self.hand", booleanexpression.OperatorContainsAny, []any{1.0, "two", true}),
+			),
+			booleanexpression.NewBooleanExpression(booleanexpression.OperatorNOT,
+				booleanexpression.NewBooleanExpression(booleanexpression.OperatorXOR,
+					booleanexpression.NewBetweenCondition("round", 1, 3),
+					booleanexpression.NewStringCondition("name", booleanexpression.OperatorMatches, "^S"),
+				),
+			),
+			booleanexpression.NewArrayLengthCondition("hand", booleanexpression.OperatorGreaterEqual, 3),
+			booleanexpression.NewArrayMatchCondition("hand", booleanexpression.OperatorAll,
+				booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+					booleanexpression.NewNumericCondition(booleanexpression.ThisJSONPath, booleanexpression.OperatorGreaterThan, 1),
+				),
+			),
+			booleanexpression.NewExistsCondition("card.face"),
+			booleanexpression.NewPathCondition("self.points", booleanexpression.OperatorGreaterThan, "opponent.points"),
+		)
+
+		data, err := json.Marshal(expression)
+		require.NoError(s.T(), err)
+
+		var decoded booleanexpression.BooleanExpression
+		require.NoError(s.T(), json.Unmarshal(data, &decoded))
+		assert.Equal(s.T(), expression, decoded)
+	})
+
+	s.Run("should decode an empty expression", func() {
+		var decoded booleanexpression.BooleanExpression
+		require.NoError(s.T(), json.Unmarshal([]byte(`{}`), &decoded))
+		assert.Equal(s.T(), booleanexpression.BooleanExpression{}, decoded)
+	})
+
+	s.Run("should fill in a missing type when encoding", func() {
+		data, err := json.Marshal(booleanexpression.NumericCondition{ActualJSONPath: "round", Operator: booleanexpression.OperatorEqual, ExpectedValue: 1})
+		require.NoError(s.T(), err)
+		assert.JSONEq(s.T(), `{"type": "numeric", "actual_json_path": "round", "operator": "==", "expected_value": 1}`, string(data))
+	})
+
+	s.Run("should reject conditions it cannot tell apart", func() {
+		var decoded booleanexpression.BooleanExpression
+		err := json.Unmarshal([]byte(`{"operator": "AND", "conditions": [{"operator": "AND", "conditions": []}, {"type": "date"}]}`), &decoded)
+		assert.ErrorContains(s.T(), err, `conditions[1]: unknown condition type "date"`)
+
+		err = json.Unmarshal([]byte(`{"operator": "AND", "conditions": [{"operator": "==", "actual_json_path": "round"}]}`), &decoded)
+		assert.ErrorContains(s.T(), err, `conditions[0]: condition with operator "==" has no type`)
+
+		err = json.Unmarshal([]byte(`{"operator": "AND", "conditions": [{"operator": "OR", "conditions": [{"type": "numeric", "expected_value": "1"}]}]}`), &decoded)
+		assert.ErrorContains(s.T(), err, "conditions[0]: conditions[0]: invalid condition")
+	})
+}
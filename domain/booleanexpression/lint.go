@@ -0,0 +1,429 @@
+package booleanexpression
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/coopersmall/subswag/utils"
+)
+
+// LintSeverity says whether a lint problem stops an expression from working
+// or only looks like a mistake
+type LintSeverity string
+
+const (
+	LintSeverityError   LintSeverity = "error"
+	LintSeverityWarning LintSeverity = "warning"
+)
+
+// LintProblem is something Lint found wrong with an expression
+type LintProblem struct {
+	Severity  LintSeverity `json:"severity"`
+	Condition string       `json:"condition"` // The condition at fault in Parse syntax
+	Message   string       `json:"message"`
+}
+
+func (p LintProblem) String() string {
+	return fmt.Sprintf("%s: %s: %s", p.Severity, p.Condition, p.Message)
+}
+
+// Lint checks an expression against the schema of the data it will be
+// evaluated against. Paths that are not in the schema, conditions that do not
+// match the type at their path and conditions that can never be true are
+// errors. Conditions that are always true are warnings. An invalid expression
+// is reported as a single error.
+func Lint(expression BooleanExpression, schema *Schema) []LintProblem {
+	if err := Validate(expression); err != nil {
+		return []LintProblem{{Severity: LintSeverityError, Condition: describe(expression), Message: utils.GetErrorMessage(err)}}
+	}
+	if expression.Operator == "" && len(expression.Conditions) == 0 {
+		return nil
+	}
+	l := &linter{}
+	l.lintExpression(expression, schema)
+	return l.problems
+}
+
+type linter struct {
+	problems []LintProblem
+}
+
+func (l *linter) report(severity LintSeverity, conditionOrExpression IsBooleanExpressionOrCondition, format string, args ...any) {
+	l.problems = append(l.problems, LintProblem{
+		Severity:  severity,
+		Condition: describe(conditionOrExpression),
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) lintExpression(expression BooleanExpression, schema *Schema) {
+	var facts []valueFact
+	for _, conditionOrExpression := range expression.Conditions {
+		if nested, ok := conditionOrExpression.(BooleanExpression); ok {
+			l.lintExpression(nested, schema)
+			continue
+		}
+		fact, ok := l.lintCondition(conditionOrExpression, schema)
+		if !ok {
+			continue
+		}
+		switch fact.judge(fact.domain(nil)) {
+		case judgementNever:
+			l.report(LintSeverityError, conditionOrExpression, "can never be true")
+		case judgementAlways:
+			l.report(LintSeverityWarning, conditionOrExpression, "is always true")
+		default:
+			facts = append(facts, fact)
+		}
+	}
+
+	if expression.Operator != OperatorAND && expression.Operator != OperatorOR {
+		return
+	}
+	for _, group := range groupFacts(facts) {
+		if len(group) < 2 {
+			continue
+		}
+		domain := group[0].domain(group)
+		switch {
+		case expression.Operator == OperatorAND && combineFacts(group, domain, true) == judgementNever:
+			l.report(LintSeverityError, expression, "conditions on %s can never all be true", group[0].key)
+		case expression.Operator == OperatorOR && combineFacts(group, domain, false) == judgementAlways:
+			l.report(LintSeverityWarning, expression, "conditions on %s are always true together", group[0].key)
+		}
+	}
+}
+
+// lintCondition checks a condition against the schema and returns what the
+// condition says about the value at its path, when that can be worked out
+func (l *linter) lintCondition(conditionOrExpression IsBooleanExpressionOrCondition, schema *Schema) (valueFact, bool) {
+	condition, ok := conditionOrExpression.(Condition)
+	if !ok {
+		return valueFact{}, false
+	}
+	actual, err := schema.Lookup(condition.GetActualJSONPath())
+	if err != nil {
+		l.report(LintSeverityError, conditionOrExpression, "%s", err)
+		return valueFact{}, false
+	}
+	expectType := func(expected SchemaType) bool {
+		if actual.Type == SchemaTypeAny || actual.Type == expected {
+			return true
+		}
+		l.report(LintSeverityError, conditionOrExpression, "%s is %s, not %s", formatPath(condition.GetActualJSONPath()), actual.Type.withArticle(), expected.withArticle())
+		return false
+	}
+
+	key := formatPath(condition.GetActualJSONPath())
+	switch c := conditionOrExpression.(type) {
+	case NumericCondition:
+		if !expectType(SchemaTypeNumber) {
+			return valueFact{}, false
+		}
+		return numberFact(key, actual, numericIntervals(c.Operator, c.ExpectedValue, c.ExpectedValues)), true
+
+	case StringCondition:
+		if !expectType(SchemaTypeString) {
+			return valueFact{}, false
+		}
+		return stringFact(key, actual, c)
+
+	case BooleanCondition:
+		if !expectType(SchemaTypeBoolean) {
+			return valueFact{}, false
+		}
+		return valueFact{
+			key:     key,
+			options: []string{"true", "false"},
+			test: func(value string) bool {
+				return evaluateOperator(c.Operator, value == "true", c.ExpectedValue)
+			},
+		}, true
+
+	case ArrayLengthCondition:
+		if !expectType(SchemaTypeArray) {
+			return valueFact{}, false
+		}
+		length := &Schema{Type: SchemaTypeNumber}
+		return numberFact(fmt.Sprintf("length(%s)", key), length.WithMinimum(0), numericIntervals(c.Operator, c.ExpectedValue, nil)), true
+
+	case ArrayMatchCondition:
+		if expectType(SchemaTypeArray) && actual.Items != nil {
+			l.lintExpression(c.Expression, actual.Items)
+		}
+
+	case ArrayCondition:
+		// A single value is read as an array of one, so that lists can be
+		// used to test a value against several options
+		if actual.Type == SchemaTypeObject {
+			expectType(SchemaTypeArray)
+		}
+
+	case PathCondition:
+		expected, err := schema.Lookup(c.ExpectedJSONPath)
+		if err != nil {
+			l.report(LintSeverityError, conditionOrExpression, "%s", err)
+			return valueFact{}, false
+		}
+		if actual.Type == SchemaTypeAny || expected.Type == SchemaTypeAny {
+			return valueFact{}, false
+		}
+		switch {
+		case c.Operator != OperatorEqual && c.Operator != OperatorNotEqual && (actual.Type != SchemaTypeNumber || expected.Type != SchemaTypeNumber):
+			l.report(LintSeverityError, conditionOrExpression, "only numbers can be ordered but %s is %s and %s is %s",
+				key, actual.Type.withArticle(), formatPath(c.ExpectedJSONPath), expected.Type.withArticle())
+		case actual.Type != expected.Type:
+			l.report(LintSeverityError, conditionOrExpression, "%s is %s and %s is %s, so they are never equal",
+				key, actual.Type.withArticle(), formatPath(c.ExpectedJSONPath), expected.Type.withArticle())
+		}
+	}
+	return valueFact{}, false
+}
+
+type judgement int
+
+const (
+	judgementSometimes judgement = iota
+	judgementNever
+	judgementAlways
+)
+
+// valueFact is the set of values a condition allows at a path. Numbers are
+// described by intervals. Strings and booleans are described by a test
+// applied to every value the path can have, which is the schema's enum, or
+// when there is none the values the conditions compare with plus one other.
+type valueFact struct {
+	key string
+
+	numbers []interval
+	bounds  interval
+
+	options  []string // Every value the path can have, nil if only the values compared with are known
+	literals []string // Values the condition compares with
+	test     func(value string) bool
+}
+
+// otherValue stands for every string that no condition compares with
+const otherValue = "\x00other"
+
+func (f valueFact) isNumber() bool {
+	return f.numbers != nil
+}
+
+// domain returns the values to test for a fact, taking the literals of every
+// fact in group into account
+func (f valueFact) domain(group []valueFact) []string {
+	if f.isNumber() || f.options != nil {
+		return f.options
+	}
+	domain := []string{otherValue}
+	for _, fact := range append([]valueFact{f}, group...) {
+		for _, literal := range fact.literals {
+			if !slices.Contains(domain, literal) {
+				domain = append(domain, literal)
+			}
+		}
+	}
+	return domain
+}
+
+func (f valueFact) judge(domain []string) judgement {
+	return combineFacts([]valueFact{f}, domain, true)
+}
+
+// combineFacts judges facts about one path combined with and, or with or when
+// all is false
+func combineFacts(facts []valueFact, domain []string, all bool) judgement {
+	if facts[0].isNumber() {
+		combined := facts[0].numbers
+		for _, fact := range facts[1:] {
+			if all {
+				combined = intersectIntervals(combined, fact.numbers)
+			} else {
+				combined = append(slices.Clone(combined), fact.numbers...)
+			}
+		}
+		return judgeIntervals(combined, facts[0].bounds)
+	}
+
+	matched := 0
+	for _, value := range domain {
+		result := all
+		for _, fact := range facts {
+			if fact.test(value) != all {
+				result = !all
+				break
+			}
+		}
+		if result {
+			matched++
+		}
+	}
+	switch matched {
+	case 0:
+		return judgementNever
+	case len(domain):
+		return judgementAlways
+	}
+	return judgementSometimes
+}
+
+// groupFacts groups facts by their path, keeping the order they appear in.
+// Facts about numbers and strings at a path of any type are kept apart.
+func groupFacts(facts []valueFact) [][]valueFact {
+	type groupKey struct {
+		key      string
+		isNumber bool
+	}
+	var groups [][]valueFact
+	index := map[groupKey]int{}
+	for _, fact := range facts {
+		key := groupKey{fact.key, fact.isNumber()}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], fact)
+	}
+	return groups
+}
+
+func stringFact(key string, schema *Schema, c StringCondition) (valueFact, bool) {
+	fact := valueFact{key: key, options: schema.Enum, literals: []string{c.ExpectedValue}}
+	switch c.Operator {
+	case OperatorEqual, OperatorNotEqual:
+		fact.test = func(value string) bool { return evaluateOperator(c.Operator, value, c.ExpectedValue) }
+		return fact, true
+	}
+	if schema.Enum == nil {
+		return valueFact{}, false // The other value cannot be tested with these operators
+	}
+	switch c.Operator {
+	case OperatorStartsWith:
+		fact.test = func(value string) bool { return strings.HasPrefix(value, c.ExpectedValue) }
+	case OperatorEndsWith:
+		fact.test = func(value string) bool { return strings.HasSuffix(value, c.ExpectedValue) }
+	case OperatorStringContains:
+		fact.test = func(value string) bool { return strings.Contains(value, c.ExpectedValue) }
+	case OperatorEqualIgnoreCase:
+		fact.test = func(value string) bool { return strings.EqualFold(value, c.ExpectedValue) }
+	case OperatorMatches:
+		pattern := regexp.MustCompile(c.ExpectedValue) // Checked by Validate
+		fact.test = pattern.MatchString
+	default:
+		return valueFact{}, false
+	}
+	return fact, true
+}
+
+func numberFact(key string, schema *Schema, numbers []interval) valueFact {
+	bounds := interval{lo: math.Inf(-1), hi: math.Inf(1), loOpen: true, hiOpen: true}
+	if schema.Minimum != nil {
+		bounds.lo, bounds.loOpen = *schema.Minimum, false
+	}
+	if schema.Maximum != nil {
+		bounds.hi, bounds.hiOpen = *schema.Maximum, false
+	}
+	return valueFact{key: key, numbers: numbers, bounds: bounds}
+}
+
+// interval is a range of numbers, open at an end that it does not include.
+// Infinite ends are always open.
+type interval struct {
+	lo, hi         float64
+	loOpen, hiOpen bool
+}
+
+func (i interval) empty() bool {
+	return i.lo > i.hi || (i.lo == i.hi && (i.loOpen || i.hiOpen))
+}
+
+func numericIntervals(operator Operator, expected float64, expectedValues []float64) []interval {
+	inf := math.Inf(1)
+	switch operator {
+	case OperatorEqual:
+		return []interval{{lo: expected, hi: expected}}
+	case OperatorNotEqual:
+		return []interval{{lo: -inf, hi: expected, loOpen: true, hiOpen: true}, {lo: expected, hi: inf, loOpen: true, hiOpen: true}}
+	case OperatorGreaterThan:
+		return []interval{{lo: expected, hi: inf, loOpen: true, hiOpen: true}}
+	case OperatorGreaterEqual:
+		return []interval{{lo: expected, hi: inf, hiOpen: true}}
+	case OperatorLessThan:
+		return []interval{{lo: -inf, hi: expected, loOpen: true, hiOpen: true}}
+	case OperatorLessEqual:
+		return []interval{{lo: -inf, hi: expected, loOpen: true}}
+	case OperatorBetween:
+		return []interval{{lo: expectedValues[0], hi: expectedValues[1]}}
+	case OperatorIn:
+		intervals := make([]interval, len(expectedValues))
+		for i, value := range expectedValues {
+			intervals[i] = interval{lo: value, hi: value}
+		}
+		return intervals
+	}
+	return []interval{}
+}
+
+func intersectIntervals(a, b []interval) []interval {
+	intersection := []interval{}
+	for _, x := range a {
+		for _, y := range b {
+			i := x
+			if y.lo > i.lo || (y.lo == i.lo && y.loOpen) {
+				i.lo, i.loOpen = y.lo, y.loOpen
+			}
+			if y.hi < i.hi || (y.hi == i.hi && y.hiOpen) {
+				i.hi, i.hiOpen = y.hi, y.hiOpen
+			}
+			if !i.empty() {
+				intersection = append(intersection, i)
+			}
+		}
+	}
+	return intersection
+}
+
+// judgeIntervals judges whether a value within bounds can fall inside, or
+// always falls inside, the union of the intervals
+func judgeIntervals(intervals []interval, bounds interval) judgement {
+	intervals = intersectIntervals(intervals, []interval{bounds})
+	if len(intervals) == 0 {
+		return judgementNever
+	}
+	slices.SortFunc(intervals, func(a, b interval) int {
+		if c := cmp.Compare(a.lo, b.lo); c != 0 {
+			return c
+		}
+		switch {
+		case a.loOpen == b.loOpen:
+			return 0
+		case a.loOpen:
+			return 1
+		}
+		return -1
+	})
+
+	// Every value below reach is covered, and reach itself when reachCovered
+	reach, reachCovered := bounds.lo, bounds.loOpen
+	for _, i := range intervals {
+		if i.lo > reach || (i.lo == reach && !reachCovered && i.loOpen) {
+			return judgementSometimes
+		}
+		if i.hi > reach {
+			reach, reachCovered = i.hi, !i.hiOpen
+		} else if i.hi == reach {
+			reachCovered = reachCovered || !i.hiOpen
+		}
+	}
+	if reach > bounds.hi || (reach == bounds.hi && (reachCovered || bounds.hiOpen)) {
+		return judgementAlways
+	}
+	return judgementSometimes
+}
@@ -0,0 +1,160 @@
+package booleanexpression_test
+
+import (
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type lintPlayer struct {
+	Points  int      `json:"points"`
+	HasDrew bool     `json:"has_drawn"`
+	Hand    []int    `json:"hand"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+type lintSnapshot struct {
+	Trigger  string         `json:"trigger"`
+	Name     string         `json:"name"`
+	Round    int            `json:"round"`
+	Self     lintPlayer     `json:"self"`
+	Opponent *lintPlayer    `json:"opponent"`
+	Extra    map[string]any `json:"extra"`
+	internal int
+}
+
+func lintSchema() *booleanexpression.Schema {
+	schema := booleanexpression.SchemaOf(lintSnapshot{})
+	schema.Property("trigger").WithEnum("draw", "reveal", "war")
+	schema.Property("round").WithMinimum(1)
+	schema.Property("self", "points").WithRange(0, 20)
+	return schema
+}
+
+func (s *BooleanExpressionTestSuite) TestSchema() {
+	s.Run("should describe a type by its json fields", func() {
+		schema := booleanexpression.SchemaOf(lintSnapshot{})
+		assert.Equal(s.T(), booleanexpression.SchemaTypeObject, schema.Type)
+		assert.Len(s.T(), schema.Properties, 6)
+		assert.Equal(s.T(), booleanexpression.SchemaTypeBoolean, schema.Property("opponent", "has_drawn").Type)
+		assert.Equal(s.T(), booleanexpression.SchemaTypeNumber, schema.Property("self", "hand").Items.Type)
+	})
+
+	s.Run("should look up paths", func() {
+		schema := lintSchema()
+		tests := map[string]booleanexpression.SchemaType{
+			"self.points":    booleanexpression.SchemaTypeNumber,
+			"self.hand":      booleanexpression.SchemaTypeArray,
+			"self.hand.0":    booleanexpression.SchemaTypeNumber,
+			"self.hand.#":    booleanexpression.SchemaTypeNumber,
+			"self.tags":      booleanexpression.SchemaTypeArray,
+			"extra.anything": booleanexpression.SchemaTypeAny,
+			"@this":          booleanexpression.SchemaTypeObject,
+		}
+		for path, expected := range tests {
+			found, err := schema.Lookup(utils.JSONPathQuery(path))
+			s.Require().NoError(err, path)
+			assert.Equal(s.T(), expected, found.Type, path)
+		}
+	})
+
+	s.Run("should fail to look up paths that are not in the schema", func() {
+		schema := lintSchema()
+		_, err := schema.Lookup("self.score")
+		assert.EqualError(s.T(), err, `$.self has no field "score", expected one of hand, has_drawn, points, tags`)
+		_, err = schema.Lookup("round.value")
+		assert.EqualError(s.T(), err, `$.round is a number and has no field "value"`)
+		_, err = schema.Lookup("self.hand.first")
+		assert.EqualError(s.T(), err, `$.self.hand is an array and has no field "first"`)
+	})
+}
+
+func (s *BooleanExpressionTestSuite) TestLint() {
+	lint := func(input string) []booleanexpression.LintProblem {
+		expression, err := booleanexpression.Parse(input)
+		s.Require().NoError(err, input)
+		return booleanexpression.Lint(expression, lintSchema())
+	}
+
+	s.Run("should accept expressions that fit the schema", func() {
+		for _, input := range []string{
+			`$.round > 3 and $.self.points < 10`,
+			`$.trigger == "war" or $.trigger == "reveal"`,
+			`$.trigger starts_with "dr"`,
+			`$.self.has_drawn and not $.opponent.has_drawn`,
+			`length($.self.hand) >= 2 and any($.self.hand, $ > 10)`,
+			`$.self.points > $.opponent.points`,
+			`$.name == "a" or $.name != "b" and $.name == "c"`,
+			`$.extra.anything == 3`,
+			`$.trigger contains_any ["war", "reveal"]`,
+			`$.round > 3 xor $.round > 5`,
+		} {
+			assert.Empty(s.T(), lint(input), input)
+		}
+	})
+
+	s.Run("should report paths and types that do not fit the schema", func() {
+		tests := map[string]string{
+			`$.self.score > 3`:                     `error: $.self.score > 3: $.self has no field "score", expected one of hand, has_drawn, points, tags`,
+			`$.name > 3`:                           `error: $.name > 3: $.name is a string, not a number`,
+			`length($.round) > 1`:                  `error: length($.round) > 1: $.round is a number, not an array`,
+			`any($.self.hand, $.value > 1)`:        `error: $.value > 1: the data is a number and has no field "value"`,
+			`$.self.points > $.name`:               `error: $.self.points > $.name: only numbers can be ordered but $.self.points is a number and $.name is a string`,
+			`$.self.points == $.self.has_drawn`:    `error: $.self.points == $.self.has_drawn: $.self.points is a number and $.self.has_drawn is a boolean, so they are never equal`,
+			`$.self contains [1]`:                  `error: $.self contains [1]: $.self is an object, not an array`,
+			`$.self.points > 1 and $.round == "x"`: `error: $.round == "x": $.round is a number, not a string`,
+		}
+		for input, expected := range tests {
+			problems := lint(input)
+			s.Require().Len(problems, 1, input)
+			assert.Equal(s.T(), expected, problems[0].String(), input)
+		}
+	})
+
+	s.Run("should report conditions that can never be true", func() {
+		tests := map[string]string{
+			`$.trigger == "raveal"`:                          `$.trigger == "raveal"`,
+			`$.self.points > 20`:                             `$.self.points > 20`,
+			`$.round between [-3, 0]`:                        `$.round between [-3, 0]`,
+			`length($.self.hand) < 0`:                        `length($.self.hand) < 0`,
+			`$.round > 5 and $.round < 3`:                    `$.round > 5 and $.round < 3`,
+			`$.round == 2 and $.round in [3, 4]`:             `$.round == 2 and $.round in [3, 4]`,
+			`$.name == "a" and $.name == "b"`:                `$.name == "a" and $.name == "b"`,
+			`$.self.has_drawn and $.self.has_drawn == false`: `$.self.has_drawn == true and $.self.has_drawn == false`,
+		}
+		for input, condition := range tests {
+			problems := lint(input)
+			s.Require().Len(problems, 1, input)
+			assert.Equal(s.T(), booleanexpression.LintSeverityError, problems[0].Severity, input)
+			assert.Equal(s.T(), condition, problems[0].Condition, input)
+		}
+	})
+
+	s.Run("should warn about conditions that are always true", func() {
+		tests := map[string]string{
+			`$.round >= 1`:                                 `is always true`,
+			`$.self.points between [0, 20]`:                `is always true`,
+			`length($.self.hand) >= 0`:                     `is always true`,
+			`$.round < 5 or $.round >= 5`:                  `conditions on $.round are always true together`,
+			`$.round <= 5 or $.round > 4 or $.name == "a"`: `conditions on $.round are always true together`,
+			`$.name != "a" or $.name != "b"`:               `conditions on $.name are always true together`,
+			`$.self.has_drawn or $.self.has_drawn != true`: `conditions on $.self.has_drawn are always true together`,
+		}
+		for input, message := range tests {
+			problems := lint(input)
+			s.Require().Len(problems, 1, input)
+			assert.Equal(s.T(), booleanexpression.LintSeverityWarning, problems[0].Severity, input)
+			assert.Equal(s.T(), message, problems[0].Message, input)
+		}
+	})
+
+	s.Run("should report invalid expressions", func() {
+		problems := booleanexpression.Lint(booleanexpression.BooleanExpression{Operator: booleanexpression.OperatorNOT}, lintSchema())
+		s.Require().Len(problems, 1)
+		assert.Equal(s.T(), booleanexpression.LintSeverityError, problems[0].Severity)
+	})
+
+	s.Run("should accept the empty expression", func() {
+		assert.Empty(s.T(), booleanexpression.Lint(booleanexpression.BooleanExpression{}, lintSchema()))
+	})
+}
@@ -0,0 +1,545 @@
+package booleanexpression
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/coopersmall/subswag/utils"
+)
+
+// ParseError points at the place in an expression that could not be parsed.
+// Lines and columns start at 1.
+type ParseError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// Parse reads an infix expression such as
+//
+//	(length($.hand) >= 3 AND $.suite == "hearts") OR NOT $.revealed
+//
+// Paths start with "$." and "$" alone is the data itself. A path can be
+// compared with:
+//
+//   - a number, string, true, false or another path using == != < <= > >=
+//   - a string using starts_with, ends_with, contains, matches (a regular
+//     expression) or equals_ignore_case
+//   - a list using contains, contains_all, contains_any or not_contains
+//   - a list of numbers using in, or between [low, high]
+//
+// A path on its own is true when its value is true. length($.path) compares
+// the length of an array, exists($.path) checks that a value is there and
+// any($.path, expression) and all($.path, expression) match the elements of
+// an array, with paths in the expression relative to the element.
+//
+// NOT binds tightest, then AND, XOR and OR. Parentheses group. AND, OR, XOR
+// and NOT are case insensitive. The result is always an expression, with a
+// single condition wrapped in an AND.
+func Parse(input string) (BooleanExpression, error) {
+	p := newExpressionParser(input)
+	node, err := p.parseOr()
+	if err != nil {
+		return BooleanExpression{}, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return BooleanExpression{}, p.unexpected(t, "expected AND, OR, XOR or the end of the expression")
+	}
+	return wrapCondition(node), nil
+}
+
+// ParsePrefix parses the longest expression at the start of input and
+// returns the byte offset where it stopped, so that the expression can be
+// embedded in a larger language. Errors are relative to the start of input.
+func ParsePrefix(input string) (BooleanExpression, int, error) {
+	p := newExpressionParser(input)
+	node, err := p.parseOr()
+	if err != nil {
+		return BooleanExpression{}, 0, err
+	}
+	return wrapCondition(node), p.peek().offset, nil
+}
+
+func wrapCondition(node IsBooleanExpressionOrCondition) BooleanExpression {
+	if expression, ok := node.(BooleanExpression); ok {
+		return expression
+	}
+	return NewBooleanExpression(OperatorAND, node)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenError
+	tokenWord
+	tokenNumber
+	tokenString
+	tokenPath
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenLeftBracket
+	tokenRightBracket
+	tokenComma
+)
+
+type token struct {
+	kind   tokenKind
+	text   string // Strings are unquoted and paths lose their "$."
+	offset int
+	line   int
+	column int
+	err    error // Set on tokenError
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "the end of the expression"
+	case tokenString:
+		return strconv.Quote(t.text)
+	case tokenPath:
+		return formatPath(utils.JSONPathQuery(t.text))
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+func (t token) errorf(format string, args ...any) error {
+	return &ParseError{Line: t.line, Column: t.column, Message: fmt.Sprintf(format, args...)}
+}
+
+// expressionLexer reads one token at a time so that parsing can stop in the
+// middle of input that is not an expression
+type expressionLexer struct {
+	input  string
+	offset int
+	line   int
+	column int
+}
+
+func (l *expressionLexer) peekRune(ahead int) rune {
+	offset := l.offset
+	for ; ahead > 0 && offset < len(l.input); ahead-- {
+		_, size := utf8.DecodeRuneInString(l.input[offset:])
+		offset += size
+	}
+	if offset >= len(l.input) {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(l.input[offset:])
+	return r
+}
+
+func (l *expressionLexer) advance() rune {
+	r, size := utf8.DecodeRuneInString(l.input[l.offset:])
+	l.offset += size
+	if r == '\n' {
+		l.line, l.column = l.line+1, 1
+	} else {
+		l.column++
+	}
+	return r
+}
+
+func (l *expressionLexer) readWhile(accept func(rune) bool) string {
+	start := l.offset
+	for l.offset < len(l.input) && accept(l.peekRune(0)) {
+		l.advance()
+	}
+	return l.input[start:l.offset]
+}
+
+func (l *expressionLexer) next() token {
+	l.readWhile(unicode.IsSpace)
+	t := token{offset: l.offset, line: l.line, column: l.column}
+	fail := func(t token, format string, args ...any) token {
+		return token{kind: tokenError, offset: t.offset, line: t.line, column: t.column, err: t.errorf(format, args...)}
+	}
+	if l.offset >= len(l.input) {
+		t.kind = tokenEOF
+		return t
+	}
+
+	r := l.peekRune(0)
+	switch {
+	case r == '_' || unicode.IsLetter(r):
+		t.kind, t.text = tokenWord, l.readWhile(isPathRune)
+	case unicode.IsDigit(r) || (r == '-' && unicode.IsDigit(l.peekRune(1))):
+		l.advance()
+		l.readWhile(unicode.IsDigit)
+		if l.peekRune(0) == '.' && unicode.IsDigit(l.peekRune(1)) {
+			l.advance()
+			l.readWhile(unicode.IsDigit)
+		}
+		t.kind, t.text = tokenNumber, l.input[t.offset:l.offset]
+	case r == '"':
+		l.advance()
+		for l.offset < len(l.input) && l.peekRune(0) != '"' && l.peekRune(0) != '\n' {
+			if l.advance() == '\\' && l.offset < len(l.input) {
+				l.advance()
+			}
+		}
+		if l.peekRune(0) != '"' {
+			return fail(t, "unterminated string")
+		}
+		l.advance()
+		text, err := strconv.Unquote(l.input[t.offset:l.offset])
+		if err != nil {
+			return fail(t, "invalid string %s", l.input[t.offset:l.offset])
+		}
+		t.kind, t.text = tokenString, text
+	case r == '$':
+		l.advance()
+		var segments []string
+		for l.peekRune(0) == '.' {
+			l.advance()
+			segment := l.readWhile(isPathRune)
+			if segment == "" {
+				return fail(token{offset: l.offset, line: l.line, column: l.column}, "expected a path segment after \".\"")
+			}
+			segments = append(segments, segment)
+		}
+		t.kind, t.text = tokenPath, strings.Join(segments, ".")
+		if len(segments) == 0 {
+			t.text = string(ThisJSONPath)
+		}
+	case strings.ContainsRune("=!<>", r):
+		l.advance()
+		if l.peekRune(0) == '=' {
+			l.advance()
+		}
+		t.kind, t.text = tokenOperator, l.input[t.offset:l.offset]
+		if t.text == "=" || t.text == "!" {
+			return fail(t, "unknown operator %q", t.text)
+		}
+	default:
+		kinds := map[rune]tokenKind{
+			'(': tokenLeftParen,
+			')': tokenRightParen,
+			'[': tokenLeftBracket,
+			']': tokenRightBracket,
+			',': tokenComma,
+		}
+		kind, ok := kinds[r]
+		if !ok {
+			return fail(t, "unexpected character %q", r)
+		}
+		l.advance()
+		t.kind, t.text = kind, string(r)
+	}
+	return t
+}
+
+func isPathRune(r rune) bool {
+	return r == '_' || r == '#' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+type expressionParser struct {
+	lexer     *expressionLexer
+	lookahead *token
+}
+
+func newExpressionParser(input string) *expressionParser {
+	return &expressionParser{lexer: &expressionLexer{input: input, line: 1, column: 1}}
+}
+
+func (p *expressionParser) peek() token {
+	if p.lookahead == nil {
+		t := p.lexer.next()
+		p.lookahead = &t
+	}
+	return *p.lookahead
+}
+
+func (p *expressionParser) advance() token {
+	t := p.peek()
+	if t.kind != tokenEOF && t.kind != tokenError {
+		p.lookahead = nil
+	}
+	return t
+}
+
+// unexpected reports a token the parser cannot use, preferring the lexer's
+// error when the token could not be read at all
+func (p *expressionParser) unexpected(t token, expected string) error {
+	if t.kind == tokenError {
+		return t.err
+	}
+	return t.errorf("%s but found %s", expected, t.describe())
+}
+
+func (p *expressionParser) expect(kind tokenKind, text string) error {
+	if t := p.peek(); !t.is(kind, text) {
+		return p.unexpected(t, fmt.Sprintf("expected %q", text))
+	}
+	p.advance()
+	return nil
+}
+
+func (p *expressionParser) parseOr() (IsBooleanExpressionOrCondition, error) {
+	return p.parseGroup(OperatorOR, p.parseXor)
+}
+
+func (p *expressionParser) parseXor() (IsBooleanExpressionOrCondition, error) {
+	return p.parseGroup(OperatorXOR, p.parseAnd)
+}
+
+func (p *expressionParser) parseAnd() (IsBooleanExpressionOrCondition, error) {
+	return p.parseGroup(OperatorAND, p.parseNot)
+}
+
+// parseGroup collects terms joined by the same keyword. A single term is
+// returned as is so that expressions only nest where they have to.
+func (p *expressionParser) parseGroup(operator BooleanOperator, parseTerm func() (IsBooleanExpressionOrCondition, error)) (IsBooleanExpressionOrCondition, error) {
+	first, err := parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	terms := []IsBooleanExpressionOrCondition{first}
+	for p.peek().isKeyword(string(operator)) {
+		p.advance()
+		term, err := parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 1 {
+		return first, nil
+	}
+	return NewBooleanExpression(operator, terms...), nil
+}
+
+func (p *expressionParser) parseNot() (IsBooleanExpressionOrCondition, error) {
+	if !p.peek().isKeyword(string(OperatorNOT)) {
+		return p.parsePrimary()
+	}
+	p.advance()
+	node, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	return NewBooleanExpression(OperatorNOT, node), nil
+}
+
+func (p *expressionParser) parsePrimary() (IsBooleanExpressionOrCondition, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenLeftParen:
+		p.advance()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(tokenRightParen, ")")
+	case t.kind == tokenWord && p.peekSecond().kind == tokenLeftParen:
+		return p.parseFunction()
+	case t.kind != tokenPath:
+		return nil, p.unexpected(t, "expected a condition like $.self.points < 5")
+	}
+
+	p.advance()
+	path := utils.JSONPathQuery(t.text)
+	operator := p.peek()
+	switch {
+	case operator.kind == tokenError:
+		return nil, operator.err
+	case operator.kind == tokenOperator:
+		p.advance()
+		return p.parseComparison(path, operator)
+	case operator.kind == tokenWord:
+		if condition, ok, err := p.parseWordComparison(path, operator); ok || err != nil {
+			return condition, err
+		}
+	}
+	return NewBooleanCondition(path, OperatorEqual, true), nil
+}
+
+func (p *expressionParser) parseFunction() (IsBooleanExpressionOrCondition, error) {
+	name := p.advance()
+	switch name.text {
+	case "length", "exists", "any", "all":
+	default:
+		return nil, name.errorf("unknown function %q, expected length, exists, any or all", name.text)
+	}
+	if err := p.expect(tokenLeftParen, "("); err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind != tokenPath {
+		return nil, p.unexpected(t, "expected a path")
+	}
+	p.advance()
+	path := utils.JSONPathQuery(t.text)
+
+	switch name.text {
+	case "exists":
+		return NewExistsCondition(path), p.expect(tokenRightParen, ")")
+	case "length":
+		if err := p.expect(tokenRightParen, ")"); err != nil {
+			return nil, err
+		}
+		operator := p.peek()
+		if operator.kind != tokenOperator {
+			return nil, p.unexpected(operator, "expected a comparison")
+		}
+		p.advance()
+		value := p.peek()
+		if value.kind != tokenNumber {
+			return nil, p.unexpected(value, "expected a number")
+		}
+		number, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		return NewArrayLengthCondition(path, Operator(operator.text), number), nil
+	}
+
+	if err := p.expect(tokenComma, ","); err != nil {
+		return nil, err
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	return NewArrayMatchCondition(path, ArrayMatchOperator(name.text), wrapCondition(node)), p.expect(tokenRightParen, ")")
+}
+
+func (p *expressionParser) parseComparison(path utils.JSONPathQuery, operator token) (IsBooleanExpressionOrCondition, error) {
+	op := Operator(operator.text)
+	if t := p.peek(); t.kind == tokenPath {
+		p.advance()
+		return NewPathCondition(path, op, utils.JSONPathQuery(t.text)), nil
+	}
+	value, err := p.parseScalar()
+	if err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case float64:
+		return NewNumericCondition(path, op, v), nil
+	case string:
+		if !slices.Contains(equalityOperators, op) {
+			return nil, operator.errorf("strings can only be compared with == or !=")
+		}
+		return NewStringCondition(path, op, v), nil
+	default:
+		if !slices.Contains(equalityOperators, op) {
+			return nil, operator.errorf("true and false can only be compared with == or !=")
+		}
+		return NewBooleanCondition(path, op, v.(bool)), nil
+	}
+}
+
+// parseWordComparison reads comparisons written with a word operator. It
+// reports false when the word is not an operator, leaving it for whatever
+// follows the expression.
+func (p *expressionParser) parseWordComparison(path utils.JSONPathQuery, operator token) (IsBooleanExpressionOrCondition, bool, error) {
+	switch op := operator.text; {
+	case op == string(OperatorStringContains) && p.peekSecond().kind == tokenString,
+		slices.Contains(stringOperators, Operator(op)) && op != string(OperatorStringContains):
+		p.advance()
+		t := p.peek()
+		if t.kind != tokenString {
+			return nil, true, p.unexpected(t, fmt.Sprintf("%s needs a string", op))
+		}
+		p.advance()
+		return NewStringCondition(path, Operator(op), t.text), true, nil
+	case slices.Contains(arrayOperators, ArryConditionOperator(op)):
+		p.advance()
+		values, err := p.parseList(op)
+		if err != nil {
+			return nil, true, err
+		}
+		return NewArrayCondition(path, ArryConditionOperator(op), values), true, nil
+	case op == string(OperatorBetween) || op == string(OperatorIn):
+		p.advance()
+		values, err := p.parseList(op)
+		if err != nil {
+			return nil, true, err
+		}
+		numbers := make([]float64, 0, len(values))
+		for _, value := range values {
+			number, ok := value.(float64)
+			if !ok {
+				return nil, true, operator.errorf("%s needs a list of numbers", op)
+			}
+			numbers = append(numbers, number)
+		}
+		if op == string(OperatorBetween) && len(numbers) != 2 {
+			return nil, true, operator.errorf("between needs a list of two numbers like [1, 5]")
+		}
+		return NumericCondition{Type: ConditionTypeNumeric, ActualJSONPath: path, Operator: Operator(op), ExpectedValues: numbers}, true, nil
+	}
+	return nil, false, nil
+}
+
+// peekSecond looks one token past the next without consuming either
+func (p *expressionParser) peekSecond() token {
+	p.peek()
+	lexer := *p.lexer
+	return lexer.next()
+}
+
+func (p *expressionParser) parseList(operator string) ([]any, error) {
+	if t := p.peek(); t.kind != tokenLeftBracket {
+		return nil, p.unexpected(t, fmt.Sprintf("%s needs a list like [1, 2]", operator))
+	}
+	p.advance()
+	values := []any{}
+	for !p.peek().is(tokenRightBracket, "]") {
+		if len(values) > 0 {
+			if err := p.expect(tokenComma, ","); err != nil {
+				return nil, err
+			}
+		}
+		value, err := p.parseScalar()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	p.advance()
+	return values, nil
+}
+
+func (p *expressionParser) parseScalar() (any, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenNumber:
+		return p.parseNumber()
+	case t.kind == tokenString:
+		p.advance()
+		return t.text, nil
+	case t.kind == tokenWord && (t.text == "true" || t.text == "false"):
+		p.advance()
+		return t.text == "true", nil
+	}
+	return nil, p.unexpected(t, "expected a number, string, true or false")
+}
+
+func (p *expressionParser) parseNumber() (float64, error) {
+	t := p.advance()
+	value, err := strconv.ParseFloat(t.text, 64)
+	if err != nil {
+		return 0, t.errorf("invalid number %q", t.text)
+	}
+	return value, nil
+}
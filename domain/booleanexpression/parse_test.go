@@ -0,0 +1,134 @@
+package booleanexpression_test
+
+import (
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *BooleanExpressionTestSuite) TestParse() {
+	s.Run("should parse nested infix expressions", func() {
+		expression, err := booleanexpression.Parse(`($.hand.length >= 3 AND $.suite == "hearts") OR NOT $.revealed`)
+		require.NoError(s.T(), err)
+
+		assert.Equal(s.T(), booleanexpression.NewBooleanExpression(booleanexpression.OperatorOR,
+			booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+				booleanexpression.NewNumericCondition("hand.length", booleanexpression.OperatorGreaterEqual, 3),
+				booleanexpression.NewStringCondition("suite", booleanexpression.OperatorEqual, "hearts"),
+			),
+			booleanexpression.NewBooleanExpression(booleanexpression.OperatorNOT,
+				booleanexpression.NewBooleanCondition("revealed", booleanexpression.OperatorEqual, true),
+			),
+		), expression)
+	})
+
+	s.Run("should bind AND tighter than OR", func() {
+		expression, err := booleanexpression.Parse(`$.a == 1 or $.b == 2 and $.c contains [1, "x", false]`)
+		require.NoError(s.T(), err)
+
+		assert.Equal(s.T(), booleanexpression.NewBooleanExpression(booleanexpression.OperatorOR,
+			booleanexpression.NewNumericCondition("a", booleanexpression.OperatorEqual, 1),
+			booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+				booleanexpression.NewNumericCondition("b", booleanexpression.OperatorEqual, 2),
+				booleanexpression.NewArrayCondition("c", booleanexpression.OperatorContains, []any{1.0, "x", false}),
+			),
+		), expression)
+	})
+
+	s.Run("should wrap a single condition in an AND", func() {
+		expression, err := booleanexpression.Parse(`$.self.points < -2.5`)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+			booleanexpression.NewNumericCondition("self.points", booleanexpression.OperatorLessThan, -2.5),
+		), expression)
+	})
+
+	s.Run("should bind NOT tightest, then AND, XOR and OR", func() {
+		expression, err := booleanexpression.Parse(`not $.a xor $.b and $.c or $.d`)
+		require.NoError(s.T(), err)
+
+		condition := func(path string) booleanexpression.BooleanCondition {
+			return booleanexpression.NewBooleanCondition(utils.JSONPathQuery(path), booleanexpression.OperatorEqual, true)
+		}
+		assert.Equal(s.T(), booleanexpression.NewBooleanExpression(booleanexpression.OperatorOR,
+			booleanexpression.NewBooleanExpression(booleanexpression.OperatorXOR,
+				booleanexpression.NewBooleanExpression(booleanexpression.OperatorNOT, condition("a")),
+				booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND, condition("b"), condition("c")),
+			),
+			condition("d"),
+		), expression)
+	})
+
+	s.Run("should parse string, range, array and path operators", func() {
+		tests := map[string]booleanexpression.IsBooleanExpressionOrCondition{
+			`$.name starts_with "Sir"`:            booleanexpression.NewStringCondition("name", booleanexpression.OperatorStartsWith, "Sir"),
+			`$.name ends_with "III"`:              booleanexpression.NewStringCondition("name", booleanexpression.OperatorEndsWith, "III"),
+			`$.name contains "ar"`:                booleanexpression.NewStringCondition("name", booleanexpression.OperatorStringContains, "ar"),
+			`$.name matches "^[A-Z]"`:             booleanexpression.NewStringCondition("name", booleanexpression.OperatorMatches, "^[A-Z]"),
+			`$.suite equals_ignore_case "HEARTS"`: booleanexpression.NewStringCondition("suite", booleanexpression.OperatorEqualIgnoreCase, "HEARTS"),
+			`$.hand contains [1]`:                 booleanexpression.NewArrayCondition("hand", booleanexpression.OperatorContains, []any{1.0}),
+			`$.round between [2, 5]`:              booleanexpression.NewBetweenCondition("round", 2, 5),
+			`$.round in [1, 3, 5]`:                booleanexpression.NewInCondition("round", 1, 3, 5),
+			`length($.hand) >= 3`:                 booleanexpression.NewArrayLengthCondition("hand", booleanexpression.OperatorGreaterEqual, 3),
+			`exists($.card.face)`:                 booleanexpression.NewExistsCondition("card.face"),
+			`$.self.points > $.opponent.points`:   booleanexpression.NewPathCondition("self.points", booleanexpression.OperatorGreaterThan, "opponent.points"),
+			`any($.hand, $ > 10)`: booleanexpression.NewArrayMatchCondition("hand", booleanexpression.OperatorAny,
+				booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+					booleanexpression.NewNumericCondition(booleanexpression.ThisJSONPath, booleanexpression.OperatorGreaterThan, 10),
+				),
+			),
+			`all($.cards, $.suite == "hearts" or $.value < 3)`: booleanexpression.NewArrayMatchCondition("cards", booleanexpression.OperatorAll,
+				booleanexpression.NewBooleanExpression(booleanexpression.OperatorOR,
+					booleanexpression.NewStringCondition("suite", booleanexpression.OperatorEqual, "hearts"),
+					booleanexpression.NewNumericCondition("value", booleanexpression.OperatorLessThan, 3),
+				),
+			),
+		}
+		for input, condition := range tests {
+			expression, err := booleanexpression.Parse(input)
+			require.NoError(s.T(), err, input)
+			assert.Equal(s.T(), booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND, condition), expression, input)
+		}
+	})
+
+	s.Run("should stop at the end of an embedded expression", func() {
+		input := `$.round > 3 and $.self.has_drawn then draw 1`
+		expression, end, err := booleanexpression.ParsePrefix(input)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), "then draw 1", input[end:])
+		assert.Len(s.T(), expression.Conditions, 2)
+	})
+
+	s.Run("should report where an expression is malformed", func() {
+		tests := []struct {
+			input   string
+			column  int
+			message string
+		}{
+			{`$.a >`, 6, "expected a number, string, true or false"},
+			{`($.a == 1`, 10, `expected ")"`},
+			{`$.a == 1 $.b == 2`, 10, "expected AND, OR, XOR or the end"},
+			{`$.a > "x"`, 5, "strings can only be compared with == or !="},
+			{`$.a contains 1`, 14, "contains needs a list"},
+			{`$.a between [1]`, 5, "between needs a list of two numbers"},
+			{`$.a in ["x"]`, 5, "in needs a list of numbers"},
+			{`length($.a) > "x"`, 15, "expected a number"},
+			{`size($.a) > 1`, 1, `unknown function "size"`},
+			{`any($.a $.b)`, 9, `expected ","`},
+			{`$.a starts_with 1`, 17, "starts_with needs a string"},
+			{`$.a = 1`, 5, `unknown operator "="`},
+			{`$.a == "x`, 8, "unterminated string"},
+			{`a == 1`, 1, "expected a condition"},
+			{`$.a & 1`, 5, "unexpected character"},
+		}
+		for _, test := range tests {
+			_, err := booleanexpression.Parse(test.input)
+			var parseErr *booleanexpression.ParseError
+			require.ErrorAs(s.T(), err, &parseErr, test.input)
+			assert.Equal(s.T(), 1, parseErr.Line, test.input)
+			assert.Equal(s.T(), test.column, parseErr.Column, test.input)
+			assert.Contains(s.T(), parseErr.Message, test.message, test.input)
+		}
+	})
+}
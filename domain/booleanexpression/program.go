@@ -0,0 +1,348 @@
+package booleanexpression
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/coopersmall/subswag/utils"
+	"github.com/tidwall/gjson"
+)
+
+// Program is an expression that has been validated and compiled once so it
+// can be evaluated many times. Every evaluation serializes the data once and
+// reads all of its conditions from that one document, stopping as soon as
+// the result is known. It gives the same results as EvaluateBooleanExpression,
+// except that an empty expression is always true.
+type Program struct {
+	root programNode // nil when the expression is empty
+}
+
+// ConditionTrace records how a condition or nested expression was evaluated.
+// Conditions skipped because the result was already known are left out.
+type ConditionTrace struct {
+	Condition string            `json:"condition"` // The condition in Parse syntax
+	Actual    any               `json:"actual,omitempty"`
+	Result    bool              `json:"result"`
+	Error     string            `json:"error,omitempty"`
+	Children  []*ConditionTrace `json:"children,omitempty"`
+}
+
+// Compile validates an expression and compiles it into a Program
+func Compile(expression BooleanExpression) (*Program, error) {
+	if err := Validate(expression); err != nil {
+		return nil, err
+	}
+	if expression.Operator == "" && len(expression.Conditions) == 0 {
+		return &Program{}, nil
+	}
+	root, err := compileNode(expression)
+	if err != nil {
+		return nil, err
+	}
+	return &Program{root: root}, nil
+}
+
+// Evaluate runs the program against data
+func (p *Program) Evaluate(data any) (bool, error) {
+	document, err := json.Marshal(data)
+	if err != nil {
+		return false, utils.NewJSONMarshError("failed to marshal json while evaluating expression", err)
+	}
+	return p.EvaluateJSON(document)
+}
+
+// EvaluateJSON runs the program against data that is already JSON
+func (p *Program) EvaluateJSON(document []byte) (bool, error) {
+	if p.root == nil {
+		return true, nil
+	}
+	return p.root.evaluate(string(document), nil)
+}
+
+// Trace runs the program against data and records what every condition that
+// was evaluated saw. The trace is returned even when evaluation fails.
+func (p *Program) Trace(data any) (bool, *ConditionTrace, error) {
+	if p.root == nil {
+		return true, &ConditionTrace{Result: true}, nil
+	}
+	document, err := json.Marshal(data)
+	if err != nil {
+		return false, nil, utils.NewJSONMarshError("failed to marshal json while evaluating expression", err)
+	}
+	trace := &ConditionTrace{}
+	result, err := p.root.evaluate(string(document), trace)
+	return result, trace, err
+}
+
+type programNode interface {
+	// evaluate records into trace when it is not nil
+	evaluate(document string, trace *ConditionTrace) (bool, error)
+}
+
+type expressionNode struct {
+	operator    BooleanOperator
+	children    []programNode
+	description string
+}
+
+func (n *expressionNode) evaluate(document string, trace *ConditionTrace) (bool, error) {
+	result, err := n.run(document, trace)
+	if trace != nil {
+		trace.Condition, trace.Result = n.description, result
+		if err != nil {
+			trace.Error = err.Error()
+		}
+	}
+	return result, err
+}
+
+func (n *expressionNode) run(document string, trace *ConditionTrace) (bool, error) {
+	result := n.operator == OperatorAND
+	for _, child := range n.children {
+		var childTrace *ConditionTrace
+		if trace != nil {
+			childTrace = &ConditionTrace{}
+			trace.Children = append(trace.Children, childTrace)
+		}
+		r, err := child.evaluate(document, childTrace)
+		if err != nil {
+			return false, err
+		}
+		switch n.operator {
+		case OperatorAND:
+			if !r {
+				return false, nil
+			}
+		case OperatorOR:
+			if r {
+				return true, nil
+			}
+		case OperatorXOR:
+			result = result != r
+		case OperatorNOT:
+			return !r, nil
+		}
+	}
+	return result, nil
+}
+
+type conditionNode struct {
+	description string
+	check       func(document string) (result bool, actual any, err error)
+}
+
+func (n *conditionNode) evaluate(document string, trace *ConditionTrace) (bool, error) {
+	result, actual, err := n.check(document)
+	if trace != nil {
+		trace.Condition, trace.Actual, trace.Result = n.description, actual, result
+		if err != nil {
+			trace.Error = err.Error()
+		}
+	}
+	return result, err
+}
+
+func compileNode(conditionOrExpression IsBooleanExpressionOrCondition) (programNode, error) {
+	if expression, ok := conditionOrExpression.(BooleanExpression); ok {
+		node := &expressionNode{operator: expression.Operator, description: describe(expression)}
+		for _, condition := range expression.Conditions {
+			child, err := compileNode(condition)
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		}
+		return node, nil
+	}
+
+	check, err := compileCondition(conditionOrExpression)
+	if err != nil {
+		return nil, err
+	}
+	return &conditionNode{description: describe(conditionOrExpression), check: check}, nil
+}
+
+func compileCondition(conditionOrExpression IsBooleanExpressionOrCondition) (func(string) (bool, any, error), error) {
+	switch c := conditionOrExpression.(type) {
+	case NumericCondition:
+		path := string(c.ActualJSONPath)
+		return func(document string) (bool, any, error) {
+			r := gjson.Get(document, path)
+			actual := resultValue(r)
+			if r.Type != gjson.Number {
+				return false, actual, fmt.Errorf("value is not a number")
+			}
+			switch c.Operator {
+			case OperatorBetween:
+				return r.Num >= c.ExpectedValues[0] && r.Num <= c.ExpectedValues[1], actual, nil
+			case OperatorIn:
+				return slices.Contains(c.ExpectedValues, r.Num), actual, nil
+			}
+			return evaluateOperator(c.Operator, r.Num, c.ExpectedValue), actual, nil
+		}, nil
+
+	case StringCondition:
+		path := string(c.ActualJSONPath)
+		var test func(actual string) bool
+		switch c.Operator {
+		case OperatorStartsWith:
+			test = func(actual string) bool { return strings.HasPrefix(actual, c.ExpectedValue) }
+		case OperatorEndsWith:
+			test = func(actual string) bool { return strings.HasSuffix(actual, c.ExpectedValue) }
+		case OperatorStringContains:
+			test = func(actual string) bool { return strings.Contains(actual, c.ExpectedValue) }
+		case OperatorEqualIgnoreCase:
+			test = func(actual string) bool { return strings.EqualFold(actual, c.ExpectedValue) }
+		case OperatorMatches:
+			pattern := regexp.MustCompile(c.ExpectedValue) // Checked by Validate
+			test = pattern.MatchString
+		default:
+			test = func(actual string) bool { return evaluateOperator(c.Operator, actual, c.ExpectedValue) }
+		}
+		return func(document string) (bool, any, error) {
+			r := gjson.Get(document, path)
+			if !r.Exists() {
+				return test(""), "", nil
+			}
+			if r.Type != gjson.String {
+				return false, resultValue(r), fmt.Errorf("value is not a string")
+			}
+			return test(r.Str), r.Str, nil
+		}, nil
+
+	case BooleanCondition:
+		path := string(c.ActualJSONPath)
+		return func(document string) (bool, any, error) {
+			r := gjson.Get(document, path)
+			if r.Type != gjson.True && r.Type != gjson.False {
+				return false, resultValue(r), fmt.Errorf("value is not a boolean")
+			}
+			return evaluateOperator(c.Operator, r.Bool(), c.ExpectedValue), r.Bool(), nil
+		}, nil
+
+	case ArrayCondition:
+		path := string(c.ActualJSONPath)
+		return func(document string) (bool, any, error) {
+			actual := resultValue(gjson.Get(document, path))
+			elements, ok := actual.([]any)
+			if !ok {
+				elements = []any{actual}
+			}
+			switch c.Operator {
+			case OperatorContains, OperatorContainsAll:
+				return evaluateArrayContains(elements, c.ExpectedValue), actual, nil
+			case OperatorContainsAny:
+				return evaluateArrayContainsAny(elements, c.ExpectedValue), actual, nil
+			}
+			return !evaluateArrayContains(elements, c.ExpectedValue), actual, nil
+		}, nil
+
+	case ArrayLengthCondition:
+		path := string(c.ActualJSONPath)
+		return func(document string) (bool, any, error) {
+			r := gjson.Get(document, path)
+			if !r.Exists() {
+				return evaluateOperator(c.Operator, 0.0, c.ExpectedValue), 0, nil
+			}
+			if !r.IsArray() {
+				return false, resultValue(r), fmt.Errorf("value is not an array")
+			}
+			length := len(r.Array())
+			return evaluateOperator(c.Operator, float64(length), c.ExpectedValue), length, nil
+		}, nil
+
+	case ArrayMatchCondition:
+		path := string(c.ActualJSONPath)
+		element, err := compileNode(c.Expression)
+		if err != nil {
+			return nil, err
+		}
+		return func(document string) (bool, any, error) {
+			r := gjson.Get(document, path)
+			if !r.Exists() {
+				return c.Operator == OperatorAll, nil, nil
+			}
+			if !r.IsArray() {
+				return false, resultValue(r), fmt.Errorf("value is not an array")
+			}
+			elements := r.Array()
+			for _, e := range elements {
+				matched, err := element.evaluate(e.Raw, nil)
+				if err != nil {
+					return false, len(elements), err
+				}
+				if matched == (c.Operator == OperatorAny) {
+					return matched, len(elements), nil
+				}
+			}
+			return c.Operator == OperatorAll, len(elements), nil
+		}, nil
+
+	case ExistsCondition:
+		path := string(c.ActualJSONPath)
+		return func(document string) (bool, any, error) {
+			r := gjson.Get(document, path)
+			return r.Exists(), resultValue(r), nil
+		}, nil
+
+	case PathCondition:
+		actualPath, expectedPath := string(c.ActualJSONPath), string(c.ExpectedJSONPath)
+		return func(document string) (bool, any, error) {
+			actual := gjson.Get(document, actualPath)
+			expected := gjson.Get(document, expectedPath)
+			values := []any{resultValue(actual), resultValue(expected)}
+			switch c.Operator {
+			case OperatorEqual:
+				return equalValues(values[0], values[1]), values, nil
+			case OperatorNotEqual:
+				return !equalValues(values[0], values[1]), values, nil
+			}
+			if actual.Type != gjson.Number || expected.Type != gjson.Number {
+				return false, values, &EvaluationError{
+					Message: "only numbers can be ordered",
+					Context: map[string]any{
+						"actual":   values[0],
+						"expected": values[1],
+					},
+				}
+			}
+			return evaluateOperator(c.Operator, actual.Num, expected.Num), values, nil
+		}, nil
+	}
+	return nil, utils.NewInvalidArgumentError(fmt.Sprintf("unsupported condition type %T", conditionOrExpression))
+}
+
+// resultValue converts a gjson result the same way utils.EvaluateJSONPathQuery
+// does, so that compiled and interpreted conditions see the same values
+func resultValue(r gjson.Result) any {
+	if !r.Exists() {
+		return ""
+	}
+	if r.IsArray() {
+		var results []any
+		for _, element := range r.Array() {
+			results = append(results, element.Value())
+		}
+		return results
+	}
+	return r.Value()
+}
+
+func describe(conditionOrExpression IsBooleanExpressionOrCondition) string {
+	if expression, ok := conditionOrExpression.(BooleanExpression); ok {
+		if description, err := formatExpression(expression, false); err == nil {
+			return description
+		}
+		return string(expression.Operator)
+	}
+	if description, err := formatConditionOrExpression(conditionOrExpression); err == nil {
+		return description
+	}
+	if condition, ok := conditionOrExpression.(Condition); ok {
+		return fmt.Sprintf("%s condition on %s", condition.GetType(), condition.GetActualJSONPath())
+	}
+	return fmt.Sprintf("%T", conditionOrExpression)
+}
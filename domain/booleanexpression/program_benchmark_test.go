@@ -0,0 +1,72 @@
+package booleanexpression_test
+
+import (
+	"testing"
+
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+)
+
+// benchmarkData is shaped like a game snapshot with full hands and decks so
+// that serializing it is a realistic share of the cost
+func benchmarkData() map[string]any {
+	player := func(points int) map[string]any {
+		var hand, deck []any
+		for i := 0; i < 40; i++ {
+			c := map[string]any{"id": i, "type": "number", "value": i%10 + 1, "suite": "hearts"}
+			if i < 5 {
+				hand = append(hand, c)
+			}
+			deck = append(deck, c)
+		}
+		return map[string]any{
+			"points":     points,
+			"rounds_won": 2,
+			"has_drawn":  true,
+			"hand":       hand,
+			"deck":       deck,
+		}
+	}
+	return map[string]any{
+		"trigger":  "reveal",
+		"round":    6,
+		"card":     map[string]any{"id": 12, "type": "face", "value": 11},
+		"self":     player(14),
+		"opponent": player(9),
+	}
+}
+
+const benchmarkExpression = `$.card.type == "face" and ($.round > 3 or $.self.has_drawn) ` +
+	`and $.self.points > $.opponent.points and length($.self.hand) >= 3 ` +
+	`and any($.self.deck, $.value == 10) and not $.opponent.rounds_won between [3, 5]`
+
+func BenchmarkEvaluateBooleanExpression(b *testing.B) {
+	expression, err := booleanexpression.Parse(benchmarkExpression)
+	if err != nil {
+		b.Fatal(err)
+	}
+	data := benchmarkData()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := booleanexpression.EvaluateBooleanExpression(expression, data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkProgramEvaluate(b *testing.B) {
+	expression, err := booleanexpression.Parse(benchmarkExpression)
+	if err != nil {
+		b.Fatal(err)
+	}
+	program, err := booleanexpression.Compile(expression)
+	if err != nil {
+		b.Fatal(err)
+	}
+	data := benchmarkData()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := program.Evaluate(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
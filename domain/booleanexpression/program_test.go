@@ -0,0 +1,169 @@
+package booleanexpression_test
+
+import (
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *BooleanExpressionTestSuite) TestProgram() {
+	data := map[string]any{
+		"name":     "Sir Lancelot",
+		"round":    4,
+		"active":   true,
+		"tags":     []any{"knight", "round table"},
+		"hand":     []any{map[string]any{"value": 3, "suite": "hearts"}, map[string]any{"value": 12, "suite": "spades"}},
+		"values":   []any{1, 5, 10},
+		"empty":    []any{},
+		"self":     map[string]any{"points": 7, "suite": "hearts"},
+		"opponent": map[string]any{"points": 5, "suite": "Hearts"},
+		"nothing":  nil,
+	}
+
+	s.Run("should give the same results as EvaluateBooleanExpression", func() {
+		for _, input := range []string{
+			`$.round == 4 and $.name == "Sir Lancelot"`,
+			`$.round > 10 or $.active`,
+			`$.round > 10 or $.active == false`,
+			`not $.round == 4`,
+			`$.round == 4 xor $.round > 1 xor $.round < 10`,
+			`$.name starts_with "Sir" and $.name ends_with "lot"`,
+			`$.name matches "^Sir [A-Z][a-z]+$"`,
+			`$.self.suite equals_ignore_case "HEARTS"`,
+			`$.missing == ""`,
+			`$.round between [4, 6]`,
+			`$.round in [1, 2]`,
+			`$.tags contains ["knight"]`,
+			`$.tags contains_any ["squire", "round table"]`,
+			`$.tags not_contains ["squire"]`,
+			`$.name contains_all ["Sir Lancelot"]`,
+			`$.missing contains [""]`,
+			`length($.hand) == 2 and length($.missing) == 0`,
+			`exists($.nothing) and not exists($.missing)`,
+			`any($.hand, $.suite == "spades" and $.value > 3)`,
+			`all($.hand, $.value > 10)`,
+			`all($.empty, $.value > 10) and not any($.missing, $.value > 10)`,
+			`any($.values, $ >= 10) and all($.values, $ > 0)`,
+			`$.self.points > $.opponent.points`,
+			`$.self.suite != $.opponent.suite`,
+			`$.hand == $.hand`,
+		} {
+			expression, err := booleanexpression.Parse(input)
+			s.Require().NoError(err, input)
+			program, err := booleanexpression.Compile(expression)
+			s.Require().NoError(err, input)
+
+			expected, err := booleanexpression.EvaluateBooleanExpression(expression, data)
+			s.Require().NoError(err, input)
+			result, err := program.Evaluate(data)
+			s.Require().NoError(err, input)
+			assert.Equal(s.T(), expected, result, input)
+		}
+	})
+
+	s.Run("should fail where EvaluateBooleanExpression fails", func() {
+		for _, input := range []string{
+			`$.name > 3`,
+			`$.missing > 3`,
+			`$.round == "4"`,
+			`$.round == true`,
+			`$.self.suite > $.opponent.suite`,
+			`length($.name) > 1`,
+			`any($.name, $ == "S")`,
+		} {
+			expression, err := booleanexpression.Parse(input)
+			s.Require().NoError(err, input)
+			program, err := booleanexpression.Compile(expression)
+			s.Require().NoError(err, input)
+
+			_, err = booleanexpression.EvaluateBooleanExpression(expression, data)
+			s.Require().Error(err, input)
+			_, err = program.Evaluate(data)
+			assert.Error(s.T(), err, input)
+		}
+	})
+
+	s.Run("should stop once the result is known", func() {
+		expression, err := booleanexpression.Parse(`$.active or $.name > 3`)
+		s.Require().NoError(err)
+		program, err := booleanexpression.Compile(expression)
+		s.Require().NoError(err)
+
+		result, err := program.Evaluate(data)
+		s.Require().NoError(err)
+		assert.True(s.T(), result)
+	})
+
+	s.Run("should evaluate data that is already JSON", func() {
+		expression, err := booleanexpression.Parse(`$.self.points >= 7 and length($.hand) == 1`)
+		s.Require().NoError(err)
+		program, err := booleanexpression.Compile(expression)
+		s.Require().NoError(err)
+
+		result, err := program.EvaluateJSON([]byte(`{"self": {"points": 7}, "hand": [{"value": 2}]}`))
+		s.Require().NoError(err)
+		assert.True(s.T(), result)
+	})
+
+	s.Run("should treat an empty expression as true", func() {
+		program, err := booleanexpression.Compile(booleanexpression.BooleanExpression{})
+		s.Require().NoError(err)
+
+		result, err := program.Evaluate(data)
+		s.Require().NoError(err)
+		assert.True(s.T(), result)
+	})
+
+	s.Run("should reject invalid expressions", func() {
+		_, err := booleanexpression.Compile(booleanexpression.BooleanExpression{
+			Operator: booleanexpression.OperatorAND,
+			Conditions: []booleanexpression.IsBooleanExpressionOrCondition{
+				booleanexpression.StringCondition{
+					ActualJSONPath: "name",
+					Operator:       booleanexpression.OperatorMatches,
+					ExpectedValue:  "(",
+				},
+			},
+		})
+		assert.Error(s.T(), err)
+	})
+
+	s.Run("should trace the conditions it evaluated", func() {
+		expression, err := booleanexpression.Parse(`$.round > 10 or ($.self.points == 7 and $.name starts_with "Sir") or $.active`)
+		s.Require().NoError(err)
+		program, err := booleanexpression.Compile(expression)
+		s.Require().NoError(err)
+
+		result, trace, err := program.Trace(data)
+		s.Require().NoError(err)
+		assert.True(s.T(), result)
+		assert.Equal(s.T(), &booleanexpression.ConditionTrace{
+			Condition: `$.round > 10 or ($.self.points == 7 and $.name starts_with "Sir") or $.active == true`,
+			Result:    true,
+			Children: []*booleanexpression.ConditionTrace{
+				{Condition: `$.round > 10`, Actual: 4.0, Result: false},
+				{
+					Condition: `$.self.points == 7 and $.name starts_with "Sir"`,
+					Result:    true,
+					Children: []*booleanexpression.ConditionTrace{
+						{Condition: `$.self.points == 7`, Actual: 7.0, Result: true},
+						{Condition: `$.name starts_with "Sir"`, Actual: "Sir Lancelot", Result: true},
+					},
+				},
+			},
+		}, trace)
+	})
+
+	s.Run("should trace the condition that failed", func() {
+		expression, err := booleanexpression.Parse(`$.active and $.name > 3`)
+		s.Require().NoError(err)
+		program, err := booleanexpression.Compile(expression)
+		s.Require().NoError(err)
+
+		_, trace, err := program.Trace(data)
+		s.Require().Error(err)
+		s.Require().Len(trace.Children, 2)
+		assert.Equal(s.T(), "Sir Lancelot", trace.Children[1].Actual)
+		assert.Equal(s.T(), "value is not a number", trace.Children[1].Error)
+		assert.NotEmpty(s.T(), trace.Error)
+	})
+}
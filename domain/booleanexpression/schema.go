@@ -0,0 +1,242 @@
+package booleanexpression
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coopersmall/subswag/utils"
+)
+
+// SchemaType is the kind of JSON value a schema describes
+type SchemaType string
+
+const (
+	SchemaTypeNumber  SchemaType = "number"
+	SchemaTypeString  SchemaType = "string"
+	SchemaTypeBoolean SchemaType = "boolean"
+	SchemaTypeArray   SchemaType = "array"
+	SchemaTypeObject  SchemaType = "object"
+	SchemaTypeAny     SchemaType = "any" // Anything, paths into it are not checked
+)
+
+func (t SchemaType) withArticle() string {
+	switch t {
+	case SchemaTypeArray, SchemaTypeObject, SchemaTypeAny:
+		return "an " + string(t)
+	}
+	return "a " + string(t)
+}
+
+// Schema describes the data an expression is evaluated against, so that
+// Lint can check the expression's paths and values without any data
+type Schema struct {
+	Type       SchemaType
+	Properties map[string]*Schema // Fields of an object. An object without properties is a map and may have any field.
+	Items      *Schema            // Elements of an array
+	Enum       []string           // Every value a string can have, when only some are possible
+	Minimum    *float64           // Bounds of a number
+	Maximum    *float64
+}
+
+// SchemaOf describes the JSON encoding of v's type, following json struct
+// tags. Types with their own JSON encoding are described as any.
+func SchemaOf(v any) *Schema {
+	return schemaOfType(reflect.TypeOf(v), map[reflect.Type]bool{})
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	timeType          = reflect.TypeFor[time.Time]()
+)
+
+func schemaOfType(t reflect.Type, seen map[reflect.Type]bool) *Schema {
+	if t == nil {
+		return &Schema{Type: SchemaTypeAny}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return &Schema{Type: SchemaTypeString}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return &Schema{Type: SchemaTypeAny}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &Schema{Type: SchemaTypeString}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: SchemaTypeBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return &Schema{Type: SchemaTypeNumber}
+	case reflect.String:
+		return &Schema{Type: SchemaTypeString}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: SchemaTypeString} // Encoded as base64
+		}
+		return &Schema{Type: SchemaTypeArray, Items: schemaOfType(t.Elem(), seen)}
+	case reflect.Map:
+		return &Schema{Type: SchemaTypeObject}
+	case reflect.Struct:
+		if seen[t] {
+			return &Schema{Type: SchemaTypeAny}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		schema := &Schema{Type: SchemaTypeObject, Properties: map[string]*Schema{}}
+		addStructFields(schema, t, seen)
+		return schema
+	}
+	return &Schema{Type: SchemaTypeAny}
+}
+
+func addStructFields(schema *Schema, t reflect.Type, seen map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			addStructFields(schema, fieldType, seen)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = schemaOfType(field.Type, seen)
+	}
+}
+
+// Property returns the schema of an object's field. It panics if the field
+// does not exist, and is meant for decorating schemas made by SchemaOf.
+func (s *Schema) Property(path ...string) *Schema {
+	property := s
+	for _, name := range path {
+		next, ok := property.Properties[name]
+		if !ok {
+			panic(fmt.Sprintf("schema has no property %q", name))
+		}
+		property = next
+	}
+	return property
+}
+
+// WithEnum limits a string to the given values
+func (s *Schema) WithEnum(values ...string) *Schema {
+	s.Enum = values
+	return s
+}
+
+// WithRange limits a number to the bounds, inclusive
+func (s *Schema) WithRange(minimum, maximum float64) *Schema {
+	s.Minimum, s.Maximum = &minimum, &maximum
+	return s
+}
+
+// WithMinimum limits a number to at least minimum
+func (s *Schema) WithMinimum(minimum float64) *Schema {
+	s.Minimum = &minimum
+	return s
+}
+
+// Lookup returns the schema of the value a JSON path reads. Paths use the
+// gjson syntax that conditions use: dotted fields, array indexes, "#" for an
+// array's length or all of its elements, and "@this".
+func (s *Schema) Lookup(path utils.JSONPathQuery) (*Schema, error) {
+	if path == ThisJSONPath {
+		return s, nil
+	}
+	current := s
+	keys := splitPath(string(path))
+	for i, key := range keys {
+		switch {
+		case current.Type == SchemaTypeAny:
+			return current, nil
+		case key == "@this":
+		case strings.ContainsAny(key, "*?|@()="):
+			return &Schema{Type: SchemaTypeAny}, nil // Queries and modifiers are not checked
+		case current.Type == SchemaTypeArray && key == "#":
+			if i == len(keys)-1 {
+				return (&Schema{Type: SchemaTypeNumber}).WithMinimum(0), nil
+			}
+			items, err := current.Items.Lookup(utils.JSONPathQuery(strings.Join(keys[i+1:], ".")))
+			if err != nil {
+				return nil, err
+			}
+			return &Schema{Type: SchemaTypeArray, Items: items}, nil
+		case current.Type == SchemaTypeArray:
+			if _, err := strconv.Atoi(key); err != nil {
+				return nil, fmt.Errorf("%s is an array and has no field %q", describePath(keys[:i]), key)
+			}
+			current = current.Items
+		case current.Type == SchemaTypeObject && current.Properties == nil:
+			return &Schema{Type: SchemaTypeAny}, nil
+		case current.Type == SchemaTypeObject:
+			next, ok := current.Properties[key]
+			if !ok {
+				return nil, fmt.Errorf("%s has no field %q, expected one of %s", describePath(keys[:i]), key, strings.Join(current.propertyNames(), ", "))
+			}
+			current = next
+		default:
+			return nil, fmt.Errorf("%s is %s and has no field %q", describePath(keys[:i]), current.Type.withArticle(), key)
+		}
+	}
+	return current, nil
+}
+
+func (s *Schema) propertyNames() []string {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// splitPath splits a gjson path on the dots that are not escaped
+func splitPath(path string) []string {
+	var (
+		keys []string
+		key  strings.Builder
+	)
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == '\\' && i+1 < len(path):
+			i++
+			key.WriteByte(path[i])
+		case path[i] == '.':
+			keys = append(keys, key.String())
+			key.Reset()
+		default:
+			key.WriteByte(path[i])
+		}
+	}
+	return append(keys, key.String())
+}
+
+func describePath(keys []string) string {
+	if len(keys) == 0 {
+		return "the data"
+	}
+	return formatPath(utils.JSONPathQuery(strings.Join(keys, ".")))
+}
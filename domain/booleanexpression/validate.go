@@ -0,0 +1,146 @@
+package booleanexpression
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/coopersmall/subswag/utils"
+)
+
+var (
+	comparisonOperators = []Operator{
+		OperatorEqual,
+		OperatorNotEqual,
+		OperatorGreaterThan,
+		OperatorGreaterEqual,
+		OperatorLessThan,
+		OperatorLessEqual,
+	}
+	numericOperators  = append(slices.Clone(comparisonOperators), OperatorBetween, OperatorIn)
+	equalityOperators = []Operator{OperatorEqual, OperatorNotEqual}
+	stringOperators   = append(slices.Clone(equalityOperators),
+		OperatorStartsWith,
+		OperatorEndsWith,
+		OperatorStringContains,
+		OperatorMatches,
+		OperatorEqualIgnoreCase,
+	)
+	arrayOperators = []ArryConditionOperator{
+		OperatorContains,
+		OperatorContainsAll,
+		OperatorContainsAny,
+		OperatorNotContains,
+	}
+)
+
+// Validate checks that an expression can be evaluated: every operator is
+// known and allowed for its condition type, every condition has a JSON path
+// and nested expressions are valid too. An expression with no operator and no
+// conditions is valid and always true.
+func Validate(expression BooleanExpression) error {
+	if expression.Operator == "" && len(expression.Conditions) == 0 {
+		return nil
+	}
+	return validateExpression(expression, "condition")
+}
+
+func validateExpression(expression BooleanExpression, path string) error {
+	switch expression.Operator {
+	case OperatorAND, OperatorOR, OperatorXOR:
+	case OperatorNOT:
+		if len(expression.Conditions) != 1 {
+			return utils.NewInvalidArgumentError(fmt.Sprintf("%s: NOT takes exactly one condition", path))
+		}
+	default:
+		return utils.NewInvalidArgumentError(fmt.Sprintf("%s: unsupported operator %q", path, expression.Operator))
+	}
+	if len(expression.Conditions) == 0 {
+		return utils.NewInvalidArgumentError(fmt.Sprintf("%s: %s has no conditions", path, expression.Operator))
+	}
+	for i, condition := range expression.Conditions {
+		if err := validateConditionOrExpression(condition, fmt.Sprintf("%s.conditions[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateConditionOrExpression(conditionOrExpression IsBooleanExpressionOrCondition, path string) error {
+	switch c := conditionOrExpression.(type) {
+	case BooleanExpression:
+		return validateExpression(c, path)
+	case NumericCondition:
+		if err := validateCondition(c, ConditionTypeNumeric, slices.Contains(numericOperators, c.Operator), c.Operator, path); err != nil {
+			return err
+		}
+		return validateExpectedValues(c, path)
+	case StringCondition:
+		if err := validateCondition(c, ConditionTypeString, slices.Contains(stringOperators, c.Operator), c.Operator, path); err != nil {
+			return err
+		}
+		if c.Operator == OperatorMatches {
+			if _, err := regexp.Compile(c.ExpectedValue); err != nil {
+				return utils.NewInvalidArgumentError(fmt.Sprintf("%s: invalid regular expression %q", path, c.ExpectedValue))
+			}
+		}
+		return nil
+	case BooleanCondition:
+		return validateCondition(c, ConditionTypeBoolean, slices.Contains(equalityOperators, c.Operator), c.Operator, path)
+	case ArrayCondition:
+		return validateCondition(c, ConditionTypeArray, slices.Contains(arrayOperators, c.Operator), c.Operator, path)
+	case ArrayLengthCondition:
+		return validateCondition(c, ConditionTypeArrayLength, slices.Contains(comparisonOperators, c.Operator), c.Operator, path)
+	case ArrayMatchCondition:
+		operatorAllowed := c.Operator == OperatorAny || c.Operator == OperatorAll
+		if err := validateCondition(c, ConditionTypeArrayMatch, operatorAllowed, c.Operator, path); err != nil {
+			return err
+		}
+		return validateExpression(c.Expression, path+".expression")
+	case ExistsCondition:
+		return validateCondition(c, ConditionTypeExists, true, "", path)
+	case PathCondition:
+		if err := validateCondition(c, ConditionTypePath, slices.Contains(comparisonOperators, c.Operator), c.Operator, path); err != nil {
+			return err
+		}
+		if c.ExpectedJSONPath == "" {
+			return utils.NewInvalidArgumentError(fmt.Sprintf("%s: missing expected JSON path", path))
+		}
+		return nil
+	default:
+		return utils.NewInvalidArgumentError(fmt.Sprintf("%s: unsupported condition type %T", path, c))
+	}
+}
+
+func validateCondition[O ~string](
+	condition Condition,
+	conditionType ConditionType,
+	operatorAllowed bool,
+	operator O,
+	path string,
+) error {
+	if condition.GetType() != conditionType {
+		return utils.NewInvalidArgumentError(fmt.Sprintf("%s: type %q does not match a %s condition", path, condition.GetType(), conditionType))
+	}
+	if condition.GetActualJSONPath() == "" {
+		return utils.NewInvalidArgumentError(fmt.Sprintf("%s: missing JSON path", path))
+	}
+	if !operatorAllowed {
+		return utils.NewInvalidArgumentError(fmt.Sprintf("%s: operator %q cannot be used in a %s condition", path, operator, conditionType))
+	}
+	return nil
+}
+
+func validateExpectedValues(condition NumericCondition, path string) error {
+	switch condition.Operator {
+	case OperatorBetween:
+		if len(condition.ExpectedValues) != 2 || condition.ExpectedValues[0] > condition.ExpectedValues[1] {
+			return utils.NewInvalidArgumentError(fmt.Sprintf("%s: between needs a lower and an upper bound", path))
+		}
+	case OperatorIn:
+		if len(condition.ExpectedValues) == 0 {
+			return utils.NewInvalidArgumentError(fmt.Sprintf("%s: in needs at least one value", path))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package booleanexpression_test
+
+import (
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *BooleanExpressionTestSuite) TestValidate() {
+	s.Run("should accept an empty expression", func() {
+		assert.NoError(s.T(), booleanexpression.Validate(booleanexpression.BooleanExpression{}))
+	})
+
+	s.Run("should accept nested expressions with known operators", func() {
+		expression := booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+			booleanexpression.NewNumericCondition("self.points", booleanexpression.OperatorLessThan, 5),
+			booleanexpression.NewBooleanExpression(booleanexpression.OperatorOR,
+				booleanexpression.NewStringCondition("trigger", booleanexpression.OperatorEqual, "war"),
+				booleanexpression.NewArrayCondition("self.hand", booleanexpression.OperatorContains, []any{1.0}),
+			),
+		)
+		assert.NoError(s.T(), booleanexpression.Validate(expression))
+	})
+
+	s.Run("should accept every operator and condition kind", func() {
+		expression, err := booleanexpression.Parse(`not $.a xor $.b starts_with "x" or $.c between [1, 2] or $.c in [3] or length($.d) > 1 or exists($.e) or any($.f, $ > 1) or $.g <= $.h`)
+		s.Require().NoError(err)
+		assert.NoError(s.T(), booleanexpression.Validate(expression))
+	})
+
+	s.Run("should reject malformed expressions", func() {
+		for name, expression := range map[string]booleanexpression.BooleanExpression{
+			"unknown operator": booleanexpression.NewBooleanExpression("NAND",
+				booleanexpression.NewNumericCondition("self.points", booleanexpression.OperatorLessThan, 5),
+			),
+			"no conditions": booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND),
+			"missing path": booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+				booleanexpression.NewNumericCondition("", booleanexpression.OperatorLessThan, 5),
+			),
+			"ordering a string": booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+				booleanexpression.NewStringCondition("trigger", booleanexpression.OperatorGreaterThan, "war"),
+			),
+			"mismatched type": booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+				booleanexpression.NumericCondition{
+					Type:           booleanexpression.ConditionTypeString,
+					ActualJSONPath: "self.points",
+					Operator:       booleanexpression.OperatorEqual,
+				},
+			),
+			"invalid nested expression": booleanexpression.NewBooleanExpression(booleanexpression.OperatorOR,
+				booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND),
+			),
+			"NOT with two conditions": booleanexpression.NewBooleanExpression(booleanexpression.OperatorNOT,
+				booleanexpression.NewExistsCondition("a"),
+				booleanexpression.NewExistsCondition("b"),
+			),
+			"invalid regular expression": booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+				booleanexpression.NewStringCondition("name", booleanexpression.OperatorMatches, "(unclosed"),
+			),
+			"reversed bounds": booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+				booleanexpression.NewBetweenCondition("round", 5, 2),
+			),
+			"empty in": booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+				booleanexpression.NewInCondition("round"),
+			),
+			"invalid element expression": booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+				booleanexpression.NewArrayMatchCondition("hand", booleanexpression.OperatorAny, booleanexpression.BooleanExpression{}),
+			),
+			"missing expected path": booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+				booleanexpression.NewPathCondition("self.points", booleanexpression.OperatorGreaterThan, ""),
+			),
+		} {
+			assert.Error(s.T(), booleanexpression.Validate(expression), name)
+		}
+	})
+}
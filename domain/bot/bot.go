@@ -0,0 +1,180 @@
+package bot
+
+import (
+	"math/rand"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+)
+
+// Bot chooses moves for one seat of a game. It only acts on what its View
+// shows, and card effects are not taken into account.
+type Bot struct {
+	difficulty Difficulty
+	values     map[card.SerializableCardID]int
+	rand       *rand.Rand
+}
+
+// NewBot creates a bot that values cards from values. r drives every random
+// choice, so a seeded r makes the bot's play repeatable.
+func NewBot(
+	difficulty Difficulty,
+	values map[card.SerializableCardID]int,
+	r *rand.Rand,
+) *Bot {
+	return &Bot{
+		difficulty: difficulty,
+		values:     values,
+		rand:       r,
+	}
+}
+
+// NextCommand returns the bot's move for the current phase, or false if it
+// has nothing left to do this phase
+func (b *Bot) NextCommand(data game.GameStateData, playerIndex int) (game.Command, bool) {
+	if data.IsComplete {
+		return nil, false
+	}
+	player := data.Players[playerIndex]
+	view := NewView(data, playerIndex)
+	switch data.GamePhase {
+	case game.PhaseCardAction:
+		if player.HasActed() {
+			return nil, false
+		}
+		if cmd, ok := b.chooseAction(view); ok {
+			return cmd, true
+		}
+		// The phase only ends once both players have acted
+		return game.PassCommand{Type: game.CommandTypePass}, true
+	case game.PhaseReveal:
+		if player.SelectedCard != nil || len(view.Own) == 0 {
+			return nil, false
+		}
+		return game.SelectRevealCommand{
+			Type:     game.CommandTypeSelectReveal,
+			Position: b.chooseReveal(view),
+		}, true
+	}
+	return nil, false
+}
+
+func (b *Bot) chooseAction(view View) (game.Command, bool) {
+	switch b.difficulty {
+	case DifficultyEasy:
+		return b.randomAction(view)
+	case DifficultyHard:
+		return b.searchAction(view)
+	default:
+		return b.greedyAction(view)
+	}
+}
+
+func (b *Bot) chooseReveal(view View) game.Position {
+	switch b.difficulty {
+	case DifficultyEasy:
+		return view.Own[b.rand.Intn(len(view.Own))].Position
+	case DifficultyHard:
+		return b.searchReveal(view)
+	default:
+		return b.greedyReveal(view)
+	}
+}
+
+// randomAction picks any legal action, or no action at all
+func (b *Bot) randomAction(view View) (game.Command, bool) {
+	actions := []game.Command{nil}
+	if view.CanDraw {
+		actions = append(actions, drawCommand())
+	}
+	for _, cardId := range view.Hand {
+		actions = append(actions, game.DiscardCommand{Type: game.CommandTypeDiscard, CardID: cardId})
+		for _, space := range view.Own {
+			actions = append(actions, swapCommand(cardId, space.Position))
+		}
+	}
+	action := actions[b.rand.Intn(len(actions))]
+	return action, action != nil
+}
+
+// greedyAction swaps the best card in hand for the weakest face-down card if
+// that is an upgrade, and otherwise draws
+func (b *Bot) greedyAction(view View) (game.Command, bool) {
+	if len(view.Hand) > 0 && len(view.Own) > 0 {
+		best := view.Hand[0]
+		for _, cardId := range view.Hand[1:] {
+			if b.values[cardId] > b.values[best] {
+				best = cardId
+			}
+		}
+		weakest := 0
+		for i := range view.Own {
+			if b.estimate(view.Own[i], view.OwnPool) < b.estimate(view.Own[weakest], view.OwnPool) {
+				weakest = i
+			}
+		}
+		if float64(b.values[best]) > b.estimate(view.Own[weakest], view.OwnPool) {
+			return swapCommand(best, view.Own[weakest].Position), true
+		}
+	}
+	if view.CanDraw {
+		return drawCommand(), true
+	}
+	return nil, false
+}
+
+// greedyReveal plays the weakest card expected to beat the opponent's
+// average card, saving stronger cards for later rounds. If nothing is
+// expected to win the weakest card is thrown away.
+func (b *Bot) greedyReveal(view View) game.Position {
+	var target float64
+	for _, space := range view.Opponent {
+		target += b.estimate(space, view.OppPool)
+	}
+	if len(view.Opponent) > 0 {
+		target /= float64(len(view.Opponent))
+	}
+
+	weakest, winner := -1, -1
+	for i, space := range view.Own {
+		estimate := b.estimate(space, view.OwnPool)
+		if weakest == -1 || estimate < b.estimate(view.Own[weakest], view.OwnPool) {
+			weakest = i
+		}
+		if estimate > target && (winner == -1 || estimate < b.estimate(view.Own[winner], view.OwnPool)) {
+			winner = i
+		}
+	}
+	if winner != -1 {
+		return view.Own[winner].Position
+	}
+	return view.Own[weakest].Position
+}
+
+// estimate is the value of a face-down card, or the average value of the
+// cards it could be if it has not been seen
+func (b *Bot) estimate(space Space, pool []card.SerializableCardID) float64 {
+	if space.IsKnown() {
+		return float64(b.values[space.Card])
+	}
+	if len(pool) == 0 {
+		return 0
+	}
+	var total int
+	for _, cardId := range pool {
+		total += b.values[cardId]
+	}
+	return float64(total) / float64(len(pool))
+}
+
+func drawCommand() game.Command {
+	return game.DrawCommand{Type: game.CommandTypeDraw}
+}
+
+func swapCommand(cardId card.SerializableCardID, position game.Position) game.Command {
+	return game.SwapCommand{
+		Type:     game.CommandTypeSwap,
+		CardID:   cardId,
+		Position: position,
+	}
+}
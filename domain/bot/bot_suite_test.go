@@ -0,0 +1,15 @@
+package bot_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type BotTestSuite struct {
+	suite.Suite
+}
+
+func TestBotSuite(t *testing.T) {
+	suite.Run(t, new(BotTestSuite))
+}
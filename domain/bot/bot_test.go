@@ -0,0 +1,144 @@
+package bot_test
+
+import (
+	"math/rand"
+
+	"github.com/coopersmall/subswag/domain/bot"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Card IDs double as their values
+func cardValues() map[card.SerializableCardID]int {
+	values := make(map[card.SerializableCardID]int)
+	for i := 1; i <= 20; i++ {
+		values[card.SerializableCardID(i)] = i
+	}
+	return values
+}
+
+func newData(phase game.GamePhase) game.GameStateData {
+	data := game.GameStateData{
+		GamePhase:   phase,
+		RoundNumber: 0,
+		Rules:       game.Rules{RoundLimit: 2, HandLimit: 5, DrawLimit: 1},
+		BoardState:  game.NewBoardState(4),
+		Players: [2]game.PlayerState{
+			{User: 1, Hand: []card.SerializableCardID{9}, Deck: []card.SerializableCardID{1, 2}, RevealedCards: map[game.Position]bool{}},
+			{User: 2, Hand: []card.SerializableCardID{3}, Deck: []card.SerializableCardID{4}, RevealedCards: map[game.Position]bool{}},
+		},
+	}
+	data.Board[0][0] = game.BoardSpace{Card: 12, Owner: 1}
+	data.Board[0][1] = game.BoardSpace{Card: 5, Owner: 1}
+	data.Board[1][0] = game.BoardSpace{Card: 8, Owner: 2}
+	data.Board[1][1] = game.BoardSpace{Card: 6, Owner: 2}
+	for _, cardId := range []card.SerializableCardID{12, 5, 8, 6} {
+		data.Players[0].See(cardId)
+	}
+	return data
+}
+
+func newBot(difficulty bot.Difficulty) *bot.Bot {
+	return bot.NewBot(difficulty, cardValues(), rand.New(rand.NewSource(1)))
+}
+
+func (s *BotTestSuite) TestNextCommand() {
+	difficulties := []bot.Difficulty{bot.DifficultyEasy, bot.DifficultyMedium, bot.DifficultyHard}
+
+	s.Run("should only make legal moves", func() {
+		for _, difficulty := range difficulties {
+			for _, phase := range []game.GamePhase{game.PhaseCardAction, game.PhaseReveal} {
+				data := newData(phase)
+				b := newBot(difficulty)
+				for range 20 {
+					cmd, ok := b.NextCommand(data, 0)
+					if !ok {
+						continue
+					}
+					assert.Nil(s.T(), game.ValidateCommand(data, 0, cmd), "%s bot in %s", difficulty, phase)
+				}
+			}
+		}
+	})
+
+	s.Run("should not act twice in a phase", func() {
+		data := newData(game.PhaseCardAction)
+		data.Players[0].HasDrawnThisTurn = true
+		_, ok := newBot(bot.DifficultyMedium).NextCommand(data, 0)
+		assert.False(s.T(), ok)
+
+		data = newData(game.PhaseReveal)
+		data.Players[0].SelectedCard = &game.Position{X: 0, Y: 0}
+		_, ok = newBot(bot.DifficultyMedium).NextCommand(data, 0)
+		assert.False(s.T(), ok)
+	})
+
+	s.Run("should not act outside the player phases", func() {
+		_, ok := newBot(bot.DifficultyHard).NextCommand(newData(game.PhaseWar), 0)
+		assert.False(s.T(), ok)
+	})
+
+	s.Run("should swap a better card onto the board", func() {
+		data := newData(game.PhaseCardAction)
+		// Without the swap both of the bot's possible pairings are drawn
+		data.Board[0][1].Card = 2
+		for _, difficulty := range []bot.Difficulty{bot.DifficultyMedium, bot.DifficultyHard} {
+			cmd, ok := newBot(difficulty).NextCommand(data, 0)
+			require.True(s.T(), ok)
+			assert.Equal(s.T(), game.SwapCommand{
+				Type:     game.CommandTypeSwap,
+				CardID:   9,
+				Position: game.Position{X: 0, Y: 1},
+			}, cmd, difficulty)
+		}
+	})
+
+	s.Run("should draw when no swap helps", func() {
+		data := newData(game.PhaseCardAction)
+		data.Players[0].Hand = []card.SerializableCardID{1}
+		cmd, ok := newBot(bot.DifficultyMedium).NextCommand(data, 0)
+		require.True(s.T(), ok)
+		assert.Equal(s.T(), game.CommandTypeDraw, cmd.GetType())
+	})
+
+	s.Run("should pass when it has no action to take", func() {
+		data := newData(game.PhaseCardAction)
+		data.Players[0].Hand = nil
+		data.Players[0].Deck = nil
+		cmd, ok := newBot(bot.DifficultyMedium).NextCommand(data, 0)
+		require.True(s.T(), ok)
+		assert.Equal(s.T(), game.CommandTypePass, cmd.GetType())
+	})
+
+	s.Run("should save its best card when a weaker one wins", func() {
+		cmd, ok := newBot(bot.DifficultyMedium).NextCommand(newData(game.PhaseReveal), 0)
+		require.True(s.T(), ok)
+		// 12 beats the opponent's average of 7, 5 does not
+		assert.Equal(s.T(), game.Position{X: 0, Y: 0}, cmd.(game.SelectRevealCommand).Position)
+	})
+
+	s.Run("should find the winning reveal", func() {
+		data := newData(game.PhaseReveal)
+		data.Rules.RoundLimit = 1
+		data.Board[1][1] = game.BoardSpace{}
+		cmd, ok := newBot(bot.DifficultyHard).NextCommand(data, 0)
+		require.True(s.T(), ok)
+		// Only 12 beats the opponent's last card
+		assert.Equal(s.T(), game.Position{X: 0, Y: 0}, cmd.(game.SelectRevealCommand).Position)
+	})
+}
+
+func (s *BotTestSuite) TestDifficulty() {
+	s.Run("should map each difficulty to its own bot user", func() {
+		for _, difficulty := range []bot.Difficulty{bot.DifficultyEasy, bot.DifficultyMedium, bot.DifficultyHard} {
+			found, ok := bot.DifficultyOf(difficulty.UserID())
+			require.True(s.T(), ok)
+			assert.Equal(s.T(), difficulty, found)
+			assert.Equal(s.T(), difficulty.UserID(), bot.NewBotUser(difficulty).ID)
+		}
+		assert.False(s.T(), bot.IsBot(1000000000))
+		assert.False(s.T(), bot.Difficulty("impossible").IsValid())
+	})
+}
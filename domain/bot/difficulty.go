@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"slices"
+
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/user"
+)
+
+type Difficulty string
+
+const (
+	DifficultyEasy   Difficulty = "easy"   // Plays at random
+	DifficultyMedium Difficulty = "medium" // Greedy play from what it has seen
+	DifficultyHard   Difficulty = "hard"   // Monte-Carlo search over the hidden cards
+)
+
+var difficulties = []Difficulty{
+	DifficultyEasy,
+	DifficultyMedium,
+	DifficultyHard,
+}
+
+func (d Difficulty) IsValid() bool {
+	return slices.Contains(difficulties, d)
+}
+
+// UserID is the account the bot plays from. Each difficulty has its own
+// reserved ID, well below any generated ID.
+func (d Difficulty) UserID() user.UserID {
+	return user.UserID(slices.Index(difficulties, d) + 1)
+}
+
+// DifficultyOf returns the difficulty of the bot playing as userId, or false
+// if the user is not a bot
+func DifficultyOf(userId user.UserID) (Difficulty, bool) {
+	for _, d := range difficulties {
+		if d.UserID() == userId {
+			return d, true
+		}
+	}
+	return "", false
+}
+
+func IsBot(userId user.UserID) bool {
+	_, ok := DifficultyOf(userId)
+	return ok
+}
+
+// NewBotUser is the account a bot is created with the first time it plays
+func NewBotUser(d Difficulty) *user.User {
+	return &user.User{
+		ID: d.UserID(),
+		UserData: user.UserData{
+			FirstName: "Bot",
+			LastName:  string(d),
+		},
+		Metadata: domain.NewMetadata(),
+	}
+}
@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"slices"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+)
+
+// searchSamples is how many times the hidden cards are dealt out when
+// searching for a move
+const searchSamples = 200
+
+// world is one guess at the hidden cards: the value of every face-down card,
+// in the same order as the View's spaces
+type world struct {
+	own      []int
+	opponent []int
+}
+
+// searchReveal plays out the rest of the game from every card the bot could
+// reveal and picks the one that wins most often
+func (b *Bot) searchReveal(view View) game.Position {
+	scores := make([]float64, len(view.Own))
+	for range searchSamples {
+		w := b.deal(view)
+		for i := range view.Own {
+			scores[i] += b.playOut(view, w, i)
+		}
+	}
+	return view.Own[best(scores)].Position
+}
+
+// searchAction compares every swap against leaving the board as it is. A
+// swap is only made if it wins more often, otherwise the bot draws.
+func (b *Bot) searchAction(view View) (game.Command, bool) {
+	type swap struct {
+		cardId card.SerializableCardID
+		space  int
+	}
+	swaps := make([]swap, 0, len(view.Hand)*len(view.Own))
+	for _, cardId := range view.Hand {
+		for i := range view.Own {
+			swaps = append(swaps, swap{cardId: cardId, space: i})
+		}
+	}
+
+	var baseline float64
+	scores := make([]float64, len(swaps))
+	for range searchSamples {
+		w := b.deal(view)
+		baseline += b.playOut(view, w, -1)
+		for i, s := range swaps {
+			swapped := world{own: slices.Clone(w.own), opponent: w.opponent}
+			swapped.own[s.space] = b.values[s.cardId]
+			scores[i] += b.playOut(view, swapped, -1)
+		}
+	}
+	if len(swaps) > 0 {
+		if i := best(scores); scores[i] > baseline {
+			return swapCommand(swaps[i].cardId, view.Own[swaps[i].space].Position), true
+		}
+	}
+	if view.CanDraw {
+		return drawCommand(), true
+	}
+	return nil, false
+}
+
+// deal guesses the hidden cards by dealing them at random from the cards
+// they could be
+func (b *Bot) deal(view View) world {
+	return world{
+		own:      b.dealSpaces(view.Own, view.OwnPool),
+		opponent: b.dealSpaces(view.Opponent, view.OppPool),
+	}
+}
+
+func (b *Bot) dealSpaces(spaces []Space, pool []card.SerializableCardID) []int {
+	shuffled := slices.Clone(pool)
+	b.rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	values := make([]int, len(spaces))
+	for i, space := range spaces {
+		cardId := space.Card
+		if !space.IsKnown() && len(shuffled) > 0 {
+			cardId, shuffled = shuffled[0], shuffled[1:]
+		}
+		values[i] = b.values[cardId]
+	}
+	return values
+}
+
+// playOut plays the rest of the game with both players revealing cards at
+// random, except for the bot's first reveal when first is not -1. It returns
+// 1 for a win, 0.5 for a draw and 0 for a loss.
+func (b *Bot) playOut(view View, w world, first int) float64 {
+	own, opponent := slices.Clone(w.own), slices.Clone(w.opponent)
+	roundsWon, points := view.RoundsWon, view.Points
+	for round := 0; round < view.RoundsLeft && len(own) > 0 && len(opponent) > 0; round++ {
+		pick := b.rand.Intn(len(own))
+		if round == 0 && first != -1 {
+			pick = first
+		}
+		theirs := b.rand.Intn(len(opponent))
+		winner, won := game.ResolveWar([2]int{own[pick], opponent[theirs]})
+		if winner != game.NoWinner {
+			roundsWon[winner]++
+			points[winner] += won
+		}
+		own = slices.Delete(own, pick, pick+1)
+		opponent = slices.Delete(opponent, theirs, theirs+1)
+	}
+
+	data := game.GameStateData{}
+	for i := range data.Players {
+		data.Players[i].RoundsWon = roundsWon[i]
+		data.Players[i].Points = points[i]
+	}
+	switch data.Leader() {
+	case 0:
+		return 1
+	case 1:
+		return 0
+	default:
+		return 0.5
+	}
+}
+
+// best returns the index of the highest score
+func best(scores []float64) int {
+	index := 0
+	for i, score := range scores {
+		if score > scores[index] {
+			index = i
+		}
+	}
+	return index
+}
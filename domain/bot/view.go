@@ -0,0 +1,75 @@
+package bot
+
+import (
+	"slices"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+)
+
+// View is what one player knows about a game. Face-down cards they have not
+// seen are left out; only the pool of cards they could be is known. A
+// player's deck list is treated as public, but not where its cards are.
+type View struct {
+	Hand       []card.SerializableCardID
+	CanDraw    bool
+	Own        []Space // The player's face-down cards
+	Opponent   []Space // The opponent's face-down cards
+	OwnPool    []card.SerializableCardID
+	OppPool    []card.SerializableCardID
+	RoundsLeft int
+	RoundsWon  [2]int // The player's first, then the opponent's
+	Points     [2]int
+}
+
+// Space is a face-down card on the board. Card is 0 if the viewer has not
+// seen it.
+type Space struct {
+	Position game.Position
+	Card     card.SerializableCardID
+}
+
+func (s Space) IsKnown() bool {
+	return s.Card != 0
+}
+
+// NewView projects the game onto what the player at playerIndex can see
+func NewView(data game.GameStateData, playerIndex int) View {
+	player := data.Players[playerIndex]
+	opponent := data.Players[1-playerIndex]
+	view := View{
+		Hand:       slices.Clone(player.Hand),
+		CanDraw:    len(player.Hand) < data.HandLimit && len(player.Deck) > 0,
+		OwnPool:    slices.Clone(player.Deck),
+		OppPool:    append(slices.Clone(opponent.Deck), opponent.Hand...),
+		RoundsLeft: max(data.RoundLimit-data.RoundNumber, 0),
+		RoundsWon:  [2]int{player.RoundsWon, opponent.RoundsWon},
+		Points:     [2]int{player.Points, opponent.Points},
+	}
+	for x, column := range data.Board {
+		for y, space := range column {
+			if space.Card == 0 || space.Revealed {
+				continue
+			}
+			position := game.Position{X: x, Y: y}
+			seen := data.HasSeen(playerIndex, position)
+			known := Space{Position: position}
+			if seen {
+				known.Card = space.Card
+			}
+			switch space.Owner {
+			case player.User:
+				view.Own = append(view.Own, known)
+				if !seen {
+					view.OwnPool = append(view.OwnPool, space.Card)
+				}
+			case opponent.User:
+				view.Opponent = append(view.Opponent, known)
+				if !seen {
+					view.OppPool = append(view.OppPool, space.Card)
+				}
+			}
+		}
+	}
+	return view
+}
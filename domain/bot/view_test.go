@@ -0,0 +1,38 @@
+package bot_test
+
+import (
+	"github.com/coopersmall/subswag/domain/bot"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *BotTestSuite) TestNewView() {
+	s.Run("should hide cards the player has not seen", func() {
+		data := newData(game.PhaseReveal)
+		data.Players[0].SeenCards = map[card.SerializableCardID]bool{12: true, 8: true}
+
+		view := bot.NewView(data, 0)
+		require.Len(s.T(), view.Own, 2)
+		assert.Equal(s.T(), bot.Space{Position: game.Position{X: 0, Y: 0}, Card: 12}, view.Own[0])
+		assert.False(s.T(), view.Own[1].IsKnown())
+		assert.ElementsMatch(s.T(), []card.SerializableCardID{1, 2, 5}, view.OwnPool)
+
+		require.Len(s.T(), view.Opponent, 2)
+		assert.Equal(s.T(), card.SerializableCardID(8), view.Opponent[0].Card)
+		assert.False(s.T(), view.Opponent[1].IsKnown())
+		assert.ElementsMatch(s.T(), []card.SerializableCardID{3, 4, 6}, view.OppPool)
+	})
+
+	s.Run("should skip revealed and cleared spaces", func() {
+		data := newData(game.PhaseWar)
+		data.Board[0][0].Revealed = true
+		data.Board[1][1] = game.BoardSpace{}
+		view := bot.NewView(data, 1)
+		assert.Len(s.T(), view.Own, 1)
+		assert.Len(s.T(), view.Opponent, 1)
+		assert.Equal(s.T(), 2, view.RoundsLeft)
+		assert.True(s.T(), view.CanDraw)
+	})
+}
@@ -0,0 +1,179 @@
+package card
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/coopersmall/subswag/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// CardSetFormat is the encoding of an imported card set
+type CardSetFormat string
+
+const (
+	CardSetFormatJSON CardSetFormat = "json"
+	CardSetFormatYAML CardSetFormat = "yaml"
+)
+
+// CardSet is a batch of cards loaded into the catalog together. Cards are
+// matched to the catalog by ID, so a set can both add new cards and change
+// existing ones.
+type CardSet struct {
+	Name  string
+	Cards []Card
+}
+
+// ParseCardSet decodes a card set, e.g. in YAML:
+//
+//	name: Core Set
+//	cards:
+//	  - id: 1
+//	    type: number
+//	    number: 7
+//	    suite: hearts
+//	    ...
+func ParseCardSet(data []byte, format CardSetFormat) (*CardSet, error) {
+	switch format {
+	case CardSetFormatJSON, "":
+	case CardSetFormatYAML:
+		var value any
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return nil, utils.NewInvalidArgumentError("card set is not valid YAML", err)
+		}
+		var err error
+		data, err = utils.Marshal(value)
+		if err != nil {
+			return nil, utils.NewInvalidArgumentError("card set cannot be converted to JSON", err)
+		}
+	default:
+		return nil, utils.NewInvalidArgumentError(fmt.Sprintf("unknown card set format %q", format))
+	}
+
+	var raw struct {
+		Name  string            `json:"name"`
+		Cards []json.RawMessage `json:"cards"`
+	}
+	if err := utils.Unmarshal(data, &raw); err != nil {
+		return nil, utils.NewInvalidArgumentError("card set is not valid JSON", err)
+	}
+	set := &CardSet{
+		Name:  raw.Name,
+		Cards: make([]Card, len(raw.Cards)),
+	}
+	for i, data := range raw.Cards {
+		c, err := UnmarshalCard(data)
+		if err != nil {
+			return nil, utils.NewInvalidArgumentError(fmt.Sprintf("cards[%d] could not be decoded", i), err)
+		}
+		set.Cards[i] = c
+	}
+	return set, nil
+}
+
+// CardSetImport reports what importing a card set did, or would do in a dry
+// run, to the catalog
+type CardSetImport struct {
+	Name      string               `json:"name"`
+	DryRun    bool                 `json:"dry_run"`
+	Created   []SerializableCardID `json:"created"`
+	Updated   []CardUpdate         `json:"updated"`
+	Unchanged []SerializableCardID `json:"unchanged"`
+	Invalid   []InvalidCard        `json:"invalid"`
+}
+
+// CardUpdate is an existing card that the import changes
+type CardUpdate struct {
+	CardID  SerializableCardID `json:"card_id"`
+	Version int                `json:"version"` // The version the card moves to
+	Changes []utils.JSONChange `json:"changes"`
+}
+
+// InvalidCard is a card in the set that cannot be imported
+type InvalidCard struct {
+	Index   int                `json:"index"` // Position in the set's cards
+	CardID  SerializableCardID `json:"card_id"`
+	Message string             `json:"message"`
+}
+
+// DiffCardSet validates every card in the set, with its effects checked
+// against schema, and compares it with the catalog's current cards. Cards are
+// changed if anything but their version and metadata differ.
+func DiffCardSet(set *CardSet, catalog []Card, schema *booleanexpression.Schema) (*CardSetImport, error) {
+	existing := make(map[SerializableCardID]Card, len(catalog))
+	for _, c := range catalog {
+		existing[c.GetID()] = c
+	}
+	result := &CardSetImport{
+		Name:      set.Name,
+		Created:   []SerializableCardID{},
+		Updated:   []CardUpdate{},
+		Unchanged: []SerializableCardID{},
+		Invalid:   []InvalidCard{},
+	}
+	seen := make(map[SerializableCardID]bool, len(set.Cards))
+	for i, c := range set.Cards {
+		if err := ValidateCard(c); err != nil {
+			result.Invalid = append(result.Invalid, InvalidCard{Index: i, CardID: c.GetID(), Message: utils.GetErrorMessage(err)})
+			continue
+		}
+		if err := ValidateCardEffects(c, schema); err != nil {
+			result.Invalid = append(result.Invalid, InvalidCard{Index: i, CardID: c.GetID(), Message: utils.GetErrorMessage(err)})
+			continue
+		}
+		if seen[c.GetID()] {
+			result.Invalid = append(result.Invalid, InvalidCard{Index: i, CardID: c.GetID(), Message: "card appears more than once in the set"})
+			continue
+		}
+		seen[c.GetID()] = true
+
+		current, ok := existing[c.GetID()]
+		if !ok {
+			result.Created = append(result.Created, c.GetID())
+			continue
+		}
+		changes, err := DiffCards(current, c)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) == 0 {
+			result.Unchanged = append(result.Unchanged, c.GetID())
+			continue
+		}
+		result.Updated = append(result.Updated, CardUpdate{
+			CardID:  c.GetID(),
+			Version: current.GetVersion() + 1,
+			Changes: changes,
+		})
+	}
+	return result, nil
+}
+
+// DiffCards returns how a card's rules and artwork differ between two
+// versions, ignoring the version number and metadata
+func DiffCards(from Card, to Card) ([]utils.JSONChange, error) {
+	fromContent, err := cardContent(from)
+	if err != nil {
+		return nil, err
+	}
+	toContent, err := cardContent(to)
+	if err != nil {
+		return nil, err
+	}
+	return utils.DiffJSON(fromContent, toContent)
+}
+
+func cardContent(c Card) (map[string]any, error) {
+	data, err := utils.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var content map[string]any
+	if err := utils.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+	delete(content, "version")
+	delete(content, "metadata")
+	return content, nil
+}
@@ -0,0 +1,153 @@
+package card_test
+
+import (
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const coreSetYAML = `
+name: Core Set
+cards:
+  - id: 1
+    type: number
+    number: 7
+    artwork_url: https://example.com/7.png
+    suite: hearts
+    rarity: common
+    tribe: military
+    on_reveal_effects:
+      - condition:
+          operator: AND
+          conditions:
+            - type: numeric
+              actual_json_path: self.points
+              operator: "<"
+              expected_value: 5
+        gain_points_effects:
+          - amount: 2
+            is_quick_effect: true
+  - id: 2
+    type: face
+    face: King
+    artwork_url: https://example.com/king.png
+    suite: spades
+    rarity: rare
+    tribe: magic
+`
+
+func newNumberCard(id card.SerializableCardID, number int) *card.SerializableNumberCard {
+	return &card.SerializableNumberCard{
+		SerializableCardBaseData: card.SerializableCardBaseData{
+			ID: id,
+			SerializableCardData: card.SerializableCardData{
+				ArtworkURL: "https://example.com/7.png",
+				Suite:      card.CardSuiteHearts,
+				Rarity:     card.CardRarityCommon,
+				Tribe:      card.CardTribeMilitary,
+			},
+		},
+		Type:   card.SerializableCardTypeNumber,
+		Number: number,
+	}
+}
+
+func (s *CardTestSuite) TestValidateCard() {
+	s.Run("should accept a valid card", func() {
+		assert.NoError(s.T(), card.ValidateCard(newNumberCard(1, 7)))
+	})
+
+	s.Run("should report every problem with a card", func() {
+		c := newNumberCard(1, 11)
+		c.Suite = "stars"
+		c.OnWarEffects = []card.CardEffect{{
+			Condition: booleanexpression.NewBooleanExpression("NAND"),
+			DrawEffectAttributes: []card.DrawEffectAttributes{
+				{Amount: 0},
+			},
+		}}
+
+		err := card.ValidateCard(c)
+		require.Error(s.T(), err)
+		assert.Contains(s.T(), err.Error(), "number must be between 2 and 10")
+		assert.Contains(s.T(), err.Error(), `unknown suite "stars"`)
+		assert.Contains(s.T(), err.Error(), "on_war_effects[0]: condition: unsupported operator")
+		assert.Contains(s.T(), err.Error(), "on_war_effects[0]: draw_effects[0]: amount must be positive")
+	})
+
+	s.Run("should reject effects that do nothing", func() {
+		c := newNumberCard(1, 7)
+		c.OnDrawEffects = []card.CardEffect{{}}
+		assert.ErrorContains(s.T(), card.ValidateCard(c), "effect does nothing")
+	})
+}
+
+func (s *CardTestSuite) TestParseCardSet() {
+	s.Run("should decode a YAML card set", func() {
+		set, err := card.ParseCardSet([]byte(coreSetYAML), card.CardSetFormatYAML)
+		require.NoError(s.T(), err)
+
+		assert.Equal(s.T(), "Core Set", set.Name)
+		require.Len(s.T(), set.Cards, 2)
+		assert.Equal(s.T(), 7, set.Cards[0].(*card.SerializableNumberCard).Number)
+		effect := set.Cards[0].GetEffects(card.CardEffectTypeReveal)[0]
+		assert.Equal(s.T(), 2, effect.GainPointsEffectAttributes[0].Amount)
+		assert.Equal(s.T(), booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+			booleanexpression.NewNumericCondition("self.points", booleanexpression.OperatorLessThan, 5),
+		), effect.Condition)
+		assert.Equal(s.T(), "King", set.Cards[1].(*card.SerializableFaceCard).Face)
+	})
+
+	s.Run("should decode a JSON card set", func() {
+		set, err := card.ParseCardSet([]byte(`{"name": "Promo", "cards": [{"id": 3, "type": "number", "number": 2}]}`), card.CardSetFormatJSON)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), card.SerializableCardID(3), set.Cards[0].GetID())
+	})
+
+	s.Run("should reject unknown formats and card types", func() {
+		_, err := card.ParseCardSet([]byte(`{}`), "xml")
+		assert.Error(s.T(), err)
+
+		_, err = card.ParseCardSet([]byte(`{"cards": [{"id": 3, "type": "joker"}]}`), card.CardSetFormatJSON)
+		assert.ErrorContains(s.T(), err, "cards[0]")
+	})
+}
+
+func (s *CardTestSuite) TestDiffCardSet() {
+	s.Run("should sort the set's cards into created, updated, unchanged and invalid", func() {
+		unchanged := newNumberCard(1, 7)
+		unchanged.Version = 3
+		changed := newNumberCard(2, 8)
+		changed.Version = 1
+
+		incomingChanged := newNumberCard(2, 9)
+		set := &card.CardSet{
+			Name: "Core Set",
+			Cards: []card.Card{
+				newNumberCard(1, 7),
+				incomingChanged,
+				newNumberCard(3, 4),
+				newNumberCard(4, 1),
+				newNumberCard(3, 4),
+			},
+		}
+
+		result, err := card.DiffCardSet(set, []card.Card{unchanged, changed}, game.EffectSnapshotSchema())
+		require.NoError(s.T(), err)
+
+		assert.Equal(s.T(), []card.SerializableCardID{3}, result.Created)
+		assert.Equal(s.T(), []card.SerializableCardID{1}, result.Unchanged)
+		require.Len(s.T(), result.Updated, 1)
+		assert.Equal(s.T(), card.SerializableCardID(2), result.Updated[0].CardID)
+		assert.Equal(s.T(), 2, result.Updated[0].Version)
+		require.Len(s.T(), result.Updated[0].Changes, 1)
+		assert.Equal(s.T(), "number", result.Updated[0].Changes[0].Path)
+
+		require.Len(s.T(), result.Invalid, 2)
+		assert.Equal(s.T(), 3, result.Invalid[0].Index)
+		assert.Equal(s.T(), 4, result.Invalid[1].Index)
+		assert.Contains(s.T(), result.Invalid[1].Message, "more than once")
+	})
+}
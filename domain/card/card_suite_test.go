@@ -0,0 +1,15 @@
+package card_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CardTestSuite struct {
+	suite.Suite
+}
+
+func TestCardSuite(t *testing.T) {
+	suite.Run(t, new(CardTestSuite))
+}
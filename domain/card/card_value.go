@@ -0,0 +1,34 @@
+package card
+
+import "strings"
+
+const (
+	FaceJack  = "jack"
+	FaceQueen = "queen"
+	FaceKing  = "king"
+	FaceAce   = "ace"
+)
+
+// MaxCardValue is the value of an ace, the highest card
+const MaxCardValue = 14
+
+// Face cards always beat number cards, so their values start above the
+// highest number card (10)
+var faceValues = map[string]int{
+	FaceJack:  11,
+	FaceQueen: 12,
+	FaceKing:  13,
+	FaceAce:   MaxCardValue,
+}
+
+// GetCardValue returns the base War value of a card. Unknown faces and card
+// types are worth 0.
+func GetCardValue(c Card) int {
+	switch v := c.(type) {
+	case *SerializableNumberCard:
+		return v.Number
+	case *SerializableFaceCard:
+		return faceValues[strings.ToLower(v.Face)]
+	}
+	return 0
+}
@@ -0,0 +1,119 @@
+package card
+
+import (
+	"slices"
+
+	"github.com/coopersmall/subswag/domain/user"
+)
+
+// CollectionEventsStream receives an event whenever copies of a card are
+// added to or removed from any user's collection
+const CollectionEventsStream = "collections"
+
+type CollectionEventType string
+
+const (
+	CollectionEventCardsGranted CollectionEventType = "cards_granted"
+	CollectionEventCardsRevoked CollectionEventType = "cards_revoked"
+)
+
+// CollectionEvent records a change to the copies of one card a user owns
+type CollectionEvent struct {
+	UserID user.UserID         `json:"user_id"`
+	Type   CollectionEventType `json:"type"`
+	CardID SerializableCardID  `json:"card_id"`
+	Copies int                 `json:"copies"` // Copies added or removed
+	Owned  int                 `json:"owned"`  // Copies owned afterwards
+	Source CardSource          `json:"source,omitempty"`
+}
+
+// NewGrantEvents describes copies added to a user's collection, one event for
+// each card in the order it first appears. owned is the copies the user has
+// afterwards.
+func NewGrantEvents(
+	userId user.UserID,
+	cardIds []SerializableCardID,
+	owned map[SerializableCardID]int,
+	source CardSource,
+) []*CollectionEvent {
+	order, copies := CountCopies(cardIds)
+	events := make([]*CollectionEvent, len(order))
+	for i, cardId := range order {
+		events[i] = &CollectionEvent{
+			UserID: userId,
+			Type:   CollectionEventCardsGranted,
+			CardID: cardId,
+			Copies: copies[cardId],
+			Owned:  owned[cardId],
+			Source: source,
+		}
+	}
+	return events
+}
+
+// CollectionCard is a card in a user's collection and how many copies of it
+// they own
+type CollectionCard struct {
+	Card       Card `json:"card"`
+	Copies     int  `json:"copies"`
+	Duplicates int  `json:"duplicates"` // Copies beyond the first
+}
+
+func NewCollectionCard(c Card, copies int) *CollectionCard {
+	return &CollectionCard{
+		Card:       c,
+		Copies:     copies,
+		Duplicates: max(copies-1, 0),
+	}
+}
+
+// CollectionFilter narrows a collection down. Each field matches any of its
+// values and is ignored when empty.
+type CollectionFilter struct {
+	Suites         []CardSuite  `json:"suites,omitempty"`
+	Rarities       []CardRarity `json:"rarities,omitempty"`
+	Tribes         []CardTribe  `json:"tribes,omitempty"`
+	DuplicatesOnly bool         `json:"duplicates_only,omitempty"`
+}
+
+// Matches reports whether the card, owned copies times, passes the filter
+func (f CollectionFilter) Matches(c Card, copies int) bool {
+	data := CardData(c)
+	switch {
+	case len(f.Suites) > 0 && !slices.Contains(f.Suites, data.Suite):
+		return false
+	case len(f.Rarities) > 0 && !slices.Contains(f.Rarities, data.Rarity):
+		return false
+	case len(f.Tribes) > 0 && !slices.Contains(f.Tribes, data.Tribe):
+		return false
+	case f.DuplicatesOnly && copies < 2:
+		return false
+	}
+	return true
+}
+
+// MissingCard is a card a deck needs more copies of than the user owns
+type MissingCard struct {
+	CardID  SerializableCardID `json:"card_id"`
+	Needed  int                `json:"needed"`
+	Owned   int                `json:"owned"`
+	Missing int                `json:"missing"`
+}
+
+// MissingCards lists the cards in the deck the user does not own enough
+// copies of, in the order they first appear in the deck
+func MissingCards(deck *SerializableDeck, owned map[SerializableCardID]int) []MissingCard {
+	order, needed := CountCopies(deck.CardIDs)
+	missing := []MissingCard{}
+	for _, cardId := range order {
+		if owned[cardId] < needed[cardId] {
+			missing = append(missing, MissingCard{
+				CardID:  cardId,
+				Needed:  needed[cardId],
+				Owned:   owned[cardId],
+				Missing: needed[cardId] - owned[cardId],
+			})
+		}
+	}
+	return missing
+}
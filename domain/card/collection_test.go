@@ -0,0 +1,59 @@
+package card_test
+
+import (
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *CardTestSuite) TestCollection() {
+	hearts := newNumberCard(1, 7)
+	hearts.Suite = card.CardSuiteHearts
+	hearts.Rarity = card.CardRarityEpic
+	hearts.Tribe = card.CardTribeMagic
+
+	s.Run("should count duplicates beyond the first copy", func() {
+		assert.Equal(s.T(), 2, card.NewCollectionCard(hearts, 3).Duplicates)
+		assert.Equal(s.T(), 0, card.NewCollectionCard(hearts, 1).Duplicates)
+	})
+
+	s.Run("should match cards against every part of the filter", func() {
+		tests := map[string]struct {
+			filter   card.CollectionFilter
+			copies   int
+			expected bool
+		}{
+			"empty":             {card.CollectionFilter{}, 1, true},
+			"suite":             {card.CollectionFilter{Suites: []card.CardSuite{card.CardSuiteClubs, card.CardSuiteHearts}}, 1, true},
+			"other suite":       {card.CollectionFilter{Suites: []card.CardSuite{card.CardSuiteClubs}}, 1, false},
+			"rarity":            {card.CollectionFilter{Rarities: []card.CardRarity{card.CardRarityEpic}}, 1, true},
+			"other rarity":      {card.CollectionFilter{Rarities: []card.CardRarity{card.CardRarityCommon}}, 1, false},
+			"other tribe":       {card.CollectionFilter{Tribes: []card.CardTribe{card.CardTribeTech}}, 1, false},
+			"duplicates":        {card.CollectionFilter{DuplicatesOnly: true}, 2, true},
+			"single copy":       {card.CollectionFilter{DuplicatesOnly: true}, 1, false},
+			"every field match": {card.CollectionFilter{Suites: []card.CardSuite{card.CardSuiteHearts}, Tribes: []card.CardTribe{card.CardTribeMagic}, DuplicatesOnly: true}, 2, true},
+		}
+		for name, test := range tests {
+			assert.Equal(s.T(), test.expected, test.filter.Matches(hearts, test.copies), name)
+		}
+	})
+
+	s.Run("should describe granted copies with one event per card", func() {
+		owned := map[card.SerializableCardID]int{2: 3, 3: 1}
+		events := card.NewGrantEvents(1, []card.SerializableCardID{2, 3, 2}, owned, card.CardSourcePack)
+		assert.Equal(s.T(), []*card.CollectionEvent{
+			{UserID: 1, Type: card.CollectionEventCardsGranted, CardID: 2, Copies: 2, Owned: 3, Source: card.CardSourcePack},
+			{UserID: 1, Type: card.CollectionEventCardsGranted, CardID: 3, Copies: 1, Owned: 1, Source: card.CardSourcePack},
+		}, events)
+	})
+
+	s.Run("should list the cards a deck needs more copies of", func() {
+		deck := card.NewDeck(1, card.SerializableDeckData{
+			CardIDs: []card.SerializableCardID{3, 1, 1, 2, 3, 3},
+		})
+		owned := map[card.SerializableCardID]int{1: 2, 3: 1}
+		assert.Equal(s.T(), []card.MissingCard{
+			{CardID: 3, Needed: 3, Owned: 1, Missing: 2},
+			{CardID: 2, Needed: 1, Owned: 0, Missing: 1},
+		}, card.MissingCards(deck, owned))
+	})
+}
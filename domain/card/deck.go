@@ -6,13 +6,18 @@ import (
 )
 
 func ShuffleCards(deck []SerializableCardID) []SerializableCardID {
+	// Seed the random number generator
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return ShuffleCardsWithRand(deck, r)
+}
+
+// ShuffleCardsWithRand shuffles a copy of deck using r, so the same source
+// always produces the same order
+func ShuffleCardsWithRand(deck []SerializableCardID, r *rand.Rand) []SerializableCardID {
 	// Create a copy of the deck to avoid modifying the original
 	shuffled := make([]SerializableCardID, len(deck))
 	copy(shuffled, deck)
 
-	// Seed the random number generator
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-
 	// Fisher-Yates shuffle algorithm
 	for i := len(shuffled) - 1; i > 0; i-- {
 		// Generate random index between 0 and i
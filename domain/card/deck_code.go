@@ -0,0 +1,147 @@
+package card
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"slices"
+
+	"github.com/coopersmall/subswag/utils"
+)
+
+// DeckCodeVersion is the version of the deck code layout written by String.
+// ParseDeckCode rejects codes from any other version.
+const DeckCodeVersion = 1
+
+const deckCodeChecksumSize = crc32.Size
+
+// DeckCode is the part of a deck that can be shared with another player:
+// its format and cards. Names, records and favourites stay with the deck.
+type DeckCode struct {
+	Format  DeckFormatName       `json:"format,omitempty"`
+	CardIDs []SerializableCardID `json:"cards"`
+}
+
+// NewDeckCode returns the shareable part of the deck
+func NewDeckCode(deck *SerializableDeck) DeckCode {
+	return DeckCode{
+		Format:  deck.Format,
+		CardIDs: slices.Clone(deck.CardIDs),
+	}
+}
+
+// String encodes the deck code as URL safe base64. The layout is
+//
+//	version | len(format) | format | distinct cards | (id delta, copies)... | crc32
+//
+// with every number a uvarint. Card IDs are sorted and written as the
+// difference from the previous ID, so the order of the deck is not kept.
+func (c DeckCode) String() string {
+	copies := map[SerializableCardID]int{}
+	for _, cardId := range c.CardIDs {
+		copies[cardId]++
+	}
+	ids := make([]SerializableCardID, 0, len(copies))
+	for cardId := range copies {
+		ids = append(ids, cardId)
+	}
+	slices.Sort(ids)
+
+	buf := []byte{}
+	buf = binary.AppendUvarint(buf, DeckCodeVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(c.Format)))
+	buf = append(buf, c.Format...)
+	buf = binary.AppendUvarint(buf, uint64(len(ids)))
+	previous := SerializableCardID(0)
+	for _, cardId := range ids {
+		buf = binary.AppendUvarint(buf, uint64(cardId-previous))
+		buf = binary.AppendUvarint(buf, uint64(copies[cardId]))
+		previous = cardId
+	}
+	buf = binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// ParseDeckCode decodes a code written by DeckCode.String. Copies of a card
+// are next to each other in the returned deck, in card ID order.
+func ParseDeckCode(code string) (DeckCode, error) {
+	data, err := base64.RawURLEncoding.DecodeString(code)
+	if err != nil {
+		return DeckCode{}, utils.NewInvalidArgumentError("deck code is not valid base64", err)
+	}
+	if len(data) <= deckCodeChecksumSize {
+		return DeckCode{}, utils.NewInvalidArgumentError("deck code is too short")
+	}
+	body, checksum := data[:len(data)-deckCodeChecksumSize], data[len(data)-deckCodeChecksumSize:]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(checksum) {
+		return DeckCode{}, utils.NewInvalidArgumentError("deck code checksum does not match, the code may be mistyped")
+	}
+
+	r := &deckCodeReader{data: body}
+	if version := r.uvarint(); r.err == nil && version != DeckCodeVersion {
+		return DeckCode{}, utils.NewInvalidArgumentError(fmt.Sprintf("deck code version %d is not supported", version))
+	}
+	deckCode := DeckCode{
+		Format:  DeckFormatName(r.bytes(r.uvarint())),
+		CardIDs: []SerializableCardID{},
+	}
+	distinct := r.uvarint()
+	previous := SerializableCardID(0)
+	for i := uint64(0); i < distinct && r.err == nil; i++ {
+		cardId := previous + SerializableCardID(r.uvarint())
+		copies := r.uvarint()
+		if r.err == nil && (copies == 0 || copies > MaxDeckSize || (i > 0 && cardId == previous)) {
+			r.err = fmt.Errorf("card %d is not valid", i)
+		}
+		for j := uint64(0); j < copies && r.err == nil; j++ {
+			deckCode.CardIDs = append(deckCode.CardIDs, cardId)
+		}
+		if len(deckCode.CardIDs) > MaxDeckSize {
+			r.err = errors.New("it has more cards than any deck can hold")
+		}
+		previous = cardId
+	}
+	if r.err == nil && r.offset != len(r.data) {
+		r.err = fmt.Errorf("%d unexpected bytes at the end", len(r.data)-r.offset)
+	}
+	if r.err != nil {
+		return DeckCode{}, utils.NewInvalidArgumentError(fmt.Sprintf("deck code is not valid: %s", r.err))
+	}
+	return deckCode, nil
+}
+
+// deckCodeReader reads the fields of a deck code, keeping the first error so
+// the fields can be read one after another and checked once
+type deckCodeReader struct {
+	data   []byte
+	offset int
+	err    error
+}
+
+func (r *deckCodeReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	value, n := binary.Uvarint(r.data[r.offset:])
+	if n <= 0 {
+		r.err = errors.New("it ends early")
+		return 0
+	}
+	r.offset += n
+	return value
+}
+
+func (r *deckCodeReader) bytes(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > uint64(len(r.data)-r.offset) {
+		r.err = errors.New("it ends early")
+		return nil
+	}
+	value := r.data[r.offset : r.offset+int(n)]
+	r.offset += int(n)
+	return value
+}
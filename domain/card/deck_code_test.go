@@ -0,0 +1,75 @@
+package card_test
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"slices"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *CardTestSuite) TestDeckCode() {
+	deck := card.NewDeck(1, card.SerializableDeckData{
+		Name:    "Shared",
+		Format:  card.DeckFormatSingleton,
+		CardIDs: []card.SerializableCardID{1_700_000_000_042, 12, 1_700_000_000_042, 7, 12, 12},
+	})
+
+	s.Run("should round trip a deck's format and cards", func() {
+		code := card.NewDeckCode(deck).String()
+		parsed, err := card.ParseDeckCode(code)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), card.DeckFormatSingleton, parsed.Format)
+		assert.Equal(s.T(), []card.SerializableCardID{7, 12, 12, 12, 1_700_000_000_042, 1_700_000_000_042}, parsed.CardIDs)
+	})
+
+	s.Run("should write the same code for the same cards in any order", func() {
+		shuffled := card.NewDeck(2, deck.SerializableDeckData)
+		shuffled.CardIDs = slices.Clone(deck.CardIDs)
+		slices.Reverse(shuffled.CardIDs)
+		assert.Equal(s.T(), card.NewDeckCode(deck).String(), card.NewDeckCode(shuffled).String())
+	})
+
+	s.Run("should round trip an empty deck", func() {
+		parsed, err := card.ParseDeckCode(card.DeckCode{}.String())
+		require.NoError(s.T(), err)
+		assert.Empty(s.T(), parsed.CardIDs)
+		assert.Empty(s.T(), parsed.Format)
+	})
+
+	s.Run("should reject codes that are mistyped or malformed", func() {
+		code := card.NewDeckCode(deck).String()
+		mistyped := []byte(code)
+		if mistyped[3] == 'A' {
+			mistyped[3] = 'B'
+		} else {
+			mistyped[3] = 'A'
+		}
+		// Codes with valid checksums that are wrong in other ways
+		encode := func(data ...byte) string {
+			data = binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+			return base64.RawURLEncoding.EncodeToString(data)
+		}
+		version2 := encode(0x02, 0x00, 0x00)
+		truncated := encode(0x01, 0x00, 0x02, 0x07, 0x01)
+		duplicated := encode(0x01, 0x00, 0x02, 0x07, 0x01, 0x00, 0x01)
+		trailing := encode(0x01, 0x00, 0x01, 0x07, 0x01, 0xff)
+
+		tests := map[string]string{
+			"not base64!":    "deck code is not valid base64",
+			"AAAA":           "deck code is too short",
+			string(mistyped): "deck code checksum does not match",
+			version2:         "deck code version 2 is not supported",
+			truncated:        "deck code is not valid: it ends early",
+			duplicated:       "deck code is not valid: card 1 is not valid",
+			trailing:         "deck code is not valid: 1 unexpected bytes at the end",
+		}
+		for input, expected := range tests {
+			_, err := card.ParseDeckCode(input)
+			assert.ErrorContains(s.T(), err, expected, input)
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package card
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/coopersmall/subswag/utils"
+)
+
+// DeckFormatName names a set of deck-building rules a deck can be built for
+type DeckFormatName string
+
+const (
+	DeckFormatStandard  DeckFormatName = "standard"
+	DeckFormatSingleton DeckFormatName = "singleton"  // One copy of every card
+	DeckFormatMonoSuite DeckFormatName = "mono_suite" // Every card from one suite
+)
+
+// MaxDeckSize is the most cards any deck can hold, the game's deck limit
+const MaxDeckSize = 52
+
+// MinDeckSize is the fewest cards that can deal a starting hand and board
+// under every rules preset
+const MinDeckSize = 21
+
+// DeckFormat is the rules a deck must follow to be played. Zero limits are
+// not checked.
+type DeckFormat struct {
+	Name              DeckFormatName     `json:"name"`
+	MinCards          int                `json:"min_cards"`
+	MaxCards          int                `json:"max_cards"`
+	MaxCopies         int                `json:"max_copies"`                     // Copies of any one card
+	MaxCopiesByRarity map[CardRarity]int `json:"max_copies_by_rarity,omitempty"` // Tighter copy limits for rarer cards
+	MaxByRarity       map[CardRarity]int `json:"max_by_rarity,omitempty"`        // Cards of a rarity across the deck
+	Suites            []CardSuite        `json:"suites,omitempty"`               // The only suites allowed, any when empty
+	MaxSuites         int                `json:"max_suites,omitempty"`           // Different suites across the deck
+	RequireOwnership  bool               `json:"require_ownership"`              // Every copy must be in the user's collection
+}
+
+var standardDeckFormat = DeckFormat{
+	Name:      DeckFormatStandard,
+	MinCards:  MinDeckSize,
+	MaxCards:  MaxDeckSize,
+	MaxCopies: 3,
+	MaxCopiesByRarity: map[CardRarity]int{
+		CardRarityEpic:      2,
+		CardRarityLegendary: 1,
+	},
+	MaxByRarity: map[CardRarity]int{
+		CardRarityLegendary: 5,
+	},
+	RequireOwnership: true,
+}
+
+var deckFormats = map[DeckFormatName]func() DeckFormat{
+	DeckFormatStandard: func() DeckFormat {
+		return standardDeckFormat
+	},
+	DeckFormatSingleton: func() DeckFormat {
+		format := standardDeckFormat
+		format.Name = DeckFormatSingleton
+		format.MaxCopies = 1
+		format.MaxCopiesByRarity = nil
+		return format
+	},
+	DeckFormatMonoSuite: func() DeckFormat {
+		format := standardDeckFormat
+		format.Name = DeckFormatMonoSuite
+		format.MaxSuites = 1
+		return format
+	},
+}
+
+// NewDeckFormat returns the named format. The standard format is used when
+// name is empty.
+func NewDeckFormat(name DeckFormatName) (DeckFormat, error) {
+	if name == "" {
+		name = DeckFormatStandard
+	}
+	format, ok := deckFormats[name]
+	if !ok {
+		return DeckFormat{}, utils.NewInvalidArgumentError(fmt.Sprintf("unknown deck format %q", name))
+	}
+	return format(), nil
+}
+
+// DeckViolationRule names the rule a deck broke
+type DeckViolationRule string
+
+const (
+	DeckViolationTooFewCards   DeckViolationRule = "too_few_cards"
+	DeckViolationTooManyCards  DeckViolationRule = "too_many_cards"
+	DeckViolationUnknownCard   DeckViolationRule = "unknown_card"
+	DeckViolationTooManyCopies DeckViolationRule = "too_many_copies"
+	DeckViolationRarityLimit   DeckViolationRule = "rarity_limit"
+	DeckViolationSuite         DeckViolationRule = "suite"
+	DeckViolationNotOwned      DeckViolationRule = "not_owned"
+)
+
+// DeckViolation is one rule a deck breaks, with the card that breaks it when
+// there is one
+type DeckViolation struct {
+	Rule    DeckViolationRule  `json:"rule"`
+	CardID  SerializableCardID `json:"card_id,omitempty"`
+	Message string             `json:"message"`
+}
+
+// DeckLegality is whether a deck can be played in a format, and every rule it
+// breaks if not
+type DeckLegality struct {
+	DeckID     SerializableDeckID `json:"deck_id"`
+	Format     DeckFormatName     `json:"format"`
+	Legal      bool               `json:"legal"`
+	Violations []DeckViolation    `json:"violations"`
+}
+
+// Err returns the violations as one error, or nil when the deck is legal
+func (l *DeckLegality) Err() error {
+	if l.Legal {
+		return nil
+	}
+	messages := make([]string, len(l.Violations))
+	for i, violation := range l.Violations {
+		messages[i] = violation.Message
+	}
+	return utils.NewInvalidArgumentError(fmt.Sprintf("deck is not legal in the %s format: %s", l.Format, strings.Join(messages, "; ")))
+}
+
+// ValidateDeck checks the deck against every rule of the format. The catalog
+// holds the deck's cards by id and owned the number of copies of each card in
+// the user's collection; owned is only used when the format requires
+// ownership. Violations about a card are reported once, in the order the card
+// first appears in the deck.
+func (f DeckFormat) ValidateDeck(
+	deck *SerializableDeck,
+	catalog map[SerializableCardID]Card,
+	owned map[SerializableCardID]int,
+) *DeckLegality {
+	legality := &DeckLegality{
+		DeckID:     deck.ID,
+		Format:     f.Name,
+		Violations: []DeckViolation{},
+	}
+	add := func(rule DeckViolationRule, cardId SerializableCardID, format string, args ...any) {
+		legality.Violations = append(legality.Violations, DeckViolation{
+			Rule:    rule,
+			CardID:  cardId,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	size := len(deck.CardIDs)
+	if f.MinCards > 0 && size < f.MinCards {
+		add(DeckViolationTooFewCards, 0, "deck has %d cards, needs at least %d", size, f.MinCards)
+	}
+	if f.MaxCards > 0 && size > f.MaxCards {
+		add(DeckViolationTooManyCards, 0, "deck has %d cards, can have at most %d", size, f.MaxCards)
+	}
+
+	order, copies := CountCopies(deck.CardIDs)
+
+	byRarity := map[CardRarity]int{}
+	var suites []CardSuite
+	for _, cardId := range order {
+		count := copies[cardId]
+		c, ok := catalog[cardId]
+		if !ok {
+			add(DeckViolationUnknownCard, cardId, "card %d is not in the catalog", cardId)
+			continue
+		}
+		data := CardData(c)
+		byRarity[data.Rarity] += count
+
+		maxCopies := f.MaxCopies
+		if limit, ok := f.MaxCopiesByRarity[data.Rarity]; ok {
+			maxCopies = limit
+		}
+		if maxCopies > 0 && count > maxCopies {
+			add(DeckViolationTooManyCopies, cardId, "card %d has %d copies, %s cards can have at most %d", cardId, count, data.Rarity, maxCopies)
+		}
+		if len(f.Suites) > 0 && !slices.Contains(f.Suites, data.Suite) {
+			add(DeckViolationSuite, cardId, "card %d is %s, the format only allows %s", cardId, data.Suite, joinSuites(f.Suites))
+		}
+		// The first suites the deck uses are the ones it keeps
+		if !slices.Contains(suites, data.Suite) {
+			suites = append(suites, data.Suite)
+		}
+		if f.MaxSuites > 0 && slices.Index(suites, data.Suite) >= f.MaxSuites {
+			add(DeckViolationSuite, cardId, "card %d is %s, the deck already uses %s", cardId, data.Suite, joinSuites(suites[:f.MaxSuites]))
+		}
+		if f.RequireOwnership && owned[cardId] < count {
+			add(DeckViolationNotOwned, cardId, "deck needs %d of card %d, the collection has %d", count, cardId, owned[cardId])
+		}
+	}
+
+	for _, rarity := range CardRarities {
+		limit, ok := f.MaxByRarity[rarity]
+		if ok && byRarity[rarity] > limit {
+			add(DeckViolationRarityLimit, 0, "deck has %d %s cards, can have at most %d", byRarity[rarity], rarity, limit)
+		}
+	}
+
+	legality.Legal = len(legality.Violations) == 0
+	return legality
+}
+
+// CountCopies returns the distinct cards in the order they first appear and
+// the copies of each
+func CountCopies(cardIds []SerializableCardID) ([]SerializableCardID, map[SerializableCardID]int) {
+	var order []SerializableCardID
+	copies := map[SerializableCardID]int{}
+	for _, cardId := range cardIds {
+		if copies[cardId] == 0 {
+			order = append(order, cardId)
+		}
+		copies[cardId]++
+	}
+	return order, copies
+}
+
+// CardData returns the suite, rarity and tribe shared by every kind of card
+func CardData(c Card) SerializableCardData {
+	switch v := c.(type) {
+	case *SerializableFaceCard:
+		return v.SerializableCardData
+	case *SerializableNumberCard:
+		return v.SerializableCardData
+	}
+	return SerializableCardData{}
+}
+
+func joinSuites(suites []CardSuite) string {
+	names := make([]string, len(suites))
+	for i, suite := range suites {
+		names[i] = string(suite)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,137 @@
+package card_test
+
+import (
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *CardTestSuite) TestDeckLegality() {
+	catalog := map[card.SerializableCardID]card.Card{}
+	addCard := func(id card.SerializableCardID, suite card.CardSuite, rarity card.CardRarity) {
+		c := newNumberCard(id, 7)
+		c.Suite = suite
+		c.Rarity = rarity
+		catalog[id] = c
+	}
+	for id := card.SerializableCardID(1); id <= 10; id++ {
+		addCard(id, card.CardSuiteHearts, card.CardRarityCommon)
+	}
+	addCard(11, card.CardSuiteSpades, card.CardRarityCommon)
+	addCard(12, card.CardSuiteClubs, card.CardRarityCommon)
+	for id := card.SerializableCardID(20); id < 27; id++ {
+		addCard(id, card.CardSuiteHearts, card.CardRarityLegendary)
+	}
+	addCard(30, card.CardSuiteHearts, card.CardRarityEpic)
+
+	newDeck := func(cardIds ...card.SerializableCardID) *card.SerializableDeck {
+		return card.NewDeck(1, card.SerializableDeckData{CardIDs: cardIds})
+	}
+	repeat := func(times int, cardIds ...card.SerializableCardID) []card.SerializableCardID {
+		var repeated []card.SerializableCardID
+		for i := 0; i < times; i++ {
+			repeated = append(repeated, cardIds...)
+		}
+		return repeated
+	}
+	commons := func() []card.SerializableCardID {
+		return repeat(3, 1, 2, 3, 4, 5, 6, 7) // 21 cards
+	}
+	ownAll := func(deck *card.SerializableDeck) map[card.SerializableCardID]int {
+		owned := map[card.SerializableCardID]int{}
+		for _, cardId := range deck.CardIDs {
+			owned[cardId]++
+		}
+		return owned
+	}
+	rules := func(legality *card.DeckLegality) []card.DeckViolationRule {
+		var rules []card.DeckViolationRule
+		for _, violation := range legality.Violations {
+			rules = append(rules, violation.Rule)
+		}
+		return rules
+	}
+
+	s.Run("should return the standard format by default", func() {
+		format, err := card.NewDeckFormat("")
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), card.DeckFormatStandard, format.Name)
+		assert.Equal(s.T(), card.MaxDeckSize, format.MaxCards)
+
+		_, err = card.NewDeckFormat("pauper")
+		assert.ErrorContains(s.T(), err, `unknown deck format "pauper"`)
+	})
+
+	s.Run("should accept a legal deck", func() {
+		format, _ := card.NewDeckFormat(card.DeckFormatStandard)
+		deck := newDeck(append(commons(), 20, 21, 22, 30, 30)...)
+		legality := format.ValidateDeck(deck, catalog, ownAll(deck))
+		assert.True(s.T(), legality.Legal)
+		assert.Empty(s.T(), legality.Violations)
+		assert.NoError(s.T(), legality.Err())
+	})
+
+	s.Run("should report the size of the deck", func() {
+		format, _ := card.NewDeckFormat(card.DeckFormatStandard)
+		deck := newDeck(1, 2, 3)
+		legality := format.ValidateDeck(deck, catalog, ownAll(deck))
+		assert.Equal(s.T(), []card.DeckViolationRule{card.DeckViolationTooFewCards}, rules(legality))
+
+		deck = newDeck(repeat(6, 1, 2, 3, 4, 5, 6, 7, 8, 9)...)
+		format.MaxCopies = 0
+		legality = format.ValidateDeck(deck, catalog, ownAll(deck))
+		assert.Equal(s.T(), []card.DeckViolationRule{card.DeckViolationTooManyCards}, rules(legality))
+		assert.Equal(s.T(), "deck has 54 cards, can have at most 52", legality.Violations[0].Message)
+	})
+
+	s.Run("should report every violation with the offending card", func() {
+		format, _ := card.NewDeckFormat(card.DeckFormatStandard)
+		deck := newDeck(append(commons(), 1, 99, 20, 20, 30, 30, 30, 21, 22, 23, 24, 25)...)
+		owned := ownAll(deck)
+		owned[22] = 0
+		legality := format.ValidateDeck(deck, catalog, owned)
+		assert.False(s.T(), legality.Legal)
+		assert.Equal(s.T(), []card.DeckViolation{
+			{Rule: card.DeckViolationTooManyCopies, CardID: 1, Message: "card 1 has 4 copies, common cards can have at most 3"},
+			{Rule: card.DeckViolationUnknownCard, CardID: 99, Message: "card 99 is not in the catalog"},
+			{Rule: card.DeckViolationTooManyCopies, CardID: 20, Message: "card 20 has 2 copies, legendary cards can have at most 1"},
+			{Rule: card.DeckViolationTooManyCopies, CardID: 30, Message: "card 30 has 3 copies, epic cards can have at most 2"},
+			{Rule: card.DeckViolationNotOwned, CardID: 22, Message: "deck needs 1 of card 22, the collection has 0"},
+			{Rule: card.DeckViolationRarityLimit, Message: "deck has 7 legendary cards, can have at most 5"},
+		}, legality.Violations)
+		assert.ErrorContains(s.T(), legality.Err(), "deck is not legal in the standard format: card 1 has 4 copies")
+	})
+
+	s.Run("should only check ownership when the format requires it", func() {
+		format, _ := card.NewDeckFormat(card.DeckFormatStandard)
+		deck := newDeck(commons()...)
+		assert.Len(s.T(), format.ValidateDeck(deck, catalog, nil).Violations, 7)
+		format.RequireOwnership = false
+		assert.True(s.T(), format.ValidateDeck(deck, catalog, nil).Legal)
+	})
+
+	s.Run("should restrict copies in the singleton format", func() {
+		format, _ := card.NewDeckFormat(card.DeckFormatSingleton)
+		deck := newDeck(commons()...)
+		legality := format.ValidateDeck(deck, catalog, ownAll(deck))
+		assert.Len(s.T(), legality.Violations, 7)
+		assert.Equal(s.T(), "card 1 has 3 copies, common cards can have at most 1", legality.Violations[0].Message)
+	})
+
+	s.Run("should restrict suites", func() {
+		format, _ := card.NewDeckFormat(card.DeckFormatMonoSuite)
+		deck := newDeck(append(commons(), 11, 12, 12)...)
+		legality := format.ValidateDeck(deck, catalog, ownAll(deck))
+		assert.Equal(s.T(), []card.DeckViolation{
+			{Rule: card.DeckViolationSuite, CardID: 11, Message: "card 11 is spades, the deck already uses hearts"},
+			{Rule: card.DeckViolationSuite, CardID: 12, Message: "card 12 is clubs, the deck already uses hearts"},
+		}, legality.Violations)
+
+		format, _ = card.NewDeckFormat(card.DeckFormatStandard)
+		format.Suites = []card.CardSuite{card.CardSuiteHearts, card.CardSuiteClubs}
+		legality = format.ValidateDeck(deck, catalog, ownAll(deck))
+		assert.Equal(s.T(), []card.DeckViolation{
+			{Rule: card.DeckViolationSuite, CardID: 11, Message: "card 11 is spades, the format only allows hearts, clubs"},
+		}, legality.Violations)
+	})
+}
@@ -0,0 +1,261 @@
+package card
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+)
+
+// Effect scripts describe a card's effects one statement at a time:
+//
+//	on reveal if $.self.points < 5 then gain_points 2 for opponent quick
+//	on war if $.card.type == "face" and ($.round > 3 or $.opponent.has_drawn == true) then draw 1, discard 1
+//
+// A statement names its trigger, an optional condition over the effect
+// snapshot in the syntax of booleanexpression.Parse and one or more comma
+// separated actions. Actions apply to the
+// player who owns the card unless they say "for opponent", and wait on the
+// effect stack unless they say "quick".
+
+// Effect script actions and the CardEffect attributes they compile to
+const (
+	EffectActionGainValue    = "gain_value"
+	EffectActionGainPoints   = "gain_points"
+	EffectActionSwapPosition = "swap_position"
+	EffectActionLoseValue    = "lose_value"
+	EffectActionLosePoints   = "lose_points"
+	EffectActionDraw         = "draw"
+	EffectActionDiscard      = "discard"
+)
+
+type effectStatement struct {
+	trigger   effectToken
+	condition booleanexpression.BooleanExpression // Empty when the effect always fires
+	actions   []effectAction
+}
+
+type effectAction struct {
+	verb        effectToken
+	amount      *effectToken
+	forOpponent bool
+	quick       bool
+}
+
+// CompileEffects parses an effect script and compiles every statement into
+// the CardEffect list for its trigger, keeping the order of the script.
+// Errors are *EffectSyntaxError values with the line and column at fault.
+func CompileEffects(source string) (SeralizableFaceCardEffectData, error) {
+	var effects SeralizableFaceCardEffectData
+	statements, err := parseEffects(source)
+	if err != nil {
+		return effects, err
+	}
+	for _, statement := range statements {
+		effect, err := compileEffectStatement(statement)
+		if err != nil {
+			return effects, err
+		}
+		list := effects.effectList(CardEffectType(statement.trigger.text))
+		*list = append(*list, effect)
+	}
+	return effects, nil
+}
+
+func (d *SeralizableFaceCardEffectData) effectList(effectType CardEffectType) *[]CardEffect {
+	switch effectType {
+	case CardEffectTypeDraw:
+		return &d.OnDrawEffects
+	case CardEffectTypeSwap:
+		return &d.OnSwapEffects
+	case CardEffectTypePlace:
+		return &d.OnPlaceEffects
+	case CardEffectTypeReveal:
+		return &d.OnRevealEffects
+	case CardEffectTypeWar:
+		return &d.OnWarEffects
+	case CardEffectTypeDiscard:
+		return &d.OnDiscardEffects
+	}
+	return nil
+}
+
+type effectParser struct {
+	tokens []effectToken
+	offset int
+}
+
+func parseEffects(source string) ([]effectStatement, error) {
+	tokens, err := lexEffects(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &effectParser{tokens: tokens}
+	var statements []effectStatement
+	for p.peek().kind != effectTokenEOF {
+		statement, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
+
+func (p *effectParser) peek() effectToken {
+	return p.tokens[p.offset]
+}
+
+func (p *effectParser) advance() effectToken {
+	token := p.tokens[p.offset]
+	if token.kind != effectTokenEOF {
+		p.offset++
+	}
+	return token
+}
+
+func (p *effectParser) isWord(word string) bool {
+	token := p.peek()
+	return token.kind == effectTokenWord && token.text == word
+}
+
+func (p *effectParser) expectWord(word string) (effectToken, error) {
+	if !p.isWord(word) {
+		return p.peek(), effectErrorf(p.peek(), "expected %q but found %s", word, p.peek().describe())
+	}
+	return p.advance(), nil
+}
+
+func (p *effectParser) expect(kind effectTokenKind, what string) (effectToken, error) {
+	if p.peek().kind != kind {
+		return p.peek(), effectErrorf(p.peek(), "expected %s but found %s", what, p.peek().describe())
+	}
+	return p.advance(), nil
+}
+
+func (p *effectParser) parseStatement() (effectStatement, error) {
+	var statement effectStatement
+	if _, err := p.expectWord("on"); err != nil {
+		return statement, effectErrorf(p.peek(), "expected an effect starting with \"on\" but found %s", p.peek().describe())
+	}
+	trigger, err := p.expect(effectTokenWord, "a trigger")
+	if err != nil {
+		return statement, err
+	}
+	if !slices.Contains(CardEffectTypes, CardEffectType(trigger.text)) {
+		return statement, effectErrorf(trigger, "unknown trigger %q, expected one of %v", trigger.text, CardEffectTypes)
+	}
+	statement.trigger = trigger
+
+	if p.isWord("if") {
+		p.advance()
+		statement.condition = p.advance().condition
+	}
+	if _, err := p.expectWord("then"); err != nil {
+		return statement, err
+	}
+
+	for {
+		action, err := p.parseAction()
+		if err != nil {
+			return statement, err
+		}
+		statement.actions = append(statement.actions, action)
+		if p.peek().kind != effectTokenComma {
+			break
+		}
+		p.advance()
+	}
+	if p.peek().kind != effectTokenEOF && !p.isWord("on") {
+		return statement, effectErrorf(p.peek(), "expected \",\" or a new effect but found %s", p.peek().describe())
+	}
+	return statement, nil
+}
+
+func (p *effectParser) parseAction() (effectAction, error) {
+	var action effectAction
+	verb, err := p.expect(effectTokenWord, "an action")
+	if err != nil {
+		return action, err
+	}
+	action.verb = verb
+	if p.peek().kind == effectTokenNumber {
+		amount := p.advance()
+		action.amount = &amount
+	}
+	if p.isWord("for") {
+		p.advance()
+		target, err := p.expect(effectTokenWord, `"self" or "opponent"`)
+		if err != nil {
+			return action, err
+		}
+		switch target.text {
+		case "self":
+		case "opponent":
+			action.forOpponent = true
+		default:
+			return action, effectErrorf(target, "expected \"self\" or \"opponent\" but found %s", target.describe())
+		}
+	}
+	if p.isWord("quick") {
+		p.advance()
+		action.quick = true
+	}
+	return action, nil
+}
+
+func compileEffectStatement(statement effectStatement) (CardEffect, error) {
+	effect := CardEffect{Condition: statement.condition}
+	for _, action := range statement.actions {
+		if err := compileEffectAction(action, &effect); err != nil {
+			return effect, err
+		}
+	}
+	return effect, nil
+}
+
+func compileEffectAction(action effectAction, effect *CardEffect) error {
+	base := EffectAttributeBase{IsQuickEffect: action.quick, ForOpponent: action.forOpponent}
+	if action.verb.text == EffectActionSwapPosition {
+		if action.amount != nil {
+			return effectErrorf(*action.amount, "%s does not take an amount", EffectActionSwapPosition)
+		}
+		effect.SwapPositionEffectAttributes = append(effect.SwapPositionEffectAttributes, SwapPositionEffectAttributes{EffectAttributeBase: base})
+		return nil
+	}
+
+	var amount int
+	switch action.verb.text {
+	case EffectActionGainValue, EffectActionGainPoints, EffectActionLoseValue, EffectActionLosePoints, EffectActionDraw, EffectActionDiscard:
+		if action.amount == nil {
+			return effectErrorf(action.verb, "%s needs an amount", action.verb.text)
+		}
+		var err error
+		if amount, err = strconv.Atoi(action.amount.text); err != nil || amount <= 0 {
+			return effectErrorf(*action.amount, "amount must be a positive whole number but found %s", action.amount.text)
+		}
+	default:
+		return effectErrorf(action.verb, "unknown action %q", action.verb.text)
+	}
+
+	switch action.verb.text {
+	case EffectActionGainValue:
+		effect.GainValueEffectAttributes = append(effect.GainValueEffectAttributes, GainValueEffectAttributes{EffectAttributeBase: base, Amount: amount})
+	case EffectActionGainPoints:
+		effect.GainPointsEffectAttributes = append(effect.GainPointsEffectAttributes, GainPointsEffectAttributes{EffectAttributeBase: base, Amount: amount})
+	case EffectActionLoseValue:
+		effect.LoseValueEffectAttributes = append(effect.LoseValueEffectAttributes, LoseValueEffectAttributes{EffectAttributeBase: base, Amount: amount})
+	case EffectActionLosePoints:
+		effect.LosePointsEffectAttributes = append(effect.LosePointsEffectAttributes, LoseEffectAttributes{EffectAttributeBase: base, Amount: amount})
+	case EffectActionDraw:
+		effect.DrawEffectAttributes = append(effect.DrawEffectAttributes, DrawEffectAttributes{EffectAttributeBase: base, Amount: amount})
+	case EffectActionDiscard:
+		effect.DiscardEffectAttributes = append(effect.DiscardEffectAttributes, DiscardEffectAttributes{EffectAttributeBase: base, Amount: amount})
+	}
+	return nil
+}
+
+func effectErrorf(token effectToken, format string, args ...any) error {
+	return &EffectSyntaxError{Line: token.line, Column: token.column, Message: fmt.Sprintf(format, args...)}
+}
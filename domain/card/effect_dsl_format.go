@@ -0,0 +1,100 @@
+package card
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// FormatCardEffects writes a card's effects as an effect script
+func FormatCardEffects(c Card) (string, error) {
+	var effects SeralizableFaceCardEffectData
+	for _, effectType := range CardEffectTypes {
+		*effects.effectList(effectType) = c.GetEffects(effectType)
+	}
+	return FormatEffects(effects)
+}
+
+// FormatEffects writes effects as an effect script, one statement per line,
+// grouped by trigger. CompileEffects turns the script back into effects that
+// evaluate the same way.
+func FormatEffects(effects SeralizableFaceCardEffectData) (string, error) {
+	var lines []string
+	for _, effectType := range CardEffectTypes {
+		for i, effect := range *effects.effectList(effectType) {
+			line, err := FormatEffect(effectType, effect)
+			if err != nil {
+				return "", utils.NewInvalidArgumentError(fmt.Sprintf("on_%s_effects[%d]: %s", effectType, i, utils.GetErrorMessage(err)))
+			}
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// FormatEffect writes a single effect as an effect script statement
+func FormatEffect(effectType CardEffectType, effect CardEffect) (string, error) {
+	var b strings.Builder
+	b.WriteString("on ")
+	b.WriteString(string(effectType))
+
+	condition, err := booleanexpression.Format(effect.Condition)
+	if err != nil {
+		return "", err
+	}
+	if condition != "" {
+		b.WriteString(" if ")
+		b.WriteString(condition)
+	}
+
+	actions := formatEffectActions(effect)
+	if len(actions) == 0 {
+		return "", utils.NewInvalidArgumentError("effect does nothing")
+	}
+	b.WriteString(" then ")
+	b.WriteString(strings.Join(actions, ", "))
+	return b.String(), nil
+}
+
+func formatEffectActions(effect CardEffect) []string {
+	var actions []string
+	add := func(verb string, amount *int, base EffectAttributeBase) {
+		action := verb
+		if amount != nil {
+			action += " " + strconv.Itoa(*amount)
+		}
+		if base.ForOpponent {
+			action += " for opponent"
+		}
+		if base.IsQuickEffect {
+			action += " quick"
+		}
+		actions = append(actions, action)
+	}
+
+	for _, a := range effect.GainValueEffectAttributes {
+		add(EffectActionGainValue, &a.Amount, a.EffectAttributeBase)
+	}
+	for _, a := range effect.GainPointsEffectAttributes {
+		add(EffectActionGainPoints, &a.Amount, a.EffectAttributeBase)
+	}
+	for _, a := range effect.SwapPositionEffectAttributes {
+		add(EffectActionSwapPosition, nil, a.EffectAttributeBase)
+	}
+	for _, a := range effect.LoseValueEffectAttributes {
+		add(EffectActionLoseValue, &a.Amount, a.EffectAttributeBase)
+	}
+	for _, a := range effect.LosePointsEffectAttributes {
+		add(EffectActionLosePoints, &a.Amount, a.EffectAttributeBase)
+	}
+	for _, a := range effect.DrawEffectAttributes {
+		add(EffectActionDraw, &a.Amount, a.EffectAttributeBase)
+	}
+	for _, a := range effect.DiscardEffectAttributes {
+		add(EffectActionDiscard, &a.Amount, a.EffectAttributeBase)
+	}
+	return actions
+}
@@ -0,0 +1,175 @@
+package card
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+)
+
+type effectTokenKind int
+
+const (
+	effectTokenEOF effectTokenKind = iota
+	effectTokenWord
+	effectTokenNumber
+	effectTokenComma
+	effectTokenCondition
+)
+
+type effectToken struct {
+	kind      effectTokenKind
+	text      string
+	condition booleanexpression.BooleanExpression // Set on effectTokenCondition
+	line      int
+	column    int
+}
+
+func (t effectToken) describe() string {
+	switch t.kind {
+	case effectTokenEOF:
+		return "end of input"
+	case effectTokenCondition:
+		return "a condition"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// EffectSyntaxError points at the place in an effect script that could not
+// be parsed or compiled. Lines and columns start at 1.
+type EffectSyntaxError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *EffectSyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+type effectLexer struct {
+	source []rune
+	offset int
+	line   int
+	column int
+}
+
+// lexEffects splits an effect script into tokens. Whitespace separates
+// tokens and "#" starts a comment that runs to the end of the line. The
+// condition after "if" is read by booleanexpression.ParsePrefix and becomes
+// a single token.
+func lexEffects(source string) ([]effectToken, error) {
+	l := &effectLexer{source: []rune(source), line: 1, column: 1}
+	var tokens []effectToken
+	for {
+		token, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+		if token.kind == effectTokenEOF {
+			return tokens, nil
+		}
+		if token.kind == effectTokenWord && token.text == "if" {
+			condition, err := l.readCondition()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, condition)
+		}
+	}
+}
+
+func (l *effectLexer) peek() rune {
+	if l.offset >= len(l.source) {
+		return 0
+	}
+	return l.source[l.offset]
+}
+
+func (l *effectLexer) advance() rune {
+	r := l.source[l.offset]
+	l.offset++
+	if r == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+	return r
+}
+
+func (l *effectLexer) next() (effectToken, error) {
+	l.skipSpaceAndComments()
+	token := effectToken{line: l.line, column: l.column}
+	if l.offset >= len(l.source) {
+		token.kind = effectTokenEOF
+		return token, nil
+	}
+
+	switch r := l.peek(); {
+	case unicode.IsDigit(r) || r == '-':
+		token.kind = effectTokenNumber
+		token.text = l.readWhile(func(r rune) bool { return r == '-' || r == '.' || unicode.IsDigit(r) })
+	case isEffectWordRune(r):
+		token.kind = effectTokenWord
+		token.text = l.readWhile(isEffectWordRune)
+	case r == ',':
+		token.kind = effectTokenComma
+		token.text = string(l.advance())
+	default:
+		return token, effectErrorf(token, "unexpected character %q", r)
+	}
+	return token, nil
+}
+
+func (l *effectLexer) readCondition() (effectToken, error) {
+	token := effectToken{kind: effectTokenCondition, line: l.line, column: l.column}
+	rest := string(l.source[l.offset:])
+	condition, end, err := booleanexpression.ParsePrefix(rest)
+	if err != nil {
+		var parseErr *booleanexpression.ParseError
+		if !errors.As(err, &parseErr) {
+			return token, err
+		}
+		at := effectToken{line: token.line + parseErr.Line - 1, column: parseErr.Column}
+		if parseErr.Line == 1 {
+			at.column += token.column - 1
+		}
+		return token, effectErrorf(at, "%s", parseErr.Message)
+	}
+	for range []rune(rest[:end]) {
+		l.advance()
+	}
+	token.condition = condition
+	return token, nil
+}
+
+func (l *effectLexer) skipSpaceAndComments() {
+	for l.offset < len(l.source) {
+		r := l.peek()
+		switch {
+		case unicode.IsSpace(r):
+			l.advance()
+		case r == '#':
+			for l.offset < len(l.source) && l.peek() != '\n' {
+				l.advance()
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *effectLexer) readWhile(accept func(rune) bool) string {
+	start := l.offset
+	for l.offset < len(l.source) && accept(l.peek()) {
+		l.advance()
+	}
+	return string(l.source[start:l.offset])
+}
+
+func isEffectWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,153 @@
+package card_test
+
+import (
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *CardTestSuite) TestCompileEffects() {
+	s.Run("should compile a statement into a card effect", func() {
+		effects, err := card.CompileEffects(`on reveal if $.self.points < 5 then gain_points 2 for opponent quick`)
+		require.NoError(s.T(), err)
+
+		require.Len(s.T(), effects.OnRevealEffects, 1)
+		assert.Equal(s.T(), card.CardEffect{
+			Condition: booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+				booleanexpression.NewNumericCondition("self.points", booleanexpression.OperatorLessThan, 5),
+			),
+			GainPointsEffectAttributes: []card.GainPointsEffectAttributes{
+				{EffectAttributeBase: card.EffectAttributeBase{IsQuickEffect: true, ForOpponent: true}, Amount: 2},
+			},
+		}, effects.OnRevealEffects[0])
+	})
+
+	s.Run("should group conditions with and binding tighter than or", func() {
+		effects, err := card.CompileEffects(`
+			# Face cards punish a player who is ahead
+			on war if $.card.type == "face" and ($.round > 3 or $.opponent.has_drawn == true) or $.self.hand_size >= 4
+			then draw 1, discard 1 for opponent
+		`)
+		require.NoError(s.T(), err)
+
+		require.Len(s.T(), effects.OnWarEffects, 1)
+		effect := effects.OnWarEffects[0]
+		assert.Equal(s.T(), booleanexpression.NewBooleanExpression(booleanexpression.OperatorOR,
+			booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+				booleanexpression.NewStringCondition("card.type", booleanexpression.OperatorEqual, "face"),
+				booleanexpression.NewBooleanExpression(booleanexpression.OperatorOR,
+					booleanexpression.NewNumericCondition("round", booleanexpression.OperatorGreaterThan, 3),
+					booleanexpression.NewBooleanCondition("opponent.has_drawn", booleanexpression.OperatorEqual, true),
+				),
+			),
+			booleanexpression.NewNumericCondition("self.hand_size", booleanexpression.OperatorGreaterEqual, 4),
+		), effect.Condition)
+		assert.Equal(s.T(), []card.DrawEffectAttributes{{Amount: 1}}, effect.DrawEffectAttributes)
+		assert.Equal(s.T(), []card.DiscardEffectAttributes{{EffectAttributeBase: card.EffectAttributeBase{ForOpponent: true}, Amount: 1}}, effect.DiscardEffectAttributes)
+	})
+
+	s.Run("should compile several statements in order", func() {
+		effects, err := card.CompileEffects(`
+			on place then swap_position
+			on place if $.trigger contains_any ["war", "reveal"] then gain_value 3
+			on discard then lose_points 1 for opponent
+		`)
+		require.NoError(s.T(), err)
+
+		require.Len(s.T(), effects.OnPlaceEffects, 2)
+		assert.Len(s.T(), effects.OnPlaceEffects[0].SwapPositionEffectAttributes, 1)
+		assert.Equal(s.T(), booleanexpression.NewArrayCondition("trigger", booleanexpression.OperatorContainsAny, []any{"war", "reveal"}), effects.OnPlaceEffects[1].Condition.Conditions[0])
+		assert.Empty(s.T(), effects.OnPlaceEffects[0].Condition.Conditions)
+		require.Len(s.T(), effects.OnDiscardEffects, 1)
+		assert.Equal(s.T(), 1, effects.OnDiscardEffects[0].LosePointsEffectAttributes[0].Amount)
+	})
+
+	s.Run("should report the line and column of a mistake", func() {
+		tests := []struct {
+			source  string
+			line    int
+			column  int
+			message string
+		}{
+			{`on reveal then gain_points`, 1, 16, "gain_points needs an amount"},
+			{`on flip then draw 1`, 1, 4, `unknown trigger "flip"`},
+			{"on war\n  if $.self.points < 5\n  then draw 0", 3, 13, "amount must be a positive whole number"},
+			{`on war if $.self.points < 5 then draw 1 on`, 1, 43, `expected a trigger`},
+			{`on war if $.card.type > "face" then draw 1`, 1, 23, "strings can only be compared with == or !="},
+			{`on war if ($.round > 3 then draw 1`, 1, 24, `expected ")"`},
+			{`on war if $.round is 3 then draw 1`, 1, 19, `expected "then" but found "is"`},
+			{`on war if $.round = 3 then draw 1`, 1, 19, `unknown operator "="`},
+			{`on war if $.round > "3 then draw 1`, 1, 21, "unterminated string"},
+			{`on war then draw 1 draw 2`, 1, 20, `expected "," or a new effect`},
+			{`on war then swap_position 2`, 1, 27, "swap_position does not take an amount"},
+			{`on war then fly 2`, 1, 13, `unknown action "fly"`},
+			{`on war if $. == 1 then draw 1`, 1, 13, "expected a path segment"},
+			{`draw 1`, 1, 1, `expected an effect starting with "on"`},
+		}
+		for _, test := range tests {
+			_, err := card.CompileEffects(test.source)
+			var syntaxErr *card.EffectSyntaxError
+			require.ErrorAs(s.T(), err, &syntaxErr, test.source)
+			assert.Equal(s.T(), test.line, syntaxErr.Line, test.source)
+			assert.Equal(s.T(), test.column, syntaxErr.Column, test.source)
+			assert.Contains(s.T(), syntaxErr.Message, test.message, test.source)
+		}
+	})
+}
+
+func (s *CardTestSuite) TestFormatEffects() {
+	s.Run("should round trip an effect script", func() {
+		source := `on place if $.self.has_swapped != true then swap_position quick
+on reveal if $.self.points < 5 then gain_points 2 for opponent quick
+on reveal if ($.card.value >= 10 and $.card.type != "face") or $.round == 1 then gain_value 1, lose_value 2 for opponent
+on war if $.self.points > -1.5 and ($.phase == "war" or $.opponent.rounds_won <= 2) then lose_points 1, draw 2, discard 1 for opponent
+on war if not $.self.has_drawn == true xor $.self.points > $.opponent.points then gain_value 2
+on discard if $.trigger not_contains ["draw", 1, true] then draw 1
+on discard if $.round between [2, 4] and ($.phase starts_with "w" or $.card.value in [11, 12]) then discard 1`
+
+		effects, err := card.CompileEffects(source)
+		require.NoError(s.T(), err)
+		formatted, err := card.FormatEffects(effects)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), source, formatted)
+
+		recompiled, err := card.CompileEffects(formatted)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), effects, recompiled)
+	})
+
+	s.Run("should format an existing card", func() {
+		c := newNumberCard(1, 7)
+		c.OnWarEffects = []card.CardEffect{{
+			Condition: booleanexpression.NewBooleanExpression(booleanexpression.OperatorOR,
+				booleanexpression.NewNumericCondition("self.points", booleanexpression.OperatorLessThan, 5),
+				booleanexpression.NewStringCondition("card.type", booleanexpression.OperatorEqual, `the "best"`),
+			),
+			DrawEffectAttributes: []card.DrawEffectAttributes{{Amount: 2}},
+		}}
+
+		formatted, err := card.FormatCardEffects(c)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), `on war if $.self.points < 5 or $.card.type == "the \"best\"" then draw 2`, formatted)
+
+		effects, err := card.CompileEffects(formatted)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), c.OnWarEffects, effects.OnWarEffects)
+	})
+
+	s.Run("should reject effects the script cannot express", func() {
+		_, err := card.FormatEffect(card.CardEffectTypeWar, card.CardEffect{})
+		assert.ErrorContains(s.T(), err, "effect does nothing")
+
+		c := newNumberCard(1, 7)
+		c.OnDrawEffects = []card.CardEffect{{
+			Condition: booleanexpression.NewBooleanExpression(booleanexpression.OperatorAND,
+				booleanexpression.NewNumericCondition("self.hand size", booleanexpression.OperatorLessThan, 5),
+			),
+			DrawEffectAttributes: []card.DrawEffectAttributes{{Amount: 1}},
+		}}
+		_, err = card.FormatCardEffects(c)
+		assert.ErrorContains(s.T(), err, `on_draw_effects[0]: path "self.hand size" cannot be written`)
+	})
+}
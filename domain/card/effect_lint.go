@@ -0,0 +1,131 @@
+package card
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// The largest amounts an effect can use. Anything bigger would decide a game
+// on its own.
+const (
+	MaxEffectValueAmount  = MaxCardNumber // A number card's worth of War value
+	MaxEffectPointsAmount = 5
+	MaxEffectCardsAmount  = 5 // Cards drawn or discarded, the largest hand limit
+)
+
+// EffectLintProblem is something LintCard found wrong with one of a card's
+// effects
+type EffectLintProblem struct {
+	CardID   SerializableCardID             `json:"card_id"`
+	Effect   string                         `json:"effect,omitempty"` // e.g. on_war_effects[0], empty for the card as a whole
+	Severity booleanexpression.LintSeverity `json:"severity"`
+	Message  string                         `json:"message"`
+}
+
+func (p EffectLintProblem) String() string {
+	if p.Effect == "" {
+		return fmt.Sprintf("card %d: %s: %s", p.CardID, p.Severity, p.Message)
+	}
+	return fmt.Sprintf("card %d %s: %s: %s", p.CardID, p.Effect, p.Severity, p.Message)
+}
+
+// LintCard checks a card's effects beyond what ValidateCard requires. Every
+// condition is linted against the schema of the data effects are evaluated
+// against, and every effect is checked for amounts above the maximums and for
+// gaining and losing the same thing for the same player.
+func LintCard(c Card, schema *booleanexpression.Schema) []EffectLintProblem {
+	var problems []EffectLintProblem
+	for _, effectType := range CardEffectTypes {
+		for i, effect := range c.GetEffects(effectType) {
+			add := func(severity booleanexpression.LintSeverity, format string, args ...any) {
+				problems = append(problems, EffectLintProblem{
+					CardID:   c.GetID(),
+					Effect:   fmt.Sprintf("on_%s_effects[%d]", effectType, i),
+					Severity: severity,
+					Message:  fmt.Sprintf(format, args...),
+				})
+			}
+			for _, problem := range booleanexpression.Lint(effect.Condition, schema) {
+				add(problem.Severity, "condition %s: %s", problem.Condition, problem.Message)
+			}
+			for _, problem := range effectAttributeProblems(effect) {
+				add(booleanexpression.LintSeverityError, "%s", problem)
+			}
+		}
+	}
+	return problems
+}
+
+// ValidateCardEffects returns the errors LintCard finds as one error.
+// Warnings do not stop a card from being saved.
+func ValidateCardEffects(c Card, schema *booleanexpression.Schema) error {
+	var messages []string
+	for _, problem := range LintCard(c, schema) {
+		if problem.Severity == booleanexpression.LintSeverityError {
+			messages = append(messages, fmt.Sprintf("%s: %s", problem.Effect, problem.Message))
+		}
+	}
+	if len(messages) > 0 {
+		return utils.NewInvalidArgumentError(fmt.Sprintf("card %d has invalid effects: %s", c.GetID(), strings.Join(messages, "; ")))
+	}
+	return nil
+}
+
+func effectAttributeProblems(effect CardEffect) []string {
+	var problems []string
+	checkAmounts := func(action string, maximum int, amounts []int) {
+		for _, amount := range amounts {
+			if amount > maximum {
+				problems = append(problems, fmt.Sprintf("%s %d is more than the most an effect can use, %d", action, amount, maximum))
+			}
+		}
+	}
+
+	// Amounts are grouped by target, false for the card's owner and true for
+	// the opponent
+	gainValue, loseValue := map[bool][]int{}, map[bool][]int{}
+	gainPoints, losePoints := map[bool][]int{}, map[bool][]int{}
+	cards := map[string][]int{}
+	for _, a := range effect.GainValueEffectAttributes {
+		gainValue[a.ForOpponent] = append(gainValue[a.ForOpponent], a.Amount)
+	}
+	for _, a := range effect.LoseValueEffectAttributes {
+		loseValue[a.ForOpponent] = append(loseValue[a.ForOpponent], a.Amount)
+	}
+	for _, a := range effect.GainPointsEffectAttributes {
+		gainPoints[a.ForOpponent] = append(gainPoints[a.ForOpponent], a.Amount)
+	}
+	for _, a := range effect.LosePointsEffectAttributes {
+		losePoints[a.ForOpponent] = append(losePoints[a.ForOpponent], a.Amount)
+	}
+	for _, a := range effect.DrawEffectAttributes {
+		cards[EffectActionDraw] = append(cards[EffectActionDraw], a.Amount)
+	}
+	for _, a := range effect.DiscardEffectAttributes {
+		cards[EffectActionDiscard] = append(cards[EffectActionDiscard], a.Amount)
+	}
+
+	for _, forOpponent := range []bool{false, true} {
+		checkAmounts(EffectActionGainValue, MaxEffectValueAmount, gainValue[forOpponent])
+		checkAmounts(EffectActionLoseValue, MaxEffectValueAmount, loseValue[forOpponent])
+		checkAmounts(EffectActionGainPoints, MaxEffectPointsAmount, gainPoints[forOpponent])
+		checkAmounts(EffectActionLosePoints, MaxEffectPointsAmount, losePoints[forOpponent])
+
+		target := "self"
+		if forOpponent {
+			target = "opponent"
+		}
+		if len(gainValue[forOpponent]) > 0 && len(loseValue[forOpponent]) > 0 {
+			problems = append(problems, fmt.Sprintf("%s and %s for %s cancel each other out", EffectActionGainValue, EffectActionLoseValue, target))
+		}
+		if len(gainPoints[forOpponent]) > 0 && len(losePoints[forOpponent]) > 0 {
+			problems = append(problems, fmt.Sprintf("%s and %s for %s cancel each other out", EffectActionGainPoints, EffectActionLosePoints, target))
+		}
+	}
+	checkAmounts(EffectActionDraw, MaxEffectCardsAmount, cards[EffectActionDraw])
+	checkAmounts(EffectActionDiscard, MaxEffectCardsAmount, cards[EffectActionDiscard])
+	return problems
+}
@@ -0,0 +1,63 @@
+package card_test
+
+import (
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *CardTestSuite) TestLintCard() {
+	withEffects := func(script string) *card.SerializableNumberCard {
+		effects, err := card.CompileEffects(script)
+		require.NoError(s.T(), err)
+		c := newNumberCard(1, 7)
+		c.SeralizableFaceCardEffectData = effects
+		return c
+	}
+	schema := game.EffectSnapshotSchema()
+
+	s.Run("should accept effects that make sense", func() {
+		c := withEffects(`
+			on reveal if $.self.points < 5 then gain_points 2, lose_points 2 for opponent
+			on war if $.card.type == "face" then gain_value 3, lose_value 3 for opponent, draw 1, discard 1`)
+		assert.Empty(s.T(), card.LintCard(c, schema))
+		assert.NoError(s.T(), card.ValidateCardEffects(c, schema))
+	})
+
+	s.Run("should report conditions that do not fit the snapshot", func() {
+		c := withEffects(`
+			on reveal if $.self.score < 5 then gain_points 2
+			on war if $.round >= 0 then draw 1`)
+		problems := card.LintCard(c, schema)
+		require.Len(s.T(), problems, 2)
+		assert.Equal(s.T(), "on_reveal_effects[0]", problems[0].Effect)
+		assert.Equal(s.T(), booleanexpression.LintSeverityError, problems[0].Severity)
+		assert.Contains(s.T(), problems[0].Message, `condition $.self.score < 5: $.self has no field "score"`)
+		assert.Equal(s.T(), "on_war_effects[0]", problems[1].Effect)
+		assert.Equal(s.T(), booleanexpression.LintSeverityWarning, problems[1].Severity)
+	})
+
+	s.Run("should report contradictory attributes and amounts that are too big", func() {
+		c := withEffects(`
+			on place then gain_value 2, lose_value 1
+			on swap then gain_points 6 for opponent, lose_points 1 for opponent, draw 9`)
+		var messages []string
+		for _, problem := range card.LintCard(c, schema) {
+			messages = append(messages, problem.String())
+		}
+		assert.Equal(s.T(), []string{
+			"card 1 on_swap_effects[0]: error: gain_points 6 is more than the most an effect can use, 5",
+			"card 1 on_swap_effects[0]: error: gain_points and lose_points for opponent cancel each other out",
+			"card 1 on_swap_effects[0]: error: draw 9 is more than the most an effect can use, 5",
+			"card 1 on_place_effects[0]: error: gain_value and lose_value for self cancel each other out",
+		}, messages)
+	})
+
+	s.Run("should only fail validation on errors", func() {
+		assert.NoError(s.T(), card.ValidateCardEffects(withEffects(`on war if $.round >= 0 then draw 1`), schema))
+		err := card.ValidateCardEffects(withEffects(`on war if $.phase == "battle" then draw 1`), schema)
+		assert.ErrorContains(s.T(), err, `on_war_effects[0]: condition $.phase == "battle": can never be true`)
+	})
+}
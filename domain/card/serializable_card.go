@@ -1,14 +1,21 @@
 package card
 
 import (
+	"cmp"
+
 	"github.com/coopersmall/subswag/domain"
 	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/coopersmall/subswag/utils"
 )
 
 type Card interface {
 	GetID() SerializableCardID
+	GetVersion() int
 	GetType() SerializableCardType
 	GetMetadata() *domain.Metadata
+	GetEffects(effectType CardEffectType) []CardEffect
+	CompileConditions() error
+	SetVersion(version int, metadata *domain.Metadata)
 }
 
 type SerializableFaceCard struct {
@@ -33,6 +40,7 @@ func (n *SerializableNumberCard) GetType() SerializableCardType {
 
 type SerializableCardBaseData struct {
 	ID                            SerializableCardID `json:"id" validate:"required,gt=0"`
+	Version                       int                `json:"version" tstype:"number"` // Bumped by the catalog on every change
 	SerializableCardData          `json:",inline" validate:"required" tstype:",extends"`
 	SeralizableFaceCardEffectData `json:",inline" validate:"required" tstype:",extends"`
 	Metadata                      *domain.Metadata `json:"metadata" validate:"required" tstype:"Metadata"`
@@ -42,10 +50,64 @@ func (c *SerializableCardBaseData) GetID() SerializableCardID {
 	return c.ID
 }
 
+func (c *SerializableCardBaseData) GetVersion() int {
+	return c.Version
+}
+
 func (c *SerializableCardBaseData) GetMetadata() *domain.Metadata {
 	return c.Metadata
 }
 
+// SetVersion stamps the card as a new catalog version
+func (c *SerializableCardBaseData) SetVersion(version int, metadata *domain.Metadata) {
+	c.Version = version
+	c.Metadata = metadata
+}
+
+// GetEffects returns the effects triggered by the given event
+func (c *SerializableCardBaseData) GetEffects(effectType CardEffectType) []CardEffect {
+	switch effectType {
+	case CardEffectTypeDraw:
+		return c.OnDrawEffects
+	case CardEffectTypeSwap:
+		return c.OnSwapEffects
+	case CardEffectTypePlace:
+		return c.OnPlaceEffects
+	case CardEffectTypeReveal:
+		return c.OnRevealEffects
+	case CardEffectTypeWar:
+		return c.OnWarEffects
+	case CardEffectTypeDiscard:
+		return c.OnDiscardEffects
+	}
+	return nil
+}
+
+// CompileConditions compiles the condition of every effect on the card so
+// that evaluating them does not compile them again. Effects whose condition
+// does not compile are left as they are, and the first error is returned.
+func (c *SerializableCardBaseData) CompileConditions() error {
+	var firstErr error
+	for _, effects := range [][]CardEffect{
+		c.OnDrawEffects,
+		c.OnSwapEffects,
+		c.OnPlaceEffects,
+		c.OnRevealEffects,
+		c.OnWarEffects,
+		c.OnDiscardEffects,
+	} {
+		for i := range effects {
+			program, err := booleanexpression.Compile(effects[i].Condition)
+			if err != nil {
+				firstErr = cmp.Or(firstErr, err)
+				continue
+			}
+			effects[i].program = program
+		}
+	}
+	return firstErr
+}
+
 type SerializableCardData struct {
 	ArtworkURL string     `json:"artwork_url" validate:"required" tstype:"string"`
 	Suite      CardSuite  `json:"suite" validate:"required" tstype:"Suite"`
@@ -55,6 +117,8 @@ type SerializableCardData struct {
 
 type SeralizableFaceCardEffectData struct {
 	OnDrawEffects    []CardEffect `json:"on_draw_effects" validate:"required" tstype:"Array<OnDrawEffectAttributes>"`
+	OnSwapEffects    []CardEffect `json:"on_swap_effects" validate:"required" tstype:"Array<OnSwapEffectAttributes>"`
+	OnPlaceEffects   []CardEffect `json:"on_place_effects" validate:"required" tstype:"Array<OnPlaceEffectAttributes>"`
 	OnRevealEffects  []CardEffect `json:"on_reveal_effects" validate:"required" tstype:"Array<OnRevealEffectAttributes>"`
 	OnWarEffects     []CardEffect `json:"on_war_effects" validate:"required" tstype:"Array<OnWarEffectAttributes>"`
 	OnDiscardEffects []CardEffect `json:"on_discard_effects" validate:"required" tstype:"Array<OnDiscardEffectAttributes>"`
@@ -69,6 +133,22 @@ type CardEffect struct {
 	LosePointsEffectAttributes   []LoseEffectAttributes              `json:"lose_effects" validate:"required" tstype:"Array<LoseEffectAttributes>"`
 	DrawEffectAttributes         []DrawEffectAttributes              `json:"draw_effects" validate:"required" tstype:"Array<DrawEffectAttributes>"`
 	DiscardEffectAttributes      []DiscardEffectAttributes           `json:"discard_effects" validate:"required" tstype:"Array<DiscardEffectAttributes>"`
+
+	program *booleanexpression.Program // Set by CompileConditions
+}
+
+// EvaluateCondition reports whether the effect triggers for data. An empty
+// condition is always true. The condition is compiled here if its card's
+// conditions were not compiled when it was loaded.
+func (e CardEffect) EvaluateCondition(data any) (bool, error) {
+	program := e.program
+	if program == nil {
+		var err error
+		if program, err = booleanexpression.Compile(e.Condition); err != nil {
+			return false, err
+		}
+	}
+	return program.Evaluate(data)
 }
 
 type EffectAttributeBase struct {
@@ -109,3 +189,25 @@ type DiscardEffectAttributes struct {
 	EffectAttributeBase `json:",inline" validate:"required" tstype:",extends"`
 	Amount              int `json:"amount" validate:"required" tstype:"number"`
 }
+
+// UnmarshalCard decodes a face or number card using its type field
+func UnmarshalCard(data []byte) (Card, error) {
+	var base struct {
+		Type SerializableCardType `json:"type"`
+	}
+	if err := utils.Unmarshal(data, &base); err != nil {
+		return nil, err
+	}
+	switch base.Type {
+	case SerializableCardTypeFace:
+		var c SerializableFaceCard
+		err := utils.Unmarshal(data, &c)
+		return &c, err
+	case SerializableCardTypeNumber:
+		var c SerializableNumberCard
+		err := utils.Unmarshal(data, &c)
+		return &c, err
+	default:
+		return nil, utils.NewInvalidArgumentError("unknown card type")
+	}
+}
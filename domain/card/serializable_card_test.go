@@ -0,0 +1,58 @@
+package card_test
+
+import (
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *CardTestSuite) TestEffectConditions() {
+	withEffects := func(script string) *card.SerializableNumberCard {
+		effects, err := card.CompileEffects(script)
+		require.NoError(s.T(), err)
+		c := newNumberCard(1, 7)
+		c.SeralizableFaceCardEffectData = effects
+		return c
+	}
+	round := func(round int) map[string]any {
+		return map[string]any{"round": round}
+	}
+
+	s.Run("should evaluate conditions the same before and after compiling them", func() {
+		c := withEffects(`
+			on reveal if $.round > 3 then draw 1
+			on war then gain_value 1`)
+		reveal := c.GetEffects(card.CardEffectTypeReveal)[0]
+		triggered, err := reveal.EvaluateCondition(round(5))
+		require.NoError(s.T(), err)
+		assert.True(s.T(), triggered)
+
+		require.NoError(s.T(), c.CompileConditions())
+		reveal = c.GetEffects(card.CardEffectTypeReveal)[0]
+		triggered, err = reveal.EvaluateCondition(round(5))
+		require.NoError(s.T(), err)
+		assert.True(s.T(), triggered)
+		triggered, err = reveal.EvaluateCondition(round(2))
+		require.NoError(s.T(), err)
+		assert.False(s.T(), triggered)
+
+		triggered, err = c.GetEffects(card.CardEffectTypeWar)[0].EvaluateCondition(round(0))
+		require.NoError(s.T(), err)
+		assert.True(s.T(), triggered, "an empty condition is always true")
+	})
+
+	s.Run("should compile the other conditions when one of them is invalid", func() {
+		c := withEffects(`on reveal if $.round > 3 then draw 1`)
+		c.OnWarEffects = []card.CardEffect{{
+			Condition: booleanexpression.BooleanExpression{Operator: booleanexpression.OperatorNOT},
+		}}
+		assert.Error(s.T(), c.CompileConditions())
+
+		triggered, err := c.GetEffects(card.CardEffectTypeReveal)[0].EvaluateCondition(round(5))
+		require.NoError(s.T(), err)
+		assert.True(s.T(), triggered)
+		_, err = c.GetEffects(card.CardEffectTypeWar)[0].EvaluateCondition(round(5))
+		assert.Error(s.T(), err)
+	})
+}
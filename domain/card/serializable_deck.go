@@ -15,9 +15,10 @@ type SerializableDeckData struct {
 	user.UserID `json:"user_id" validate:"required" tstype:"string"`
 	CardIDs     []SerializableCardID `json:"cards" validate:"required" tstype:"Array<SerializableCardID>"`
 	Name        string               `json:"name" validate:"required" tstype:"string"`
-	Favorited   bool                 `json:"favorited" validate:"required" tstype:"boolean"`
-	GamesPlayed int                  `json:"games_played" validate:"required" tstype:"number"`
-	GamesWon    int                  `json:"games_won" validate:"required" tstype:"number"`
+	Format      DeckFormatName       `json:"format,omitempty" tstype:"string"` // The standard format when empty
+	Favorited   bool                 `json:"favorited" tstype:"boolean"`
+	GamesPlayed int                  `json:"games_played" validate:"gte=0" tstype:"number"`
+	GamesWon    int                  `json:"games_won" validate:"gte=0" tstype:"number"`
 }
 
 func NewDeck(
@@ -12,6 +12,14 @@ func NewUserSerializableCardID() UserSerializableCardID {
 	return UserSerializableCardID(utils.NewID())
 }
 
+// CardSource is how a copy of a card came into a user's collection
+type CardSource string
+
+const (
+	CardSourceGrant CardSource = "grant" // Given by an admin or a reward
+	CardSourcePack  CardSource = "pack"  // Opened from a booster pack
+)
+
 type UserSerializableCard struct {
 	ID                       UserSerializableCardID `json:"id" validate:"required,gt=0" tstype:"string"`
 	UserSerializableCardData `json:",inline" validate:"required" tstype:",extends"`
@@ -21,4 +29,14 @@ type UserSerializableCard struct {
 type UserSerializableCardData struct {
 	UserID user.UserID        `json:"user_id" validate:"required" tstype:"string"`
 	CardID SerializableCardID `json:"card_id" validate:"required" tstype:"string"`
+	Source CardSource         `json:"source,omitempty" tstype:"'grant' | 'pack',optional"`
+}
+
+// NewUserSerializableCard is one copy of a card in the user's collection
+func NewUserSerializableCard(data UserSerializableCardData) *UserSerializableCard {
+	return &UserSerializableCard{
+		ID:                       NewUserSerializableCardID(),
+		UserSerializableCardData: data,
+		Metadata:                 domain.NewMetadata(),
+	}
 }
@@ -0,0 +1,130 @@
+package card
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/coopersmall/subswag/utils"
+)
+
+const (
+	MinCardNumber = 2
+	MaxCardNumber = 10 // Aces are face cards
+)
+
+var (
+	CardSuites      = []CardSuite{CardSuiteHearts, CardSuiteDiamonds, CardSuiteClubs, CardSuiteSpades}
+	CardRarities    = []CardRarity{CardRarityCommon, CardRarityRare, CardRarityEpic, CardRarityLegendary}
+	CardTribes      = []CardTribe{CardTribeMilitary, CardTribeMagic, CardTribeTech, CardTribeNature}
+	CardEffectTypes = []CardEffectType{
+		CardEffectTypeDraw,
+		CardEffectTypeSwap,
+		CardEffectTypePlace,
+		CardEffectTypeReveal,
+		CardEffectTypeWar,
+		CardEffectTypeDiscard,
+	}
+)
+
+// ValidateCard checks everything the catalog relies on before a card can be
+// saved: its kind and value, suite, rarity and tribe, and that every effect
+// has a condition that can be evaluated and at least one attribute with a
+// positive amount. All problems are reported together.
+func ValidateCard(c Card) error {
+	var problems []string
+	addProblem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.GetID() <= 0 {
+		addProblem("id must be set")
+	}
+	var data SerializableCardData
+	switch v := c.(type) {
+	case *SerializableFaceCard:
+		data = v.SerializableCardData
+		if v.Type != SerializableCardTypeFace {
+			addProblem("type must be %q", SerializableCardTypeFace)
+		}
+		if _, ok := faceValues[strings.ToLower(v.Face)]; !ok {
+			addProblem("unknown face %q", v.Face)
+		}
+	case *SerializableNumberCard:
+		data = v.SerializableCardData
+		if v.Type != SerializableCardTypeNumber {
+			addProblem("type must be %q", SerializableCardTypeNumber)
+		}
+		if v.Number < MinCardNumber || v.Number > MaxCardNumber {
+			addProblem("number must be between %d and %d", MinCardNumber, MaxCardNumber)
+		}
+	default:
+		addProblem("unknown card type %q", c.GetType())
+	}
+	if data.ArtworkURL == "" {
+		addProblem("artwork_url must be set")
+	}
+	if !slices.Contains(CardSuites, data.Suite) {
+		addProblem("unknown suite %q", data.Suite)
+	}
+	if !slices.Contains(CardRarities, data.Rarity) {
+		addProblem("unknown rarity %q", data.Rarity)
+	}
+	if !slices.Contains(CardTribes, data.Tribe) {
+		addProblem("unknown tribe %q", data.Tribe)
+	}
+
+	for _, effectType := range CardEffectTypes {
+		for i, effect := range c.GetEffects(effectType) {
+			for _, problem := range effectProblems(effect) {
+				addProblem("on_%s_effects[%d]: %s", effectType, i, problem)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return utils.NewInvalidArgumentError(fmt.Sprintf("card %d is invalid: %s", c.GetID(), strings.Join(problems, "; ")))
+	}
+	return nil
+}
+
+func effectProblems(effect CardEffect) []string {
+	var problems []string
+	if err := booleanexpression.Validate(effect.Condition); err != nil {
+		problems = append(problems, utils.GetErrorMessage(err))
+	}
+
+	amounts := map[string][]int{}
+	for _, a := range effect.GainValueEffectAttributes {
+		amounts["gain_value_effects"] = append(amounts["gain_value_effects"], a.Amount)
+	}
+	for _, a := range effect.GainPointsEffectAttributes {
+		amounts["gain_points_effects"] = append(amounts["gain_points_effects"], a.Amount)
+	}
+	for _, a := range effect.LoseValueEffectAttributes {
+		amounts["lose_value_effects"] = append(amounts["lose_value_effects"], a.Amount)
+	}
+	for _, a := range effect.LosePointsEffectAttributes {
+		amounts["lose_effects"] = append(amounts["lose_effects"], a.Amount)
+	}
+	for _, a := range effect.DrawEffectAttributes {
+		amounts["draw_effects"] = append(amounts["draw_effects"], a.Amount)
+	}
+	for _, a := range effect.DiscardEffectAttributes {
+		amounts["discard_effects"] = append(amounts["discard_effects"], a.Amount)
+	}
+	for _, field := range slices.Sorted(maps.Keys(amounts)) {
+		for i, amount := range amounts[field] {
+			if amount <= 0 {
+				problems = append(problems, fmt.Sprintf("%s[%d]: amount must be positive", field, i))
+			}
+		}
+	}
+
+	if len(amounts) == 0 && len(effect.SwapPositionEffectAttributes) == 0 {
+		problems = append(problems, "effect does nothing")
+	}
+	return problems
+}
@@ -0,0 +1,211 @@
+package game
+
+import (
+	"slices"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/utils"
+)
+
+type CommandType string
+
+const (
+	CommandTypeDraw         CommandType = "draw"
+	CommandTypeSwap         CommandType = "swap"
+	CommandTypeDiscard      CommandType = "discard"
+	CommandTypeSelectReveal CommandType = "select_reveal"
+	CommandTypePass         CommandType = "pass"
+)
+
+// Command is an action a player takes on a game
+type Command interface {
+	GetType() CommandType
+}
+
+type DrawCommand struct {
+	Type CommandType `json:"type" validate:"required,eq=draw" tstype:"'draw'"`
+}
+
+func (c DrawCommand) GetType() CommandType {
+	return CommandTypeDraw
+}
+
+// SwapCommand swaps a card in the player's hand with one of their face-down
+// cards on the board
+type SwapCommand struct {
+	Type     CommandType             `json:"type" validate:"required,eq=swap" tstype:"'swap'"`
+	CardID   card.SerializableCardID `json:"card_id" validate:"required,gt=0"`
+	Position Position                `json:"position" tstype:"string"`
+}
+
+func (c SwapCommand) GetType() CommandType {
+	return CommandTypeSwap
+}
+
+type DiscardCommand struct {
+	Type   CommandType             `json:"type" validate:"required,eq=discard" tstype:"'discard'"`
+	CardID card.SerializableCardID `json:"card_id" validate:"required,gt=0"`
+}
+
+func (c DiscardCommand) GetType() CommandType {
+	return CommandTypeDiscard
+}
+
+// PassCommand ends the player's card action turn without drawing, swapping
+// or discarding
+type PassCommand struct {
+	Type CommandType `json:"type" validate:"required,eq=pass" tstype:"'pass'"`
+}
+
+func (c PassCommand) GetType() CommandType {
+	return CommandTypePass
+}
+
+// SelectRevealCommand picks the face-down card the player will reveal for
+// the War. The selection can be changed until the other player has selected
+// too, which ends the reveal phase.
+type SelectRevealCommand struct {
+	Type     CommandType `json:"type" validate:"required,eq=select_reveal" tstype:"'select_reveal'"`
+	Position Position    `json:"position" tstype:"string"`
+}
+
+func (c SelectRevealCommand) GetType() CommandType {
+	return CommandTypeSelectReveal
+}
+
+// ParseCommand decodes a command using its type field
+func ParseCommand(data []byte) (Command, error) {
+	var base struct {
+		Type CommandType `json:"type"`
+	}
+	if err := utils.Unmarshal(data, &base); err != nil {
+		return nil, err
+	}
+	switch base.Type {
+	case CommandTypeDraw:
+		var c DrawCommand
+		err := utils.Unmarshal(data, &c)
+		return c, err
+	case CommandTypeSwap:
+		var c SwapCommand
+		err := utils.Unmarshal(data, &c)
+		return c, err
+	case CommandTypeDiscard:
+		var c DiscardCommand
+		err := utils.Unmarshal(data, &c)
+		return c, err
+	case CommandTypeSelectReveal:
+		var c SelectRevealCommand
+		err := utils.Unmarshal(data, &c)
+		return c, err
+	case CommandTypePass:
+		var c PassCommand
+		err := utils.Unmarshal(data, &c)
+		return c, err
+	default:
+		return nil, utils.NewInvalidArgumentError("unknown command type")
+	}
+}
+
+type RejectionReason string
+
+const (
+	RejectionGameComplete     RejectionReason = "game_complete"
+	RejectionNotAPlayer       RejectionReason = "not_a_player"
+	RejectionWrongPhase       RejectionReason = "wrong_phase"
+	RejectionAlreadyActed     RejectionReason = "already_acted"
+	RejectionHandFull         RejectionReason = "hand_full"
+	RejectionDeckEmpty        RejectionReason = "deck_empty"
+	RejectionCardNotInHand    RejectionReason = "card_not_in_hand"
+	RejectionInvalidPosition  RejectionReason = "invalid_position"
+	RejectionSpaceNotOwned    RejectionReason = "space_not_owned"
+	RejectionSpaceNotFaceDown RejectionReason = "space_not_face_down"
+	RejectionUnknownCommand   RejectionReason = "unknown_command"
+)
+
+// CommandRejection explains why a command was not applied
+type CommandRejection struct {
+	Reason  RejectionReason `json:"reason"`
+	Message string          `json:"message"`
+}
+
+func newRejection(reason RejectionReason, message string) *CommandRejection {
+	return &CommandRejection{
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// CommandResult is returned for every command. Rejection is set, and the
+// state left unchanged, when the command was not applied.
+type CommandResult struct {
+	Accepted  bool              `json:"accepted"`
+	Rejection *CommandRejection `json:"rejection,omitempty"`
+	State     *GameState        `json:"state"`
+}
+
+// ValidateCommand checks a command against the phase and turn rules. Nil is
+// returned if the player at playerIndex may apply it.
+func ValidateCommand(data GameStateData, playerIndex int, cmd Command) *CommandRejection {
+	if data.IsComplete {
+		return newRejection(RejectionGameComplete, "the game is complete")
+	}
+	player := data.Players[playerIndex]
+
+	switch c := cmd.(type) {
+	case DrawCommand, SwapCommand, DiscardCommand, PassCommand:
+		if data.GamePhase != PhaseCardAction {
+			return newRejection(RejectionWrongPhase, "cards can only be drawn, swapped or discarded in the card action phase")
+		}
+		// Players get one action per turn
+		if player.HasActed() {
+			return newRejection(RejectionAlreadyActed, "an action has already been taken this turn")
+		}
+		switch c := c.(type) {
+		case DrawCommand:
+			if len(player.Hand) >= data.HandLimit {
+				return newRejection(RejectionHandFull, "hand is full")
+			}
+			if len(player.Deck) == 0 {
+				return newRejection(RejectionDeckEmpty, "deck is empty")
+			}
+		case SwapCommand:
+			if !slices.Contains(player.Hand, c.CardID) {
+				return newRejection(RejectionCardNotInHand, "card is not in hand")
+			}
+			return validateOwnFaceDownSpace(data, player, c.Position)
+		case DiscardCommand:
+			if !slices.Contains(player.Hand, c.CardID) {
+				return newRejection(RejectionCardNotInHand, "card is not in hand")
+			}
+		}
+	case SelectRevealCommand:
+		if data.GamePhase != PhaseReveal {
+			return newRejection(RejectionWrongPhase, "cards can only be selected in the reveal phase")
+		}
+		return validateOwnFaceDownSpace(data, player, c.Position)
+	default:
+		return newRejection(RejectionUnknownCommand, "unknown command")
+	}
+	return nil
+}
+
+// HasActed reports whether the player has taken their action, or passed, in
+// this turn's card action phase
+func (p PlayerState) HasActed() bool {
+	return p.HasDrawnThisTurn || p.HasSwappedThisTurn || p.HasDiscardedThisTurn || p.HasPassedThisTurn
+}
+
+func validateOwnFaceDownSpace(data GameStateData, player PlayerState, position Position) *CommandRejection {
+	if !data.BoardState.Contains(position) {
+		return newRejection(RejectionInvalidPosition, "position is off the board")
+	}
+	space := data.Board[position.X][position.Y]
+	if space.Card == 0 || space.Revealed {
+		return newRejection(RejectionSpaceNotFaceDown, "space has no face-down card")
+	}
+	if space.Owner != player.User {
+		return newRejection(RejectionSpaceNotOwned, "space is owned by the other player")
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package game_test
+
+import (
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *GameTestSuite) TestParseCommand() {
+	s.Run("should decode each command by its type", func() {
+		testCases := []struct {
+			data     string
+			expected game.Command
+		}{
+			{`{"type":"draw"}`, game.DrawCommand{Type: game.CommandTypeDraw}},
+			{`{"type":"pass"}`, game.PassCommand{Type: game.CommandTypePass}},
+			{`{"type":"discard","card_id":4}`, game.DiscardCommand{Type: game.CommandTypeDiscard, CardID: 4}},
+			{
+				`{"type":"swap","card_id":4,"position":"1,2"}`,
+				game.SwapCommand{Type: game.CommandTypeSwap, CardID: 4, Position: game.Position{X: 1, Y: 2}},
+			},
+			{
+				`{"type":"select_reveal","position":"3,0"}`,
+				game.SelectRevealCommand{Type: game.CommandTypeSelectReveal, Position: game.Position{X: 3, Y: 0}},
+			},
+		}
+		for _, tc := range testCases {
+			cmd, err := game.ParseCommand([]byte(tc.data))
+			require.NoError(s.T(), err, "Data: %s", tc.data)
+			assert.Equal(s.T(), tc.expected, cmd, "Data: %s", tc.data)
+		}
+	})
+
+	s.Run("should reject unknown command types", func() {
+		_, err := game.ParseCommand([]byte(`{"type":"attack"}`))
+		assert.Error(s.T(), err)
+	})
+}
+
+func (s *GameTestSuite) TestValidateCommand() {
+	newData := func() game.GameStateData {
+		data := game.GameStateData{
+			GamePhase: game.PhaseCardAction,
+			Players: [2]game.PlayerState{
+				{User: 1, Hand: []card.SerializableCardID{10, 11}, Deck: []card.SerializableCardID{12}},
+				{User: 2},
+			},
+			Rules:      game.Rules{HandLimit: 5},
+			BoardState: game.NewBoardState(4),
+		}
+		data.Board[0][0] = game.BoardSpace{Card: 20, Owner: 1}
+		data.Board[0][1] = game.BoardSpace{Card: 21, Owner: 2}
+		data.Board[0][2] = game.BoardSpace{Card: 22, Owner: 1, Revealed: true}
+		return data
+	}
+	reason := func(rejection *game.CommandRejection) game.RejectionReason {
+		if rejection == nil {
+			return ""
+		}
+		return rejection.Reason
+	}
+
+	s.Run("should allow valid card actions", func() {
+		data := newData()
+		assert.Nil(s.T(), game.ValidateCommand(data, 0, game.DrawCommand{}))
+		assert.Nil(s.T(), game.ValidateCommand(data, 0, game.DiscardCommand{CardID: 10}))
+		assert.Nil(s.T(), game.ValidateCommand(data, 0, game.SwapCommand{CardID: 11, Position: game.Position{X: 0, Y: 0}}))
+	})
+
+	s.Run("should allow one action per turn", func() {
+		data := newData()
+		data.Players[0].HasDiscardedThisTurn = true
+		assert.Equal(s.T(), game.RejectionAlreadyActed, reason(game.ValidateCommand(data, 0, game.DrawCommand{})))
+
+		data = newData()
+		assert.Nil(s.T(), game.ValidateCommand(data, 0, game.PassCommand{}))
+		data.Players[0].HasPassedThisTurn = true
+		assert.Equal(s.T(), game.RejectionAlreadyActed, reason(game.ValidateCommand(data, 0, game.DiscardCommand{CardID: 10})))
+	})
+
+	s.Run("should end a phase once both players are done with it", func() {
+		data := newData()
+		assert.False(s.T(), data.IsPhaseDone())
+		data.Players[0].HasDrawnThisTurn = true
+		assert.False(s.T(), data.IsPhaseDone())
+		data.Players[1].HasPassedThisTurn = true
+		assert.True(s.T(), data.IsPhaseDone())
+
+		data.GamePhase = game.PhaseReveal
+		data.Players[0].SelectedCard = &game.Position{X: 0, Y: 0}
+		assert.False(s.T(), data.IsPhaseDone())
+		data.Players[1].SelectedCard = &game.Position{X: 0, Y: 1}
+		assert.True(s.T(), data.IsPhaseDone())
+
+		for _, phase := range []game.GamePhase{game.PhaseSetup, game.PhaseWar, game.PhaseCleanup} {
+			assert.True(s.T(), game.GameStateData{GamePhase: phase}.IsPhaseDone(), phase)
+		}
+		data.IsComplete = true
+		assert.False(s.T(), data.IsPhaseDone())
+	})
+
+	s.Run("should enforce the phase", func() {
+		data := newData()
+		assert.Equal(s.T(), game.RejectionWrongPhase, reason(game.ValidateCommand(data, 0, game.SelectRevealCommand{})))
+		data.GamePhase = game.PhaseReveal
+		assert.Equal(s.T(), game.RejectionWrongPhase, reason(game.ValidateCommand(data, 0, game.DrawCommand{})))
+		assert.Nil(s.T(), game.ValidateCommand(data, 0, game.SelectRevealCommand{Position: game.Position{X: 0, Y: 0}}))
+	})
+
+	s.Run("should reject invalid cards and spaces", func() {
+		data := newData()
+		testCases := []struct {
+			cmd      game.Command
+			expected game.RejectionReason
+		}{
+			{game.DiscardCommand{CardID: 99}, game.RejectionCardNotInHand},
+			{game.SwapCommand{CardID: 10, Position: game.Position{X: 4, Y: 0}}, game.RejectionInvalidPosition},
+			{game.SwapCommand{CardID: 10, Position: game.Position{X: 0, Y: 1}}, game.RejectionSpaceNotOwned},
+			{game.SwapCommand{CardID: 10, Position: game.Position{X: 0, Y: 2}}, game.RejectionSpaceNotFaceDown},
+			{game.SwapCommand{CardID: 10, Position: game.Position{X: 3, Y: 3}}, game.RejectionSpaceNotFaceDown},
+		}
+		for _, tc := range testCases {
+			assert.Equal(s.T(), tc.expected, reason(game.ValidateCommand(data, 0, tc.cmd)), "Command: %+v", tc.cmd)
+		}
+	})
+
+	s.Run("should reject draws when the hand is full or the deck is empty", func() {
+		data := newData()
+		data.Players[0].Deck = nil
+		assert.Equal(s.T(), game.RejectionDeckEmpty, reason(game.ValidateCommand(data, 0, game.DrawCommand{})))
+		data.Players[0].Hand = []card.SerializableCardID{1, 2, 3, 4, 5}
+		assert.Equal(s.T(), game.RejectionHandFull, reason(game.ValidateCommand(data, 0, game.DrawCommand{})))
+	})
+
+	s.Run("should reject commands once the game is complete", func() {
+		data := newData()
+		data.IsComplete = true
+		assert.Equal(s.T(), game.RejectionGameComplete, reason(game.ValidateCommand(data, 0, game.DrawCommand{})))
+	})
+}
@@ -0,0 +1,100 @@
+package game
+
+import (
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/coopersmall/subswag/domain/card"
+)
+
+// EffectSnapshot is the data card effect conditions are evaluated against.
+// Conditions address it with JSON paths, e.g. "self.points" or "card.value".
+type EffectSnapshot struct {
+	Trigger  card.CardEffectType `json:"trigger"`
+	Phase    GamePhase           `json:"phase"`
+	Round    int                 `json:"round"`
+	Card     EffectCardSnapshot  `json:"card"`
+	Self     EffectPlayerState   `json:"self"`
+	Opponent EffectPlayerState   `json:"opponent"`
+}
+
+type EffectCardSnapshot struct {
+	ID    card.SerializableCardID   `json:"id"`
+	Type  card.SerializableCardType `json:"type"`
+	Value int                       `json:"value"`
+}
+
+type EffectPlayerState struct {
+	Points           int  `json:"points"`
+	RoundsWon        int  `json:"rounds_won"`
+	WarValueModifier int  `json:"war_value_modifier"`
+	HandSize         int  `json:"hand_size"`
+	DeckSize         int  `json:"deck_size"`
+	DiscardedSize    int  `json:"discarded_size"`
+	HasDrawn         bool `json:"has_drawn"`
+	HasSwapped       bool `json:"has_swapped"`
+	HasDiscarded     bool `json:"has_discarded"`
+}
+
+// EffectSnapshotSchema describes EffectSnapshot for linting effect
+// conditions, including the values its fields can take
+func EffectSnapshotSchema() *booleanexpression.Schema {
+	schema := booleanexpression.SchemaOf(EffectSnapshot{})
+
+	triggers := make([]string, len(card.CardEffectTypes))
+	for i, trigger := range card.CardEffectTypes {
+		triggers[i] = string(trigger)
+	}
+	schema.Property("trigger").WithEnum(triggers...)
+	schema.Property("phase").WithEnum(
+		string(PhaseSetup),
+		string(PhaseCardAction),
+		string(PhaseReveal),
+		string(PhaseWar),
+		string(PhaseCleanup),
+	)
+	schema.Property("round").WithMinimum(0)
+	schema.Property("card", "id").WithMinimum(1)
+	schema.Property("card", "type").WithEnum(string(card.SerializableCardTypeFace), string(card.SerializableCardTypeNumber))
+	schema.Property("card", "value").WithRange(card.MinCardNumber, card.MaxCardValue)
+	for _, player := range []string{"self", "opponent"} {
+		for _, count := range []string{"rounds_won", "hand_size", "deck_size", "discarded_size"} {
+			schema.Property(player, count).WithMinimum(0)
+		}
+	}
+	return schema
+}
+
+// NewEffectSnapshot builds the condition data for an effect on c, activated
+// by the player at playerIndex
+func NewEffectSnapshot(
+	data GameStateData,
+	playerIndex int,
+	c card.Card,
+	trigger card.CardEffectType,
+) EffectSnapshot {
+	return EffectSnapshot{
+		Trigger: trigger,
+		Phase:   data.GamePhase,
+		Round:   data.RoundNumber,
+		Card: EffectCardSnapshot{
+			ID:    c.GetID(),
+			Type:  c.GetType(),
+			Value: card.GetCardValue(c),
+		},
+		Self:     newEffectPlayerState(data.Players[playerIndex]),
+		Opponent: newEffectPlayerState(data.Players[1-playerIndex]),
+	}
+}
+
+func newEffectPlayerState(p PlayerState) EffectPlayerState {
+	return EffectPlayerState{
+		Points:           p.Points,
+		RoundsWon:        p.RoundsWon,
+		WarValueModifier: p.WarValueModifier,
+		HandSize:         len(p.Hand),
+		DeckSize:         len(p.Deck),
+		DiscardedSize:    len(p.DiscardedCards),
+		HasDrawn:         p.HasDrawnThisTurn,
+		HasSwapped:       p.HasSwappedThisTurn,
+		HasDiscarded:     p.HasDiscardedThisTurn,
+	}
+}
@@ -0,0 +1,80 @@
+package game_test
+
+import (
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *GameTestSuite) TestNewEffectSnapshot() {
+	numberCard := &card.SerializableNumberCard{
+		SerializableCardBaseData: card.SerializableCardBaseData{ID: 7},
+		Type:                     card.SerializableCardTypeNumber,
+		Number:                   7,
+	}
+	data := game.GameStateData{
+		GamePhase:   game.PhaseWar,
+		RoundNumber: 3,
+		Players: [2]game.PlayerState{
+			{Points: 4, Hand: []card.SerializableCardID{1, 2}, HasDrawnThisTurn: true},
+			{Points: 9, RoundsWon: 2, Deck: []card.SerializableCardID{3}},
+		},
+	}
+
+	s.Run("should describe the activator as self", func() {
+		snapshot := game.NewEffectSnapshot(data, 1, numberCard, card.CardEffectTypeWar)
+		assert.Equal(s.T(), card.CardEffectTypeWar, snapshot.Trigger)
+		assert.Equal(s.T(), game.PhaseWar, snapshot.Phase)
+		assert.Equal(s.T(), 3, snapshot.Round)
+		assert.Equal(s.T(), 7, snapshot.Card.Value)
+		assert.Equal(s.T(), 9, snapshot.Self.Points)
+		assert.Equal(s.T(), 2, snapshot.Self.RoundsWon)
+		assert.Equal(s.T(), 1, snapshot.Self.DeckSize)
+		assert.Equal(s.T(), 4, snapshot.Opponent.Points)
+		assert.Equal(s.T(), 2, snapshot.Opponent.HandSize)
+		assert.True(s.T(), snapshot.Opponent.HasDrawn)
+	})
+
+	s.Run("should be addressable by effect conditions", func() {
+		snapshot := game.NewEffectSnapshot(data, 0, numberCard, card.CardEffectTypeWar)
+		expression := booleanexpression.NewBooleanExpression(
+			booleanexpression.OperatorAND,
+			booleanexpression.NewNumericCondition("self.points", booleanexpression.OperatorLessThan, 5),
+			booleanexpression.NewNumericCondition("opponent.rounds_won", booleanexpression.OperatorEqual, 2),
+			booleanexpression.NewStringCondition("trigger", booleanexpression.OperatorEqual, "war"),
+		)
+		result, err := booleanexpression.EvaluateBooleanExpression(expression, snapshot)
+		require.NoError(s.T(), err)
+		assert.True(s.T(), result)
+	})
+}
+
+func (s *GameTestSuite) TestEffectSnapshotSchema() {
+	schema := game.EffectSnapshotSchema()
+
+	s.Run("should accept conditions on the snapshot", func() {
+		expression, err := booleanexpression.Parse(`$.trigger == "war" and $.card.type == "face" and $.self.hand_size > $.opponent.hand_size`)
+		require.NoError(s.T(), err)
+		assert.Empty(s.T(), booleanexpression.Lint(expression, schema))
+	})
+
+	s.Run("should reject paths the snapshot does not have", func() {
+		expression, err := booleanexpression.Parse(`$.self.hand > 2`)
+		require.NoError(s.T(), err)
+		problems := booleanexpression.Lint(expression, schema)
+		require.Len(s.T(), problems, 1)
+		assert.Contains(s.T(), problems[0].Message, `has no field "hand"`)
+	})
+
+	s.Run("should know the values fields can take", func() {
+		for _, input := range []string{`$.phase == "battle"`, `$.card.value > 14`, `$.self.deck_size < 0`} {
+			expression, err := booleanexpression.Parse(input)
+			require.NoError(s.T(), err)
+			problems := booleanexpression.Lint(expression, schema)
+			require.Len(s.T(), problems, 1, input)
+			assert.Equal(s.T(), "can never be true", problems[0].Message, input)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/user"
+)
+
+type GameEventType string
+
+const (
+	GameEventActionApplied  GameEventType = "action_applied"
+	GameEventPhaseChanged   GameEventType = "phase_changed"
+	GameEventCardRevealed   GameEventType = "card_revealed"
+	GameEventAutoSelected   GameEventType = "auto_selected"
+	GameEventWarResolved    GameEventType = "war_resolved"
+	GameEventEffectResolved GameEventType = "effect_resolved"
+	GameEventGameCompleted  GameEventType = "game_completed"
+	GameEventRolledBack     GameEventType = "rolled_back"
+)
+
+// GameEvent is pushed to players and spectators as a game progresses
+type GameEvent struct {
+	GameStateID GameStateID   `json:"game_state_id"`
+	Type        GameEventType `json:"type"`
+	RoundNumber int           `json:"round_number"`
+	GamePhase   GamePhase     `json:"game_phase"`
+	Data        any           `json:"data"`
+}
+
+type PhaseChangedEventData struct {
+	From GamePhase `json:"from"`
+	To   GamePhase `json:"to"`
+}
+
+type CardRevealedEventData struct {
+	Position Position                `json:"position"`
+	Card     card.SerializableCardID `json:"card"`
+	Owner    user.UserID             `json:"owner"`
+}
+
+// AutoSelectedEventData is sent when the reveal window closed before the
+// player selected a card
+type AutoSelectedEventData struct {
+	User     user.UserID `json:"user"`
+	Position Position    `json:"position"`
+}
+
+type ActionAppliedEventData struct {
+	User    user.UserID `json:"user"`
+	Command CommandType `json:"command"`
+}
+
+type GameCompletedEventData struct {
+	Winner *user.UserID `json:"winner"`
+}
+
+// RolledBackEventData is sent when the game is restored to an earlier
+// version. Version is the new version the restored state was committed as,
+// and From is the version it was restored from.
+type RolledBackEventData struct {
+	From    int `json:"from"`
+	Version int `json:"version"`
+}
+
+// CompletedGamesStream receives the game_completed event of every game, for
+// consumers that act on finished games
+const CompletedGamesStream = "games.completed"
+
+// GameEventsStream is the name of the stream a game's events are published to
+func GameEventsStream(gameStateId GameStateID) string {
+	return fmt.Sprintf("games.%d", gameStateId)
+}
+
+func NewGameEvent(gameState *GameState, eventType GameEventType, data any) *GameEvent {
+	return &GameEvent{
+		GameStateID: gameState.ID,
+		Type:        eventType,
+		RoundNumber: gameState.RoundNumber,
+		GamePhase:   gameState.GamePhase,
+		Data:        data,
+	}
+}
+
+// NewGameEvents describes what changed between before and the game's current
+// state, in the order it happened during a phase transition
+func NewGameEvents(before GameStateData, after *GameState) []*GameEvent {
+	events := make([]*GameEvent, 0)
+
+	for x, column := range after.Board {
+		for y, space := range column {
+			if space.Revealed && !before.Board[x][y].Revealed {
+				events = append(events, NewGameEvent(after, GameEventCardRevealed, CardRevealedEventData{
+					Position: Position{X: x, Y: y},
+					Card:     space.Card,
+					Owner:    space.Owner,
+				}))
+			}
+		}
+	}
+	if len(after.EffectsStack) > len(before.EffectsStack) {
+		// Events go to both players and spectators, so effects from cards
+		// that may still be hidden do not name their source
+		for _, effect := range after.EffectsStack[len(before.EffectsStack):] {
+			events = append(events, NewGameEvent(after, GameEventEffectResolved, effect.Redacted()))
+		}
+	}
+	if len(after.WarResults) > len(before.WarResults) {
+		for _, result := range after.WarResults[len(before.WarResults):] {
+			events = append(events, NewGameEvent(after, GameEventWarResolved, result))
+		}
+	}
+	if after.GamePhase != before.GamePhase {
+		events = append(events, NewGameEvent(after, GameEventPhaseChanged, PhaseChangedEventData{
+			From: before.GamePhase,
+			To:   after.GamePhase,
+		}))
+	}
+	if after.IsComplete && !before.IsComplete {
+		data := GameCompletedEventData{}
+		if after.Winner != nil {
+			data.Winner = &after.Winner.ID
+		}
+		events = append(events, NewGameEvent(after, GameEventGameCompleted, data))
+	}
+	return events
+}
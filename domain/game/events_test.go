@@ -0,0 +1,51 @@
+package game_test
+
+import (
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *GameTestSuite) TestNewGameEvents() {
+	s.Run("should describe a phase transition in order", func() {
+		before := game.GameStateData{GamePhase: game.PhaseReveal, BoardState: game.NewBoardState(4)}
+		before.Board[1][2] = game.BoardSpace{Card: 5, Owner: 1}
+
+		after := &game.GameState{ID: 9, GameStateData: before.Clone()}
+		after.GamePhase = game.PhaseWar
+		after.Board[1][2].Revealed = true
+		after.EffectsStack = []game.EffectContext{{Source: 5}}
+
+		events := game.NewGameEvents(before, after)
+		types := make([]game.GameEventType, len(events))
+		for i, event := range events {
+			types[i] = event.Type
+			assert.Equal(s.T(), game.GameStateID(9), event.GameStateID)
+		}
+		assert.Equal(s.T(), []game.GameEventType{
+			game.GameEventCardRevealed,
+			game.GameEventEffectResolved,
+			game.GameEventPhaseChanged,
+		}, types)
+		assert.Equal(s.T(), game.CardRevealedEventData{
+			Position: game.Position{X: 1, Y: 2},
+			Card:     5,
+			Owner:    1,
+		}, events[0].Data)
+	})
+
+	s.Run("should announce the winner when the game completes", func() {
+		before := game.GameStateData{GamePhase: game.PhaseCleanup, WarResults: []game.WarResult{{}}}
+		after := &game.GameState{GameStateData: before}
+		after.WarResults = append(after.WarResults, game.WarResult{RoundNumber: 1})
+		after.IsComplete = true
+		after.Winner = &user.User{ID: 2}
+
+		events := game.NewGameEvents(before, after)
+		assert.Len(s.T(), events, 2)
+		assert.Equal(s.T(), game.GameEventWarResolved, events[0].Type)
+		assert.Equal(s.T(), game.GameEventGameCompleted, events[1].Type)
+		winner := user.UserID(2)
+		assert.Equal(s.T(), game.GameCompletedEventData{Winner: &winner}, events[1].Data)
+	})
+}
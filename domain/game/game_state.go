@@ -1,58 +1,83 @@
 package game
 
 import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"time"
+
 	"github.com/coopersmall/subswag/domain"
 	"github.com/coopersmall/subswag/domain/card"
 	"github.com/coopersmall/subswag/domain/user"
 	"github.com/coopersmall/subswag/utils"
 )
 
-const (
-	RoundLimit       = 15
-	RoundTimer       = 15
-	StartingHandSize = 3
-	HandLimit        = 5
-	DeckLimit        = 52
-	DrawLimit        = 1
-)
-
 type PlayerState struct {
 	User                 user.UserID
-	Hand                 []card.SerializableCardID // Max size of 5
-	Deck                 []card.SerializableCardID // Max size of 52
-	DiscardedCards       []card.SerializableCardID // Track discarded cards
-	Points               int                       // Accumulated points from winning Wars
-	SelectedCard         *Position                 // Current card selection for War
-	RevealedCards        map[Position]bool         // Track which cards this player has seen
-	EmptySpaces          []Position                // Track empty spaces on the board
-	LastPlacedCard       card.SerializableCardID   // For auto-reveal if time runs out
-	LastDrawnCard        card.SerializableCardID   // For auto-reveal if time runs out
-	LastDiscardedCard    card.SerializableCardID   // For auto-reveal if time runs out
-	HasDrawnThisTurn     bool                      // Track if player has drawn this turn
-	HasSwappedThisTurn   bool                      // Track if player has swapped this turn
-	HasDiscardedThisTurn bool                      // Track if player has discarded this turn
+	DeckID               card.SerializableDeckID          // Deck the player brought to the game
+	Hand                 []card.SerializableCardID        // Max size of Rules.HandLimit
+	Deck                 []card.SerializableCardID        // Max size of Rules.DeckLimit
+	DiscardedCards       []card.SerializableCardID        // Track discarded cards
+	Points               int                              // Accumulated points from winning Wars
+	RoundsWon            int                              // Number of Wars won, decides the winner
+	WarValueModifier     int                              // Effect adjustments to this round's War value
+	SelectedCard         *Position                        // Current card selection for War
+	RevealedCards        map[Position]bool                // Track which cards this player has seen
+	SeenCards            map[card.SerializableCardID]bool // Every face-down card this player has placed or seen
+	EmptySpaces          []Position                       // Track empty spaces on the board
+	LastPlacedCard       card.SerializableCardID          // For auto-reveal if time runs out
+	LastDrawnCard        card.SerializableCardID          // For auto-reveal if time runs out
+	LastDiscardedCard    card.SerializableCardID          // For auto-reveal if time runs out
+	HasDrawnThisTurn     bool                             // Track if player has drawn this turn
+	HasSwappedThisTurn   bool                             // Track if player has swapped this turn
+	HasDiscardedThisTurn bool                             // Track if player has discarded this turn
+	HasPassedThisTurn    bool                             // Track if player has ended the turn without acting
 }
 
 func NewPlayerState(
 	userId user.UserID,
 	deck *card.SerializableDeck,
+	startingHandSize int,
+	r *rand.Rand,
 ) PlayerState {
-	shuffled := card.ShuffleCards(deck.CardIDs)
-	hand := shuffled[:StartingHandSize]
+	shuffled := card.ShuffleCardsWithRand(deck.CardIDs, r)
+	hand := shuffled[:startingHandSize]
 	return PlayerState{
 		User:           userId,
+		DeckID:         deck.ID,
 		Deck:           shuffled,
 		Hand:           hand,
 		Points:         0,
 		RevealedCards:  make(map[Position]bool),
+		SeenCards:      make(map[card.SerializableCardID]bool),
 		EmptySpaces:    make([]Position, 0),
 		DiscardedCards: make([]card.SerializableCardID, 0),
 	}
 }
 
+// See records that the player knows the card, so it stays known to them
+// wherever it is moved face-down
+func (p *PlayerState) See(cardId card.SerializableCardID) {
+	if p.SeenCards == nil {
+		p.SeenCards = make(map[card.SerializableCardID]bool)
+	}
+	p.SeenCards[cardId] = true
+}
+
 type Position struct {
-	X int // 0-3
-	Y int // 0-3
+	X int // 0 to Rules.BoardSize-1
+	Y int // 0 to Rules.BoardSize-1
+}
+
+// MarshalText allows positions to be used as JSON object keys
+func (p Position) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d,%d", p.X, p.Y)), nil
+}
+
+func (p *Position) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d,%d", &p.X, &p.Y)
+	return err
 }
 
 type BoardSpace struct {
@@ -80,13 +105,17 @@ func NewGameStateID() GameStateID {
 type GameState struct {
 	ID GameStateID
 	GameStateData
-	*domain.Metadata
+	Version  int64            // Bumped on every update; writes from an older version are rejected
+	Metadata *domain.Metadata // Not embedded: its MarshalJSON would be promoted and encode the game as only its metadata
 }
 
 type GameStateData struct {
 	Players     [2]PlayerState
 	RoundNumber int
 
+	// When the reveal window closes, nil outside the reveal phase
+	RevealDeadline *time.Time
+
 	GamePhase
 
 	// Board state tracking
@@ -95,18 +124,73 @@ type GameStateData struct {
 	// Effect resolution tracking
 	EffectsState
 
+	// War resolution history, one entry per round
+	WarResults []WarResult
+
 	// Game completion
 	CompletionState
 
 	// Rules
 	Rules
+
+	// Who may watch the game, and how far behind
+	Spectating SpectatorSettings
+
+	// Seeded random stream used for every shuffle
+	RNG RNGState
+
+	// The catalog version of every card in play, fixed when the game starts
+	CardVersions map[card.SerializableCardID]int
 }
 
 type BoardState struct {
-	Board         [4][4]BoardSpace
+	Board         [][]BoardSpace // Indexed by X then Y
 	ClearedSpaces map[Position]bool
 }
 
+// NewBoardState returns an empty size x size board
+func NewBoardState(size int) BoardState {
+	board := make([][]BoardSpace, size)
+	for x := range board {
+		board[x] = make([]BoardSpace, size)
+	}
+	return BoardState{
+		Board:         board,
+		ClearedSpaces: make(map[Position]bool),
+	}
+}
+
+// Clone copies the board so that changes to either copy leave the other as
+// it was
+func (b BoardState) Clone() BoardState {
+	board := make([][]BoardSpace, len(b.Board))
+	for x, column := range b.Board {
+		board[x] = slices.Clone(column)
+	}
+	return BoardState{
+		Board:         board,
+		ClearedSpaces: maps.Clone(b.ClearedSpaces),
+	}
+}
+
+// Contains reports whether position is on the board
+func (b BoardState) Contains(position Position) bool {
+	return position.X >= 0 && position.X < len(b.Board) &&
+		position.Y >= 0 && position.Y < len(b.Board[position.X])
+}
+
+// IsEmpty reports whether every space on the board has been cleared
+func (b BoardState) IsEmpty() bool {
+	for _, row := range b.Board {
+		for _, space := range row {
+			if space.Card != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 type EffectsState struct {
 	ActiveEffectsStack []EffectContext
 	EffectsStack       []EffectContext
@@ -121,89 +205,83 @@ type CompletionState struct {
 	Winner     *user.User
 }
 
-type Rules struct {
-	RoundLimit       int
-	RoundTimer       int
-	HandLimit        int
-	StartingHandSize int
-	DeckLimit        int
-	DrawLimit        int
-}
-
 type EffectContext struct {
 	Trigger        Position                // Position of triggering card
 	Target         Position                // Position of target card (if any)
 	Source         card.SerializableCardID // Card causing the effect
 	Activator      user.UserID             // Player who activated the effect
 	PhaseTriggered GamePhase               // Phase when effect was triggered
+	EffectType     card.CardEffectType     // Event that triggered the effect
+	EffectIndex    int                     // Index into the source card's effects for EffectType
 }
 
-func NewGameState(players [2]PlayerState) *GameState {
-	board := [4][4]BoardSpace{}
-
-	// Start after initial hand (0,1,2)
-	p1CardIndex := 3
-	p2CardIndex := 3
-
-	// Use a boolean flag to track turns
-	isPlayer1Turn := true
-
-	// Populate board alternating between players
-	for y := 0; y < 4; y++ {
-		for x := 0; x < 4; x++ {
-			if isPlayer1Turn {
-				board[y][x] = BoardSpace{
-					Card:     players[0].Deck[p1CardIndex],
-					Revealed: false,
-					Owner:    players[0].User,
-				}
-				p1CardIndex++
-			} else {
-				board[y][x] = BoardSpace{
-					Card:     players[1].Deck[p2CardIndex],
-					Revealed: false,
-					Owner:    players[1].User,
-				}
-				p2CardIndex++
+// NewGameState deals the board from the top of the players' decks, after
+// their starting hands, alternating between the players
+func NewGameState(players [2]PlayerState, rules Rules, rng RNGState) *GameState {
+	boardState := NewBoardState(rules.BoardSize)
+
+	next := [2]int{rules.StartingHandSize, rules.StartingHandSize}
+	for y := 0; y < rules.BoardSize; y++ {
+		for x := 0; x < rules.BoardSize; x++ {
+			player := (y*rules.BoardSize + x) % 2
+			boardState.Board[y][x] = BoardSpace{
+				Card:     players[player].Deck[next[player]],
+				Revealed: false,
+				Owner:    players[player].User,
 			}
-			// Flip the flag after each card placement
-			isPlayer1Turn = !isPlayer1Turn
+			next[player]++
 		}
 	}
 
 	// Update players' decks to remove placed cards
-	players[0].Deck = players[0].Deck[p1CardIndex:]
-	players[1].Deck = players[1].Deck[p2CardIndex:]
+	players[0].Deck = players[0].Deck[next[0]:]
+	players[1].Deck = players[1].Deck[next[1]:]
 
 	return &GameState{
 		ID: NewGameStateID(),
 		GameStateData: GameStateData{
-			Players:   players,
-			GamePhase: PhaseSetup,
-			BoardState: BoardState{
-				Board:         board,
-				ClearedSpaces: make(map[Position]bool),
-			},
+			Players:    players,
+			GamePhase:  PhaseSetup,
+			BoardState: boardState,
 			EffectsState: EffectsState{
 				ActiveEffectsStack: make([]EffectContext, 0),
 			},
+			WarResults: make([]WarResult, 0),
 			CompletionState: CompletionState{
 				IsComplete: false,
 				Winner:     nil,
 			},
-			Rules: Rules{
-				RoundLimit:       RoundLimit,
-				RoundTimer:       RoundTimer,
-				HandLimit:        HandLimit,
-				StartingHandSize: StartingHandSize,
-				DeckLimit:        DeckLimit,
-				DrawLimit:        DrawLimit,
-			},
+			Rules: rules,
+			RNG:   rng,
 		},
 		Metadata: domain.NewMetadata(),
 	}
 }
 
+// Clone copies the game's data so that changes to either copy leave the other
+// as it was, e.g. to compare a game before and after a change
+func (g GameStateData) Clone() GameStateData {
+	clone := g
+	for i, player := range g.Players {
+		player.Hand = slices.Clone(player.Hand)
+		player.Deck = slices.Clone(player.Deck)
+		player.DiscardedCards = slices.Clone(player.DiscardedCards)
+		player.RevealedCards = maps.Clone(player.RevealedCards)
+		player.SeenCards = maps.Clone(player.SeenCards)
+		player.EmptySpaces = slices.Clone(player.EmptySpaces)
+		if player.SelectedCard != nil {
+			selected := *player.SelectedCard
+			player.SelectedCard = &selected
+		}
+		clone.Players[i] = player
+	}
+	clone.BoardState = g.BoardState.Clone()
+	clone.ActiveEffectsStack = slices.Clone(g.ActiveEffectsStack)
+	clone.EffectsStack = slices.Clone(g.EffectsStack)
+	clone.WarResults = slices.Clone(g.WarResults)
+	return clone
+}
+
 func NewEffectContext(
 	trigger Position,
 	source card.SerializableCardID,
@@ -0,0 +1,29 @@
+package game_test
+
+import (
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *GameTestSuite) TestGameStateJSON() {
+	s.Run("should encode the game and its metadata", func() {
+		gameState := &game.GameState{
+			ID:            1,
+			GameStateData: game.GameStateData{RoundNumber: 3, GamePhase: game.PhaseWar},
+			Metadata:      domain.NewMetadata(),
+		}
+		data, err := utils.Marshal(gameState)
+		require.NoError(s.T(), err)
+
+		var decoded game.GameState
+		require.NoError(s.T(), utils.Unmarshal(data, &decoded))
+		assert.Equal(s.T(), gameState.ID, decoded.ID)
+		assert.Equal(s.T(), 3, decoded.RoundNumber)
+		assert.Equal(s.T(), game.PhaseWar, decoded.GamePhase)
+		require.NotNil(s.T(), decoded.Metadata)
+		assert.Equal(s.T(), gameState.Metadata.CreatedAt.Unix(), decoded.Metadata.CreatedAt.Unix())
+	})
+}
@@ -0,0 +1,15 @@
+package game_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type GameTestSuite struct {
+	suite.Suite
+}
+
+func TestGameSuite(t *testing.T) {
+	suite.Run(t, new(GameTestSuite))
+}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"time"
+
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// GameStateVersionSummary describes a stored version without its full state
+type GameStateVersionSummary struct {
+	Version     int                `json:"version"`
+	ID          GameStateVersionID `json:"id"`
+	GamePhase   GamePhase          `json:"game_phase"`
+	RoundNumber int                `json:"round_number"`
+	IsComplete  bool               `json:"is_complete"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+func NewGameStateVersionSummary(version int, v *GameStateVersion) GameStateVersionSummary {
+	return GameStateVersionSummary{
+		Version:     version,
+		ID:          v.ID,
+		GamePhase:   v.State.GamePhase,
+		RoundNumber: v.State.RoundNumber,
+		IsComplete:  v.State.IsComplete,
+		CreatedAt:   v.Metadata.CreatedAt,
+	}
+}
+
+// GameStateVersionView is a stored version with its state projected for the
+// viewer, see NewViewFor
+type GameStateVersionView struct {
+	GameStateVersionSummary `json:",inline" tstype:",extends"`
+	State                   any `json:"state"`
+}
+
+func NewGameStateVersionView(version int, v *GameStateVersion, userId user.UserID) GameStateVersionView {
+	return GameStateVersionView{
+		GameStateVersionSummary: NewGameStateVersionSummary(version, v),
+		State:                   NewViewFor(v.State, userId),
+	}
+}
+
+// NewSpectatorVersionView projects the version for spectators
+func NewSpectatorVersionView(version int, v *GameStateVersion) GameStateVersionView {
+	return GameStateVersionView{
+		GameStateVersionSummary: NewGameStateVersionSummary(version, v),
+		State:                   NewSpectatorView(v.State),
+	}
+}
+
+// StateChange is a single difference between two game states
+type StateChange = utils.JSONChange
+
+// DiffGameViews returns the structural differences between the JSON forms of
+// two views of a game, ordered by path
+func DiffGameViews(from any, to any) ([]StateChange, error) {
+	return utils.DiffJSON(from, to)
+}
@@ -0,0 +1,51 @@
+package game_test
+
+import (
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *GameTestSuite) TestDiffGameViews() {
+	newState := func() *game.GameState {
+		return &game.GameState{
+			ID: 1,
+			GameStateData: game.GameStateData{
+				GamePhase: game.PhaseReveal,
+				Players: [2]game.PlayerState{
+					{User: 1, Hand: []card.SerializableCardID{1, 2}},
+					{User: 2},
+				},
+			},
+		}
+	}
+
+	spectator := user.UserID(3)
+
+	s.Run("should return no changes for equal states", func() {
+		changes, err := game.DiffGameViews(game.NewViewFor(newState(), spectator), game.NewViewFor(newState(), spectator))
+		require.NoError(s.T(), err)
+		assert.Empty(s.T(), changes)
+	})
+
+	s.Run("should report changed values by path, as the viewer sees them", func() {
+		from, to := newState(), newState()
+		to.GamePhase = game.PhaseWar
+		to.Players[0].Points = 3
+		to.Players[0].Hand = []card.SerializableCardID{1}
+
+		changes, err := game.DiffGameViews(game.NewViewFor(from, spectator), game.NewViewFor(to, spectator))
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), []game.StateChange{
+			{Path: "game_phase", From: "reveal", To: "war"},
+			{Path: "players.0.hand_size", From: float64(2), To: float64(1)},
+			{Path: "players.0.points", From: float64(0), To: float64(3)},
+		}, changes)
+
+		changes, err = game.DiffGameViews(game.NewViewFor(from, from.Players[0].User), game.NewViewFor(to, to.Players[0].User))
+		require.NoError(s.T(), err)
+		assert.Contains(s.T(), changes, game.StateChange{Path: "hand.1", From: float64(2), To: nil})
+	})
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"time"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// RevealTimer fires when a round's reveal window closes
+type RevealTimer struct {
+	GameStateID GameStateID
+	RoundNumber int
+	Deadline    time.Time
+}
+
+// NewRevealTimer returns the timer for the game's current reveal window
+func NewRevealTimer(gameState *GameState) *RevealTimer {
+	return &RevealTimer{
+		GameStateID: gameState.ID,
+		RoundNumber: gameState.RoundNumber,
+		Deadline:    *gameState.RevealDeadline,
+	}
+}
+
+// RevealDeadline is when a reveal window opened at now closes
+func (r Rules) RevealDeadline(now time.Time) time.Time {
+	return now.Add(time.Duration(r.RoundTimer) * time.Second)
+}
+
+// IsStale reports whether the game has moved on from the round the timer was
+// set for, e.g. because both players selected a card in time
+func (t *RevealTimer) IsStale(data GameStateData) bool {
+	return data.IsComplete ||
+		data.GamePhase != PhaseReveal ||
+		data.RoundNumber != t.RoundNumber
+}
+
+// AutoRevealPosition picks the card revealed for a player who did not select
+// one in time. The last card they placed on the field is preferred, then the
+// last card they drew if it has since been swapped onto the field, then
+// their first face-down card.
+func AutoRevealPosition(data GameStateData, playerIndex int) (Position, bool) {
+	player := data.Players[playerIndex]
+	for _, cardId := range []card.SerializableCardID{player.LastPlacedCard, player.LastDrawnCard} {
+		if cardId == 0 {
+			continue
+		}
+		if position, ok := findFaceDownCard(data, player, func(space BoardSpace) bool {
+			return space.Card == cardId
+		}); ok {
+			return position, true
+		}
+	}
+	return findFaceDownCard(data, player, func(BoardSpace) bool {
+		return true
+	})
+}
+
+// ApplyAutoReveal selects a card for every player who let the reveal window
+// expire without choosing one, and returns the indexes of those players
+func ApplyAutoReveal(data *GameStateData) ([]int, error) {
+	selected := make([]int, 0)
+	for i := range data.Players {
+		if data.Players[i].SelectedCard != nil {
+			continue
+		}
+		position, ok := AutoRevealPosition(*data, i)
+		if !ok {
+			return nil, utils.NewInvalidStateError("player has no face-down card to reveal")
+		}
+		data.Players[i].SelectedCard = &position
+		selected = append(selected, i)
+	}
+	return selected, nil
+}
+
+func findFaceDownCard(data GameStateData, player PlayerState, match func(BoardSpace) bool) (Position, bool) {
+	for x, column := range data.Board {
+		for y, space := range column {
+			if space.Card == 0 || space.Revealed || space.Owner != player.User {
+				continue
+			}
+			if match(space) {
+				return Position{X: x, Y: y}, true
+			}
+		}
+	}
+	return Position{}, false
+}
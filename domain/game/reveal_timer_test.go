@@ -0,0 +1,100 @@
+package game_test
+
+import (
+	"time"
+
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *GameTestSuite) TestApplyAutoReveal() {
+	newData := func() game.GameStateData {
+		data := game.GameStateData{
+			GamePhase: game.PhaseReveal,
+			Players: [2]game.PlayerState{
+				{User: 1, LastPlacedCard: 22},
+				{User: 2, LastPlacedCard: 30},
+			},
+			BoardState: game.NewBoardState(4),
+		}
+		data.Board[0][0] = game.BoardSpace{Card: 20, Owner: 1}
+		data.Board[0][1] = game.BoardSpace{Card: 21, Owner: 2}
+		data.Board[1][0] = game.BoardSpace{Card: 22, Owner: 1}
+		data.Board[1][1] = game.BoardSpace{Card: 23, Owner: 2}
+		return data
+	}
+
+	s.Run("should reveal the last placed card", func() {
+		data := newData()
+		position, ok := game.AutoRevealPosition(data, 0)
+		require.True(s.T(), ok)
+		assert.Equal(s.T(), game.Position{X: 1, Y: 0}, position)
+	})
+
+	s.Run("should fall back to the first face-down card", func() {
+		data := newData()
+		position, ok := game.AutoRevealPosition(data, 1)
+		require.True(s.T(), ok)
+		assert.Equal(s.T(), game.Position{X: 0, Y: 1}, position)
+	})
+
+	s.Run("should skip the last placed card once revealed", func() {
+		data := newData()
+		data.Board[1][0].Revealed = true
+		position, ok := game.AutoRevealPosition(data, 0)
+		require.True(s.T(), ok)
+		assert.Equal(s.T(), game.Position{X: 0, Y: 0}, position)
+	})
+
+	s.Run("should keep existing selections", func() {
+		data := newData()
+		data.Players[0].SelectedCard = &game.Position{X: 0, Y: 0}
+		selected, err := game.ApplyAutoReveal(&data)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), []int{1}, selected)
+		assert.Equal(s.T(), game.Position{X: 0, Y: 0}, *data.Players[0].SelectedCard)
+		assert.Equal(s.T(), game.Position{X: 0, Y: 1}, *data.Players[1].SelectedCard)
+	})
+
+	s.Run("should error when a player has no face-down card", func() {
+		data := newData()
+		data.Board[0][1] = game.BoardSpace{}
+		data.Board[1][1] = game.BoardSpace{}
+		_, err := game.ApplyAutoReveal(&data)
+		assert.Error(s.T(), err)
+	})
+}
+
+func (s *GameTestSuite) TestRevealTimer() {
+	deadline := time.Now()
+	gameState := &game.GameState{
+		ID: 1,
+		GameStateData: game.GameStateData{
+			GamePhase:      game.PhaseReveal,
+			RoundNumber:    2,
+			RevealDeadline: &deadline,
+		},
+	}
+	timer := game.NewRevealTimer(gameState)
+
+	s.Run("should be live for the round it was set for", func() {
+		assert.False(s.T(), timer.IsStale(gameState.GameStateData))
+	})
+
+	s.Run("should be stale once the game moves on", func() {
+		data := gameState.GameStateData
+		data.GamePhase = game.PhaseWar
+		assert.True(s.T(), timer.IsStale(data))
+
+		data = gameState.GameStateData
+		data.RoundNumber++
+		assert.True(s.T(), timer.IsStale(data))
+	})
+
+	s.Run("should close the window after the round timer", func() {
+		now := time.Now()
+		rules := game.Rules{RoundTimer: 15}
+		assert.Equal(s.T(), now.Add(15*time.Second), rules.RevealDeadline(now))
+	})
+}
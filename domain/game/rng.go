@@ -0,0 +1,67 @@
+package game
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	mrand "math/rand"
+)
+
+// RNGState is a game's persisted random stream. Every shuffle draws a new
+// source derived from the seed and the number of previous draws, so a game
+// can be replayed from its seed.
+//
+// The seed is committed to when the game starts: Commitment is published
+// straight away while Seed and Nonce stay hidden until the game is complete,
+// letting players verify the deal was fixed before it happened.
+type RNGState struct {
+	Seed       int64
+	Nonce      string // Random salt so the commitment can't be brute forced
+	Commitment string // hex sha256 of "seed:nonce"
+	Draws      int    // Number of shuffles taken from the stream
+}
+
+func NewRNGState(seed int64, nonce string) RNGState {
+	return RNGState{
+		Seed:       seed,
+		Nonce:      nonce,
+		Commitment: SeedCommitment(seed, nonce),
+	}
+}
+
+// NewRandomRNGState creates a stream from a cryptographically random seed
+func NewRandomRNGState() (RNGState, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return RNGState{}, err
+	}
+	seed := int64(binary.BigEndian.Uint64(buf[:8]))
+	return NewRNGState(seed, hex.EncodeToString(buf[8:])), nil
+}
+
+// Next returns the random source for the next shuffle and advances the stream
+func (r *RNGState) Next() *mrand.Rand {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%d", r.Seed, r.Draws)))
+	r.Draws++
+	return mrand.New(mrand.NewSource(int64(binary.BigEndian.Uint64(sum[:8]))))
+}
+
+func SeedCommitment(seed int64, nonce string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", seed, nonce)))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifySeedCommitment reports whether the revealed seed and nonce match the
+// commitment published at the start of the game
+func VerifySeedCommitment(seed int64, nonce string, commitment string) bool {
+	return SeedCommitment(seed, nonce) == commitment
+}
+
+// SeedReveal is published once a game is complete
+type SeedReveal struct {
+	Seed       int64
+	Nonce      string
+	Commitment string
+}
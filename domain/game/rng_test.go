@@ -0,0 +1,43 @@
+package game_test
+
+import (
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *GameTestSuite) TestRNGState() {
+	deck := make([]card.SerializableCardID, 52)
+	for i := range deck {
+		deck[i] = card.SerializableCardID(i + 1)
+	}
+
+	s.Run("should replay the same shuffles from the same seed", func() {
+		a, b := game.NewRNGState(42, "nonce"), game.NewRNGState(42, "nonce")
+		for i := 0; i < 3; i++ {
+			assert.Equal(s.T(), card.ShuffleCardsWithRand(deck, a.Next()), card.ShuffleCardsWithRand(deck, b.Next()))
+		}
+		assert.Equal(s.T(), 3, a.Draws)
+	})
+
+	s.Run("should resume the stream after being persisted", func() {
+		rng := game.NewRNGState(42, "nonce")
+		rng.Next()
+		resumed := rng
+		assert.Equal(s.T(), card.ShuffleCardsWithRand(deck, rng.Next()), card.ShuffleCardsWithRand(deck, resumed.Next()))
+	})
+
+	s.Run("should draw a different order for each shuffle", func() {
+		rng := game.NewRNGState(42, "nonce")
+		assert.NotEqual(s.T(), card.ShuffleCardsWithRand(deck, rng.Next()), card.ShuffleCardsWithRand(deck, rng.Next()))
+	})
+
+	s.Run("should verify the seed commitment", func() {
+		rng, err := game.NewRandomRNGState()
+		require.NoError(s.T(), err)
+		assert.True(s.T(), game.VerifySeedCommitment(rng.Seed, rng.Nonce, rng.Commitment))
+		assert.False(s.T(), game.VerifySeedCommitment(rng.Seed+1, rng.Nonce, rng.Commitment))
+		assert.False(s.T(), game.VerifySeedCommitment(rng.Seed, "other", rng.Commitment))
+	})
+}
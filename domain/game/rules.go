@@ -0,0 +1,123 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/coopersmall/subswag/utils"
+)
+
+// RulesPreset names a set of rules a game can be created with
+type RulesPreset string
+
+const (
+	RulesPresetClassic  RulesPreset = "classic"
+	RulesPresetBlitz    RulesPreset = "blitz"     // Fewer, faster rounds and bigger draws
+	RulesPresetBigBoard RulesPreset = "big_board" // A 6x6 board and a longer game
+)
+
+const (
+	MinBoardSize = 2
+	MaxBoardSize = 8
+)
+
+// Rules are the limits a game is played with. They are copied onto the game
+// when it is created so that changing a preset never affects games in play.
+type Rules struct {
+	Preset           RulesPreset
+	BoardSize        int // The board is BoardSize x BoardSize, split evenly between the players
+	RoundLimit       int
+	RoundTimer       int // Seconds the reveal window stays open
+	HandLimit        int
+	StartingHandSize int
+	DeckLimit        int
+	DrawLimit        int // Cards taken by a single draw
+}
+
+var rulesPresets = map[RulesPreset]Rules{
+	RulesPresetClassic: {
+		Preset:           RulesPresetClassic,
+		BoardSize:        4,
+		RoundLimit:       15,
+		RoundTimer:       15,
+		HandLimit:        5,
+		StartingHandSize: 3,
+		DeckLimit:        52,
+		DrawLimit:        1,
+	},
+	RulesPresetBlitz: {
+		Preset:           RulesPresetBlitz,
+		BoardSize:        4,
+		RoundLimit:       8,
+		RoundTimer:       5,
+		HandLimit:        4,
+		StartingHandSize: 3,
+		DeckLimit:        52,
+		DrawLimit:        2,
+	},
+	RulesPresetBigBoard: {
+		Preset:           RulesPresetBigBoard,
+		BoardSize:        6,
+		RoundLimit:       25,
+		RoundTimer:       20,
+		HandLimit:        5,
+		StartingHandSize: 3,
+		DeckLimit:        52,
+		DrawLimit:        1,
+	},
+}
+
+// NewRules returns the preset's rules. The classic rules are used when preset
+// is empty.
+func NewRules(preset RulesPreset) (Rules, error) {
+	if preset == "" {
+		preset = RulesPresetClassic
+	}
+	rules, ok := rulesPresets[preset]
+	if !ok {
+		return Rules{}, utils.NewInvalidArgumentError(fmt.Sprintf("unknown rules preset %q", preset))
+	}
+	return rules, nil
+}
+
+// ClassicRules are the rules used when none are chosen
+func ClassicRules() Rules {
+	return rulesPresets[RulesPresetClassic]
+}
+
+// Validate checks that a game can be played with the rules
+func (r Rules) Validate() error {
+	switch {
+	case r.BoardSize < MinBoardSize || r.BoardSize > MaxBoardSize:
+		return utils.NewInvalidArgumentError(fmt.Sprintf("board size must be between %d and %d", MinBoardSize, MaxBoardSize))
+	case r.BoardSize%2 != 0:
+		return utils.NewInvalidArgumentError("board size must be even so both players get the same number of spaces")
+	case r.RoundLimit < 1:
+		return utils.NewInvalidArgumentError("round limit must be at least 1")
+	case r.RoundTimer < 1:
+		return utils.NewInvalidArgumentError("round timer must be at least 1 second")
+	case r.StartingHandSize < 0 || r.StartingHandSize > r.HandLimit:
+		return utils.NewInvalidArgumentError("starting hand must fit in the hand limit")
+	case r.DrawLimit < 1:
+		return utils.NewInvalidArgumentError("draw limit must be at least 1")
+	case r.CardsNeeded() > r.DeckLimit:
+		return utils.NewInvalidArgumentError("the deck limit is too small to deal the starting hand and board")
+	}
+	return nil
+}
+
+// CardsNeeded is the fewest cards a deck needs to deal a player's starting
+// hand and their half of the board
+func (r Rules) CardsNeeded() int {
+	return r.StartingHandSize + r.BoardSize*r.BoardSize/2
+}
+
+// ValidateDeckSize checks that a deck of size cards can be played
+func (r Rules) ValidateDeckSize(size int) error {
+	if size < r.CardsNeeded() {
+		return utils.NewInvalidArgumentError(fmt.Sprintf("deck needs at least %d cards for %s rules", r.CardsNeeded(), r.Preset))
+	}
+	if size > r.DeckLimit {
+		return utils.NewInvalidArgumentError(fmt.Sprintf("deck can have at most %d cards for %s rules", r.DeckLimit, r.Preset))
+	}
+	return nil
+}
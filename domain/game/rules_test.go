@@ -0,0 +1,99 @@
+package game_test
+
+import (
+	"math/rand"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *GameTestSuite) TestRules() {
+	s.Run("should have valid presets", func() {
+		for _, preset := range []game.RulesPreset{game.RulesPresetClassic, game.RulesPresetBlitz, game.RulesPresetBigBoard} {
+			rules, err := game.NewRules(preset)
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), preset, rules.Preset)
+			assert.NoError(s.T(), rules.Validate())
+		}
+	})
+
+	s.Run("should default to the classic rules", func() {
+		rules, err := game.NewRules("")
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), game.ClassicRules(), rules)
+	})
+
+	s.Run("should reject unknown presets", func() {
+		_, err := game.NewRules("chaos")
+		assert.Error(s.T(), err)
+	})
+
+	s.Run("should reject rules a game cannot be played with", func() {
+		for _, change := range []func(*game.Rules){
+			func(r *game.Rules) { r.BoardSize = 5 },
+			func(r *game.Rules) { r.BoardSize = 10 },
+			func(r *game.Rules) { r.RoundLimit = 0 },
+			func(r *game.Rules) { r.DrawLimit = 0 },
+			func(r *game.Rules) { r.StartingHandSize = r.HandLimit + 1 },
+			func(r *game.Rules) { r.DeckLimit = 10 },
+		} {
+			rules := game.ClassicRules()
+			change(&rules)
+			assert.Error(s.T(), rules.Validate())
+		}
+	})
+
+	s.Run("should check a deck can deal the starting hand and board", func() {
+		rules, err := game.NewRules(game.RulesPresetBigBoard)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), 21, rules.CardsNeeded())
+		assert.Error(s.T(), rules.ValidateDeckSize(20))
+		assert.NoError(s.T(), rules.ValidateDeckSize(21))
+		assert.Error(s.T(), rules.ValidateDeckSize(53))
+	})
+}
+
+func (s *GameTestSuite) TestNewGameState() {
+	newPlayer := func(userId int, first int, rules game.Rules) game.PlayerState {
+		deck := &card.SerializableDeck{}
+		for i := 0; i < rules.CardsNeeded(); i++ {
+			deck.CardIDs = append(deck.CardIDs, card.SerializableCardID(first+i))
+		}
+		return game.NewPlayerState(1, deck, rules.StartingHandSize, rand.New(rand.NewSource(1)))
+	}
+
+	s.Run("should deal a board of the rules' size", func() {
+		rules, err := game.NewRules(game.RulesPresetBigBoard)
+		require.NoError(s.T(), err)
+		players := [2]game.PlayerState{newPlayer(1, 100, rules), newPlayer(2, 200, rules)}
+		players[1].User = 2
+
+		gameState := game.NewGameState(players, rules, game.NewRNGState(1, "nonce"))
+		require.Len(s.T(), gameState.Board, 6)
+		owned := map[int]int{}
+		for _, column := range gameState.Board {
+			require.Len(s.T(), column, 6)
+			for _, space := range column {
+				assert.NotZero(s.T(), space.Card)
+				owned[int(space.Owner)]++
+			}
+		}
+		assert.Equal(s.T(), map[int]int{1: 18, 2: 18}, owned)
+		assert.Len(s.T(), gameState.Players[0].Hand, 3)
+		assert.Empty(s.T(), gameState.Players[0].Deck)
+	})
+
+	s.Run("should clone the board so changes do not leak between copies", func() {
+		rules := game.ClassicRules()
+		players := [2]game.PlayerState{newPlayer(1, 100, rules), newPlayer(2, 200, rules)}
+		gameState := game.NewGameState(players, rules, game.NewRNGState(1, "nonce"))
+
+		before := gameState.GameStateData.Clone()
+		gameState.Board[0][0].Revealed = true
+		gameState.Players[0].Hand[0] = 999
+		assert.False(s.T(), before.Board[0][0].Revealed)
+		assert.NotEqual(s.T(), card.SerializableCardID(999), before.Players[0].Hand[0])
+	})
+}
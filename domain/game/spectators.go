@@ -0,0 +1,78 @@
+package game
+
+import (
+	"time"
+)
+
+const (
+	// DefaultSpectatorDelay holds spectators back far enough that relaying
+	// the game to a player would not help them
+	DefaultSpectatorDelay = 30 * time.Second
+	MaxSpectatorDelay     = 10 * time.Minute
+
+	// SpectatorLeaseTTL is how long a spectator is counted without renewing
+	// their lease, so watchers on an API instance that dies drop out
+	SpectatorLeaseTTL = 30 * time.Second
+)
+
+// SpectatorSettings decide whether a game can be watched and how far behind
+// the players spectators are kept
+type SpectatorSettings struct {
+	Disallowed bool          `json:"disallowed"`
+	Delay      time.Duration `json:"delay"` // 0 uses DefaultSpectatorDelay
+}
+
+// BroadcastDelay is how long after a version is created spectators are sent it
+func (s SpectatorSettings) BroadcastDelay() time.Duration {
+	if s.Delay <= 0 {
+		return DefaultSpectatorDelay
+	}
+	return min(s.Delay, MaxSpectatorDelay)
+}
+
+// SpectatorLeaseID identifies one spectator connection
+type SpectatorLeaseID string
+
+// Spectators tracks who is watching a game. Each connection holds a lease
+// that it renews while it stays open.
+type Spectators struct {
+	Leases map[SpectatorLeaseID]time.Time `json:"leases"` // Lease expiry by connection
+}
+
+// Renew adds or extends the connection's lease
+func (s *Spectators) Renew(id SpectatorLeaseID, now time.Time) {
+	if s.Leases == nil {
+		s.Leases = make(map[SpectatorLeaseID]time.Time)
+	}
+	s.Leases[id] = now.Add(SpectatorLeaseTTL)
+	s.prune(now)
+}
+
+func (s *Spectators) Leave(id SpectatorLeaseID) {
+	delete(s.Leases, id)
+}
+
+// Count returns the number of spectators whose lease has not expired
+func (s *Spectators) Count(now time.Time) int {
+	count := 0
+	for _, expiresAt := range s.Leases {
+		if expiresAt.After(now) {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *Spectators) prune(now time.Time) {
+	for id, expiresAt := range s.Leases {
+		if !expiresAt.After(now) {
+			delete(s.Leases, id)
+		}
+	}
+}
+
+// SpectatorCount is the number of users watching a game
+type SpectatorCount struct {
+	GameStateID GameStateID `json:"game_state_id"`
+	Count       int         `json:"count"`
+}
@@ -0,0 +1,33 @@
+package game_test
+
+import (
+	"time"
+
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *GameTestSuite) TestSpectators() {
+	s.Run("should use the default delay unless one is set", func() {
+		assert.Equal(s.T(), game.DefaultSpectatorDelay, game.SpectatorSettings{}.BroadcastDelay())
+		assert.Equal(s.T(), time.Minute, game.SpectatorSettings{Delay: time.Minute}.BroadcastDelay())
+		assert.Equal(s.T(), game.MaxSpectatorDelay, game.SpectatorSettings{Delay: time.Hour}.BroadcastDelay())
+	})
+
+	s.Run("should count spectators until they leave or their lease expires", func() {
+		now := time.Now()
+		spectators := &game.Spectators{}
+		spectators.Renew("a", now)
+		spectators.Renew("b", now)
+		spectators.Renew("c", now)
+		assert.Equal(s.T(), 3, spectators.Count(now))
+
+		spectators.Leave("a")
+		assert.Equal(s.T(), 2, spectators.Count(now))
+
+		later := now.Add(game.SpectatorLeaseTTL)
+		spectators.Renew("b", later)
+		assert.Equal(s.T(), 1, spectators.Count(later))
+		assert.Len(s.T(), spectators.Leases, 1)
+	})
+}
@@ -0,0 +1,240 @@
+package game
+
+import (
+	"slices"
+	"time"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// GameView is the part of a game that is public to everyone watching it.
+// Hands, deck order and face-down cards are left out; see PlayerView and
+// SpectatorView for what each kind of viewer is shown on top of this.
+type GameView struct {
+	ID             GameStateID        `json:"id"`
+	RoundNumber    int                `json:"round_number"`
+	GamePhase      GamePhase          `json:"game_phase"`
+	RevealDeadline *time.Time         `json:"reveal_deadline,omitempty"`
+	Players        [2]PlayerStateView `json:"players"`
+	Board          [][]SpaceView      `json:"board"`
+	ClearedSpaces  map[Position]bool  `json:"cleared_spaces"`
+	EffectsStack   []EffectContext    `json:"effects_stack"`
+	WarResults     []WarResult        `json:"war_results"`
+	IsComplete     bool               `json:"is_complete"`
+	Winner         *user.UserID       `json:"winner,omitempty"`
+	Rules          Rules              `json:"rules"`
+	Commitment     string             `json:"commitment"`
+	Seed           *SeedReveal        `json:"seed,omitempty"` // Set once the game is complete
+	UpdatedAt      *time.Time         `json:"updated_at,omitempty"`
+}
+
+// PlayerStateView is what everyone can see of a player
+type PlayerStateView struct {
+	User                 user.UserID `json:"user"`
+	HandSize             int         `json:"hand_size"`
+	DeckSize             int         `json:"deck_size"`
+	DiscardedSize        int         `json:"discarded_size"`
+	Points               int         `json:"points"`
+	RoundsWon            int         `json:"rounds_won"`
+	WarValueModifier     int         `json:"war_value_modifier"`
+	HasSelected          bool        `json:"has_selected"`
+	EmptySpaces          []Position  `json:"empty_spaces"`
+	HasDrawnThisTurn     bool        `json:"has_drawn_this_turn"`
+	HasSwappedThisTurn   bool        `json:"has_swapped_this_turn"`
+	HasDiscardedThisTurn bool        `json:"has_discarded_this_turn"`
+	HasPassedThisTurn    bool        `json:"has_passed_this_turn"`
+}
+
+// SpaceView is a board space as the viewer sees it. Card is 0 both for an
+// empty space and for a face-down card the viewer has not seen; Hidden tells
+// the two apart.
+type SpaceView struct {
+	Card     card.SerializableCardID `json:"card,omitempty"`
+	Hidden   bool                    `json:"hidden"`
+	Revealed bool                    `json:"revealed"`
+	Owner    user.UserID             `json:"owner,omitempty"`
+}
+
+// PlayerView is a game as one of its players sees it: the public view plus
+// their own hand, the contents of their deck without its order, and the
+// face-down cards they have seen
+type PlayerView struct {
+	GameView       `json:",inline" tstype:",extends"`
+	Seat           int                       `json:"seat"` // The viewer's index in Players
+	Hand           []card.SerializableCardID `json:"hand"`
+	Deck           []card.SerializableCardID `json:"deck"` // Sorted so the draw order stays hidden
+	DiscardedCards []card.SerializableCardID `json:"discarded_cards"`
+	SelectedCard   *Position                 `json:"selected_card,omitempty"`
+	RevealedCards  map[Position]bool         `json:"revealed_cards"`
+}
+
+// SpectatorView is a game as someone who is not playing in it sees it. Only
+// face-up cards are shown.
+type SpectatorView struct {
+	GameView `json:",inline" tstype:",extends"`
+}
+
+// NewPlayerView projects the game onto what the player userId may see
+func NewPlayerView(gameState *GameState, userId user.UserID) (*PlayerView, error) {
+	seat, ok := gameState.PlayerIndex(userId)
+	if !ok {
+		return nil, utils.NewPermissionDeniedError("not a player in this game")
+	}
+	player := gameState.Players[seat]
+	deck := slices.Clone(player.Deck)
+	slices.Sort(deck)
+	return &PlayerView{
+		GameView: newGameView(gameState, func(position Position) bool {
+			return gameState.HasSeen(seat, position)
+		}, func(effect EffectContext) bool {
+			return effect.Activator == userId
+		}),
+		Seat:           seat,
+		Hand:           slices.Clone(player.Hand),
+		Deck:           deck,
+		DiscardedCards: slices.Clone(player.DiscardedCards),
+		SelectedCard:   player.SelectedCard,
+		RevealedCards:  player.RevealedCards,
+	}, nil
+}
+
+// NewSpectatorView projects the game onto what a spectator may see
+func NewSpectatorView(gameState *GameState) *SpectatorView {
+	return &SpectatorView{
+		GameView: newGameView(gameState, func(Position) bool {
+			return false
+		}, func(EffectContext) bool {
+			return false
+		}),
+	}
+}
+
+// NewViewFor returns the player's view if userId is playing in the game, and
+// the spectator view otherwise
+func NewViewFor(gameState *GameState, userId user.UserID) any {
+	if view, err := NewPlayerView(gameState, userId); err == nil {
+		return view
+	}
+	return NewSpectatorView(gameState)
+}
+
+// CommandResultView is a CommandResult with the state projected for the user
+// who sent the command
+type CommandResultView struct {
+	Accepted  bool              `json:"accepted"`
+	Rejection *CommandRejection `json:"rejection,omitempty"`
+	State     any               `json:"state"`
+}
+
+func NewCommandResultView(result *CommandResult, userId user.UserID) *CommandResultView {
+	return &CommandResultView{
+		Accepted:  result.Accepted,
+		Rejection: result.Rejection,
+		State:     NewViewFor(result.State, userId),
+	}
+}
+
+// HasSeen reports whether the player at playerIndex knows which card is at
+// position. Face-up cards are seen by everyone. A face-down card has been seen
+// if it is in the player's SeenCards, which holds every card they placed
+// themselves or saw revealed.
+func (g GameStateData) HasSeen(playerIndex int, position Position) bool {
+	if !g.Contains(position) {
+		return false
+	}
+	space := g.Board[position.X][position.Y]
+	if space.Card == 0 || space.Revealed {
+		return true
+	}
+	return g.Players[playerIndex].SeenCards[space.Card]
+}
+
+// IsPublic reports whether everyone can see which card caused the effect.
+// Reveal and war effects come from face-up cards; the rest may come from a
+// card in a hand or face-down on the board.
+func (e EffectContext) IsPublic() bool {
+	return e.EffectType == card.CardEffectTypeReveal || e.EffectType == card.CardEffectTypeWar
+}
+
+// Redacted returns the effect with its source card hidden unless the effect
+// is public
+func (e EffectContext) Redacted() EffectContext {
+	if !e.IsPublic() {
+		e.Source = 0
+	}
+	return e
+}
+
+func newGameView(
+	gameState *GameState,
+	seen func(position Position) bool,
+	sawEffect func(effect EffectContext) bool,
+) GameView {
+	view := GameView{
+		ID:             gameState.ID,
+		RoundNumber:    gameState.RoundNumber,
+		GamePhase:      gameState.GamePhase,
+		RevealDeadline: gameState.RevealDeadline,
+		ClearedSpaces:  gameState.ClearedSpaces,
+		EffectsStack:   make([]EffectContext, len(gameState.EffectsStack)),
+		WarResults:     gameState.WarResults,
+		IsComplete:     gameState.IsComplete,
+		Rules:          gameState.Rules,
+		Commitment:     gameState.RNG.Commitment,
+	}
+	for i, player := range gameState.Players {
+		view.Players[i] = PlayerStateView{
+			User:                 player.User,
+			HandSize:             len(player.Hand),
+			DeckSize:             len(player.Deck),
+			DiscardedSize:        len(player.DiscardedCards),
+			Points:               player.Points,
+			RoundsWon:            player.RoundsWon,
+			WarValueModifier:     player.WarValueModifier,
+			HasSelected:          player.SelectedCard != nil,
+			EmptySpaces:          player.EmptySpaces,
+			HasDrawnThisTurn:     player.HasDrawnThisTurn,
+			HasSwappedThisTurn:   player.HasSwappedThisTurn,
+			HasDiscardedThisTurn: player.HasDiscardedThisTurn,
+			HasPassedThisTurn:    player.HasPassedThisTurn,
+		}
+	}
+	view.Board = make([][]SpaceView, len(gameState.Board))
+	for x, column := range gameState.Board {
+		view.Board[x] = make([]SpaceView, len(column))
+		for y, space := range column {
+			spaceView := SpaceView{
+				Card:     space.Card,
+				Revealed: space.Revealed,
+				Owner:    space.Owner,
+			}
+			if space.Card != 0 && !space.Revealed && !seen(Position{X: x, Y: y}) {
+				spaceView.Card = 0
+				spaceView.Hidden = true
+			}
+			view.Board[x][y] = spaceView
+		}
+	}
+	for i, effect := range gameState.EffectsStack {
+		if !sawEffect(effect) {
+			effect = effect.Redacted()
+		}
+		view.EffectsStack[i] = effect
+	}
+	if gameState.Winner != nil {
+		view.Winner = &gameState.Winner.ID
+	}
+	if gameState.IsComplete {
+		view.Seed = &SeedReveal{
+			Seed:       gameState.RNG.Seed,
+			Nonce:      gameState.RNG.Nonce,
+			Commitment: gameState.RNG.Commitment,
+		}
+	}
+	if gameState.Metadata != nil {
+		view.UpdatedAt = &gameState.Metadata.UpdatedAt
+	}
+	return view
+}
@@ -0,0 +1,145 @@
+package game_test
+
+import (
+	"encoding/json"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *GameTestSuite) TestGameViews() {
+	newState := func() *game.GameState {
+		selected := game.Position{X: 0, Y: 1}
+		state := &game.GameState{
+			ID: 7,
+			GameStateData: game.GameStateData{
+				GamePhase: game.PhaseReveal,
+				Players: [2]game.PlayerState{
+					{
+						User:           1,
+						Hand:           []card.SerializableCardID{10, 11},
+						Deck:           []card.SerializableCardID{14, 12, 13},
+						DiscardedCards: []card.SerializableCardID{15},
+						RevealedCards:  map[game.Position]bool{{X: 0, Y: 1}: true},
+						SeenCards:      map[card.SerializableCardID]bool{21: true, 22: true},
+						LastPlacedCard: 22,
+					},
+					{
+						User:          2,
+						Hand:          []card.SerializableCardID{30},
+						Deck:          []card.SerializableCardID{31, 32},
+						SelectedCard:  &selected,
+						RevealedCards: map[game.Position]bool{},
+					},
+				},
+				EffectsState: game.EffectsState{
+					EffectsStack: []game.EffectContext{
+						{Source: 10, Activator: 1, EffectType: card.CardEffectTypeDraw},
+						{Source: 30, Activator: 2, EffectType: card.CardEffectTypeDraw},
+						{Source: 20, Activator: 1, EffectType: card.CardEffectTypeReveal},
+					},
+				},
+				BoardState: game.NewBoardState(4),
+				RNG:        game.NewRNGState(42, "nonce"),
+			},
+		}
+		state.Board[0][0] = game.BoardSpace{Card: 20, Owner: 1, Revealed: true}
+		state.Board[0][1] = game.BoardSpace{Card: 21, Owner: 2}
+		state.Board[1][0] = game.BoardSpace{Card: 22, Owner: 1}
+		state.Board[1][1] = game.BoardSpace{Card: 23, Owner: 2}
+		return state
+	}
+
+	s.Run("should show a player their own hand, deck contents and seen cards", func() {
+		view, err := game.NewPlayerView(newState(), 1)
+		require.NoError(s.T(), err)
+
+		assert.Equal(s.T(), 0, view.Seat)
+		assert.Equal(s.T(), []card.SerializableCardID{10, 11}, view.Hand)
+		assert.Equal(s.T(), []card.SerializableCardID{12, 13, 14}, view.Deck)
+		assert.Equal(s.T(), []card.SerializableCardID{15}, view.DiscardedCards)
+		assert.Equal(s.T(), game.SpaceView{Card: 20, Owner: 1, Revealed: true}, view.Board[0][0])
+		assert.Equal(s.T(), game.SpaceView{Card: 21, Owner: 2}, view.Board[0][1])
+		assert.Equal(s.T(), game.SpaceView{Card: 22, Owner: 1}, view.Board[1][0])
+	})
+
+	s.Run("should keep showing a player every card they placed", func() {
+		state := newState()
+		state.Board[2][0] = game.BoardSpace{Card: 24, Owner: 1}
+		state.Players[0].See(24)
+		state.Players[0].LastPlacedCard = 24
+
+		view, err := game.NewPlayerView(state, 1)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), game.SpaceView{Card: 22, Owner: 1}, view.Board[1][0])
+		assert.Equal(s.T(), game.SpaceView{Card: 24, Owner: 1}, view.Board[2][0])
+	})
+
+	s.Run("should follow a seen card when it is moved", func() {
+		state := newState()
+		state.Board[1][0], state.Board[2][2] = game.BoardSpace{Card: 25, Owner: 1}, state.Board[1][0]
+
+		view, err := game.NewPlayerView(state, 1)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), game.SpaceView{Hidden: true, Owner: 1}, view.Board[1][0])
+		assert.Equal(s.T(), game.SpaceView{Card: 22, Owner: 1}, view.Board[2][2])
+	})
+
+	s.Run("should mask the opponent's hand, deck and selection", func() {
+		view, err := game.NewPlayerView(newState(), 1)
+		require.NoError(s.T(), err)
+
+		opponent := view.Players[1]
+		assert.Equal(s.T(), 1, opponent.HandSize)
+		assert.Equal(s.T(), 2, opponent.DeckSize)
+		assert.True(s.T(), opponent.HasSelected)
+		assert.Equal(s.T(), game.SpaceView{Hidden: true, Owner: 2}, view.Board[1][1])
+
+		assert.Nil(s.T(), view.SelectedCard)
+		data, err := json.Marshal(view)
+		require.NoError(s.T(), err)
+		assert.NotContains(s.T(), string(data), "nonce")
+	})
+
+	s.Run("should only name the source of hidden effects to their activator", func() {
+		view, err := game.NewPlayerView(newState(), 1)
+		require.NoError(s.T(), err)
+
+		sources := make([]card.SerializableCardID, len(view.EffectsStack))
+		for i, effect := range view.EffectsStack {
+			sources[i] = effect.Source
+		}
+		assert.Equal(s.T(), []card.SerializableCardID{10, 0, 20}, sources)
+	})
+
+	s.Run("should only show face-up cards to spectators", func() {
+		view := game.NewSpectatorView(newState())
+
+		assert.Equal(s.T(), game.SpaceView{Card: 20, Owner: 1, Revealed: true}, view.Board[0][0])
+		assert.Equal(s.T(), game.SpaceView{Hidden: true, Owner: 2}, view.Board[0][1])
+		assert.Equal(s.T(), game.SpaceView{Hidden: true, Owner: 1}, view.Board[1][0])
+		assert.Equal(s.T(), 2, view.Players[0].HandSize)
+		assert.Nil(s.T(), view.Seed)
+	})
+
+	s.Run("should reveal the seed once the game is complete", func() {
+		state := newState()
+		state.IsComplete = true
+		state.Winner = &user.User{ID: 2}
+
+		view := game.NewSpectatorView(state)
+		require.NotNil(s.T(), view.Seed)
+		assert.Equal(s.T(), int64(42), view.Seed.Seed)
+		assert.Equal(s.T(), user.UserID(2), *view.Winner)
+	})
+
+	s.Run("should give users who are not playing the spectator view", func() {
+		_, err := game.NewPlayerView(newState(), 3)
+		assert.Error(s.T(), err)
+		assert.IsType(s.T(), &game.SpectatorView{}, game.NewViewFor(newState(), 3))
+		assert.IsType(s.T(), &game.PlayerView{}, game.NewViewFor(newState(), 2))
+	})
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/user"
+)
+
+// NoWinner is returned in place of a player index when a War or game is tied
+const NoWinner = -1
+
+var nextPhase = map[GamePhase]GamePhase{
+	PhaseSetup:      PhaseCardAction,
+	PhaseCardAction: PhaseReveal,
+	PhaseReveal:     PhaseWar,
+	PhaseWar:        PhaseCleanup,
+	PhaseCleanup:    PhaseCardAction,
+}
+
+// Next returns the phase that follows p in the round structure. Cleanup
+// loops back to the card action phase for the next round.
+func (p GamePhase) Next() (GamePhase, bool) {
+	next, ok := nextPhase[p]
+	return next, ok
+}
+
+// IsPhaseDone reports whether the game is ready to leave its current phase.
+// The card action phase is done once both players have acted or passed and
+// the reveal phase once both have selected a card. Setup, War and cleanup
+// need nothing from the players.
+func (g GameStateData) IsPhaseDone() bool {
+	if g.IsComplete {
+		return false
+	}
+	for _, player := range g.Players {
+		switch {
+		case g.GamePhase == PhaseCardAction && !player.HasActed():
+			return false
+		case g.GamePhase == PhaseReveal && player.SelectedCard == nil:
+			return false
+		}
+	}
+	return true
+}
+
+type WarResult struct {
+	RoundNumber int
+	Positions   [2]Position
+	Cards       [2]card.SerializableCardID
+	Values      [2]int
+	Winner      *user.UserID // Nil when the War is tied
+	Points      int          // Points awarded to the winner
+}
+
+// ResolveWar compares the two revealed card values. The higher value wins
+// and is awarded the difference in points; equal values are a tie.
+func ResolveWar(values [2]int) (winner int, points int) {
+	switch {
+	case values[0] > values[1]:
+		return 0, values[0] - values[1]
+	case values[1] > values[0]:
+		return 1, values[1] - values[0]
+	default:
+		return NoWinner, 0
+	}
+}
+
+// Leader returns the index of the player who has won the most rounds.
+// NoWinner is returned if the players are level.
+func (g GameStateData) Leader() int {
+	p1, p2 := g.Players[0], g.Players[1]
+	switch {
+	case p1.RoundsWon > p2.RoundsWon:
+		return 0
+	case p2.RoundsWon > p1.RoundsWon:
+		return 1
+	default:
+		return NoWinner
+	}
+}
+
+// IsFinished reports whether the round limit has been reached or the board
+// has been cleared
+func (g GameStateData) IsFinished() bool {
+	return g.RoundNumber >= g.Rules.RoundLimit || g.BoardState.IsEmpty()
+}
+
+// PlayerIndex returns the index of the player with the given user ID
+func (g GameStateData) PlayerIndex(userId user.UserID) (int, bool) {
+	for i, player := range g.Players {
+		if player.User == userId {
+			return i, true
+		}
+	}
+	return NoWinner, false
+}
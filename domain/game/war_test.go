@@ -0,0 +1,105 @@
+package game_test
+
+import (
+	"encoding/json"
+
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *GameTestSuite) TestResolveWar() {
+	s.Run("should award the difference to the higher card", func() {
+		testCases := []struct {
+			values         [2]int
+			expectedWinner int
+			expectedPoints int
+		}{
+			{[2]int{10, 4}, 0, 6},
+			{[2]int{3, 14}, 1, 11},
+			{[2]int{7, 7}, game.NoWinner, 0},
+		}
+
+		for _, tc := range testCases {
+			winner, points := game.ResolveWar(tc.values)
+			assert.Equal(s.T(), tc.expectedWinner, winner, "Values: %v", tc.values)
+			assert.Equal(s.T(), tc.expectedPoints, points, "Values: %v", tc.values)
+		}
+	})
+}
+
+func (s *GameTestSuite) TestGamePhaseNext() {
+	s.Run("should loop cleanup back to card action", func() {
+		phase := game.PhaseSetup
+		expected := []game.GamePhase{
+			game.PhaseCardAction,
+			game.PhaseReveal,
+			game.PhaseWar,
+			game.PhaseCleanup,
+			game.PhaseCardAction,
+		}
+		for _, e := range expected {
+			next, ok := phase.Next()
+			assert.True(s.T(), ok)
+			assert.Equal(s.T(), e, next)
+			phase = next
+		}
+	})
+
+	s.Run("should reject unknown phases", func() {
+		_, ok := game.GamePhase("unknown").Next()
+		assert.False(s.T(), ok)
+	})
+}
+
+func (s *GameTestSuite) TestLeader() {
+	s.Run("should pick the player who won the most rounds", func() {
+		data := game.GameStateData{}
+		data.Players[0].RoundsWon = 3
+		data.Players[0].Points = 2
+		data.Players[1].RoundsWon = 2
+		data.Players[1].Points = 20
+		assert.Equal(s.T(), 0, data.Leader())
+	})
+
+	s.Run("should return no winner when level on rounds won", func() {
+		data := game.GameStateData{}
+		data.Players[0].RoundsWon = 2
+		data.Players[1].RoundsWon = 2
+		data.Players[1].Points = 5
+		assert.Equal(s.T(), game.NoWinner, data.Leader())
+	})
+}
+
+func (s *GameTestSuite) TestIsFinished() {
+	s.Run("should finish at the round limit", func() {
+		data := game.GameStateData{BoardState: game.NewBoardState(4)}
+		data.Board[0][0].Card = 1
+		data.Rules.RoundLimit = 3
+		data.RoundNumber = 2
+		assert.False(s.T(), data.IsFinished())
+		data.RoundNumber = 3
+		assert.True(s.T(), data.IsFinished())
+	})
+
+	s.Run("should finish when the board is empty", func() {
+		data := game.GameStateData{}
+		data.Rules.RoundLimit = 15
+		assert.True(s.T(), data.IsFinished())
+	})
+}
+
+func (s *GameTestSuite) TestPositionJSON() {
+	s.Run("should round trip positions used as map keys", func() {
+		revealed := map[game.Position]bool{
+			{X: 1, Y: 3}: true,
+		}
+		data, err := json.Marshal(revealed)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), `{"1,3":true}`, string(data))
+
+		var decoded map[game.Position]bool
+		err = json.Unmarshal(data, &decoded)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), revealed, decoded)
+	})
+}
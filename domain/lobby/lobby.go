@@ -0,0 +1,78 @@
+package lobby
+
+import (
+	"crypto/rand"
+	"math/big"
+	"time"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/utils"
+)
+
+const (
+	inviteCodeLength = 6
+	// Letters and digits that are hard to misread when shared
+	inviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+type LobbyPlayer struct {
+	UserID user.UserID             `json:"user_id" validate:"required,gt=0"`
+	DeckID card.SerializableDeckID `json:"deck_id" validate:"required,gt=0"`
+}
+
+// Lobby is a private game that a second player joins with its invite code.
+// The game is created as soon as the guest joins.
+type Lobby struct {
+	InviteCode  string            `json:"invite_code"`
+	Host        LobbyPlayer       `json:"host"`
+	Guest       *LobbyPlayer      `json:"guest,omitempty"`
+	GameStateID *game.GameStateID `json:"game_state_id,omitempty"`
+	Settings    GameSettings      `json:"settings"` // Chosen by the host
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// GameSettings are the host's choices for the game a lobby creates
+type GameSettings struct {
+	Rules      game.RulesPreset       `json:"rules"`
+	Spectating game.SpectatorSettings `json:"spectating"`
+}
+
+func NewLobby(host LobbyPlayer, settings GameSettings) (*Lobby, error) {
+	code, err := NewInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	return &Lobby{
+		InviteCode: code,
+		Host:       host,
+		Settings:   settings,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// Join seats the guest, who must not be the host
+func (l *Lobby) Join(guest LobbyPlayer) error {
+	if l.Guest != nil {
+		return utils.NewInvalidStateError("lobby is full")
+	}
+	if guest.UserID == l.Host.UserID {
+		return utils.NewInvalidArgumentError("host cannot join their own lobby")
+	}
+	l.Guest = &guest
+	return nil
+}
+
+func NewInviteCode() (string, error) {
+	code := make([]byte, inviteCodeLength)
+	max := big.NewInt(int64(len(inviteCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", utils.NewInternalError("failed to generate invite code", err)
+		}
+		code[i] = inviteCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
@@ -0,0 +1,15 @@
+package lobby_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LobbyTestSuite struct {
+	suite.Suite
+}
+
+func TestLobbySuite(t *testing.T) {
+	suite.Run(t, new(LobbyTestSuite))
+}
@@ -0,0 +1,147 @@
+package lobby
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/user"
+)
+
+type MatchmakingMode string
+
+const (
+	MatchmakingModeFIFO   MatchmakingMode = "fifo"   // Pair players in the order they joined
+	MatchmakingModeRating MatchmakingMode = "rating" // Pair players with the closest rating
+)
+
+func (m MatchmakingMode) IsValid() bool {
+	return m == MatchmakingModeFIFO || m == MatchmakingModeRating
+}
+
+const (
+	// A waiting player will be paired with anyone within this rating of them,
+	// and the window widens the longer they wait
+	baseRatingWindow      = 100.0
+	ratingWindowPerSecond = 10.0
+)
+
+// QueueEntry is a player waiting to be matched
+type QueueEntry struct {
+	UserID     user.UserID             `json:"user_id"`
+	DeckID     card.SerializableDeckID `json:"deck_id"`
+	Rating     float64                 `json:"rating"`
+	EnqueuedAt time.Time               `json:"enqueued_at"`
+}
+
+// RatingWindow is how far from their rating a player may be matched after
+// waiting since EnqueuedAt
+func (e QueueEntry) RatingWindow(now time.Time) float64 {
+	return baseRatingWindow + now.Sub(e.EnqueuedAt).Seconds()*ratingWindowPerSecond
+}
+
+// Queue holds waiting players, oldest first
+type Queue struct {
+	Entries []QueueEntry `json:"entries"`
+}
+
+// Position returns the player's 1-based place in the queue
+func (q *Queue) Position(userId user.UserID) (int, bool) {
+	i := q.index(userId)
+	return i + 1, i >= 0
+}
+
+// Add queues the player, behind everyone who has waited longer, unless they
+// are already waiting. Players put back after a failed match keep their place.
+func (q *Queue) Add(entry QueueEntry) bool {
+	if q.index(entry.UserID) >= 0 {
+		return false
+	}
+	i, _ := slices.BinarySearchFunc(q.Entries, entry, func(e, target QueueEntry) int {
+		if e.EnqueuedAt.After(target.EnqueuedAt) {
+			return 1
+		}
+		return -1
+	})
+	q.Entries = slices.Insert(q.Entries, i, entry)
+	return true
+}
+
+func (q *Queue) Remove(userId user.UserID) bool {
+	i := q.index(userId)
+	if i < 0 {
+		return false
+	}
+	q.Entries = slices.Delete(q.Entries, i, i+1)
+	return true
+}
+
+// Match removes and returns the next pair of players. The player who has
+// waited longest is always matched first.
+func (q *Queue) Match(mode MatchmakingMode, now time.Time) (QueueEntry, QueueEntry, bool) {
+	for i, entry := range q.Entries {
+		j := -1
+		switch mode {
+		case MatchmakingModeRating:
+			closest := math.Inf(1)
+			for k := i + 1; k < len(q.Entries); k++ {
+				diff := math.Abs(entry.Rating - q.Entries[k].Rating)
+				if diff < closest && diff <= entry.RatingWindow(now) && diff <= q.Entries[k].RatingWindow(now) {
+					closest = diff
+					j = k
+				}
+			}
+		default:
+			if i+1 < len(q.Entries) {
+				j = i + 1
+			}
+		}
+		if j < 0 {
+			continue
+		}
+		first, second := q.Entries[i], q.Entries[j]
+		q.Entries = slices.Delete(q.Entries, j, j+1)
+		q.Entries = slices.Delete(q.Entries, i, i+1)
+		return first, second, true
+	}
+	return QueueEntry{}, QueueEntry{}, false
+}
+
+func (q *Queue) index(userId user.UserID) int {
+	return slices.IndexFunc(q.Entries, func(e QueueEntry) bool {
+		return e.UserID == userId
+	})
+}
+
+type QueueStatusType string
+
+const (
+	QueueStatusQueued  QueueStatusType = "queued"
+	QueueStatusMatched QueueStatusType = "matched"
+)
+
+// QueueStatus tells a player whether they are still waiting or which game
+// they were matched into
+type QueueStatus struct {
+	Status      QueueStatusType   `json:"status"`
+	Position    int               `json:"position,omitempty"`
+	GameStateID *game.GameStateID `json:"game_state_id,omitempty"`
+	Opponent    *user.UserID      `json:"opponent,omitempty"`
+}
+
+func NewQueuedStatus(position int) *QueueStatus {
+	return &QueueStatus{
+		Status:   QueueStatusQueued,
+		Position: position,
+	}
+}
+
+func NewMatchedStatus(gameStateId game.GameStateID, opponent user.UserID) *QueueStatus {
+	return &QueueStatus{
+		Status:      QueueStatusMatched,
+		GameStateID: &gameStateId,
+		Opponent:    &opponent,
+	}
+}
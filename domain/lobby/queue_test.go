@@ -0,0 +1,106 @@
+package lobby_test
+
+import (
+	"time"
+
+	"github.com/coopersmall/subswag/domain/lobby"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *LobbyTestSuite) TestQueue() {
+	now := time.Now()
+	entry := func(userId user.UserID, rating float64, waited time.Duration) lobby.QueueEntry {
+		return lobby.QueueEntry{
+			UserID:     userId,
+			DeckID:     1,
+			Rating:     rating,
+			EnqueuedAt: now.Add(-waited),
+		}
+	}
+
+	s.Run("should keep the longest waiting player first", func() {
+		queue := &lobby.Queue{}
+		require.True(s.T(), queue.Add(entry(1, 1500, time.Second)))
+		require.True(s.T(), queue.Add(entry(2, 1500, time.Minute)))
+		require.False(s.T(), queue.Add(entry(1, 1500, 0)))
+
+		position, ok := queue.Position(2)
+		require.True(s.T(), ok)
+		assert.Equal(s.T(), 1, position)
+		position, ok = queue.Position(1)
+		require.True(s.T(), ok)
+		assert.Equal(s.T(), 2, position)
+	})
+
+	s.Run("should match in order in fifo mode", func() {
+		queue := &lobby.Queue{}
+		queue.Add(entry(1, 1000, 3*time.Second))
+		queue.Add(entry(2, 2000, 2*time.Second))
+		queue.Add(entry(3, 1000, time.Second))
+
+		first, second, ok := queue.Match(lobby.MatchmakingModeFIFO, now)
+		require.True(s.T(), ok)
+		assert.Equal(s.T(), user.UserID(1), first.UserID)
+		assert.Equal(s.T(), user.UserID(2), second.UserID)
+		assert.Len(s.T(), queue.Entries, 1)
+	})
+
+	s.Run("should match the closest rating in rating mode", func() {
+		queue := &lobby.Queue{}
+		queue.Add(entry(1, 1000, 3*time.Second))
+		queue.Add(entry(2, 2000, 2*time.Second))
+		queue.Add(entry(3, 1050, time.Second))
+
+		first, second, ok := queue.Match(lobby.MatchmakingModeRating, now)
+		require.True(s.T(), ok)
+		assert.Equal(s.T(), user.UserID(1), first.UserID)
+		assert.Equal(s.T(), user.UserID(3), second.UserID)
+		_, ok = queue.Position(2)
+		assert.True(s.T(), ok)
+	})
+
+	s.Run("should widen the rating window over time", func() {
+		queue := &lobby.Queue{}
+		queue.Add(entry(1, 1000, 0))
+		queue.Add(entry(2, 1300, 0))
+
+		_, _, ok := queue.Match(lobby.MatchmakingModeRating, now)
+		assert.False(s.T(), ok)
+		_, _, ok = queue.Match(lobby.MatchmakingModeRating, now.Add(time.Minute))
+		assert.True(s.T(), ok)
+	})
+
+	s.Run("should only accept known matchmaking modes", func() {
+		assert.True(s.T(), lobby.MatchmakingModeFIFO.IsValid())
+		assert.True(s.T(), lobby.MatchmakingModeRating.IsValid())
+		assert.False(s.T(), lobby.MatchmakingMode("random").IsValid())
+	})
+
+	s.Run("should remove players", func() {
+		queue := &lobby.Queue{}
+		queue.Add(entry(1, 1500, 0))
+		assert.True(s.T(), queue.Remove(1))
+		assert.False(s.T(), queue.Remove(1))
+		assert.Empty(s.T(), queue.Entries)
+	})
+}
+
+func (s *LobbyTestSuite) TestLobby() {
+	s.Run("should generate readable invite codes", func() {
+		code, err := lobby.NewInviteCode()
+		require.NoError(s.T(), err)
+		assert.Len(s.T(), code, 6)
+		assert.NotContains(s.T(), code, "0")
+		assert.NotContains(s.T(), code, "O")
+	})
+
+	s.Run("should seat a single guest", func() {
+		l, err := lobby.NewLobby(lobby.LobbyPlayer{UserID: 1, DeckID: 1}, lobby.GameSettings{})
+		require.NoError(s.T(), err)
+		assert.Error(s.T(), l.Join(lobby.LobbyPlayer{UserID: 1, DeckID: 2}))
+		require.NoError(s.T(), l.Join(lobby.LobbyPlayer{UserID: 2, DeckID: 3}))
+		assert.Error(s.T(), l.Join(lobby.LobbyPlayer{UserID: 3, DeckID: 4}))
+	})
+}
@@ -0,0 +1,212 @@
+package pack
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/utils"
+)
+
+type PackOpeningID utils.ID
+
+func NewPackOpeningID() PackOpeningID {
+	return PackOpeningID(utils.NewID())
+}
+
+// PackOpening is the audit record of one pack a user opened. It keeps the
+// pack as it was defined at the time, every roll and the card it dealt, and
+// the pity counter before and after, so any opening can be explained later.
+type PackOpening struct {
+	ID              PackOpeningID `json:"id" validate:"required,gt=0" tstype:"string"`
+	PackOpeningData `json:",inline" validate:"required" tstype:",extends"`
+	Metadata        *domain.Metadata `json:"metadata" validate:"required" tstype:"Metadata"`
+}
+
+type PackOpeningData struct {
+	UserID        user.UserID  `json:"user_id" validate:"required" tstype:"string"`
+	PackTypeID    PackTypeID   `json:"pack_type_id" validate:"required" tstype:"string"`
+	Sequence      int          `json:"sequence" validate:"gt=0" tstype:"number"` // The user's nth pack of this type
+	PackType      PackTypeData `json:"pack_type" tstype:"PackTypeData"`          // The pack's definition when opened
+	Pulls         []PackPull   `json:"pulls" validate:"required" tstype:"Array<PackPull>"`
+	PityBefore    int          `json:"pity_before" tstype:"number"`
+	PityAfter     int          `json:"pity_after" tstype:"number"`
+	PityTriggered bool         `json:"pity_triggered" tstype:"boolean"`
+}
+
+// PackPull is the card one slot of a pack dealt
+type PackPull struct {
+	Slot       int                         `json:"slot" tstype:"number"`
+	Roll       *int                        `json:"roll,omitempty" tstype:"number,optional"` // Out of OddsTotal, for slots with odds
+	Rarity     card.CardRarity             `json:"rarity" tstype:"CardRarity"`
+	Pity       bool                        `json:"pity,omitempty" tstype:"boolean,optional"` // Raised to the pity rarity
+	CardID     card.SerializableCardID     `json:"card_id" tstype:"string"`
+	UserCardID card.UserSerializableCardID `json:"user_card_id" tstype:"string"` // The copy added to the collection
+}
+
+// PackPityCounter is how close a user is to the pity rule of a pack type
+type PackPityCounter struct {
+	PackTypeID PackTypeID `json:"pack_type_id" tstype:"string"`
+	Count      int        `json:"count" tstype:"number"`                     // Packs opened in a row without the pity rarity
+	Pity       *PackPity  `json:"pity,omitempty" tstype:"PackPity,optional"` // The rule the count is for, if any
+}
+
+// NewPackPityCounter reads the counter from the user's latest opening of the
+// pack type, nil if they have never opened one
+func NewPackPityCounter(packType *PackType, latest *PackOpening) *PackPityCounter {
+	counter := &PackPityCounter{
+		PackTypeID: packType.ID,
+		Pity:       packType.Pity,
+	}
+	if latest != nil && packType.Pity != nil {
+		counter.Count = latest.PityAfter
+	}
+	return counter
+}
+
+// Roller returns a uniformly random number in [0, n)
+type Roller func(n int) (int, error)
+
+// CryptoRoller rolls with crypto/rand, so the contents of a pack cannot be
+// predicted from earlier packs
+func CryptoRoller(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, utils.NewInternalError("failed to roll", err)
+	}
+	return int(v.Int64()), nil
+}
+
+// Open deals a pack for the user from the catalog's cards, listed by rarity.
+// previous is the user's last opening of this pack type, or nil if this is
+// their first, and carries the pity counter forward. Every rarity the pack
+// can contain must have at least one card. Nothing is saved: the opening
+// holds the copies to add to the user's collection.
+func (p *PackType) Open(
+	userId user.UserID,
+	previous *PackOpening,
+	catalog map[card.CardRarity][]card.SerializableCardID,
+	roll Roller,
+) (*PackOpening, error) {
+	byRarity := map[card.CardRarity][]card.SerializableCardID{}
+	for _, rarity := range p.Rarities() {
+		if len(catalog[rarity]) == 0 {
+			return nil, utils.NewInvalidStateError(fmt.Sprintf("pack %q can contain %s cards but the catalog has none", p.Name, rarity))
+		}
+		byRarity[rarity] = slices.Sorted(slices.Values(catalog[rarity]))
+	}
+
+	opening := &PackOpening{
+		ID: NewPackOpeningID(),
+		PackOpeningData: PackOpeningData{
+			UserID:     userId,
+			PackTypeID: p.ID,
+			Sequence:   1,
+			PackType:   p.PackTypeData,
+			Pulls:      make([]PackPull, len(p.Slots)),
+		},
+		Metadata: domain.NewMetadata(),
+	}
+	if previous != nil {
+		opening.Sequence = previous.Sequence + 1
+		opening.PityBefore = previous.PityAfter
+	}
+
+	for i, slot := range p.Slots {
+		pull := PackPull{Slot: i, Rarity: slot.Guaranteed}
+		if pull.Rarity == "" {
+			n, err := roll(OddsTotal)
+			if err != nil {
+				return nil, err
+			}
+			pull.Roll = &n
+			pull.Rarity = slot.rarityFor(n)
+		}
+		opening.Pulls[i] = pull
+	}
+
+	if p.Pity != nil {
+		hit := slices.ContainsFunc(opening.Pulls, func(pull PackPull) bool {
+			return rarityRank(pull.Rarity) >= rarityRank(p.Pity.Rarity)
+		})
+		if !hit && opening.PityBefore+1 >= p.Pity.After {
+			if raised := lowestRolled(opening.Pulls); raised != nil {
+				raised.Rarity = p.Pity.Rarity
+				raised.Pity = true
+				opening.PityTriggered = true
+				hit = true
+			}
+		}
+		if !hit {
+			opening.PityAfter = opening.PityBefore + 1
+		}
+	}
+
+	for i := range opening.Pulls {
+		pull := &opening.Pulls[i]
+		cardIds := byRarity[pull.Rarity]
+		n, err := roll(len(cardIds))
+		if err != nil {
+			return nil, err
+		}
+		pull.CardID = cardIds[n]
+		pull.UserCardID = card.NewUserSerializableCardID()
+	}
+	return opening, nil
+}
+
+// lowestRolled returns the rolled pull with the lowest rarity, the last one
+// on a tie, or nil if every slot was guaranteed
+func lowestRolled(pulls []PackPull) *PackPull {
+	var lowest *PackPull
+	for i := range pulls {
+		pull := &pulls[i]
+		if pull.Roll != nil && (lowest == nil || rarityRank(pull.Rarity) <= rarityRank(lowest.Rarity)) {
+			lowest = pull
+		}
+	}
+	return lowest
+}
+
+// rarityFor returns the rarity a roll in [0, OddsTotal) lands on. Each
+// rarity covers a range as wide as its odds, from common to legendary.
+func (s PackSlot) rarityFor(n int) card.CardRarity {
+	rarities := sortedRarities(s.Odds)
+	for _, rarity := range rarities {
+		if n < s.Odds[rarity] {
+			return rarity
+		}
+		n -= s.Odds[rarity]
+	}
+	return rarities[len(rarities)-1]
+}
+
+// CardIDs lists the card every slot dealt, in slot order
+func (o *PackOpening) CardIDs() []card.SerializableCardID {
+	cardIds := make([]card.SerializableCardID, len(o.Pulls))
+	for i, pull := range o.Pulls {
+		cardIds[i] = pull.CardID
+	}
+	return cardIds
+}
+
+// UserCards returns the copies the opening adds to the user's collection
+func (o *PackOpening) UserCards() []*card.UserSerializableCard {
+	userCards := make([]*card.UserSerializableCard, len(o.Pulls))
+	for i, pull := range o.Pulls {
+		userCards[i] = &card.UserSerializableCard{
+			ID: pull.UserCardID,
+			UserSerializableCardData: card.UserSerializableCardData{
+				UserID: o.UserID,
+				CardID: pull.CardID,
+				Source: card.CardSourcePack,
+			},
+			Metadata: &domain.Metadata{CreatedAt: o.Metadata.CreatedAt},
+		}
+	}
+	return userCards
+}
@@ -0,0 +1,15 @@
+package pack_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PackTestSuite struct {
+	suite.Suite
+}
+
+func TestPackSuite(t *testing.T) {
+	suite.Run(t, new(PackTestSuite))
+}
@@ -0,0 +1,210 @@
+package pack_test
+
+import (
+	"errors"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/pack"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPackType() *pack.PackType {
+	return pack.NewPackType(pack.PackTypeData{
+		Name: "Starter",
+		Slots: []pack.PackSlot{
+			{Guaranteed: card.CardRarityCommon},
+			{Odds: map[card.CardRarity]int{
+				card.CardRarityCommon:    7000,
+				card.CardRarityRare:      2500,
+				card.CardRarityEpic:      400,
+				card.CardRarityLegendary: 100,
+			}},
+		},
+		Pity: &pack.PackPity{Rarity: card.CardRarityEpic, After: 3},
+	})
+}
+
+// rolls returns a roller that answers with each value in turn and records
+// the range of every roll
+func rolls(values ...int) (pack.Roller, *[]int) {
+	ranges := &[]int{}
+	return func(n int) (int, error) {
+		*ranges = append(*ranges, n)
+		if len(values) == 0 {
+			return 0, errors.New("out of rolls")
+		}
+		value := values[0]
+		values = values[1:]
+		return value, nil
+	}, ranges
+}
+
+func (s *PackTestSuite) TestValidate() {
+	s.Run("should accept a valid pack type", func() {
+		assert.NoError(s.T(), newPackType().Validate())
+	})
+
+	s.Run("should report every problem", func() {
+		packType := newPackType()
+		packType.Slots = append(packType.Slots,
+			pack.PackSlot{Guaranteed: card.CardRarityRare, Odds: map[card.CardRarity]int{card.CardRarityRare: 10_000}},
+			pack.PackSlot{Guaranteed: "mythic"},
+			pack.PackSlot{Odds: map[card.CardRarity]int{card.CardRarityCommon: 9000, card.CardRarityRare: 0}},
+		)
+		packType.Pity.After = 0
+		assert.EqualError(s.T(), packType.Validate(), `subswag.invalid_argument: pack type "Starter" is invalid: `+
+			`slots[2]: cannot have odds and be guaranteed; `+
+			`slots[3]: unknown rarity "mythic"; `+
+			`slots[4]: odds of rare must be positive; `+
+			`slots[4]: odds add up to 9000, must be 10000; `+
+			`pity: after must be at least 1`)
+	})
+
+	s.Run("should need a slot with odds for the pity rule to raise", func() {
+		packType := newPackType()
+		packType.Slots = []pack.PackSlot{{Guaranteed: card.CardRarityCommon}}
+		assert.ErrorContains(s.T(), packType.Validate(), "pity: needs a slot with odds")
+	})
+
+	s.Run("should need at least one slot", func() {
+		packType := newPackType()
+		packType.Slots = []pack.PackSlot{}
+		assert.ErrorContains(s.T(), packType.Validate(), "must have between 1 and 15 slots")
+	})
+}
+
+func (s *PackTestSuite) TestOpen() {
+	userId := user.UserID(1)
+	catalog := map[card.CardRarity][]card.SerializableCardID{
+		card.CardRarityCommon:    {2, 1},
+		card.CardRarityRare:      {3},
+		card.CardRarityEpic:      {5},
+		card.CardRarityLegendary: {4},
+	}
+
+	s.Run("should roll each slot's odds then pick a card of that rarity", func() {
+		tests := map[int]card.CardRarity{
+			0:    card.CardRarityCommon,
+			6999: card.CardRarityCommon,
+			7000: card.CardRarityRare,
+			9499: card.CardRarityRare,
+			9500: card.CardRarityEpic,
+			9900: card.CardRarityLegendary,
+			9999: card.CardRarityLegendary,
+		}
+		for roll, rarity := range tests {
+			roller, _ := rolls(roll, 0, 0)
+			opening, err := newPackType().Open(userId, nil, catalog, roller)
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), rarity, opening.Pulls[1].Rarity, roll)
+			assert.Equal(s.T(), roll, *opening.Pulls[1].Roll)
+		}
+	})
+
+	s.Run("should record the opening", func() {
+		packType := newPackType()
+		roller, ranges := rolls(7000, 1, 0)
+		opening, err := packType.Open(userId, nil, catalog, roller)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), []int{10_000, 2, 1}, *ranges)
+
+		assert.Equal(s.T(), userId, opening.UserID)
+		assert.Equal(s.T(), packType.ID, opening.PackTypeID)
+		assert.Equal(s.T(), packType.PackTypeData, opening.PackType)
+		assert.Equal(s.T(), 1, opening.Sequence)
+		assert.Nil(s.T(), opening.Pulls[0].Roll)
+		assert.Equal(s.T(), []card.SerializableCardID{2, 3}, opening.CardIDs())
+
+		userCards := opening.UserCards()
+		require.Len(s.T(), userCards, 2)
+		for i, userCard := range userCards {
+			assert.Equal(s.T(), opening.Pulls[i].UserCardID, userCard.ID)
+			assert.Equal(s.T(), opening.Pulls[i].CardID, userCard.CardID)
+			assert.Equal(s.T(), card.CardSourcePack, userCard.Source)
+			assert.Equal(s.T(), userId, userCard.UserID)
+		}
+	})
+
+	s.Run("should count packs without the pity rarity and raise a rolled slot when it runs out", func() {
+		packType := newPackType()
+		previous := &pack.PackOpening{PackOpeningData: pack.PackOpeningData{Sequence: 4, PityAfter: 1}}
+
+		roller, _ := rolls(0, 0, 0)
+		opening, err := packType.Open(userId, previous, catalog, roller)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), 5, opening.Sequence)
+		assert.Equal(s.T(), 1, opening.PityBefore)
+		assert.Equal(s.T(), 2, opening.PityAfter)
+		assert.False(s.T(), opening.PityTriggered)
+
+		roller, _ = rolls(0, 0, 0)
+		opening, err = packType.Open(userId, opening, catalog, roller)
+		require.NoError(s.T(), err)
+		assert.True(s.T(), opening.PityTriggered)
+		assert.True(s.T(), opening.Pulls[1].Pity)
+		assert.Equal(s.T(), card.CardRarityEpic, opening.Pulls[1].Rarity)
+		assert.Equal(s.T(), card.SerializableCardID(5), opening.Pulls[1].CardID)
+		assert.Equal(s.T(), 0, opening.PityAfter)
+	})
+
+	s.Run("should raise the lowest rolled rarity and leave guaranteed slots alone", func() {
+		packType := newPackType()
+		packType.Slots = []pack.PackSlot{packType.Slots[1], packType.Slots[1], {Guaranteed: card.CardRarityCommon}}
+		packType.Pity.After = 1
+
+		roller, _ := rolls(7000, 0, 0, 0, 0)
+		opening, err := packType.Open(userId, nil, catalog, roller)
+		require.NoError(s.T(), err)
+		assert.True(s.T(), opening.PityTriggered)
+		assert.Equal(s.T(), []card.CardRarity{card.CardRarityRare, card.CardRarityEpic, card.CardRarityCommon},
+			[]card.CardRarity{opening.Pulls[0].Rarity, opening.Pulls[1].Rarity, opening.Pulls[2].Rarity})
+		assert.False(s.T(), opening.Pulls[0].Pity)
+		assert.True(s.T(), opening.Pulls[1].Pity)
+		assert.False(s.T(), opening.Pulls[2].Pity)
+	})
+
+	s.Run("should reset the pity counter when a pack has the rarity or better", func() {
+		previous := &pack.PackOpening{PackOpeningData: pack.PackOpeningData{Sequence: 1, PityAfter: 1}}
+		roller, _ := rolls(9900, 0, 0)
+		opening, err := newPackType().Open(userId, previous, catalog, roller)
+		require.NoError(s.T(), err)
+		assert.False(s.T(), opening.PityTriggered)
+		assert.Equal(s.T(), 0, opening.PityAfter)
+	})
+
+	s.Run("should read the pity counter from the latest opening", func() {
+		packType := newPackType()
+		latest := &pack.PackOpening{PackOpeningData: pack.PackOpeningData{PityAfter: 2}}
+		assert.Equal(s.T(), 2, pack.NewPackPityCounter(packType, latest).Count)
+		assert.Equal(s.T(), 0, pack.NewPackPityCounter(packType, nil).Count)
+
+		packType.Pity = nil
+		assert.Equal(s.T(), &pack.PackPityCounter{PackTypeID: packType.ID}, pack.NewPackPityCounter(packType, latest))
+	})
+
+	s.Run("should fail when the catalog has no cards of a rarity the pack can contain", func() {
+		roller, _ := rolls(0, 0, 0)
+		_, err := newPackType().Open(userId, nil, map[card.CardRarity][]card.SerializableCardID{
+			card.CardRarityCommon:    {1},
+			card.CardRarityRare:      {3},
+			card.CardRarityLegendary: {4},
+		}, roller)
+		assert.ErrorContains(s.T(), err, `pack "Starter" can contain epic cards but the catalog has none`)
+	})
+
+	s.Run("should fail when a roll fails", func() {
+		roller, _ := rolls(0)
+		_, err := newPackType().Open(userId, nil, catalog, roller)
+		assert.EqualError(s.T(), err, "out of rolls")
+	})
+
+	s.Run("should roll with crypto/rand", func() {
+		for range 100 {
+			n, err := pack.CryptoRoller(3)
+			require.NoError(s.T(), err)
+			assert.True(s.T(), n >= 0 && n < 3)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package pack
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// OddsTotal is what the odds of every rolled slot add up to, so odds are in
+// basis points: 2500 is a 25% chance
+const OddsTotal = 10_000
+
+// MaxSlots is the most cards one pack can hold
+const MaxSlots = 15
+
+type PackTypeID utils.ID
+
+func NewPackTypeID() PackTypeID {
+	return PackTypeID(utils.NewID())
+}
+
+// PackType is a kind of booster pack defined by an admin: the slots that are
+// filled with a card when it is opened, and the pity rule that protects
+// players from long streaks of bad luck
+type PackType struct {
+	ID           PackTypeID `json:"id" validate:"required,gt=0" tstype:"string"`
+	PackTypeData `json:",inline" validate:"required" tstype:",extends"`
+	Metadata     *domain.Metadata `json:"metadata" validate:"required" tstype:"Metadata"`
+}
+
+type PackTypeData struct {
+	Name  string     `json:"name" validate:"required,max=64" tstype:"string"`
+	Slots []PackSlot `json:"slots" validate:"required" tstype:"Array<PackSlot>"`
+	Pity  *PackPity  `json:"pity,omitempty" tstype:"PackPity,optional"`
+}
+
+// PackSlot is one card in a pack. A guaranteed slot is always its rarity;
+// any other slot rolls its odds.
+type PackSlot struct {
+	Odds       map[card.CardRarity]int `json:"odds,omitempty" tstype:"Record<CardRarity, number>,optional"`
+	Guaranteed card.CardRarity         `json:"guaranteed,omitempty" tstype:"CardRarity,optional"`
+}
+
+// PackPity guarantees a card of Rarity or better in at least one of every
+// After packs of a type a user opens. When the previous After-1 packs had
+// none, the lowest rarity rolled in the next pack is raised to Rarity;
+// guaranteed slots are never changed.
+type PackPity struct {
+	Rarity card.CardRarity `json:"rarity" tstype:"CardRarity"`
+	After  int             `json:"after" tstype:"number"`
+}
+
+func NewPackType(data PackTypeData) *PackType {
+	return &PackType{
+		ID:           NewPackTypeID(),
+		PackTypeData: data,
+		Metadata:     domain.NewMetadata(),
+	}
+}
+
+// Validate checks the pack can be opened: it has between 1 and MaxSlots
+// slots, every slot is either guaranteed or has odds of known rarities adding
+// up to OddsTotal, and the pity rule names a known rarity and has a slot with
+// odds to raise. All problems are reported together.
+func (p *PackType) Validate() error {
+	if err := domain.Validate(p); err != nil {
+		return err
+	}
+	var problems []string
+	addProblem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if len(p.Slots) == 0 || len(p.Slots) > MaxSlots {
+		addProblem("must have between 1 and %d slots", MaxSlots)
+	}
+	for i, slot := range p.Slots {
+		switch {
+		case slot.Guaranteed != "" && len(slot.Odds) > 0:
+			addProblem("slots[%d]: cannot have odds and be guaranteed", i)
+		case slot.Guaranteed != "":
+			if !slices.Contains(card.CardRarities, slot.Guaranteed) {
+				addProblem("slots[%d]: unknown rarity %q", i, slot.Guaranteed)
+			}
+		default:
+			total := 0
+			for _, rarity := range sortedRarities(slot.Odds) {
+				if !slices.Contains(card.CardRarities, rarity) {
+					addProblem("slots[%d]: unknown rarity %q", i, rarity)
+				}
+				if slot.Odds[rarity] <= 0 {
+					addProblem("slots[%d]: odds of %s must be positive", i, rarity)
+				}
+				total += slot.Odds[rarity]
+			}
+			if total != OddsTotal {
+				addProblem("slots[%d]: odds add up to %d, must be %d", i, total, OddsTotal)
+			}
+		}
+	}
+	if p.Pity != nil {
+		if !slices.Contains(card.CardRarities, p.Pity.Rarity) {
+			addProblem("pity: unknown rarity %q", p.Pity.Rarity)
+		}
+		if p.Pity.After < 1 {
+			addProblem("pity: after must be at least 1")
+		}
+		if !slices.ContainsFunc(p.Slots, func(slot PackSlot) bool { return slot.Guaranteed == "" }) {
+			addProblem("pity: needs a slot with odds")
+		}
+	}
+
+	if len(problems) > 0 {
+		return utils.NewInvalidArgumentError(fmt.Sprintf("pack type %q is invalid: %s", p.Name, strings.Join(problems, "; ")))
+	}
+	return nil
+}
+
+// Rarities returns every rarity the pack can contain, rarest last
+func (p *PackType) Rarities() []card.CardRarity {
+	var rarities []card.CardRarity
+	for _, rarity := range card.CardRarities {
+		possible := p.Pity != nil && p.Pity.Rarity == rarity
+		for _, slot := range p.Slots {
+			possible = possible || slot.Guaranteed == rarity || slot.Odds[rarity] > 0
+		}
+		if possible {
+			rarities = append(rarities, rarity)
+		}
+	}
+	return rarities
+}
+
+// rarityRank orders rarities from common to legendary. Unknown rarities rank
+// below common.
+func rarityRank(rarity card.CardRarity) int {
+	return slices.Index(card.CardRarities, rarity)
+}
+
+// sortedRarities lists the rarities in odds from common to legendary, so
+// rolls and problems are always in the same order
+func sortedRarities(odds map[card.CardRarity]int) []card.CardRarity {
+	rarities := make([]card.CardRarity, 0, len(odds))
+	for rarity := range odds {
+		rarities = append(rarities, rarity)
+	}
+	slices.SortFunc(rarities, func(a, b card.CardRarity) int {
+		if rank := rarityRank(a) - rarityRank(b); rank != 0 {
+			return rank
+		}
+		return strings.Compare(string(a), string(b))
+	})
+	return rarities
+}
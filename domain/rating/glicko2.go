@@ -0,0 +1,129 @@
+package rating
+
+import (
+	"math"
+)
+
+// Glicko-2 constants, see http://www.glicko.net/glicko/glicko2.pdf
+const (
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+
+	// tau constrains how quickly volatility can change
+	tau       = 0.5
+	scale     = 173.7178
+	tolerance = 0.000001
+)
+
+// Glicko2 is a player's skill estimate. Deviation shrinks as more games are
+// played and Volatility tracks how erratic their results have been.
+type Glicko2 struct {
+	Rating     float64 `json:"rating"`
+	Deviation  float64 `json:"deviation"`
+	Volatility float64 `json:"volatility"`
+}
+
+func NewGlicko2() Glicko2 {
+	return Glicko2{
+		Rating:     DefaultRating,
+		Deviation:  DefaultDeviation,
+		Volatility: DefaultVolatility,
+	}
+}
+
+// Score is the result of a game from the player's point of view
+type Score float64
+
+const (
+	ScoreLoss Score = 0
+	ScoreDraw Score = 0.5
+	ScoreWin  Score = 1
+)
+
+// Result is a game played against an opponent during a rating period
+type Result struct {
+	Opponent Glicko2
+	Score    Score
+}
+
+// Update returns the player's rating after the rating period's results. A
+// period without games only grows the deviation.
+func (g Glicko2) Update(results ...Result) Glicko2 {
+	mu := (g.Rating - DefaultRating) / scale
+	phi := g.Deviation / scale
+
+	if len(results) == 0 {
+		return Glicko2{
+			Rating:     g.Rating,
+			Deviation:  math.Min(math.Sqrt(phi*phi+g.Volatility*g.Volatility)*scale, DefaultDeviation),
+			Volatility: g.Volatility,
+		}
+	}
+
+	var vInv, sum float64
+	for _, result := range results {
+		muJ := (result.Opponent.Rating - DefaultRating) / scale
+		phiJ := result.Opponent.Deviation / scale
+		gPhi := weight(phiJ)
+		e := expectedScore(mu, muJ, gPhi)
+		vInv += gPhi * gPhi * e * (1 - e)
+		sum += gPhi * (float64(result.Score) - e)
+	}
+	v := 1 / vInv
+	delta := v * sum
+
+	volatility := newVolatility(phi, v, delta, g.Volatility)
+	phiStar := math.Sqrt(phi*phi + volatility*volatility)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*sum
+
+	return Glicko2{
+		Rating:     newMu*scale + DefaultRating,
+		Deviation:  math.Min(newPhi*scale, DefaultDeviation),
+		Volatility: volatility,
+	}
+}
+
+func weight(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func expectedScore(mu, muJ, gPhi float64) float64 {
+	return 1 / (1 + math.Exp(-gPhi*(mu-muJ)))
+}
+
+// newVolatility solves for the new volatility with the Illinois algorithm
+func newVolatility(phi, v, delta, sigma float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > tolerance {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
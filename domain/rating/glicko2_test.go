@@ -0,0 +1,32 @@
+package rating_test
+
+import (
+	"github.com/coopersmall/subswag/domain/rating"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *RatingTestSuite) TestGlicko2() {
+	s.Run("should match the worked example from the Glicko-2 paper", func() {
+		player := rating.Glicko2{Rating: 1500, Deviation: 200, Volatility: 0.06}
+		updated := player.Update(
+			rating.Result{Opponent: rating.Glicko2{Rating: 1400, Deviation: 30}, Score: rating.ScoreWin},
+			rating.Result{Opponent: rating.Glicko2{Rating: 1550, Deviation: 100}, Score: rating.ScoreLoss},
+			rating.Result{Opponent: rating.Glicko2{Rating: 1700, Deviation: 300}, Score: rating.ScoreLoss},
+		)
+		assert.InDelta(s.T(), 1464.06, updated.Rating, 0.01)
+		assert.InDelta(s.T(), 151.52, updated.Deviation, 0.01)
+		assert.InDelta(s.T(), 0.05999, updated.Volatility, 0.00001)
+	})
+
+	s.Run("should only grow the deviation without games", func() {
+		player := rating.Glicko2{Rating: 1600, Deviation: 50, Volatility: 0.06}
+		updated := player.Update()
+		assert.Equal(s.T(), player.Rating, updated.Rating)
+		assert.Greater(s.T(), updated.Deviation, player.Deviation)
+	})
+
+	s.Run("should never grow the deviation past the default", func() {
+		updated := rating.NewGlicko2().Update()
+		assert.Equal(s.T(), rating.DefaultDeviation, updated.Deviation)
+	})
+}
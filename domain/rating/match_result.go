@@ -0,0 +1,76 @@
+package rating
+
+import (
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// MatchPlayer is one side of a finished game
+type MatchPlayer struct {
+	UserID       user.UserID             `json:"user_id"`
+	DeckID       card.SerializableDeckID `json:"deck_id"`
+	RoundsWon    int                     `json:"rounds_won"`
+	Points       int                     `json:"points"`
+	Score        Score                   `json:"score"`
+	RatingBefore float64                 `json:"rating_before"`
+	RatingAfter  float64                 `json:"rating_after"`
+}
+
+// MatchResult records a finished game in the players' match history
+type MatchResult struct {
+	GameStateID  game.GameStateID `json:"game_state_id"`
+	Players      [2]MatchPlayer   `json:"players"`
+	Winner       *user.UserID     `json:"winner,omitempty"`
+	RoundsPlayed int              `json:"rounds_played"`
+	Metadata     *domain.Metadata `json:"metadata"`
+}
+
+// NewMatchResult summarises a completed game. The players' ratings are filled
+// in once they have been updated.
+func NewMatchResult(gameState *game.GameState) (*MatchResult, error) {
+	if !gameState.IsComplete {
+		return nil, utils.NewInvalidStateError("game is not complete")
+	}
+	result := &MatchResult{
+		GameStateID:  gameState.ID,
+		RoundsPlayed: len(gameState.WarResults),
+		Metadata:     domain.NewMetadata(),
+	}
+	if gameState.Winner != nil {
+		result.Winner = &gameState.Winner.ID
+	}
+	for i, player := range gameState.Players {
+		result.Players[i] = MatchPlayer{
+			UserID:    player.User,
+			DeckID:    player.DeckID,
+			RoundsWon: player.RoundsWon,
+			Points:    player.Points,
+			Score:     scoreFor(player.User, result.Winner),
+		}
+	}
+	return result, nil
+}
+
+// Rate applies the result to both players' ratings and records the change
+func (m *MatchResult) Rate(ratings [2]*UserRating) {
+	before := [2]Glicko2{ratings[0].Glicko2, ratings[1].Glicko2}
+	for i := range ratings {
+		m.Players[i].RatingBefore = before[i].Rating
+		ratings[i].Record(before[1-i], m.Players[i].Score)
+		m.Players[i].RatingAfter = ratings[i].Rating
+	}
+}
+
+func scoreFor(userId user.UserID, winner *user.UserID) Score {
+	switch {
+	case winner == nil:
+		return ScoreDraw
+	case *winner == userId:
+		return ScoreWin
+	default:
+		return ScoreLoss
+	}
+}
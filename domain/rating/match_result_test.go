@@ -0,0 +1,79 @@
+package rating_test
+
+import (
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/rating"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *RatingTestSuite) TestMatchResult() {
+	newGameState := func(winner *user.User) *game.GameState {
+		gameState := &game.GameState{ID: 1}
+		gameState.Players[0] = game.PlayerState{User: 1, DeckID: 10, RoundsWon: 3, Points: 40}
+		gameState.Players[1] = game.PlayerState{User: 2, DeckID: 20, RoundsWon: 2, Points: 25}
+		gameState.WarResults = make([]game.WarResult, 5)
+		gameState.IsComplete = true
+		gameState.Winner = winner
+		return gameState
+	}
+
+	s.Run("should not summarise a game in progress", func() {
+		gameState := newGameState(nil)
+		gameState.IsComplete = false
+		_, err := rating.NewMatchResult(gameState)
+		assert.True(s.T(), utils.IsInvalidStateError(err))
+	})
+
+	s.Run("should score the winner and loser", func() {
+		result, err := rating.NewMatchResult(newGameState(&user.User{ID: 1}))
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), 5, result.RoundsPlayed)
+		require.NotNil(s.T(), result.Winner)
+		assert.Equal(s.T(), user.UserID(1), *result.Winner)
+		assert.Equal(s.T(), rating.ScoreWin, result.Players[0].Score)
+		assert.Equal(s.T(), rating.ScoreLoss, result.Players[1].Score)
+		assert.EqualValues(s.T(), 20, result.Players[1].DeckID)
+	})
+
+	s.Run("should score a game without a winner as a draw", func() {
+		result, err := rating.NewMatchResult(newGameState(nil))
+		require.NoError(s.T(), err)
+		assert.Nil(s.T(), result.Winner)
+		assert.Equal(s.T(), rating.ScoreDraw, result.Players[0].Score)
+		assert.Equal(s.T(), rating.ScoreDraw, result.Players[1].Score)
+	})
+
+	s.Run("should rate both players against their rating before the game", func() {
+		result, err := rating.NewMatchResult(newGameState(&user.User{ID: 1}))
+		require.NoError(s.T(), err)
+		ratings := [2]*rating.UserRating{rating.NewUserRating(1), rating.NewUserRating(2)}
+		result.Rate(ratings)
+
+		assert.Equal(s.T(), rating.DefaultRating, result.Players[0].RatingBefore)
+		assert.Equal(s.T(), rating.DefaultRating, result.Players[1].RatingBefore)
+		assert.Greater(s.T(), result.Players[0].RatingAfter, rating.DefaultRating)
+		assert.Less(s.T(), result.Players[1].RatingAfter, rating.DefaultRating)
+		assert.InDelta(s.T(), rating.DefaultRating-result.Players[1].RatingAfter, result.Players[0].RatingAfter-rating.DefaultRating, 0.000001)
+
+		assert.Equal(s.T(), 1, ratings[0].GamesPlayed)
+		assert.Equal(s.T(), 1, ratings[0].GamesWon)
+		assert.Equal(s.T(), 1, ratings[1].GamesPlayed)
+		assert.Equal(s.T(), 0, ratings[1].GamesWon)
+	})
+}
+
+func (s *RatingTestSuite) TestLeaderboard() {
+	s.Run("should rank from the offset", func() {
+		entries := rating.NewLeaderboard([]*rating.UserRating{
+			rating.NewUserRating(1),
+			rating.NewUserRating(2),
+		}, 10)
+		require.Len(s.T(), entries, 2)
+		assert.Equal(s.T(), 11, entries[0].Rank)
+		assert.Equal(s.T(), user.UserID(1), entries[0].UserID)
+		assert.Equal(s.T(), 12, entries[1].Rank)
+	})
+}
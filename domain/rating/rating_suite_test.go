@@ -0,0 +1,15 @@
+package rating_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RatingTestSuite struct {
+	suite.Suite
+}
+
+func TestRatingSuite(t *testing.T) {
+	suite.Run(t, new(RatingTestSuite))
+}
@@ -0,0 +1,53 @@
+package rating
+
+import (
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/user"
+)
+
+// UserRating is a player's current rating and record across every game
+type UserRating struct {
+	UserID      user.UserID `json:"user_id" validate:"required,gt=0"`
+	Glicko2     `json:",inline" tstype:",extends"`
+	GamesPlayed int              `json:"games_played" validate:"gte=0"`
+	GamesWon    int              `json:"games_won" validate:"gte=0"`
+	Version     int64            `json:"-"` // Bumped each time a game is recorded; a game rated from an older version is rejected
+	Metadata    *domain.Metadata `json:"metadata" validate:"required" tstype:"Metadata"`
+}
+
+// NewUserRating is the rating of a player who has not finished a game yet
+func NewUserRating(userId user.UserID) *UserRating {
+	return &UserRating{
+		UserID:   userId,
+		Glicko2:  NewGlicko2(),
+		Metadata: domain.NewMetadata(),
+	}
+}
+
+// Record applies a finished game to the player's rating and record
+func (r *UserRating) Record(opponent Glicko2, score Score) {
+	r.Glicko2 = r.Glicko2.Update(Result{Opponent: opponent, Score: score})
+	r.GamesPlayed++
+	if score == ScoreWin {
+		r.GamesWon++
+	}
+}
+
+// LeaderboardEntry is a player's place on a leaderboard
+type LeaderboardEntry struct {
+	Rank int `json:"rank"`
+	*UserRating
+}
+
+// NewLeaderboard ranks ratings that are already sorted best first. offset is
+// the number of players ranked above the first rating.
+func NewLeaderboard(ratings []*UserRating, offset int) []LeaderboardEntry {
+	entries := make([]LeaderboardEntry, len(ratings))
+	for i, r := range ratings {
+		entries[i] = LeaderboardEntry{
+			Rank:       offset + i + 1,
+			UserRating: r,
+		}
+	}
+	return entries
+}
@@ -0,0 +1,213 @@
+package simulation
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/coopersmall/subswag/domain/bot"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+)
+
+// Report sums up a batch of simulated games between two decks. The decks
+// swap seats between games, so results are reported per side rather than
+// per seat.
+type Report struct {
+	Games         int           `json:"games"`
+	Draws         int           `json:"draws"`
+	Failed        int           `json:"failed"` // Games the runner could not finish
+	AverageRounds float64       `json:"average_rounds"`
+	Sides         [2]SideReport `json:"sides"`
+	Lengths       map[int]int   `json:"lengths"` // Number of games by rounds played
+	Effects       []EffectCount `json:"effects"`
+
+	rounds  int
+	effects map[effectKey]int
+}
+
+type SideReport struct {
+	DeckID           card.SerializableDeckID `json:"deck_id"`
+	DeckName         string                  `json:"deck_name"`
+	Bot              bot.Difficulty          `json:"bot"`
+	Wins             int                     `json:"wins"`
+	WinRate          float64                 `json:"win_rate"`
+	AveragePoints    float64                 `json:"average_points"`
+	AverageRoundsWon float64                 `json:"average_rounds_won"`
+
+	points    int
+	roundsWon int
+}
+
+// EffectCount is how often a card's effects for one trigger fired
+type EffectCount struct {
+	Side       int                     `json:"side"`
+	CardID     card.SerializableCardID `json:"card_id"`
+	EffectType card.CardEffectType     `json:"effect_type"`
+	Count      int                     `json:"count"`
+	PerGame    float64                 `json:"per_game"`
+}
+
+type effectKey struct {
+	side       int
+	cardId     card.SerializableCardID
+	effectType card.CardEffectType
+}
+
+func NewReport(decks [2]*card.SerializableDeck, bots [2]bot.Difficulty) *Report {
+	report := &Report{
+		Lengths: make(map[int]int),
+		Effects: make([]EffectCount, 0),
+		effects: make(map[effectKey]int),
+	}
+	for i, deck := range decks {
+		report.Sides[i] = SideReport{
+			DeckID:   deck.ID,
+			DeckName: deck.Name,
+			Bot:      bots[i],
+		}
+	}
+	return report
+}
+
+// Add records a completed game. sides gives the report side that sat in
+// each of the game's seats.
+func (r *Report) Add(data game.GameStateData, sides [2]int) {
+	r.Games++
+	rounds := len(data.WarResults)
+	r.rounds += rounds
+	r.Lengths[rounds]++
+
+	leader := data.Leader()
+	if leader == game.NoWinner {
+		r.Draws++
+	}
+	for seat, player := range data.Players {
+		side := &r.Sides[sides[seat]]
+		if seat == leader {
+			side.Wins++
+		}
+		side.points += player.Points
+		side.roundsWon += player.RoundsWon
+	}
+	for _, effect := range data.EffectsStack {
+		seat, ok := data.PlayerIndex(effect.Activator)
+		if !ok {
+			continue
+		}
+		r.effects[effectKey{
+			side:       sides[seat],
+			cardId:     effect.Source,
+			effectType: effect.EffectType,
+		}]++
+	}
+}
+
+// AddFailure records a game the runner could not finish
+func (r *Report) AddFailure() {
+	r.Failed++
+}
+
+// Finish works out the averages and rates once every game has been added.
+// Effects are listed most frequent first.
+func (r *Report) Finish() {
+	if r.Games == 0 {
+		return
+	}
+	games := float64(r.Games)
+	r.AverageRounds = float64(r.rounds) / games
+	for i := range r.Sides {
+		side := &r.Sides[i]
+		side.WinRate = float64(side.Wins) / games
+		side.AveragePoints = float64(side.points) / games
+		side.AverageRoundsWon = float64(side.roundsWon) / games
+	}
+
+	r.Effects = make([]EffectCount, 0, len(r.effects))
+	for key, count := range r.effects {
+		r.Effects = append(r.Effects, EffectCount{
+			Side:       key.side,
+			CardID:     key.cardId,
+			EffectType: key.effectType,
+			Count:      count,
+			PerGame:    float64(count) / games,
+		})
+	}
+	slices.SortFunc(r.Effects, func(a, b EffectCount) int {
+		return cmp.Or(
+			cmp.Compare(b.Count, a.Count),
+			cmp.Compare(a.Side, b.Side),
+			cmp.Compare(a.CardID, b.CardID),
+			cmp.Compare(a.EffectType, b.EffectType),
+		)
+	})
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes the report as three tables, separated by blank lines: the
+// sides, the game length distribution and the effect trigger counts
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{
+		{"side", "deck_id", "deck_name", "bot", "games", "wins", "draws", "failed", "win_rate", "average_points", "average_rounds_won"},
+	}
+	for i, side := range r.Sides {
+		rows = append(rows, []string{
+			strconv.Itoa(i),
+			strconv.FormatInt(int64(side.DeckID), 10),
+			side.DeckName,
+			string(side.Bot),
+			strconv.Itoa(r.Games),
+			strconv.Itoa(side.Wins),
+			strconv.Itoa(r.Draws),
+			strconv.Itoa(r.Failed),
+			formatFloat(side.WinRate),
+			formatFloat(side.AveragePoints),
+			formatFloat(side.AverageRoundsWon),
+		})
+	}
+
+	rows = append(rows, nil, []string{"rounds", "games"})
+	for _, rounds := range slices.Sorted(maps.Keys(r.Lengths)) {
+		rows = append(rows, []string{strconv.Itoa(rounds), strconv.Itoa(r.Lengths[rounds])})
+	}
+
+	rows = append(rows, nil, []string{"side", "card_id", "effect_type", "count", "per_game"})
+	for _, effect := range r.Effects {
+		rows = append(rows, []string{
+			strconv.Itoa(effect.Side),
+			strconv.FormatInt(int64(effect.CardID), 10),
+			string(effect.EffectType),
+			strconv.Itoa(effect.Count),
+			formatFloat(effect.PerGame),
+		})
+	}
+
+	for _, row := range rows {
+		if row == nil {
+			writer.Flush()
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
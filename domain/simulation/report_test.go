@@ -0,0 +1,91 @@
+package simulation_test
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/coopersmall/subswag/domain/bot"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/simulation"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *SimulationTestSuite) TestReport() {
+	newReport := func() *simulation.Report {
+		return simulation.NewReport(
+			[2]*card.SerializableDeck{
+				card.NewDeck(1, card.SerializableDeckData{Name: "first"}),
+				card.NewDeck(2, card.SerializableDeckData{Name: "second"}),
+			},
+			[2]bot.Difficulty{bot.DifficultyEasy, bot.DifficultyHard},
+		)
+	}
+	newGame := func(rounds int, roundsWon [2]int, effects ...game.EffectContext) game.GameStateData {
+		data := game.GameStateData{WarResults: make([]game.WarResult, rounds)}
+		data.Players[0] = game.PlayerState{User: 1, RoundsWon: roundsWon[0], Points: roundsWon[0] * 2}
+		data.Players[1] = game.PlayerState{User: 2, RoundsWon: roundsWon[1], Points: roundsWon[1] * 2}
+		data.EffectsStack = effects
+		return data
+	}
+	s.Run("should report results by side whichever seat it played from", func() {
+		report := newReport()
+		// The first side wins from seat 0, then again from seat 1
+		report.Add(newGame(5, [2]int{3, 2}), [2]int{0, 1})
+		report.Add(newGame(7, [2]int{3, 4}), [2]int{1, 0})
+		report.Add(newGame(5, [2]int{2, 2}), [2]int{0, 1})
+		report.AddFailure()
+		report.Finish()
+
+		assert.Equal(s.T(), 3, report.Games)
+		assert.Equal(s.T(), 1, report.Draws)
+		assert.Equal(s.T(), 1, report.Failed)
+		assert.Equal(s.T(), 2, report.Sides[0].Wins)
+		assert.Equal(s.T(), 0, report.Sides[1].Wins)
+		assert.InDelta(s.T(), 2.0/3, report.Sides[0].WinRate, 0.0001)
+		assert.InDelta(s.T(), 3.0, report.Sides[0].AverageRoundsWon, 0.0001)
+		assert.InDelta(s.T(), 17.0/3, report.AverageRounds, 0.0001)
+		assert.Equal(s.T(), map[int]int{5: 2, 7: 1}, report.Lengths)
+	})
+
+	s.Run("should count effects most frequent first", func() {
+		report := newReport()
+		war := func(activator int, source card.SerializableCardID) game.EffectContext {
+			return game.EffectContext{
+				Activator:  user.UserID(activator + 1),
+				Source:     source,
+				EffectType: card.CardEffectTypeWar,
+			}
+		}
+		report.Add(newGame(1, [2]int{1, 0}, war(0, 10), war(1, 20), war(1, 20)), [2]int{0, 1})
+		report.Add(newGame(1, [2]int{1, 0}, war(0, 20)), [2]int{1, 0})
+		report.Finish()
+
+		require.Len(s.T(), report.Effects, 2)
+		assert.Equal(s.T(), simulation.EffectCount{
+			Side:       1,
+			CardID:     20,
+			EffectType: card.CardEffectTypeWar,
+			Count:      3,
+			PerGame:    1.5,
+		}, report.Effects[0])
+		assert.Equal(s.T(), card.SerializableCardID(10), report.Effects[1].CardID)
+		assert.Equal(s.T(), 0, report.Effects[1].Side)
+	})
+
+	s.Run("should write every table to csv", func() {
+		report := newReport()
+		report.Add(newGame(5, [2]int{3, 2}), [2]int{0, 1})
+		report.Finish()
+
+		var buf bytes.Buffer
+		require.NoError(s.T(), report.WriteCSV(&buf))
+		tables := strings.Split(strings.TrimSpace(buf.String()), "\n\n")
+		require.Len(s.T(), tables, 3)
+		assert.True(s.T(), strings.HasPrefix(tables[0], "side,deck_id,deck_name,bot"))
+		assert.Contains(s.T(), tables[0], "0,1,first,easy,1,1,0,0,1.0000")
+		assert.Equal(s.T(), "rounds,games\n5,1", tables[1])
+	})
+}
@@ -0,0 +1,15 @@
+package simulation_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SimulationTestSuite struct {
+	suite.Suite
+}
+
+func TestSimulationSuite(t *testing.T) {
+	suite.Run(t, new(SimulationTestSuite))
+}
@@ -24,6 +24,8 @@ type UserData struct {
 	   @minLength 1
 	*/
 	LastName string `json:"last_name,omitempty" validate:"omitempty,min=1" tstype:",optional"`
+	// Users whose ratings appear on this user's friends leaderboard
+	Friends []UserID `json:"friends,omitempty" validate:"omitempty,dive,gt=0" tstype:",optional"`
 }
 
 func NewUser() *User {
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/coopersmall/subswag/domain/lobby"
 	"github.com/coopersmall/subswag/utils"
 )
 
@@ -41,6 +42,8 @@ const (
 	SECRETS_PRIVATE_KEY EnvVar = "SECRETS_PRIVATE_SIGNING_KEY"
 
 	JWT_SIGNING_KEY EnvVar = "JWT_SIGNING_KEY"
+
+	MATCHMAKING_MODE EnvVar = "MATCHMAKING_MODE"
 )
 
 type IEnvVars interface {
@@ -60,6 +63,7 @@ type IEnvVars interface {
 	GetGroqKey() (string, error)
 	GetAPIURL() (string, error)
 	GetAPITimeout() (time.Duration, error)
+	GetMatchmakingMode() (lobby.MatchmakingMode, error)
 }
 
 type EnvVars struct {
@@ -79,6 +83,7 @@ type EnvVars struct {
 	groqAPIKey          *string
 	sharedPostgresURL   *string
 	standardPostgresURL *string
+	matchmakingMode     *lobby.MatchmakingMode
 }
 
 var Opts = []option{
@@ -91,6 +96,7 @@ var Opts = []option{
 	WithGroq(),
 	WithJWTSigner(),
 	WithAPIConnParams(),
+	WithMatchmaking(),
 }
 
 type option func(*EnvVars) error
@@ -241,6 +247,22 @@ func WithAPIConnParams() option {
 	}
 }
 
+// WithMatchmaking reads how the public queue pairs players. Players are
+// paired in the order they joined unless a mode is set.
+func WithMatchmaking() option {
+	return func(e *EnvVars) error {
+		mode := lobby.MatchmakingModeFIFO
+		if raw, err := GetEnvVar(MATCHMAKING_MODE); err == nil {
+			mode = lobby.MatchmakingMode(raw)
+		}
+		if !mode.IsValid() {
+			return errors.New("unknown matchmaking mode")
+		}
+		e.matchmakingMode = &mode
+		return nil
+	}
+}
+
 // Interface implementation methods
 func (e *EnvVars) GetRequestsRSAPublicKey() (*rsa.PublicKey, error) {
 	if e.requestsPublicKey == nil {
@@ -354,6 +376,13 @@ func (e *EnvVars) GetAPITimeout() (time.Duration, error) {
 	return *e.apiRouterTimeout, nil
 }
 
+func (e *EnvVars) GetMatchmakingMode() (lobby.MatchmakingMode, error) {
+	if e.matchmakingMode == nil {
+		return "", errors.New("matchmaking mode not initialized")
+	}
+	return *e.matchmakingMode, nil
+}
+
 // Constructor functions
 func GetEnvVars(opts ...option) (IEnvVars, error) {
 	vars := &EnvVars{}
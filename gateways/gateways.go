@@ -140,6 +140,7 @@ type IAIGateway interface {
 type ICacheGateway interface {
 	Get(ctx context.Context, key string) ([]byte, error)
 	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	SetIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
 	Delete(ctx context.Context, key string) error
 	DeleteAll(ctx context.Context) error
 }
@@ -154,4 +155,9 @@ type ISubscriberGateway interface {
 		stream, group, name string,
 		handler func(context.Context, []byte) error,
 	) error
+	Tail(
+		ctx context.Context,
+		stream string,
+		handler func(context.Context, []byte) error,
+	) error
 }
@@ -37,6 +37,20 @@ func (r *RedisCacheGateway) Set(ctx context.Context, key string, value []byte, t
 	return err
 }
 
+// SetIfAbsent sets the key only if it is not already set. It reports whether
+// the value was set, which makes it usable as a lock.
+func (r *RedisCacheGateway) SetIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	var (
+		ok  bool
+		err error
+	)
+	r.tracer.Trace(ctx, "set-cache-if-absent", func(ctx context.Context, span apm.ISpan) error {
+		ok, err = r.redisClient.SetNX(ctx, key, string(value), ttl)
+		return utils.ErrorOrNil("failed to set cache", utils.NewInternalError, err)
+	})
+	return ok, err
+}
+
 func (r *RedisCacheGateway) Get(ctx context.Context, key string) ([]byte, error) {
 	var (
 		value string
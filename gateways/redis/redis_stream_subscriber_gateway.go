@@ -60,6 +60,47 @@ func (r *RedisStreamSubscriberGateway) Subscribe(
 	return r.processMessages(ctx, key, group, name, handler)
 }
 
+// Tail calls handler for every message added to the stream after it is
+// called, until ctx is done. No consumer group is used, so every caller
+// receives every message and nothing is acknowledged.
+func (r *RedisStreamSubscriberGateway) Tail(
+	ctx context.Context,
+	key string,
+	handler func(context.Context, []byte) error,
+) error {
+	lastId := "$"
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+		messages, err := r.client.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{key, lastId},
+			Block:   r.blockTime,
+			Count:   r.batchSize,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			r.logger.Error(ctx, "error tailing stream", err, map[string]any{
+				"stream": key,
+			})
+			time.Sleep(r.backoffTime)
+			continue
+		}
+		for _, message := range messages {
+			for _, msg := range message.Messages {
+				lastId = msg.ID
+				if err := handler(ctx, []byte(fmt.Sprintf("%v", msg.Values["data"]))); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
 func (r *RedisStreamSubscriberGateway) ensureConsumerGroup(ctx context.Context, key, group, name string) error {
 	var err error
 	r.tracer.Trace(ctx, "creating-group", func(ctx context.Context, span apm.ISpan) error {
@@ -16,5 +16,12 @@ func NewAPIRouter(env env.IEnv) server.IRouter {
 		NewChatSessionsHandler(env),
 		NewUsersHandler(env),
 		NewAnswerQuestionHandler(env),
+		NewGamesHandler(env),
+		NewLobbyHandler(env),
+		NewRatingsHandler(env),
+		NewCardsHandler(env),
+		NewDecksHandler(env),
+		NewCollectionHandler(env),
+		NewPacksHandler(env),
 	)
 }
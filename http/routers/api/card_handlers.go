@@ -0,0 +1,137 @@
+package api
+
+import (
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/env"
+	"github.com/coopersmall/subswag/http/server"
+	"github.com/coopersmall/subswag/utils"
+)
+
+type CardsHandler struct {
+	server.IHandler
+}
+
+// NewCardsHandler serves the card catalog. Anyone can read it; changing it
+// needs the admin permission.
+func NewCardsHandler(env env.IEnv) server.IHandler {
+	return &CardsHandler{
+		IHandler: server.NewHandler(
+			"/cards",
+			env,
+			[]domain.Permission{domain.APIPermission},
+			[]server.Middleware{},
+			server.APIGetRoute("", GetAllCardsRoute),
+			server.APIGetRoute("/{cardId}", GetCardRoute),
+			server.APIGetRoute("/{cardId}/versions/{version}", GetCardVersionRoute, domain.AdminPermission),
+			server.APIPostRoute("", CreateCardRoute, domain.AdminPermission),
+			server.APIPutRoute("/{cardId}", UpdateCardRoute, domain.AdminPermission),
+			server.APIDeleteRoute("/{cardId}", DeleteCardRoute, domain.AdminPermission),
+			server.APIPostRoute("/import", ImportCardSetRoute, domain.AdminPermission),
+		),
+	}
+}
+
+func GetAllCardsRoute(r server.IRequest) (any, error) {
+	return r.GetServices().CardCatalogService().GetAllCards(r.Ctx())
+}
+
+func GetCardRoute(r server.IRequest) (any, error) {
+	cardId, err := cardIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().CardCatalogService().GetCard(r.Ctx(), cardId)
+}
+
+func GetCardVersionRoute(r server.IRequest) (any, error) {
+	cardId, err := cardIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	version, err := r.Param("version")
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := parseVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().CardCatalogService().GetCardVersion(r.Ctx(), cardId, parsed)
+}
+
+// CreateCardRoute adds a card, e.g. {"id": 7, "type": "number", "number": 7,
+// "suite": "hearts", ...}
+func CreateCardRoute(r server.IRequest) (any, error) {
+	c, err := cardBody(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().CardCatalogService().CreateCard(r.Ctx(), c)
+}
+
+func UpdateCardRoute(r server.IRequest) (any, error) {
+	cardId, err := cardIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	c, err := cardBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if c.GetID() != cardId {
+		return nil, utils.NewInvalidArgumentError("card id does not match the path")
+	}
+	return r.GetServices().CardCatalogService().UpdateCard(r.Ctx(), c)
+}
+
+func DeleteCardRoute(r server.IRequest) (any, error) {
+	cardId, err := cardIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return nil, r.GetServices().CardCatalogService().DeleteCard(r.Ctx(), cardId)
+}
+
+// ImportCardSetRoute loads a card set in the request body, e.g.
+// /cards/import?format=yaml&dry_run=true. The format defaults to JSON. A dry
+// run reports what would be created and changed without saving anything.
+func ImportCardSetRoute(r server.IRequest) (any, error) {
+	format, err := r.SearchParam("format")
+	if err != nil && !utils.IsNotFoundError(err) {
+		return nil, err
+	}
+	dryRun, err := r.SearchParam("dry_run")
+	if err != nil && !utils.IsNotFoundError(err) {
+		return nil, err
+	}
+	body, err := r.Body()
+	if err != nil {
+		return nil, err
+	}
+	set, err := card.ParseCardSet(body, card.CardSetFormat(format))
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().CardCatalogService().ImportCardSet(r.Ctx(), set, dryRun == "true")
+}
+
+func cardIDParam(r server.IRequest) (card.SerializableCardID, error) {
+	cardId, err := r.Param("cardId")
+	if err != nil {
+		return 0, err
+	}
+	parsed, err := utils.ParseID(cardId)
+	if err != nil {
+		return 0, err
+	}
+	return card.SerializableCardID(parsed), nil
+}
+
+func cardBody(r server.IRequest) (card.Card, error) {
+	body, err := r.Body()
+	if err != nil {
+		return nil, err
+	}
+	return card.UnmarshalCard(body)
+}
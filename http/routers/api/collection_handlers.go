@@ -0,0 +1,119 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/env"
+	"github.com/coopersmall/subswag/http/server"
+	"github.com/coopersmall/subswag/utils"
+)
+
+type CollectionHandler struct {
+	server.IHandler
+}
+
+// NewCollectionHandler serves the caller's card collection. Admins can grant
+// and revoke cards for any user.
+func NewCollectionHandler(env env.IEnv) server.IHandler {
+	return &CollectionHandler{
+		IHandler: server.NewHandler(
+			"/collection",
+			env,
+			[]domain.Permission{domain.APIPermission},
+			[]server.Middleware{},
+			server.APIGetRoute("", GetCollectionRoute),
+			server.APIGetRoute("/decks/{deckId}/missing", GetMissingCardsRoute),
+			server.APIPostRoute("/users/{userId}/grant", GrantCardsRoute, domain.AdminPermission),
+			server.APIPostRoute("/users/{userId}/revoke", RevokeCardsRoute, domain.AdminPermission),
+		),
+	}
+}
+
+// ChangeCollectionRequest lists one card ID per copy, e.g. {"cards": [1, 1, 2]}
+// grants two copies of card 1 and one of card 2
+type ChangeCollectionRequest struct {
+	CardIDs []card.SerializableCardID `json:"cards" validate:"required,min=1" tstype:"Array<SerializableCardID>"`
+}
+
+// GetCollectionRoute returns the caller's cards, e.g.
+// /collection?suite=hearts,clubs&rarity=epic&tribe=magic&duplicates=true
+func GetCollectionRoute(r server.IRequest) (any, error) {
+	var (
+		filter card.CollectionFilter
+		err    error
+	)
+	if filter.Suites, err = listParam(r, "suite", card.CardSuites); err != nil {
+		return nil, err
+	}
+	if filter.Rarities, err = listParam(r, "rarity", card.CardRarities); err != nil {
+		return nil, err
+	}
+	if filter.Tribes, err = listParam(r, "tribe", card.CardTribes); err != nil {
+		return nil, err
+	}
+	duplicates, err := r.SearchParam("duplicates")
+	if err != nil && !utils.IsNotFoundError(err) {
+		return nil, err
+	}
+	filter.DuplicatesOnly = duplicates == "true"
+	return r.GetServices().CollectionService(r.UserID()).GetCollection(r.Ctx(), filter)
+}
+
+// GetMissingCardsRoute lists the cards one of the caller's decks needs that
+// their collection does not have enough copies of
+func GetMissingCardsRoute(r server.IRequest) (any, error) {
+	deckId, err := deckIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().CollectionService(r.UserID()).MissingCards(r.Ctx(), deckId)
+}
+
+func GrantCardsRoute(r server.IRequest) (any, error) {
+	userId, err := userIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	var req ChangeCollectionRequest
+	if err := jsonBody(r, &req); err != nil {
+		return nil, err
+	}
+	return r.GetServices().CollectionService(userId).GrantCards(r.Ctx(), req.CardIDs, card.CardSourceGrant)
+}
+
+func RevokeCardsRoute(r server.IRequest) (any, error) {
+	userId, err := userIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	var req ChangeCollectionRequest
+	if err := jsonBody(r, &req); err != nil {
+		return nil, err
+	}
+	return r.GetServices().CollectionService(userId).RevokeCards(r.Ctx(), req.CardIDs)
+}
+
+// listParam reads a comma separated search param whose values must all be
+// one of allowed
+func listParam[T ~string](r server.IRequest, key string, allowed []T) ([]T, error) {
+	value, err := r.SearchParam(key)
+	if utils.IsNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var values []T
+	for _, part := range strings.Split(value, ",") {
+		v := T(strings.TrimSpace(part))
+		if !slices.Contains(allowed, v) {
+			return nil, utils.NewInvalidArgumentError(fmt.Sprintf("invalid %s %q", key, v))
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
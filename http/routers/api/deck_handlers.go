@@ -0,0 +1,215 @@
+package api
+
+import (
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/env"
+	"github.com/coopersmall/subswag/http/server"
+	"github.com/coopersmall/subswag/utils"
+)
+
+type DecksHandler struct {
+	server.IHandler
+}
+
+// NewDecksHandler serves the caller's own decks
+func NewDecksHandler(env env.IEnv) server.IHandler {
+	return &DecksHandler{
+		IHandler: server.NewHandler(
+			"/decks",
+			env,
+			[]domain.Permission{domain.APIPermission},
+			[]server.Middleware{},
+			server.APIGetRoute("", GetAllDecksRoute),
+			server.APIGetRoute("/{deckId}", GetDeckRoute),
+			server.APIPostRoute("", CreateDeckRoute),
+			server.APIPutRoute("/{deckId}", UpdateDeckRoute),
+			server.APIDeleteRoute("/{deckId}", DeleteDeckRoute),
+			server.APIPostRoute("/{deckId}/favorite", FavoriteDeckRoute),
+			server.APIDeleteRoute("/{deckId}/favorite", UnfavoriteDeckRoute),
+			server.APIPostRoute("/{deckId}/clone", CloneDeckRoute),
+			server.APIGetRoute("/{deckId}/export", ExportDeckRoute),
+			server.APIPostRoute("/import", ImportDeckRoute),
+			server.APIGetRoute("/{deckId}/validate", ValidateDeckRoute),
+		),
+	}
+}
+
+// SaveDeckRequest is the part of a deck a player edits, e.g.
+// {"name": "Aggro", "cards": [1, 1, 2], "format": "singleton"}
+type SaveDeckRequest struct {
+	Name    string                    `json:"name" validate:"required,max=64" tstype:"string"`
+	CardIDs []card.SerializableCardID `json:"cards" validate:"required" tstype:"Array<SerializableCardID>"`
+	Format  card.DeckFormatName       `json:"format,omitempty" validate:"omitempty,oneof=standard singleton mono_suite" tstype:"'standard' | 'singleton' | 'mono_suite',optional"`
+}
+
+// CloneDeckRequest names the copy, "Copy of ..." when empty
+type CloneDeckRequest struct {
+	Name string `json:"name,omitempty" validate:"max=64" tstype:",optional"`
+}
+
+// ImportDeckRequest saves the deck in a code shared by another player
+type ImportDeckRequest struct {
+	Code string `json:"code" validate:"required" tstype:"string"`
+	Name string `json:"name,omitempty" validate:"max=64" tstype:",optional"`
+}
+
+// DeckCodeResponse is a deck's shareable code
+type DeckCodeResponse struct {
+	Code string `json:"code" tstype:"string"`
+}
+
+func GetAllDecksRoute(r server.IRequest) (any, error) {
+	return r.GetServices().DecksService(r.UserID()).GetAllDecks(r.Ctx())
+}
+
+func GetDeckRoute(r server.IRequest) (any, error) {
+	deckId, err := deckIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().DecksService(r.UserID()).GetDeck(r.Ctx(), deckId)
+}
+
+// CreateDeckRoute saves a new deck. It must be legal in its format.
+func CreateDeckRoute(r server.IRequest) (any, error) {
+	var req SaveDeckRequest
+	if err := jsonBody(r, &req); err != nil {
+		return nil, err
+	}
+	return r.GetServices().DecksService(r.UserID()).CreateDeck(r.Ctx(), card.SerializableDeckData{
+		CardIDs: req.CardIDs,
+		Name:    req.Name,
+		Format:  req.Format,
+	})
+}
+
+// UpdateDeckRoute replaces the deck's name, cards and format. Its record and
+// favourite are kept.
+func UpdateDeckRoute(r server.IRequest) (any, error) {
+	deckId, err := deckIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	var req SaveDeckRequest
+	if err := jsonBody(r, &req); err != nil {
+		return nil, err
+	}
+	decksService := r.GetServices().DecksService(r.UserID())
+	deck, err := decksService.GetDeck(r.Ctx(), deckId)
+	if err != nil {
+		return nil, err
+	}
+	deck.Name = req.Name
+	deck.CardIDs = req.CardIDs
+	deck.Format = req.Format
+	if err := decksService.UpdateDeck(r.Ctx(), deck); err != nil {
+		return nil, err
+	}
+	return deck, nil
+}
+
+func DeleteDeckRoute(r server.IRequest) (any, error) {
+	deckId, err := deckIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return nil, r.GetServices().DecksService(r.UserID()).DeleteDeck(r.Ctx(), deckId)
+}
+
+func FavoriteDeckRoute(r server.IRequest) (any, error) {
+	return favoriteDeck(r, true)
+}
+
+func UnfavoriteDeckRoute(r server.IRequest) (any, error) {
+	return favoriteDeck(r, false)
+}
+
+// CloneDeckRoute copies the deck's cards and format into a new deck, e.g.
+// {"name": "Aggro v2"}. The body is optional.
+func CloneDeckRoute(r server.IRequest) (any, error) {
+	deckId, err := deckIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	body, err := r.Body()
+	if err != nil {
+		return nil, err
+	}
+	var req CloneDeckRequest
+	if len(body) > 0 {
+		if err := utils.Unmarshal(body, &req); err != nil {
+			return nil, err
+		}
+		if err := domain.Validate(req); err != nil {
+			return nil, err
+		}
+	}
+	return r.GetServices().DecksService(r.UserID()).CloneDeck(r.Ctx(), deckId, req.Name)
+}
+
+// ExportDeckRoute returns a code that any player can import to get a copy of
+// the deck's cards
+func ExportDeckRoute(r server.IRequest) (any, error) {
+	deckId, err := deckIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	code, err := r.GetServices().DecksService(r.UserID()).ExportDeck(r.Ctx(), deckId)
+	if err != nil {
+		return nil, err
+	}
+	return DeckCodeResponse{Code: code}, nil
+}
+
+// ImportDeckRoute saves the deck in a shared code, e.g.
+// {"code": "AQAC...", "name": "Borrowed"}. The deck must be legal in its
+// format.
+func ImportDeckRoute(r server.IRequest) (any, error) {
+	var req ImportDeckRequest
+	if err := jsonBody(r, &req); err != nil {
+		return nil, err
+	}
+	return r.GetServices().DecksService(r.UserID()).ImportDeck(r.Ctx(), req.Code, req.Name)
+}
+
+// ValidateDeckRoute reports whether the deck is legal in its format and every
+// rule it breaks
+func ValidateDeckRoute(r server.IRequest) (any, error) {
+	deckId, err := deckIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().DecksService(r.UserID()).ValidateDeck(r.Ctx(), deckId)
+}
+
+func favoriteDeck(r server.IRequest, favorited bool) (any, error) {
+	deckId, err := deckIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().DecksService(r.UserID()).FavoriteDeck(r.Ctx(), deckId, favorited)
+}
+
+func deckIDParam(r server.IRequest) (card.SerializableDeckID, error) {
+	deckId, err := r.Param("deckId")
+	if err != nil {
+		return 0, err
+	}
+	parsed, err := utils.ParseID(deckId)
+	if err != nil {
+		return 0, err
+	}
+	return card.SerializableDeckID(parsed), nil
+}
+
+func jsonBody(r server.IRequest, req any) error {
+	body, err := r.Body()
+	if err != nil {
+		return err
+	}
+	if err := utils.Unmarshal(body, req); err != nil {
+		return err
+	}
+	return domain.Validate(req)
+}
@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/env"
+	"github.com/coopersmall/subswag/http/server"
+	"github.com/coopersmall/subswag/utils"
+)
+
+type GamesHandler struct {
+	server.IHandler
+}
+
+func NewGamesHandler(env env.IEnv) server.IHandler {
+	return &GamesHandler{
+		IHandler: server.NewHandler(
+			"/games",
+			env,
+			[]domain.Permission{domain.APIPermission},
+			[]server.Middleware{},
+			server.APIGetRoute("/{gameId}", GetGameRoute),
+			server.APIPostRoute("/{gameId}/actions", GameActionRoute),
+			server.APIStreamRoute("/{gameId}/events", GameEventsStreamRoute),
+			server.APIStreamRoute("/{gameId}/spectate", SpectateGameStreamRoute),
+			server.APIGetRoute("/{gameId}/spectators", GetSpectatorCountRoute),
+			server.APIGetRoute("/{gameId}/versions", GetGameVersionsRoute),
+			server.APIGetRoute("/{gameId}/versions/{version}", GetGameVersionRoute),
+			server.APIGetRoute("/{gameId}/diff", GetGameDiffRoute),
+			server.APIPostRoute("/{gameId}/rollback/{version}", RollbackGameRoute, domain.AdminPermission),
+		),
+	}
+}
+
+// GetGameRoute returns the game as the caller may see it. Players get their
+// PlayerView of the current state and everyone else the SpectatorView of the
+// newest version past the broadcast delay; the full game state is never
+// served.
+func GetGameRoute(r server.IRequest) (any, error) {
+	gameId, err := gameIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	gameState, err := r.GetServices().GameRunnerService().GetGame(r.Ctx(), gameId)
+	if err != nil {
+		return nil, err
+	}
+	if view, err := game.NewPlayerView(gameState, r.UserID()); err == nil {
+		return view, nil
+	}
+	version, err := r.GetServices().GameReplayService().GetLatestVersion(r.Ctx(), gameId, r.UserID())
+	if err != nil {
+		return nil, err
+	}
+	return version.State, nil
+}
+
+// GameActionRoute applies a player command, e.g. {"type": "draw"} or
+// {"type": "swap", "card_id": 12, "position": "1,2"}
+func GameActionRoute(r server.IRequest) (any, error) {
+	gameId, err := gameIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	body, err := r.Body()
+	if err != nil {
+		return nil, err
+	}
+	cmd, err := game.ParseCommand(body)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.Validate(cmd); err != nil {
+		return nil, err
+	}
+	result, err := r.GetServices().GameRunnerService().ApplyCommand(r.Ctx(), gameId, r.UserID(), cmd)
+	if err != nil {
+		return nil, err
+	}
+	return game.NewCommandResultView(result, r.UserID()), nil
+}
+
+// GameEventsStreamRoute pushes the game's events to its players as server-sent
+// events
+func GameEventsStreamRoute(r server.IRequest, w server.IEventWriter) error {
+	gameId, err := gameIDParam(r)
+	if err != nil {
+		return err
+	}
+	return r.GetServices().GameEventsService().Watch(r.Ctx(), gameId, r.UserID(), func(ctx context.Context, event *game.GameEvent) error {
+		return w.WriteEvent(string(event.Type), event)
+	})
+}
+
+// SpectateGameStreamRoute pushes the spectator view of each game version as a
+// "version" server-sent event, kept behind the game's broadcast delay
+func SpectateGameStreamRoute(r server.IRequest, w server.IEventWriter) error {
+	gameId, err := gameIDParam(r)
+	if err != nil {
+		return err
+	}
+	return r.GetServices().GameEventsService().Spectate(r.Ctx(), gameId, func(ctx context.Context, version game.GameStateVersionView) error {
+		return w.WriteEvent("version", version)
+	})
+}
+
+func GetSpectatorCountRoute(r server.IRequest) (any, error) {
+	gameId, err := gameIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().GameEventsService().SpectatorCount(r.Ctx(), gameId)
+}
+
+// GetGameVersionsRoute lists a page of the game's versions, given by the
+// optional limit and offset search params
+func GetGameVersionsRoute(r server.IRequest) (any, error) {
+	gameId, err := gameIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	limit, offset, err := pageParams(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().GameReplayService().ListVersions(r.Ctx(), gameId, r.UserID(), limit, offset)
+}
+
+func GetGameVersionRoute(r server.IRequest) (any, error) {
+	gameId, err := gameIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	version, err := r.Param("version")
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := parseVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().GameReplayService().GetVersion(r.Ctx(), gameId, parsed, r.UserID())
+}
+
+// GetGameDiffRoute compares the versions given by the from and to search
+// params, e.g. /games/{gameId}/diff?from=2&to=5
+func GetGameDiffRoute(r server.IRequest) (any, error) {
+	gameId, err := gameIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	from, err := r.SearchParam("from")
+	if err != nil {
+		return nil, err
+	}
+	parsedFrom, err := parseVersion(from)
+	if err != nil {
+		return nil, err
+	}
+	to, err := r.SearchParam("to")
+	if err != nil {
+		return nil, err
+	}
+	parsedTo, err := parseVersion(to)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().GameReplayService().DiffVersions(r.Ctx(), gameId, parsedFrom, parsedTo, r.UserID())
+}
+
+func RollbackGameRoute(r server.IRequest) (any, error) {
+	gameId, err := gameIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	version, err := r.Param("version")
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := parseVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	gameState, err := r.GetServices().GameReplayService().Rollback(r.Ctx(), gameId, parsed)
+	if err != nil {
+		return nil, err
+	}
+	return game.NewViewFor(gameState, r.UserID()), nil
+}
+
+func gameIDParam(r server.IRequest) (game.GameStateID, error) {
+	gameId, err := r.Param("gameId")
+	if err != nil {
+		return 0, err
+	}
+	parsed, err := utils.ParseID(gameId)
+	if err != nil {
+		return 0, err
+	}
+	return game.GameStateID(parsed), nil
+}
+
+func parseVersion(version string) (int, error) {
+	parsed, err := strconv.Atoi(version)
+	if err != nil {
+		return 0, utils.NewInvalidArgumentError("invalid version", err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"time"
+
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/bot"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/lobby"
+	"github.com/coopersmall/subswag/env"
+	"github.com/coopersmall/subswag/http/server"
+	"github.com/coopersmall/subswag/utils"
+)
+
+type LobbyHandler struct {
+	server.IHandler
+}
+
+func NewLobbyHandler(env env.IEnv) server.IHandler {
+	return &LobbyHandler{
+		IHandler: server.NewHandler(
+			"/lobby",
+			env,
+			[]domain.Permission{domain.APIPermission},
+			[]server.Middleware{},
+			server.APIPostRoute("/queue", JoinQueueRoute),
+			server.APIGetRoute("/queue", GetQueueStatusRoute),
+			server.APIDeleteRoute("/queue", LeaveQueueRoute),
+			server.APIPostRoute("/private", CreateLobbyRoute),
+			server.APIGetRoute("/private/{code}", GetLobbyRoute),
+			server.APIPostRoute("/private/{code}/join", JoinLobbyRoute),
+			server.APIDeleteRoute("/private/{code}", CloseLobbyRoute),
+			server.APIPostRoute("/bot", StartBotGameRoute),
+		),
+	}
+}
+
+// DeckRequest picks the deck a player brings to a game
+type DeckRequest struct {
+	DeckID card.SerializableDeckID `json:"deck_id" validate:"required,gt=0"`
+}
+
+// JoinQueueRoute queues the player for a match, e.g. {"deck_id": 12}. Poll
+// GET /lobby/queue to find out when a game has been created.
+func JoinQueueRoute(r server.IRequest) (any, error) {
+	req, err := deckRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().LobbyService().JoinQueue(r.Ctx(), r.UserID(), req.DeckID)
+}
+
+func GetQueueStatusRoute(r server.IRequest) (any, error) {
+	return r.GetServices().LobbyService().QueueStatus(r.Ctx(), r.UserID())
+}
+
+func LeaveQueueRoute(r server.IRequest) (any, error) {
+	return nil, r.GetServices().LobbyService().LeaveQueue(r.Ctx(), r.UserID())
+}
+
+// CreateLobbyRequest opens a private lobby. The host picks the rules preset,
+// classic by default, whether the game can be watched and how many seconds
+// spectators are kept behind the players.
+type CreateLobbyRequest struct {
+	DeckID                card.SerializableDeckID `json:"deck_id" validate:"required,gt=0"`
+	Rules                 game.RulesPreset        `json:"rules,omitempty" validate:"omitempty,oneof=classic blitz big_board" tstype:"'classic' | 'blitz' | 'big_board',optional"`
+	DisallowSpectators    bool                    `json:"disallow_spectators,omitempty" tstype:",optional"`
+	SpectatorDelaySeconds int                     `json:"spectator_delay_seconds,omitempty" validate:"gte=0,lte=600" tstype:",optional"`
+}
+
+// CreateLobbyRoute opens a private lobby and returns its invite code, e.g.
+// {"deck_id": 12, "rules": "blitz", "disallow_spectators": true}
+func CreateLobbyRoute(r server.IRequest) (any, error) {
+	body, err := r.Body()
+	if err != nil {
+		return nil, err
+	}
+	var req CreateLobbyRequest
+	if err := utils.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	if err := domain.Validate(req); err != nil {
+		return nil, err
+	}
+	return r.GetServices().LobbyService().CreateLobby(r.Ctx(), r.UserID(), req.DeckID, lobby.GameSettings{
+		Rules: req.Rules,
+		Spectating: game.SpectatorSettings{
+			Disallowed: req.DisallowSpectators,
+			Delay:      time.Duration(req.SpectatorDelaySeconds) * time.Second,
+		},
+	})
+}
+
+func GetLobbyRoute(r server.IRequest) (any, error) {
+	code, err := r.Param("code")
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().LobbyService().GetLobby(r.Ctx(), code)
+}
+
+func JoinLobbyRoute(r server.IRequest) (any, error) {
+	code, err := r.Param("code")
+	if err != nil {
+		return nil, err
+	}
+	req, err := deckRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().LobbyService().JoinLobby(r.Ctx(), r.UserID(), code, req.DeckID)
+}
+
+func CloseLobbyRoute(r server.IRequest) (any, error) {
+	code, err := r.Param("code")
+	if err != nil {
+		return nil, err
+	}
+	return nil, r.GetServices().LobbyService().CloseLobby(r.Ctx(), r.UserID(), code)
+}
+
+// BotGameRequest starts a practice game against a bot. The bot plays
+// BotDeckID, one of the player's own decks, or the player's deck if unset.
+type BotGameRequest struct {
+	DeckID     card.SerializableDeckID `json:"deck_id" validate:"required,gt=0"`
+	BotDeckID  card.SerializableDeckID `json:"bot_deck_id,omitempty" validate:"omitempty,gt=0" tstype:",optional"`
+	Difficulty bot.Difficulty          `json:"difficulty" validate:"required,oneof=easy medium hard" tstype:"'easy' | 'medium' | 'hard'"`
+	Rules      game.RulesPreset        `json:"rules,omitempty" validate:"omitempty,oneof=classic blitz big_board" tstype:"'classic' | 'blitz' | 'big_board',optional"`
+}
+
+// StartBotGameRoute creates a practice game against a bot, e.g.
+// {"deck_id": 12, "difficulty": "hard"}
+func StartBotGameRoute(r server.IRequest) (any, error) {
+	body, err := r.Body()
+	if err != nil {
+		return nil, err
+	}
+	var req BotGameRequest
+	if err := utils.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	if err := domain.Validate(req); err != nil {
+		return nil, err
+	}
+	botDeckId := req.BotDeckID
+	if botDeckId == 0 {
+		botDeckId = req.DeckID
+	}
+	gameState, err := r.GetServices().LobbyService().StartBotGame(r.Ctx(), r.UserID(), req.DeckID, botDeckId, req.Difficulty, req.Rules)
+	if err != nil {
+		return nil, err
+	}
+	return game.NewPlayerView(gameState, r.UserID())
+}
+
+func deckRequest(r server.IRequest) (*DeckRequest, error) {
+	body, err := r.Body()
+	if err != nil {
+		return nil, err
+	}
+	var req DeckRequest
+	if err := utils.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	if err := domain.Validate(req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
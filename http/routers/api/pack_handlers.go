@@ -0,0 +1,138 @@
+package api
+
+import (
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/pack"
+	"github.com/coopersmall/subswag/env"
+	"github.com/coopersmall/subswag/http/server"
+	"github.com/coopersmall/subswag/utils"
+)
+
+type PacksHandler struct {
+	server.IHandler
+}
+
+// NewPacksHandler lists and opens booster packs. Admins define the pack types
+// and can read any user's openings.
+func NewPacksHandler(env env.IEnv) server.IHandler {
+	return &PacksHandler{
+		IHandler: server.NewHandler(
+			"/packs",
+			env,
+			[]domain.Permission{domain.APIPermission},
+			[]server.Middleware{},
+			// Registered before /{packTypeId} so "openings" is not read as an ID
+			server.APIGetRoute("/openings", GetPackOpeningsRoute),
+			server.APIGetRoute("/openings/{openingId}", GetPackOpeningRoute),
+			server.APIGetRoute("/users/{userId}/openings", GetUserPackOpeningsRoute, domain.AdminPermission),
+			server.APIGetRoute("", GetAllPackTypesRoute),
+			server.APIGetRoute("/{packTypeId}", GetPackTypeRoute),
+			server.APIPostRoute("", CreatePackTypeRoute, domain.AdminPermission),
+			server.APIPutRoute("/{packTypeId}", UpdatePackTypeRoute, domain.AdminPermission),
+			server.APIDeleteRoute("/{packTypeId}", DeletePackTypeRoute, domain.AdminPermission),
+			server.APIPostRoute("/{packTypeId}/open", OpenPackRoute),
+			server.APIGetRoute("/{packTypeId}/pity", GetPackPityRoute),
+		),
+	}
+}
+
+func GetAllPackTypesRoute(r server.IRequest) (any, error) {
+	return r.GetServices().PacksService(r.UserID()).GetAllPackTypes(r.Ctx())
+}
+
+func GetPackTypeRoute(r server.IRequest) (any, error) {
+	packTypeId, err := packTypeIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().PacksService(r.UserID()).GetPackType(r.Ctx(), packTypeId)
+}
+
+// CreatePackTypeRoute saves a new pack type, e.g.
+// {"name": "Starter", "slots": [{"guaranteed": "rare"}, {"odds": {"common": 9000, "rare": 1000}}],
+// "pity": {"rarity": "legendary", "after": 20}}
+func CreatePackTypeRoute(r server.IRequest) (any, error) {
+	var data pack.PackTypeData
+	if err := jsonBody(r, &data); err != nil {
+		return nil, err
+	}
+	return r.GetServices().PacksService(r.UserID()).CreatePackType(r.Ctx(), data)
+}
+
+func UpdatePackTypeRoute(r server.IRequest) (any, error) {
+	packTypeId, err := packTypeIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	var data pack.PackTypeData
+	if err := jsonBody(r, &data); err != nil {
+		return nil, err
+	}
+	return r.GetServices().PacksService(r.UserID()).UpdatePackType(r.Ctx(), &pack.PackType{
+		ID:           packTypeId,
+		PackTypeData: data,
+	})
+}
+
+func DeletePackTypeRoute(r server.IRequest) (any, error) {
+	packTypeId, err := packTypeIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return nil, r.GetServices().PacksService(r.UserID()).DeletePackType(r.Ctx(), packTypeId)
+}
+
+// OpenPackRoute opens a pack for the caller and returns what it dealt. The
+// cards are already in their collection.
+func OpenPackRoute(r server.IRequest) (any, error) {
+	packTypeId, err := packTypeIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().PacksService(r.UserID()).OpenPack(r.Ctx(), packTypeId)
+}
+
+func GetPackPityRoute(r server.IRequest) (any, error) {
+	packTypeId, err := packTypeIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().PacksService(r.UserID()).GetPity(r.Ctx(), packTypeId)
+}
+
+func GetPackOpeningsRoute(r server.IRequest) (any, error) {
+	return r.GetServices().PacksService(r.UserID()).GetOpenings(r.Ctx())
+}
+
+func GetPackOpeningRoute(r server.IRequest) (any, error) {
+	openingId, err := r.Param("openingId")
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := utils.ParseID(openingId)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().PacksService(r.UserID()).GetOpening(r.Ctx(), pack.PackOpeningID(parsed))
+}
+
+// GetUserPackOpeningsRoute is the audit log of every pack a user has opened
+func GetUserPackOpeningsRoute(r server.IRequest) (any, error) {
+	userId, err := userIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().PacksService(userId).GetOpenings(r.Ctx())
+}
+
+func packTypeIDParam(r server.IRequest) (pack.PackTypeID, error) {
+	packTypeId, err := r.Param("packTypeId")
+	if err != nil {
+		return 0, err
+	}
+	parsed, err := utils.ParseID(packTypeId)
+	if err != nil {
+		return 0, err
+	}
+	return pack.PackTypeID(parsed), nil
+}
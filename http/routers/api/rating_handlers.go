@@ -0,0 +1,108 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/env"
+	"github.com/coopersmall/subswag/http/server"
+	"github.com/coopersmall/subswag/utils"
+)
+
+type RatingsHandler struct {
+	server.IHandler
+}
+
+func NewRatingsHandler(env env.IEnv) server.IHandler {
+	return &RatingsHandler{
+		IHandler: server.NewHandler(
+			"/ratings",
+			env,
+			[]domain.Permission{domain.APIPermission},
+			[]server.Middleware{},
+			server.APIGetRoute("/leaderboard", GetLeaderboardRoute),
+			server.APIGetRoute("/leaderboard/friends", GetFriendsLeaderboardRoute),
+			server.APIGetRoute("/users/{userId}", GetUserRatingRoute),
+			server.APIGetRoute("/users/{userId}/matches", GetMatchHistoryRoute),
+		),
+	}
+}
+
+// GetLeaderboardRoute ranks every rated player, e.g.
+// /ratings/leaderboard?limit=20&offset=40
+func GetLeaderboardRoute(r server.IRequest) (any, error) {
+	limit, offset, err := pageParams(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().RatingsService().Leaderboard(r.Ctx(), limit, offset)
+}
+
+// GetFriendsLeaderboardRoute ranks the caller against their friends
+func GetFriendsLeaderboardRoute(r server.IRequest) (any, error) {
+	return r.GetServices().RatingsService().FriendsLeaderboard(r.Ctx(), r.UserID())
+}
+
+func GetUserRatingRoute(r server.IRequest) (any, error) {
+	userId, err := userIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().RatingsService().GetRating(r.Ctx(), userId)
+}
+
+// GetMatchHistoryRoute lists the user's finished games, most recent first,
+// e.g. /ratings/users/{userId}/matches?limit=10
+func GetMatchHistoryRoute(r server.IRequest) (any, error) {
+	userId, err := userIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	limit, offset, err := pageParams(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetServices().RatingsService().MatchHistory(r.Ctx(), userId, limit, offset)
+}
+
+func userIDParam(r server.IRequest) (user.UserID, error) {
+	userId, err := r.Param("userId")
+	if err != nil {
+		return 0, err
+	}
+	parsed, err := utils.ParseID(userId)
+	if err != nil {
+		return 0, err
+	}
+	return user.UserID(parsed), nil
+}
+
+// pageParams reads the optional limit and offset search params. Missing
+// params are returned as 0 and left to the service's defaults.
+func pageParams(r server.IRequest) (int, int, error) {
+	limit, err := optionalIntParam(r, "limit")
+	if err != nil {
+		return 0, 0, err
+	}
+	offset, err := optionalIntParam(r, "offset")
+	if err != nil {
+		return 0, 0, err
+	}
+	return limit, offset, nil
+}
+
+func optionalIntParam(r server.IRequest, key string) (int, error) {
+	value, err := r.SearchParam(key)
+	if utils.IsNotFoundError(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return 0, utils.NewInvalidArgumentError("invalid "+key, err)
+	}
+	return parsed, nil
+}
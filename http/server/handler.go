@@ -152,6 +152,29 @@ func APIDeleteRoute(
 	}
 }
 
+// APIStreamRoute registers a GET route that streams server-sent events
+func APIStreamRoute(
+	suffix string,
+	streamFunc func(IRequest, IEventWriter) error,
+	permissions ...domain.Permission,
+) HandlerOption {
+	return func(h *Handler) {
+		perms := append(h.permissions, permissions...)
+		route := route{
+			method: http.MethodGet,
+			path:   h.path + suffix,
+			handlerFunc: NewAPIStreamRoute(
+				h.path,
+				suffix,
+				streamFunc,
+				h.env,
+				perms...,
+			),
+		}
+		h.routes = append(h.routes, route)
+	}
+}
+
 func WithMiddleware(middlewares ...Middleware) HandlerOption {
 	return func(h *Handler) {
 		h.middlewares = append(h.middlewares, middlewares...)
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/env"
+	"github.com/coopersmall/subswag/services"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/gorilla/mux"
+)
+
+const (
+	streamKeepAlive     = 15 * time.Second
+	streamWriteDeadline = 30 * time.Second
+)
+
+// IEventWriter writes server-sent events to a streaming route
+type IEventWriter interface {
+	WriteEvent(event string, data any) error
+}
+
+type eventWriter struct {
+	mu         sync.Mutex
+	w          http.ResponseWriter
+	controller *http.ResponseController
+}
+
+func (e *eventWriter) WriteEvent(event string, data any) error {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return utils.NewJSONMarshError("failed to marshal event", err)
+	}
+	return e.write(fmt.Sprintf("event: %s\ndata: %s\n\n", event, bytes))
+}
+
+func (e *eventWriter) keepAlive() error {
+	return e.write(": keep-alive\n\n")
+}
+
+func (e *eventWriter) write(message string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.controller.SetWriteDeadline(time.Now().Add(streamWriteDeadline))
+	if _, err := e.w.Write([]byte(message)); err != nil {
+		return err
+	}
+	return e.controller.Flush()
+}
+
+// NewAPIStreamRoute authenticates the request like NewAPIRoute and then keeps
+// the connection open, running streamFunc until it returns or the client
+// disconnects
+func NewAPIStreamRoute(
+	resource string,
+	extension string,
+	streamFunc func(IRequest, IEventWriter) error,
+	env env.IEnv,
+	permissions ...domain.Permission,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := env.GetLogger("api-stream-route")
+		ctx := r.Context()
+		correlationId := domain.GetCorrelationIDFromContext(ctx)
+		logger.Info(ctx, "running api stream route", map[string]any{
+			"method": r.Method,
+			"path":   r.URL.Path,
+		})
+
+		tracer := env.GetTracer(resource + extension)
+		tracer.Trace(ctx, "STREAM", func(ctx context.Context, span apm.ISpan) error {
+			authService, close := env.GetAuthenticationService()
+			defer close()
+
+			userId, apiTokenId, err := authService.AuthenticateToken(
+				ctx,
+				r.Header.Get("Authorization"),
+				time.Time(utils.Now()),
+				permissions...,
+			)
+			if err != nil {
+				logger.Error(ctx, "failed to authenticate token", err, nil)
+				writeErrorResponse(correlationId, unauthorized, nil, w)
+				return err
+			}
+
+			srvs, close := env.GetServices()
+			defer close()
+
+			_, err = srvs.APITokenService(userId).GetToken(ctx, apiTokenId)
+			if err != nil {
+				logger.Error(ctx, "failed to get token", err, nil)
+				internalServerError(w)
+				return err
+			}
+
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set(CorrelationIDHeader, fmt.Sprintf("%d", correlationId))
+			w.WriteHeader(http.StatusOK)
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			events := &eventWriter{
+				w:          w,
+				controller: http.NewResponseController(w),
+			}
+			// Keep-alives also detect clients that have gone away, since the
+			// custom server does not cancel the request context for us
+			go func() {
+				ticker := time.NewTicker(streamKeepAlive)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						if err := events.keepAlive(); err != nil {
+							cancel()
+							return
+						}
+					}
+				}
+			}()
+
+			err = streamFunc(&Request{
+				ctx:           ctx,
+				correlationID: correlationId,
+				userID:        userId,
+				getBody: func() ([]byte, error) {
+					return nil, nil
+				},
+				getParam: func(key string) (string, error) {
+					value := mux.Vars(r)[key]
+					if value == "" {
+						return "", utils.NewNotFoundError("param not found")
+					}
+					return value, nil
+				},
+				getSearchParam: func(key string) (string, error) {
+					value := r.URL.Query().Get(key)
+					if value == "" {
+						return "", utils.NewNotFoundError("search param not found")
+					}
+					return value, nil
+				},
+				getServices: func() services.IServices {
+					return srvs
+				},
+			}, events)
+			if err != nil {
+				logger.Error(ctx, "stream ended with error", err, nil)
+			}
+			return err
+		})
+	}
+}
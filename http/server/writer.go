@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"time"
 )
 
 type writer struct {
@@ -52,3 +53,12 @@ func (w *writer) Write(b []byte) (int, error) {
 func (w *writer) StatusCode() int {
 	return w.statusCode
 }
+
+// Flush is a no-op as writes go straight to the connection. It lets
+// streaming routes use http.ResponseController.
+func (w *writer) Flush() {}
+
+// SetWriteDeadline lets streaming routes extend the server's write timeout
+func (w *writer) SetWriteDeadline(deadline time.Time) error {
+	return w.conn.SetWriteDeadline(deadline)
+}
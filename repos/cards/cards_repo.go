@@ -3,6 +3,7 @@ package card
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/coopersmall/subswag/apm"
 	"github.com/coopersmall/subswag/db"
@@ -56,6 +57,112 @@ func NewCardsRepo(
 	}
 }
 
+// GetMany returns the cards with the given IDs in ID order. IDs that are not
+// in the catalog are left out.
+func (r *CardsRepo) GetMany(ctx context.Context, cardIds []card.SerializableCardID) ([]card.Card, error) {
+	ids := make([]int64, len(cardIds))
+	for i, cardId := range cardIds {
+		ids[i] = int64(cardId)
+	}
+	return r.SharedRepo.Query(ctx, func(ctx context.Context, queries db.ISharedQueriesReadOnly) ([]db.Card, error) {
+		return queries.GetCardsByIDs(ctx, ids)
+	})
+}
+
+// GetIDsByRarity lists the IDs of the cards of each of the rarities, in ID
+// order, without loading the cards themselves
+func (r *CardsRepo) GetIDsByRarity(ctx context.Context, rarities []card.CardRarity) (map[card.CardRarity][]card.SerializableCardID, error) {
+	names := make([]string, len(rarities))
+	for i, rarity := range rarities {
+		names[i] = string(rarity)
+	}
+	byRarity := map[card.CardRarity][]card.SerializableCardID{}
+	_, err := r.SharedRepo.Query(ctx, func(ctx context.Context, queries db.ISharedQueriesReadOnly) ([]db.Card, error) {
+		rows, err := queries.GetCardIDsByRarity(ctx, names)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			rarity := card.CardRarity(row.Rarity)
+			byRarity[rarity] = append(byRarity[rarity], card.SerializableCardID(row.ID))
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return byRarity, nil
+}
+
+// GetVersion returns the card as it was at version. Versions outlive the
+// card, so games can keep playing a card that has since been deleted.
+func (r *CardsRepo) GetVersion(ctx context.Context, cardId card.SerializableCardID, version int) (card.Card, error) {
+	found, err := r.SharedRepo.Query(ctx, func(ctx context.Context, queries db.ISharedQueriesReadOnly) ([]db.Card, error) {
+		found, err := queries.GetCardVersion(ctx, db.GetCardVersionParams{
+			CardID:  int64(cardId),
+			Version: int32(version),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []db.Card{{
+			ID:        found.CardID,
+			CreatedAt: found.CreatedAt,
+			Type:      found.Type,
+			Data:      found.Data,
+		}}, nil
+	})
+	if len(found) == 0 {
+		return nil, utils.NewNotFoundError("card version not found", err)
+	}
+	return found[0], err
+}
+
+// CreateVersion records the card at its current version. Recording a version
+// that already exists does nothing.
+func (r *CardsRepo) CreateVersion(ctx context.Context, c card.Card) error {
+	row, err := convertCardToRow(c)
+	if err != nil {
+		return utils.NewInternalError("failed to convert to row", err)
+	}
+	return r.SharedRepo.Execute(ctx, func(ctx context.Context, queries db.ISharedQueriesReadWrite) error {
+		_, err := queries.CreateCardVersion(ctx, db.CreateCardVersionParams{
+			CardID:    row.ID,
+			Version:   int32(c.GetVersion()),
+			CreatedAt: time.Now(),
+			Type:      row.Type,
+			Data:      row.Data,
+		})
+		return err
+	})
+}
+
+// SaveMany creates or updates each card and records it at its current
+// version, all in one statement: either every card is saved or none are
+func (r *CardsRepo) SaveMany(ctx context.Context, cards []card.Card) error {
+	params := db.SaveCardsParams{
+		IDs:       make([]int64, len(cards)),
+		Versions:  make([]int64, len(cards)),
+		Types:     make([]string, len(cards)),
+		Data:      make([]string, len(cards)),
+		CreatedAt: time.Now().UTC(),
+	}
+	for i, c := range cards {
+		row, err := convertCardToRow(c)
+		if err != nil {
+			return utils.NewInternalError("failed to convert to row", err)
+		}
+		params.IDs[i] = row.ID
+		params.Versions[i] = int64(c.GetVersion())
+		params.Types[i] = row.Type
+		params.Data[i] = string(row.Data)
+	}
+	return r.SharedRepo.Execute(ctx, func(ctx context.Context, queries db.ISharedQueriesReadWrite) error {
+		_, err := queries.SaveCards(ctx, params)
+		return err
+	})
+}
+
 func convertRowToCard(result db.Card) (card.Card, error) {
 	cardType := card.SerializableCardType(result.Type)
 	metadata := &domain.Metadata{
@@ -20,6 +20,16 @@ func (m *MockCardsRepo) All(ctx context.Context) ([]card.Card, error) {
 	return args.Get(0).([]card.Card), args.Error(1)
 }
 
+func (m *MockCardsRepo) GetMany(ctx context.Context, cardIds []card.SerializableCardID) ([]card.Card, error) {
+	args := m.Called(ctx, cardIds)
+	return args.Get(0).([]card.Card), args.Error(1)
+}
+
+func (m *MockCardsRepo) GetIDsByRarity(ctx context.Context, rarities []card.CardRarity) (map[card.CardRarity][]card.SerializableCardID, error) {
+	args := m.Called(ctx, rarities)
+	return args.Get(0).(map[card.CardRarity][]card.SerializableCardID), args.Error(1)
+}
+
 func (m *MockCardsRepo) Create(ctx context.Context, card card.Card) error {
 	args := m.Called(ctx, card)
 	return args.Error(0)
@@ -34,3 +44,8 @@ func (m *MockCardsRepo) Delete(ctx context.Context, cardId card.SerializableCard
 	args := m.Called(ctx, cardId)
 	return args.Error(0)
 }
+
+func (m *MockCardsRepo) SaveMany(ctx context.Context, cards []card.Card) error {
+	args := m.Called(ctx, cards)
+	return args.Error(0)
+}
@@ -2,6 +2,7 @@ package card_test
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/coopersmall/subswag/domain"
@@ -103,6 +104,119 @@ func (s *CardsRepoTestSuite) TestCardsRepoSuccess() {
 	})
 }
 
+func (s *CardsRepoTestSuite) TestGetMany() {
+	s.Run("it returns only the cards asked for", func() {
+		assert.NoError(s.T(), repo.Create(ctx, validFaceCard))
+		assert.NoError(s.T(), repo.Create(ctx, validNumberCard))
+
+		results, err := repo.GetMany(ctx, []card.SerializableCardID{validNumberCard.ID, 99})
+		assert.NoError(s.T(), err)
+		assert.Len(s.T(), results, 1)
+		assert.Equal(s.T(), validNumberCard.ID, results[0].GetID())
+
+		results, err = repo.GetMany(ctx, nil)
+		assert.NoError(s.T(), err)
+		assert.Empty(s.T(), results)
+	})
+}
+
+func (s *CardsRepoTestSuite) TestGetIDsByRarity() {
+	s.Run("it lists the IDs of the cards of each rarity asked for", func() {
+		assert.NoError(s.T(), repo.Create(ctx, validFaceCard))
+		assert.NoError(s.T(), repo.Create(ctx, validNumberCard))
+
+		results, err := repo.GetIDsByRarity(ctx, []card.CardRarity{card.CardRarityRare, card.CardRarityEpic})
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), map[card.CardRarity][]card.SerializableCardID{
+			card.CardRarityRare: {validNumberCard.ID},
+		}, results)
+	})
+}
+
+func (s *CardsRepoTestSuite) TestCardVersions() {
+	s.Run("it keeps every version after the card changes or is deleted", func() {
+		first := &card.SerializableFaceCard{}
+		utils.DeepClone(validFaceCard, first)
+		first.Version = 1
+		assert.NoError(s.T(), repo.CreateVersion(ctx, first))
+		assert.NoError(s.T(), repo.Create(ctx, first))
+
+		second := &card.SerializableFaceCard{}
+		utils.DeepClone(first, second)
+		second.Version = 2
+		second.Face = "Queen"
+		assert.NoError(s.T(), repo.CreateVersion(ctx, second))
+		assert.NoError(s.T(), repo.Update(ctx, second))
+		assert.NoError(s.T(), repo.Delete(ctx, cardId))
+
+		result, err := repo.GetVersion(ctx, cardId, 1)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), "King", result.(*card.SerializableFaceCard).Face)
+
+		result, err = repo.GetVersion(ctx, cardId, 2)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), 2, result.GetVersion())
+		assert.Equal(s.T(), "Queen", result.(*card.SerializableFaceCard).Face)
+
+		_, err = repo.GetVersion(ctx, cardId, 3)
+		assert.True(s.T(), utils.IsNotFoundError(err))
+	})
+}
+
+func (s *CardsRepoTestSuite) TestSaveMany() {
+	s.Run("it creates and updates the cards and records their versions", func() {
+		first := &card.SerializableFaceCard{}
+		utils.DeepClone(validFaceCard, first)
+		first.Version = 1
+		assert.NoError(s.T(), repo.SaveMany(ctx, []card.Card{first}))
+
+		second := &card.SerializableFaceCard{}
+		utils.DeepClone(first, second)
+		second.Version = 2
+		second.Face = "Queen"
+		created := &card.SerializableNumberCard{}
+		utils.DeepClone(validNumberCard, created)
+		created.Version = 1
+		assert.NoError(s.T(), repo.SaveMany(ctx, []card.Card{second, created}))
+
+		results, err := repo.All(ctx)
+		assert.NoError(s.T(), err)
+		assert.Len(s.T(), results, 2)
+
+		result, err := repo.Get(ctx, cardId)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), "Queen", result.(*card.SerializableFaceCard).Face)
+
+		result, err = repo.GetVersion(ctx, cardId, 1)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), "King", result.(*card.SerializableFaceCard).Face)
+
+		result, err = repo.GetVersion(ctx, validNumberCard.ID, 1)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), 7, result.(*card.SerializableNumberCard).Number)
+	})
+
+	s.Run("it saves none of the cards when one of them fails", func() {
+		saved := &card.SerializableFaceCard{}
+		utils.DeepClone(validFaceCard, saved)
+		saved.Version = 1
+		broken := &card.SerializableNumberCard{}
+		utils.DeepClone(validNumberCard, broken)
+		broken.Version = 1
+		broken.Type = card.SerializableCardType(strings.Repeat("x", 300))
+
+		err := repo.SaveMany(ctx, []card.Card{saved, broken})
+		assert.Error(s.T(), err)
+
+		results, err := repo.All(ctx)
+		assert.NoError(s.T(), err)
+		assert.Empty(s.T(), results)
+
+		_, err = repo.GetVersion(ctx, cardId, 1)
+		assert.True(s.T(), utils.IsNotFoundError(err))
+	})
+}
+
 func (s *CardsRepoTestSuite) TestCardsRepoFailure() {
 	s.Run("it fails with non-existent card", func() {
 		result, err := repo.Get(ctx, cardId)
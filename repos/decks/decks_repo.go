@@ -38,7 +38,7 @@ func NewDecksRepo(
 				})
 			},
 			func(ctx context.Context, iqro db.IStandardQueriesReadOnly) ([]db.Deck, error) {
-				return iqro.GetAllDecks(ctx)
+				return iqro.GetAllDecks(ctx, int64(userId))
 			},
 			func(ctx context.Context, iqrw db.IStandardQueriesReadWrite, d db.Deck) (sql.Result, error) {
 				return iqrw.CreateDeck(ctx, db.CreateDeckParams{
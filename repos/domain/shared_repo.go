@@ -225,11 +225,11 @@ func (r *SharedRepo[ID, Data, DBData]) Execute(
 ) error {
 	var err error
 	r.tracer.Trace(ctx, r.name+".execute", func(ctx context.Context, span apm.ISpan) error {
-		err := r.querier.SharedWrite(ctx, func(d db.ISharedQueriesReadWrite) error {
-			err := executeFunc(ctx, d)
-			return err
+		err = r.querier.SharedWrite(ctx, func(d db.ISharedQueriesReadWrite) error {
+			return executeFunc(ctx, d)
 		})
-		return utils.ErrorOrNil("operation failed", utils.NewInternalError, err)
+		err = utils.ErrorOrNil("operation failed", utils.NewInternalError, err)
+		return err
 	})
 	return err
 }
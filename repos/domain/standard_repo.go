@@ -241,11 +241,11 @@ func (r *StandardRepo[ID, Data, DBData]) Execute(
 ) error {
 	var err error
 	r.tracer.Trace(ctx, r.name+".execute", func(ctx context.Context, span apm.ISpan) error {
-		err := r.querier.StandardWrite(ctx, r.userId, func(d db.IStandardQueriesReadWrite) error {
-			err := executeFunc(ctx, d, r.userId)
-			return err
+		err = r.querier.StandardWrite(ctx, r.userId, func(d db.IStandardQueriesReadWrite) error {
+			return executeFunc(ctx, d, r.userId)
 		})
-		return utils.ErrorOrNil("operation failed", utils.NewNotFoundError, err)
+		err = utils.ErrorOrNil("operation failed", utils.NewNotFoundError, err)
+		return err
 	})
 	return err
 }
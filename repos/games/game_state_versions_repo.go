@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/coopersmall/subswag/apm"
 	"github.com/coopersmall/subswag/db"
@@ -48,12 +50,49 @@ func NewGameStateVersionRepo(
 	}
 }
 
-func (r *GameStateVersionRepo) GetVersionsForGameState(ctx context.Context, gameStateId game.GameStateID) ([]*game.GameStateVersion, error) {
+// GetVersionsPage returns up to limit of the game's versions, oldest first,
+// skipping the first offset
+func (r *GameStateVersionRepo) GetVersionsPage(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	limit int,
+	offset int,
+) ([]*game.GameStateVersion, error) {
 	return r.SharedRepo.Query(ctx, func(ctx context.Context, queries db.ISharedQueriesReadOnly) ([]db.GameStateVersion, error) {
-		return queries.GetGameStateVersionsByGameStateID(ctx, int64(gameStateId))
+		return queries.GetGameStateVersionsPage(ctx, db.GetGameStateVersionsPageParams{
+			GameStateID: int64(gameStateId),
+			Limit:       int32(limit),
+			Offset:      int32(offset),
+		})
 	})
 }
 
+// GetVersionByNumber returns the game's version with the given number,
+// counting from 0 in the order the versions were created
+func (r *GameStateVersionRepo) GetVersionByNumber(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	number int,
+) (*game.GameStateVersion, error) {
+	found, err := r.SharedRepo.Query(ctx, func(ctx context.Context, queries db.ISharedQueriesReadOnly) ([]db.GameStateVersion, error) {
+		found, err := queries.GetGameStateVersionByNumber(ctx, db.GetGameStateVersionByNumberParams{
+			GameStateID: int64(gameStateId),
+			Offset:      int64(number),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return []db.GameStateVersion{found}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, utils.NewNotFoundError("game state version not found")
+	}
+	return found[0], nil
+}
+
 func (r *GameStateVersionRepo) GetLatestVersion(ctx context.Context, gameStateId game.GameStateID) (*game.GameStateVersion, error) {
 	found, err := r.SharedRepo.Query(ctx, func(ctx context.Context, queries db.ISharedQueriesReadOnly) ([]db.GameStateVersion, error) {
 		found, err := queries.GetLatestGameStateVersionByGameStateID(ctx, int64(gameStateId))
@@ -65,6 +104,57 @@ func (r *GameStateVersionRepo) GetLatestVersion(ctx context.Context, gameStateId
 	return found[0], err
 }
 
+// GetLatestVersionBefore returns the game's newest version created no later
+// than cutoff, and how many versions were created by then
+func (r *GameStateVersionRepo) GetLatestVersionBefore(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	cutoff time.Time,
+) (*game.GameStateVersion, int, error) {
+	var total int64
+	found, err := r.SharedRepo.Query(ctx, func(ctx context.Context, queries db.ISharedQueriesReadOnly) ([]db.GameStateVersion, error) {
+		row, err := queries.GetLatestGameStateVersionBefore(ctx, db.GetLatestGameStateVersionBeforeParams{
+			GameStateID: int64(gameStateId),
+			CreatedAt:   cutoff,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		total = row.Total
+		return []db.GameStateVersion{{
+			ID:          row.ID,
+			GameStateID: row.GameStateID,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+			Data:        row.Data,
+		}}, err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(found) == 0 {
+		return nil, 0, utils.NewNotFoundError("game state version not found")
+	}
+	return found[0], int(total), nil
+}
+
+// GetVersionsBetween returns the game's versions created after since and no
+// later than cutoff, oldest first
+func (r *GameStateVersionRepo) GetVersionsBetween(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	since time.Time,
+	cutoff time.Time,
+) ([]*game.GameStateVersion, error) {
+	return r.SharedRepo.Query(ctx, func(ctx context.Context, queries db.ISharedQueriesReadOnly) ([]db.GameStateVersion, error) {
+		return queries.GetGameStateVersionsBetween(ctx, db.GetGameStateVersionsBetweenParams{
+			GameStateID: int64(gameStateId),
+			CreatedAt:   since,
+			CreatedAt_2: cutoff,
+		})
+	})
+}
+
 func convertRowToGameStateVersion(result db.GameStateVersion) (*game.GameStateVersion, error) {
 	var gameState *game.GameState
 	err := utils.Unmarshal(result.Data, &gameState)
@@ -0,0 +1,111 @@
+package games
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/db"
+	"github.com/coopersmall/subswag/domain/game"
+	reposdomain "github.com/coopersmall/subswag/repos/domain"
+)
+
+// GameTimersRepo stores reveal deadlines so they survive restarts. Due timers
+// are leased to a single instance while they are handled; a lease that runs
+// out before the timer is deleted lets another instance retry it.
+type GameTimersRepo struct {
+	*reposdomain.SharedRepo[game.GameStateID, *game.RevealTimer, db.GameTimer]
+}
+
+func NewGameTimersRepo(
+	querier db.IQuerier,
+	tracer apm.ITracer,
+) *GameTimersRepo {
+	return &GameTimersRepo{
+		SharedRepo: reposdomain.NewSharedRepo[game.GameStateID, *game.RevealTimer, db.GameTimer](
+			"game_timer",
+			querier,
+			tracer,
+			convertRowToRevealTimer,
+			convertRevealTimerToRow,
+			nil,
+			nil,
+			func(ctx context.Context, iqrw db.ISharedQueriesReadWrite, gt db.GameTimer) (sql.Result, error) {
+				return iqrw.UpsertGameTimer(ctx, db.UpsertGameTimerParams{
+					GameStateID: gt.GameStateID,
+					RoundNumber: gt.RoundNumber,
+					Deadline:    gt.Deadline,
+					CreatedAt:   gt.CreatedAt,
+				})
+			},
+			nil,
+			nil,
+		),
+	}
+}
+
+// Schedule sets the game's reveal timer, replacing any earlier one
+func (r *GameTimersRepo) Schedule(ctx context.Context, timer *game.RevealTimer) error {
+	return r.SharedRepo.Create(ctx, timer)
+}
+
+// ClaimDue leases up to limit timers whose deadline has passed. Timers leased
+// by another instance are skipped until their lease runs out.
+func (r *GameTimersRepo) ClaimDue(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]*game.RevealTimer, error) {
+	var rows []db.GameTimer
+	err := r.SharedRepo.Execute(ctx, func(ctx context.Context, queries db.ISharedQueriesReadWrite) error {
+		var err error
+		rows, err = queries.ClaimDueGameTimers(ctx, db.ClaimDueGameTimersParams{
+			Deadline: now,
+			LockedUntil: sql.NullTime{
+				Time:  now.Add(lease),
+				Valid: true,
+			},
+			Limit: int32(limit),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	timers := make([]*game.RevealTimer, len(rows))
+	for i, row := range rows {
+		timers[i], _ = convertRowToRevealTimer(row)
+	}
+	return timers, nil
+}
+
+// Complete removes a handled timer. A timer rescheduled for a later round in
+// the meantime is kept.
+func (r *GameTimersRepo) Complete(ctx context.Context, timer *game.RevealTimer) error {
+	return r.SharedRepo.Execute(ctx, func(ctx context.Context, queries db.ISharedQueriesReadWrite) error {
+		_, err := queries.DeleteGameTimer(ctx, db.DeleteGameTimerParams{
+			GameStateID: int64(timer.GameStateID),
+			RoundNumber: int32(timer.RoundNumber),
+		})
+		return err
+	})
+}
+
+func convertRowToRevealTimer(result db.GameTimer) (*game.RevealTimer, error) {
+	return &game.RevealTimer{
+		GameStateID: game.GameStateID(result.GameStateID),
+		RoundNumber: int(result.RoundNumber),
+		Deadline:    result.Deadline,
+	}, nil
+}
+
+func convertRevealTimerToRow(timer *game.RevealTimer) (db.GameTimer, error) {
+	return db.GameTimer{
+		GameStateID: int64(timer.GameStateID),
+		RoundNumber: int32(timer.RoundNumber),
+		Deadline:    timer.Deadline,
+		CreatedAt:   time.Now(),
+	}, nil
+}
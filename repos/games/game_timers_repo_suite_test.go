@@ -0,0 +1,20 @@
+package games_test
+
+import (
+	"testing"
+
+	tt "github.com/coopersmall/subswag/testing"
+)
+
+type GameTimersRepoTestSuite struct {
+	*tt.IntegrationTest
+}
+
+func TestGameTimersRepoTestSuite(t *testing.T) {
+	config := tt.GetIntegrationSuiteConfig()
+	tt.RunIntegrationTest(t, config, func(its *tt.IntegrationTest) *GameTimersRepoTestSuite {
+		return &GameTimersRepoTestSuite{
+			IntegrationTest: its,
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package games_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/repos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	ctx     = context.Background()
+	now     = time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	lease   = 30 * time.Second
+	game1Id = game.GameStateID(1)
+	game2Id = game.GameStateID(2)
+	repo    repos.IGameTimersRepo
+)
+
+func newTimer(gameStateId game.GameStateID, roundNumber int, deadline time.Time) *game.RevealTimer {
+	return &game.RevealTimer{
+		GameStateID: gameStateId,
+		RoundNumber: roundNumber,
+		Deadline:    deadline,
+	}
+}
+
+func (s *GameTimersRepoTestSuite) SetupSubTest() {
+	s.Reset()
+	repos, _ := s.GetRepos()
+	repo = repos.GameTimersRepo()
+	for _, gameStateId := range []game.GameStateID{game1Id, game2Id} {
+		err := repos.GameStateRepo().Create(ctx, &game.GameState{
+			ID:       gameStateId,
+			Metadata: domain.NewMetadata(),
+		})
+		assert.NoError(s.T(), err)
+	}
+}
+
+func (s *GameTimersRepoTestSuite) TestGameTimersRepo() {
+	s.Run("it claims due timers and deletes them once complete", func() {
+		timer := newTimer(game1Id, 3, now.Add(-time.Minute))
+		err := repo.Schedule(ctx, timer)
+		assert.NoError(s.T(), err)
+
+		claimed, err := repo.ClaimDue(ctx, now, lease, 10)
+		assert.NoError(s.T(), err)
+		require.Len(s.T(), claimed, 1)
+		assert.Equal(s.T(), game1Id, claimed[0].GameStateID)
+		assert.Equal(s.T(), 3, claimed[0].RoundNumber)
+		assert.True(s.T(), timer.Deadline.Equal(claimed[0].Deadline))
+
+		err = repo.Complete(ctx, claimed[0])
+		assert.NoError(s.T(), err)
+
+		claimed, err = repo.ClaimDue(ctx, now.Add(time.Hour), lease, 10)
+		assert.NoError(s.T(), err)
+		assert.Empty(s.T(), claimed)
+	})
+
+	s.Run("it skips timers that are not due", func() {
+		err := repo.Schedule(ctx, newTimer(game1Id, 0, now.Add(time.Minute)))
+		assert.NoError(s.T(), err)
+
+		claimed, err := repo.ClaimDue(ctx, now, lease, 10)
+		assert.NoError(s.T(), err)
+		assert.Empty(s.T(), claimed)
+	})
+
+	s.Run("it does not hand out a leased timer twice", func() {
+		err := repo.Schedule(ctx, newTimer(game1Id, 0, now.Add(-time.Minute)))
+		assert.NoError(s.T(), err)
+
+		claimed, err := repo.ClaimDue(ctx, now, lease, 10)
+		assert.NoError(s.T(), err)
+		assert.Len(s.T(), claimed, 1)
+
+		claimed, err = repo.ClaimDue(ctx, now.Add(lease-time.Second), lease, 10)
+		assert.NoError(s.T(), err)
+		assert.Empty(s.T(), claimed)
+	})
+
+	s.Run("it hands out a timer again once its lease runs out", func() {
+		err := repo.Schedule(ctx, newTimer(game1Id, 0, now.Add(-time.Minute)))
+		assert.NoError(s.T(), err)
+
+		claimed, err := repo.ClaimDue(ctx, now, lease, 10)
+		assert.NoError(s.T(), err)
+		assert.Len(s.T(), claimed, 1)
+
+		claimed, err = repo.ClaimDue(ctx, now.Add(lease), lease, 10)
+		assert.NoError(s.T(), err)
+		require.Len(s.T(), claimed, 1)
+		assert.Equal(s.T(), game1Id, claimed[0].GameStateID)
+	})
+
+	s.Run("it claims the earliest deadlines up to the limit", func() {
+		err := repo.Schedule(ctx, newTimer(game1Id, 0, now.Add(-time.Minute)))
+		assert.NoError(s.T(), err)
+		err = repo.Schedule(ctx, newTimer(game2Id, 0, now.Add(-2*time.Minute)))
+		assert.NoError(s.T(), err)
+
+		claimed, err := repo.ClaimDue(ctx, now, lease, 1)
+		assert.NoError(s.T(), err)
+		require.Len(s.T(), claimed, 1)
+		assert.Equal(s.T(), game2Id, claimed[0].GameStateID)
+
+		claimed, err = repo.ClaimDue(ctx, now, lease, 10)
+		assert.NoError(s.T(), err)
+		require.Len(s.T(), claimed, 1)
+		assert.Equal(s.T(), game1Id, claimed[0].GameStateID)
+	})
+
+	s.Run("it keeps a timer rescheduled for a later round", func() {
+		err := repo.Schedule(ctx, newTimer(game1Id, 0, now.Add(-time.Minute)))
+		assert.NoError(s.T(), err)
+		claimed, err := repo.ClaimDue(ctx, now, lease, 10)
+		assert.NoError(s.T(), err)
+		require.Len(s.T(), claimed, 1)
+
+		// Rescheduling replaces the timer and drops its lease
+		err = repo.Schedule(ctx, newTimer(game1Id, 1, now.Add(-time.Second)))
+		assert.NoError(s.T(), err)
+		err = repo.Complete(ctx, claimed[0])
+		assert.NoError(s.T(), err)
+
+		claimed, err = repo.ClaimDue(ctx, now, lease, 10)
+		assert.NoError(s.T(), err)
+		require.Len(s.T(), claimed, 1)
+		assert.Equal(s.T(), 1, claimed[0].RoundNumber)
+	})
+}
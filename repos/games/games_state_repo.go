@@ -41,13 +41,7 @@ func NewGameStateRepo(
 					Data:      gs.Data,
 				})
 			},
-			func(ctx context.Context, iqrw db.ISharedQueriesReadWrite, gs db.GameState) (sql.Result, error) {
-				return iqrw.UpdateGameState(ctx, db.UpdateGameStateParams{
-					ID:        gs.ID,
-					UpdatedAt: gs.UpdatedAt,
-					Data:      gs.Data,
-				})
-			},
+			nil,
 			func(ctx context.Context, iqrw db.ISharedQueriesReadWrite, gsi game.GameStateID) (sql.Result, error) {
 				return iqrw.DeleteGameState(ctx, int64(gsi))
 			},
@@ -55,12 +49,45 @@ func NewGameStateRepo(
 	}
 }
 
+// Update writes the game state if nobody else has updated it since it was read,
+// and moves it on to the next version. A stale write returns a conflict error
+// and leaves the stored game untouched.
+func (r *GameStateRepo) Update(ctx context.Context, gameState *game.GameState) error {
+	row, err := convertGameStateToRow(gameState)
+	if err != nil {
+		return utils.NewInternalError("failed to convert to row", err)
+	}
+	var result sql.Result
+	err = r.SharedRepo.Execute(ctx, func(ctx context.Context, queries db.ISharedQueriesReadWrite) error {
+		var err error
+		result, err = queries.UpdateGameState(ctx, db.UpdateGameStateParams{
+			ID:        row.ID,
+			UpdatedAt: row.UpdatedAt,
+			Data:      row.Data,
+			Version:   row.Version,
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		if _, err := r.SharedRepo.Get(ctx, gameState.ID); err != nil {
+			return err
+		}
+		return utils.NewConflictError("game state was updated since it was read")
+	}
+	gameState.Version++
+	return nil
+}
+
 func convertRowToGameState(result db.GameState) (*game.GameState, error) {
 	var data game.GameStateData
 	err := utils.Unmarshal(result.Data, &data)
 	return &game.GameState{
 		ID:            game.GameStateID(result.ID),
 		GameStateData: data,
+		Version:       result.Version,
 		Metadata: &domain.Metadata{
 			CreatedAt: result.CreatedAt,
 			UpdatedAt: result.UpdatedAt.Time,
@@ -77,6 +104,7 @@ func convertGameStateToRow(gameState *game.GameState) (db.GameState, error) {
 			Time:  gameState.Metadata.UpdatedAt,
 			Valid: !gameState.Metadata.UpdatedAt.IsZero(),
 		},
-		Data: json.RawMessage(data),
+		Version: gameState.Version,
+		Data:    json.RawMessage(data),
 	}, err
 }
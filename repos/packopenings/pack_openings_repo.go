@@ -0,0 +1,165 @@
+package packopenings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/db"
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/pack"
+	"github.com/coopersmall/subswag/domain/user"
+	reposdomain "github.com/coopersmall/subswag/repos/domain"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the Postgres error code for a duplicate key
+const uniqueViolation = "23505"
+
+// PackOpeningsRepo is the audit log of the packs a user has opened. Openings
+// are only ever added, together with the cards they dealt, by Record.
+type PackOpeningsRepo struct {
+	*reposdomain.StandardRepo[pack.PackOpeningID, *pack.PackOpening, db.PackOpening]
+}
+
+func NewPackOpeningsRepo(
+	querier db.IQuerier,
+	tracer apm.ITracer,
+	userId user.UserID,
+) *PackOpeningsRepo {
+	return &PackOpeningsRepo{
+		StandardRepo: reposdomain.NewStandardRepo[pack.PackOpeningID, *pack.PackOpening, db.PackOpening](
+			"pack_opening",
+			querier,
+			tracer,
+			userId,
+			convertRowToPackOpening,
+			convertPackOpeningToRow,
+			isEmptyPackOpening,
+			func(ctx context.Context, iqro db.IStandardQueriesReadOnly, id pack.PackOpeningID) (db.PackOpening, error) {
+				return iqro.GetPackOpening(ctx, db.GetPackOpeningParams{
+					ID:     int64(id),
+					UserID: int64(userId),
+				})
+			},
+			func(ctx context.Context, iqro db.IStandardQueriesReadOnly) ([]db.PackOpening, error) {
+				return iqro.GetAllPackOpenings(ctx, int64(userId))
+			},
+			nil,
+			nil,
+			nil,
+		),
+	}
+}
+
+// Latest returns the user's last opening of the pack type, or a not found
+// error if they have never opened one
+func (r *PackOpeningsRepo) Latest(ctx context.Context, packTypeId pack.PackTypeID) (*pack.PackOpening, error) {
+	found, err := r.StandardRepo.Query(ctx, func(ctx context.Context, queries db.IStandardQueriesReadOnly, userId user.UserID) ([]db.PackOpening, error) {
+		found, err := queries.GetLatestPackOpening(ctx, db.GetLatestPackOpeningParams{
+			UserID:     int64(userId),
+			PackTypeID: int64(packTypeId),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []db.PackOpening{found}, nil
+	})
+	if len(found) == 0 {
+		return nil, utils.NewNotFoundError("pack opening not found", err)
+	}
+	return found[0], err
+}
+
+// Record saves the opening and adds the cards it dealt to the user's
+// collection in one statement, so either both are saved or neither is, and
+// returns how many copies of each card the user owns afterwards. If the user
+// opened another pack of the type since the opening was dealt, the opening's
+// sequence is taken and a conflict error is returned.
+func (r *PackOpeningsRepo) Record(ctx context.Context, opening *pack.PackOpening) (map[card.SerializableCardID]int, error) {
+	row, err := convertPackOpeningToRow(opening)
+	if err != nil {
+		return nil, utils.NewInternalError("failed to convert to row", err)
+	}
+	userCards := opening.UserCards()
+	params := db.CreatePackOpeningParams{
+		ID:          row.ID,
+		PackTypeID:  row.PackTypeID,
+		Sequence:    row.Sequence,
+		CreatedAt:   row.CreatedAt,
+		Data:        row.Data,
+		UserCardIDs: make([]int64, len(userCards)),
+		CardIDs:     make([]int64, len(userCards)),
+		CardData:    make([]string, len(userCards)),
+	}
+	for i, userCard := range userCards {
+		data, err := utils.Marshal(userCard)
+		if err != nil {
+			return nil, utils.NewInternalError("failed to convert to row", err)
+		}
+		params.UserCardIDs[i] = int64(userCard.ID)
+		params.CardIDs[i] = int64(userCard.CardID)
+		params.CardData[i] = string(data)
+	}
+
+	conflict := false
+	owned := map[card.SerializableCardID]int{}
+	err = r.StandardRepo.Execute(ctx, func(ctx context.Context, q db.IStandardQueriesReadWrite, userId user.UserID) error {
+		params.UserID = int64(userId)
+		rows, err := q.CreatePackOpening(ctx, params)
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			conflict = true
+		}
+		if err != nil {
+			return err
+		}
+		var created int64
+		for _, row := range rows {
+			created += row.Copies
+			owned[card.SerializableCardID(row.CardID)] = int(row.Owned)
+		}
+		if created != int64(len(userCards)) {
+			return fmt.Errorf("%d of %d cards were added to the collection", created, len(userCards))
+		}
+		return nil
+	})
+	if conflict {
+		return nil, utils.NewConflictError(fmt.Sprintf("pack opening %d was already recorded", opening.Sequence), err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return owned, nil
+}
+
+func isEmptyPackOpening(o *pack.PackOpening) bool {
+	return o == nil || o.ID == 0
+}
+
+func convertRowToPackOpening(result db.PackOpening) (*pack.PackOpening, error) {
+	var data pack.PackOpeningData
+	err := utils.Unmarshal(result.Data, &data)
+	return &pack.PackOpening{
+		ID:              pack.PackOpeningID(result.ID),
+		PackOpeningData: data,
+		Metadata: &domain.Metadata{
+			CreatedAt: result.CreatedAt,
+		},
+	}, err
+}
+
+func convertPackOpeningToRow(o *pack.PackOpening) (db.PackOpening, error) {
+	data, err := utils.Marshal(o.PackOpeningData)
+	return db.PackOpening{
+		ID:         int64(o.ID),
+		UserID:     int64(o.UserID),
+		PackTypeID: int64(o.PackTypeID),
+		Sequence:   int32(o.Sequence),
+		CreatedAt:  o.Metadata.CreatedAt,
+		Data:       data,
+	}, err
+}
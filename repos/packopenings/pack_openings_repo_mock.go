@@ -0,0 +1,45 @@
+package packopenings
+
+import (
+	"context"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/pack"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPackOpeningsRepo struct {
+	mock.Mock
+}
+
+func (m *MockPackOpeningsRepo) Get(ctx context.Context, id pack.PackOpeningID) (*pack.PackOpening, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack.PackOpening), args.Error(1)
+}
+
+func (m *MockPackOpeningsRepo) All(ctx context.Context) ([]*pack.PackOpening, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*pack.PackOpening), args.Error(1)
+}
+
+func (m *MockPackOpeningsRepo) Latest(ctx context.Context, packTypeId pack.PackTypeID) (*pack.PackOpening, error) {
+	args := m.Called(ctx, packTypeId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack.PackOpening), args.Error(1)
+}
+
+func (m *MockPackOpeningsRepo) Record(ctx context.Context, opening *pack.PackOpening) (map[card.SerializableCardID]int, error) {
+	args := m.Called(ctx, opening)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[card.SerializableCardID]int), args.Error(1)
+}
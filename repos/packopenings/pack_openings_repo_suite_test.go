@@ -0,0 +1,20 @@
+package packopenings_test
+
+import (
+	"testing"
+
+	tt "github.com/coopersmall/subswag/testing"
+)
+
+type PackOpeningsRepoTestSuite struct {
+	*tt.IntegrationTest
+}
+
+func TestPackOpeningsRepoTestSuite(t *testing.T) {
+	config := tt.GetIntegrationSuiteConfig()
+	tt.RunIntegrationTest(t, config, func(its *tt.IntegrationTest) *PackOpeningsRepoTestSuite {
+		return &PackOpeningsRepoTestSuite{
+			IntegrationTest: its,
+		}
+	})
+}
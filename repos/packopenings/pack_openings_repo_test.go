@@ -0,0 +1,112 @@
+package packopenings_test
+
+import (
+	"context"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/pack"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/repos"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	ctx       = context.Background()
+	userId    = user.UserID(1)
+	validUser = user.NewUser()
+	packType  = pack.NewPackType(pack.PackTypeData{
+		Name: "Starter",
+		Slots: []pack.PackSlot{
+			{Guaranteed: card.CardRarityCommon},
+			{Guaranteed: card.CardRarityCommon},
+		},
+	})
+	catalog = map[card.CardRarity][]card.SerializableCardID{
+		card.CardRarityCommon: {7},
+	}
+	repo          repos.IPackOpeningsRepo
+	userCardsRepo repos.IUserCardsRepo
+)
+
+func open(previous *pack.PackOpening) *pack.PackOpening {
+	opening, err := packType.Open(userId, previous, catalog, pack.CryptoRoller)
+	if err != nil {
+		panic(err)
+	}
+	return opening
+}
+
+func (s *PackOpeningsRepoTestSuite) SetupSubTest() {
+	s.Reset()
+	repos, _ := s.GetRepos()
+	repo = repos.PackOpeningsRepo(userId)
+	userCardsRepo = repos.UserCardsRepo(userId)
+	validUser.ID = userId
+	err := repos.UsersRepo().Create(ctx, validUser)
+	assert.NoError(s.T(), err)
+}
+
+func (s *PackOpeningsRepoTestSuite) TestPackOpeningsRepo() {
+	s.Run("it records the opening and the cards it dealt together", func() {
+		first := open(nil)
+		owned, err := repo.Record(ctx, first)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), map[card.SerializableCardID]int{7: 2}, owned)
+
+		result, err := repo.Get(ctx, first.ID)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), first.PackOpeningData, result.PackOpeningData)
+
+		owned, err = userCardsRepo.Owned(ctx)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), map[card.SerializableCardID]int{7: 2}, owned)
+
+		second := open(first)
+		owned, err = repo.Record(ctx, second)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), map[card.SerializableCardID]int{7: 4}, owned)
+
+		latest, err := repo.Latest(ctx, packType.ID)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), second.ID, latest.ID)
+		assert.Equal(s.T(), 2, latest.Sequence)
+
+		results, err := repo.All(ctx)
+		assert.NoError(s.T(), err)
+		assert.Len(s.T(), results, 2)
+	})
+
+	s.Run("it adds nothing when the opening's sequence was already recorded", func() {
+		_, err := repo.Record(ctx, open(nil))
+		assert.NoError(s.T(), err)
+
+		_, err = repo.Record(ctx, open(nil))
+		assert.True(s.T(), utils.IsConflictError(err))
+
+		owned, err := userCardsRepo.Owned(ctx)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), map[card.SerializableCardID]int{7: 2}, owned)
+	})
+
+	s.Run("it fails when the user has never opened the pack type", func() {
+		_, err := repo.Latest(ctx, packType.ID)
+		assert.True(s.T(), utils.IsNotFoundError(err))
+	})
+
+	s.Run("it only lists the user's own openings", func() {
+		_, err := repo.Record(ctx, open(nil))
+		assert.NoError(s.T(), err)
+
+		repos, _ := s.GetRepos()
+		otherUser := user.NewUser()
+		otherUser.ID = 2
+		err = repos.UsersRepo().Create(ctx, otherUser)
+		assert.NoError(s.T(), err)
+
+		results, err := repos.PackOpeningsRepo(otherUser.ID).All(ctx)
+		assert.NoError(s.T(), err)
+		assert.Empty(s.T(), results)
+	})
+}
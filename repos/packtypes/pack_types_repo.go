@@ -0,0 +1,83 @@
+package packtypes
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/db"
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/pack"
+	reposdomain "github.com/coopersmall/subswag/repos/domain"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// PackTypesRepo stores the booster packs admins have defined
+type PackTypesRepo struct {
+	*reposdomain.SharedRepo[pack.PackTypeID, *pack.PackType, db.PackType]
+}
+
+func NewPackTypesRepo(
+	querier db.IQuerier,
+	tracer apm.ITracer,
+) *PackTypesRepo {
+	return &PackTypesRepo{
+		SharedRepo: reposdomain.NewSharedRepo[pack.PackTypeID, *pack.PackType, db.PackType](
+			"pack_type",
+			querier,
+			tracer,
+			convertRowToPackType,
+			convertPackTypeToRow,
+			func(ctx context.Context, iqro db.ISharedQueriesReadOnly, id pack.PackTypeID) (db.PackType, error) {
+				return iqro.GetPackType(ctx, int64(id))
+			},
+			func(ctx context.Context, iqro db.ISharedQueriesReadOnly) ([]db.PackType, error) {
+				return iqro.GetAllPackTypes(ctx)
+			},
+			func(ctx context.Context, iqrw db.ISharedQueriesReadWrite, p db.PackType) (sql.Result, error) {
+				return iqrw.CreatePackType(ctx, db.CreatePackTypeParams{
+					ID:        p.ID,
+					CreatedAt: p.CreatedAt,
+					Data:      p.Data,
+				})
+			},
+			func(ctx context.Context, iqrw db.ISharedQueriesReadWrite, p db.PackType) (sql.Result, error) {
+				return iqrw.UpdatePackType(ctx, db.UpdatePackTypeParams{
+					ID:        p.ID,
+					UpdatedAt: p.UpdatedAt,
+					Data:      p.Data,
+				})
+			},
+			func(ctx context.Context, iqrw db.ISharedQueriesReadWrite, id pack.PackTypeID) (sql.Result, error) {
+				return iqrw.DeletePackType(ctx, int64(id))
+			},
+		),
+	}
+}
+
+func convertRowToPackType(result db.PackType) (*pack.PackType, error) {
+	var data pack.PackTypeData
+	err := utils.Unmarshal(result.Data, &data)
+	return &pack.PackType{
+		ID:           pack.PackTypeID(result.ID),
+		PackTypeData: data,
+		Metadata: &domain.Metadata{
+			CreatedAt: result.CreatedAt,
+			UpdatedAt: result.UpdatedAt.Time,
+		},
+	}, err
+}
+
+func convertPackTypeToRow(p *pack.PackType) (db.PackType, error) {
+	data, err := utils.Marshal(p.PackTypeData)
+	return db.PackType{
+		ID:        int64(p.ID),
+		CreatedAt: p.Metadata.CreatedAt,
+		UpdatedAt: sql.NullTime{
+			Time:  p.Metadata.UpdatedAt,
+			Valid: p.Metadata.UpdatedAt != time.Time{},
+		},
+		Data: data,
+	}, err
+}
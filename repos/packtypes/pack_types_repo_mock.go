@@ -0,0 +1,43 @@
+package packtypes
+
+import (
+	"context"
+
+	"github.com/coopersmall/subswag/domain/pack"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPackTypesRepo struct {
+	mock.Mock
+}
+
+func (m *MockPackTypesRepo) Get(ctx context.Context, id pack.PackTypeID) (*pack.PackType, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack.PackType), args.Error(1)
+}
+
+func (m *MockPackTypesRepo) All(ctx context.Context) ([]*pack.PackType, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*pack.PackType), args.Error(1)
+}
+
+func (m *MockPackTypesRepo) Create(ctx context.Context, p *pack.PackType) error {
+	args := m.Called(ctx, p)
+	return args.Error(0)
+}
+
+func (m *MockPackTypesRepo) Update(ctx context.Context, p *pack.PackType) error {
+	args := m.Called(ctx, p)
+	return args.Error(0)
+}
+
+func (m *MockPackTypesRepo) Delete(ctx context.Context, id pack.PackTypeID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,20 @@
+package packtypes_test
+
+import (
+	"testing"
+
+	tt "github.com/coopersmall/subswag/testing"
+)
+
+type PackTypesRepoTestSuite struct {
+	*tt.IntegrationTest
+}
+
+func TestPackTypesRepoTestSuite(t *testing.T) {
+	config := tt.GetIntegrationSuiteConfig()
+	tt.RunIntegrationTest(t, config, func(its *tt.IntegrationTest) *PackTypesRepoTestSuite {
+		return &PackTypesRepoTestSuite{
+			IntegrationTest: its,
+		}
+	})
+}
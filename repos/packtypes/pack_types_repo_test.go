@@ -0,0 +1,77 @@
+package packtypes_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/pack"
+	"github.com/coopersmall/subswag/repos"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ctx           = context.Background()
+	packTypeId    = pack.PackTypeID(1)
+	validPackType = &pack.PackType{
+		ID: packTypeId,
+		PackTypeData: pack.PackTypeData{
+			Name: "Starter",
+			Slots: []pack.PackSlot{
+				{Guaranteed: card.CardRarityCommon},
+				{Odds: map[card.CardRarity]int{card.CardRarityCommon: 9000, card.CardRarityRare: 1000}},
+			},
+			Pity: &pack.PackPity{Rarity: card.CardRarityRare, After: 10},
+		},
+		Metadata: &domain.Metadata{
+			CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	repo repos.IPackTypesRepo
+)
+
+func (s *PackTypesRepoTestSuite) SetupSubTest() {
+	s.Reset()
+	repos, _ := s.GetRepos()
+	repo = repos.PackTypesRepo()
+}
+
+func (s *PackTypesRepoTestSuite) TestPackTypesRepo() {
+	s.Run("it works", func() {
+		err := repo.Create(ctx, validPackType)
+		assert.NoError(s.T(), err)
+
+		result, err := repo.Get(ctx, packTypeId)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), validPackType.PackTypeData, result.PackTypeData)
+
+		updated := &pack.PackType{}
+		utils.DeepClone(validPackType, updated)
+		updated.Name = "Veteran"
+		updated.Metadata.UpdatedAt = time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+		err = repo.Update(ctx, updated)
+		assert.NoError(s.T(), err)
+
+		results, err := repo.All(ctx)
+		assert.NoError(s.T(), err)
+		assert.Len(s.T(), results, 1)
+		assert.Equal(s.T(), "Veteran", results[0].Name)
+
+		err = repo.Delete(ctx, packTypeId)
+		assert.NoError(s.T(), err)
+
+		results, err = repo.All(ctx)
+		assert.NoError(s.T(), err)
+		assert.Empty(s.T(), results)
+	})
+
+	s.Run("it fails with non-existent pack type", func() {
+		_, err := repo.Get(ctx, packTypeId)
+		assert.Error(s.T(), err)
+
+		err = repo.Delete(ctx, packTypeId)
+		assert.Error(s.T(), err)
+	})
+}
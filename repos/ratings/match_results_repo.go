@@ -0,0 +1,149 @@
+package ratings
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/db"
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/rating"
+	"github.com/coopersmall/subswag/domain/user"
+	reposdomain "github.com/coopersmall/subswag/repos/domain"
+	"github.com/coopersmall/subswag/utils"
+)
+
+type MatchResultsRepo struct {
+	*reposdomain.SharedRepo[game.GameStateID, *rating.MatchResult, db.MatchResult]
+}
+
+func NewMatchResultsRepo(
+	querier db.IQuerier,
+	tracer apm.ITracer,
+) *MatchResultsRepo {
+	return &MatchResultsRepo{
+		SharedRepo: reposdomain.NewSharedRepo[game.GameStateID, *rating.MatchResult, db.MatchResult](
+			"match_result",
+			querier,
+			tracer,
+			convertRowToMatchResult,
+			convertMatchResultToRow,
+			func(ctx context.Context, iqro db.ISharedQueriesReadOnly, gsi game.GameStateID) (db.MatchResult, error) {
+				return iqro.GetMatchResult(ctx, int64(gsi))
+			},
+			nil,
+			func(ctx context.Context, iqrw db.ISharedQueriesReadWrite, mr db.MatchResult) (sql.Result, error) {
+				return iqrw.CreateMatchResult(ctx, db.CreateMatchResultParams{
+					GameStateID: mr.GameStateID,
+					Player1ID:   mr.Player1ID,
+					Player2ID:   mr.Player2ID,
+					CreatedAt:   mr.CreatedAt,
+					Data:        mr.Data,
+				})
+			},
+			nil,
+			nil,
+		),
+	}
+}
+
+// ForUser returns the user's match history, most recent first
+func (r *MatchResultsRepo) ForUser(ctx context.Context, userId user.UserID, limit int, offset int) ([]*rating.MatchResult, error) {
+	return r.SharedRepo.Query(ctx, func(ctx context.Context, queries db.ISharedQueriesReadOnly) ([]db.MatchResult, error) {
+		return queries.GetMatchResultsByUserID(ctx, db.GetMatchResultsByUserIDParams{
+			Player1ID: int64(userId),
+			Limit:     int32(limit),
+			Offset:    int32(offset),
+		})
+	})
+}
+
+// Record stores the result of a game together with both players' new
+// ratings and their decks' win/loss records, in a single statement so none is
+// ever written without the others. ratings are in the same order as the
+// result's players; a deck that has since been deleted is skipped. It returns a
+// conflict error if either rating changed since it was read, and an already
+// exists error if the game has been recorded.
+func (r *MatchResultsRepo) Record(ctx context.Context, matchResult *rating.MatchResult, ratings [2]*rating.UserRating) error {
+	row, err := convertMatchResultToRow(matchResult)
+	if err != nil {
+		return utils.NewInternalError("failed to convert to row", err)
+	}
+	var ratingRows [2]db.UserRating
+	for i, userRating := range ratings {
+		if ratingRows[i], err = convertUserRatingToRow(userRating); err != nil {
+			return utils.NewInternalError("failed to convert to row", err)
+		}
+	}
+	var result sql.Result
+	err = r.SharedRepo.Execute(ctx, func(ctx context.Context, queries db.ISharedQueriesReadWrite) error {
+		var err error
+		result, err = queries.RecordMatchResult(ctx, db.RecordMatchResultParams{
+			GameStateID:    row.GameStateID,
+			Player1ID:      row.Player1ID,
+			Player2ID:      row.Player2ID,
+			CreatedAt:      row.CreatedAt,
+			Data:           row.Data,
+			Player1Rating:  ratingRows[0].Rating,
+			Player1Data:    ratingRows[0].Data,
+			Player1Version: ratingRows[0].Version,
+			Player2Rating:  ratingRows[1].Rating,
+			Player2Data:    ratingRows[1].Data,
+			Player2Version: ratingRows[1].Version,
+			Player1DeckID:  int64(matchResult.Players[0].DeckID),
+			Player1Won:     matchResult.Players[0].Score == rating.ScoreWin,
+			Player2DeckID:  int64(matchResult.Players[1].DeckID),
+			Player2Won:     matchResult.Players[1].Score == rating.ScoreWin,
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		if _, err := r.SharedRepo.Get(ctx, matchResult.GameStateID); err == nil {
+			return utils.NewAlreadyExistsError("game already recorded")
+		} else if !utils.IsNotFoundError(err) {
+			return err
+		}
+		return utils.NewConflictError("rating was updated since it was read")
+	}
+	for _, userRating := range ratings {
+		userRating.Version++
+	}
+	return nil
+}
+
+func convertRowToMatchResult(result db.MatchResult) (*rating.MatchResult, error) {
+	var matchResult *rating.MatchResult
+	err := utils.Unmarshal(result.Data, &matchResult)
+	if err != nil {
+		return nil, err
+	}
+	matchResult.GameStateID = game.GameStateID(result.GameStateID)
+	matchResult.Metadata = &domain.Metadata{
+		CreatedAt: result.CreatedAt,
+		UpdatedAt: result.UpdatedAt.Time,
+	}
+	return matchResult, nil
+}
+
+func convertMatchResultToRow(matchResult *rating.MatchResult) (db.MatchResult, error) {
+	data, err := utils.Marshal(matchResult)
+	if err != nil {
+		return db.MatchResult{}, err
+	}
+	return db.MatchResult{
+		GameStateID: int64(matchResult.GameStateID),
+		Player1ID:   int64(matchResult.Players[0].UserID),
+		Player2ID:   int64(matchResult.Players[1].UserID),
+		CreatedAt:   matchResult.Metadata.CreatedAt,
+		UpdatedAt: sql.NullTime{
+			Time:  matchResult.Metadata.UpdatedAt,
+			Valid: !matchResult.Metadata.UpdatedAt.IsZero(),
+		},
+		Data: json.RawMessage(data),
+	}, err
+}
@@ -0,0 +1,20 @@
+package ratings_test
+
+import (
+	"testing"
+
+	tt "github.com/coopersmall/subswag/testing"
+)
+
+type MatchResultsRepoTestSuite struct {
+	*tt.IntegrationTest
+}
+
+func TestMatchResultsRepoTestSuite(t *testing.T) {
+	config := tt.GetIntegrationSuiteConfig()
+	tt.RunIntegrationTest(t, config, func(its *tt.IntegrationTest) *MatchResultsRepoTestSuite {
+		return &MatchResultsRepoTestSuite{
+			IntegrationTest: its,
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package ratings_test
+
+import (
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/rating"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWin is a game won by player 1
+func newWin(gameStateId game.GameStateID) *rating.MatchResult {
+	return &rating.MatchResult{
+		GameStateID: gameStateId,
+		Players: [2]rating.MatchPlayer{
+			{UserID: player1Id, DeckID: 1, RoundsWon: 2, Score: rating.ScoreWin},
+			{UserID: player2Id, DeckID: 2, RoundsWon: 1, Score: rating.ScoreLoss},
+		},
+		Winner:       &player1Id,
+		RoundsPlayed: 3,
+		Metadata:     domain.NewMetadata(),
+	}
+}
+
+// currentRatings starts and reads both players' ratings
+func (s *MatchResultsRepoTestSuite) currentRatings() [2]*rating.UserRating {
+	var ratings [2]*rating.UserRating
+	for i, userId := range []user.UserID{player1Id, player2Id} {
+		require.NoError(s.T(), userRatings.Start(ctx, userId))
+		userRating, err := userRatings.Get(ctx, userId)
+		require.NoError(s.T(), err)
+		ratings[i] = userRating
+	}
+	return ratings
+}
+
+func (s *MatchResultsRepoTestSuite) SetupSubTest() {
+	setupRatingsRepos(s.IntegrationTest)
+}
+
+func (s *MatchResultsRepoTestSuite) TestRecord() {
+	s.Run("it records the result and both ratings together", func() {
+		ratings := s.currentRatings()
+		result := newWin(game1Id)
+		result.Rate(ratings)
+
+		err := matchResults.Record(ctx, result, ratings)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), int64(1), ratings[0].Version)
+		assert.Equal(s.T(), int64(1), ratings[1].Version)
+
+		recorded, err := matchResults.Get(ctx, game1Id)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), result.Players, recorded.Players)
+
+		top, err := userRatings.Top(ctx, 10, 0)
+		require.NoError(s.T(), err)
+		require.Len(s.T(), top, 2)
+		assert.Equal(s.T(), player1Id, top[0].UserID)
+		assert.Equal(s.T(), result.Players[0].RatingAfter, top[0].Rating)
+		assert.Equal(s.T(), 1, top[0].GamesWon)
+		assert.Equal(s.T(), int64(1), top[0].Version)
+		assert.Equal(s.T(), player2Id, top[1].UserID)
+		assert.Equal(s.T(), result.Players[1].RatingAfter, top[1].Rating)
+
+		history, err := matchResults.ForUser(ctx, player2Id, 10, 0)
+		require.NoError(s.T(), err)
+		require.Len(s.T(), history, 1)
+		assert.Equal(s.T(), game1Id, history[0].GameStateID)
+	})
+
+	s.Run("it rejects ratings that changed since they were read", func() {
+		stale := s.currentRatings()
+		fresh := s.currentRatings()
+		first := newWin(game1Id)
+		first.Rate(fresh)
+		require.NoError(s.T(), matchResults.Record(ctx, first, fresh))
+
+		second := newWin(game2Id)
+		second.Rate(stale)
+		err := matchResults.Record(ctx, second, stale)
+		assert.True(s.T(), utils.IsConflictError(err))
+
+		_, err = matchResults.Get(ctx, game2Id)
+		assert.True(s.T(), utils.IsNotFoundError(err))
+		userRating, err := userRatings.Get(ctx, player1Id)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), first.Players[0].RatingAfter, userRating.Rating)
+		assert.Equal(s.T(), 1, userRating.GamesPlayed)
+	})
+
+	s.Run("it records a game only once", func() {
+		ratings := s.currentRatings()
+		result := newWin(game1Id)
+		result.Rate(ratings)
+		require.NoError(s.T(), matchResults.Record(ctx, result, ratings))
+
+		again := newWin(game1Id)
+		again.Rate(ratings)
+		err := matchResults.Record(ctx, again, ratings)
+		assert.True(s.T(), utils.IsAlreadyExistsError(err))
+
+		userRating, err := userRatings.Get(ctx, player1Id)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), int64(1), userRating.Version)
+		assert.Equal(s.T(), 1, userRating.GamesPlayed)
+	})
+	s.Run("it updates both decks' records with the result, once", func() {
+		repos, _ := s.GetRepos()
+		for i, userId := range []user.UserID{player1Id, player2Id} {
+			deck := card.NewDeck(card.SerializableDeckID(i+1), card.SerializableDeckData{UserID: userId, Name: "Deck"})
+			require.NoError(s.T(), repos.DecksRepo(userId).Create(ctx, deck))
+		}
+		ratings := s.currentRatings()
+		result := newWin(game1Id)
+		result.Rate(ratings)
+		require.NoError(s.T(), matchResults.Record(ctx, result, ratings))
+		err := matchResults.Record(ctx, result, ratings)
+		assert.True(s.T(), utils.IsAlreadyExistsError(err))
+
+		winner, err := repos.DecksRepo(player1Id).Get(ctx, 1)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), 1, winner.GamesPlayed)
+		assert.Equal(s.T(), 1, winner.GamesWon)
+		loser, err := repos.DecksRepo(player2Id).Get(ctx, 2)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), 1, loser.GamesPlayed)
+		assert.Equal(s.T(), 0, loser.GamesWon)
+	})
+}
@@ -0,0 +1,110 @@
+package ratings
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/db"
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/rating"
+	"github.com/coopersmall/subswag/domain/user"
+	reposdomain "github.com/coopersmall/subswag/repos/domain"
+	"github.com/coopersmall/subswag/utils"
+)
+
+type UserRatingsRepo struct {
+	*reposdomain.SharedRepo[user.UserID, *rating.UserRating, db.UserRating]
+}
+
+func NewUserRatingsRepo(
+	querier db.IQuerier,
+	tracer apm.ITracer,
+) *UserRatingsRepo {
+	return &UserRatingsRepo{
+		SharedRepo: reposdomain.NewSharedRepo[user.UserID, *rating.UserRating, db.UserRating](
+			"user_rating",
+			querier,
+			tracer,
+			convertRowToUserRating,
+			convertUserRatingToRow,
+			func(ctx context.Context, iqro db.ISharedQueriesReadOnly, ui user.UserID) (db.UserRating, error) {
+				return iqro.GetUserRating(ctx, int64(ui))
+			},
+			nil,
+			func(ctx context.Context, iqrw db.ISharedQueriesReadWrite, ur db.UserRating) (sql.Result, error) {
+				return iqrw.CreateUserRating(ctx, db.CreateUserRatingParams{
+					UserID:    ur.UserID,
+					Rating:    ur.Rating,
+					CreatedAt: ur.CreatedAt,
+					Data:      ur.Data,
+				})
+			},
+			nil,
+			nil,
+		),
+	}
+}
+
+// Start stores the starting rating for a user who does not have one yet, so
+// there is a row to lock when their first game is recorded. A user's rating
+// is only changed by MatchResultsRepo.Record.
+func (r *UserRatingsRepo) Start(ctx context.Context, userId user.UserID) error {
+	return r.SharedRepo.Create(ctx, rating.NewUserRating(userId))
+}
+
+// Top returns the ratings of users who have finished a game, best first
+func (r *UserRatingsRepo) Top(ctx context.Context, limit int, offset int) ([]*rating.UserRating, error) {
+	return r.SharedRepo.Query(ctx, func(ctx context.Context, queries db.ISharedQueriesReadOnly) ([]db.UserRating, error) {
+		return queries.GetTopUserRatings(ctx, db.GetTopUserRatingsParams{
+			Limit:  int32(limit),
+			Offset: int32(offset),
+		})
+	})
+}
+
+// ForUsers returns the ratings of the given users, best first. Users who have
+// not finished a game are left out.
+func (r *UserRatingsRepo) ForUsers(ctx context.Context, userIds []user.UserID) ([]*rating.UserRating, error) {
+	ids := make([]int64, len(userIds))
+	for i, userId := range userIds {
+		ids[i] = int64(userId)
+	}
+	return r.SharedRepo.Query(ctx, func(ctx context.Context, queries db.ISharedQueriesReadOnly) ([]db.UserRating, error) {
+		return queries.GetUserRatingsByUserIDs(ctx, ids)
+	})
+}
+
+func convertRowToUserRating(result db.UserRating) (*rating.UserRating, error) {
+	var userRating *rating.UserRating
+	err := utils.Unmarshal(result.Data, &userRating)
+	if err != nil {
+		return nil, err
+	}
+	userRating.UserID = user.UserID(result.UserID)
+	userRating.Version = result.Version
+	userRating.Metadata = &domain.Metadata{
+		CreatedAt: result.CreatedAt,
+		UpdatedAt: result.UpdatedAt.Time,
+	}
+	return userRating, nil
+}
+
+func convertUserRatingToRow(userRating *rating.UserRating) (db.UserRating, error) {
+	data, err := utils.Marshal(userRating)
+	if err != nil {
+		return db.UserRating{}, err
+	}
+	return db.UserRating{
+		UserID:    int64(userRating.UserID),
+		Rating:    userRating.Rating,
+		CreatedAt: userRating.Metadata.CreatedAt,
+		UpdatedAt: sql.NullTime{
+			Time:  userRating.Metadata.UpdatedAt,
+			Valid: !userRating.Metadata.UpdatedAt.IsZero(),
+		},
+		Version: userRating.Version,
+		Data:    json.RawMessage(data),
+	}, err
+}
@@ -0,0 +1,20 @@
+package ratings_test
+
+import (
+	"testing"
+
+	tt "github.com/coopersmall/subswag/testing"
+)
+
+type UserRatingsRepoTestSuite struct {
+	*tt.IntegrationTest
+}
+
+func TestUserRatingsRepoTestSuite(t *testing.T) {
+	config := tt.GetIntegrationSuiteConfig()
+	tt.RunIntegrationTest(t, config, func(its *tt.IntegrationTest) *UserRatingsRepoTestSuite {
+		return &UserRatingsRepoTestSuite{
+			IntegrationTest: its,
+		}
+	})
+}
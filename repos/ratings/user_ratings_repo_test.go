@@ -0,0 +1,75 @@
+package ratings_test
+
+import (
+	"context"
+
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/rating"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/repos"
+	tt "github.com/coopersmall/subswag/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	ctx          = context.Background()
+	player1Id    = user.UserID(1001)
+	player2Id    = user.UserID(1002)
+	game1Id      = game.GameStateID(1)
+	game2Id      = game.GameStateID(2)
+	userRatings  repos.IUserRatingsRepo
+	matchResults repos.IMatchResultsRepo
+)
+
+// setupRatingsRepos creates both players and two games for results to be
+// recorded against
+func setupRatingsRepos(s *tt.IntegrationTest) {
+	s.Reset()
+	repos, _ := s.GetRepos()
+	userRatings = repos.UserRatingsRepo()
+	matchResults = repos.MatchResultsRepo()
+	for _, userId := range []user.UserID{player1Id, player2Id} {
+		u := user.NewUser()
+		u.ID = userId
+		require.NoError(s.T(), repos.UsersRepo().Create(ctx, u))
+	}
+	for _, gameStateId := range []game.GameStateID{game1Id, game2Id} {
+		require.NoError(s.T(), repos.GameStateRepo().Create(ctx, &game.GameState{
+			ID:       gameStateId,
+			Metadata: domain.NewMetadata(),
+		}))
+	}
+}
+
+func (s *UserRatingsRepoTestSuite) SetupSubTest() {
+	setupRatingsRepos(s.IntegrationTest)
+}
+
+func (s *UserRatingsRepoTestSuite) TestUserRatingsRepo() {
+	s.Run("it starts a user at the default rating only once", func() {
+		err := userRatings.Start(ctx, player1Id)
+		require.NoError(s.T(), err)
+		err = userRatings.Start(ctx, player1Id)
+		require.NoError(s.T(), err)
+
+		userRating, err := userRatings.Get(ctx, player1Id)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), rating.DefaultRating, userRating.Rating)
+		assert.Equal(s.T(), int64(0), userRating.Version)
+		assert.Zero(s.T(), userRating.GamesPlayed)
+	})
+
+	s.Run("it leaves users who have not finished a game off the leaderboard", func() {
+		err := userRatings.Start(ctx, player1Id)
+		require.NoError(s.T(), err)
+
+		top, err := userRatings.Top(ctx, 10, 0)
+		require.NoError(s.T(), err)
+		assert.Empty(s.T(), top)
+		friends, err := userRatings.ForUsers(ctx, []user.UserID{player1Id, player2Id})
+		require.NoError(s.T(), err)
+		assert.Empty(s.T(), friends)
+	})
+}
@@ -2,6 +2,7 @@ package repos
 
 import (
 	"context"
+	"time"
 
 	"github.com/coopersmall/subswag/apm"
 	"github.com/coopersmall/subswag/db"
@@ -10,7 +11,9 @@ import (
 	"github.com/coopersmall/subswag/domain/chatsession"
 	"github.com/coopersmall/subswag/domain/game"
 	"github.com/coopersmall/subswag/domain/integrations"
+	"github.com/coopersmall/subswag/domain/pack"
 	"github.com/coopersmall/subswag/domain/ratelimit"
+	"github.com/coopersmall/subswag/domain/rating"
 	"github.com/coopersmall/subswag/domain/secret"
 	"github.com/coopersmall/subswag/domain/user"
 	apitokensrepo "github.com/coopersmall/subswag/repos/apitokens"
@@ -20,8 +23,13 @@ import (
 	decksrepo "github.com/coopersmall/subswag/repos/decks"
 	gamesstaterepo "github.com/coopersmall/subswag/repos/games"
 	gamestateversionsrepo "github.com/coopersmall/subswag/repos/games"
+	gametimersrepo "github.com/coopersmall/subswag/repos/games"
+	packopeningsrepo "github.com/coopersmall/subswag/repos/packopenings"
+	packtypesrepo "github.com/coopersmall/subswag/repos/packtypes"
 	ratelimitsrepo "github.com/coopersmall/subswag/repos/ratelimits"
+	ratingsrepo "github.com/coopersmall/subswag/repos/ratings"
 	secretsrepo "github.com/coopersmall/subswag/repos/secrets"
+	usercardsrepo "github.com/coopersmall/subswag/repos/usercards"
 	usersrepo "github.com/coopersmall/subswag/repos/users"
 )
 
@@ -33,8 +41,14 @@ type IRepos interface {
 	DecksRepo(userId user.UserID) IDecksRepo
 	GameStateRepo() IGameStateRepo
 	GameStateVersionRepo() IGameStateVersionRepo
+	GameTimersRepo() IGameTimersRepo
+	MatchResultsRepo() IMatchResultsRepo
+	PackOpeningsRepo(userId user.UserID) IPackOpeningsRepo
+	PackTypesRepo() IPackTypesRepo
 	SecretsRepo(userId user.UserID) ISecretsRepo
 	RateLimitsRepo(userId user.UserID) IRateLimitsRepo
+	UserCardsRepo(userId user.UserID) IUserCardsRepo
+	UserRatingsRepo() IUserRatingsRepo
 	UsersRepo() IUsersRepo
 }
 
@@ -46,8 +60,14 @@ type Repos struct {
 	decksRepo            func(userId user.UserID) *decksrepo.DecksRepo
 	gamesStateRepo       func() *gamesstaterepo.GameStateRepo
 	gameStateVersionRepo func() *gamestateversionsrepo.GameStateVersionRepo
+	gameTimersRepo       func() *gametimersrepo.GameTimersRepo
+	matchResultsRepo     func() *ratingsrepo.MatchResultsRepo
+	packOpeningsRepo     func(userId user.UserID) *packopeningsrepo.PackOpeningsRepo
+	packTypesRepo        func() *packtypesrepo.PackTypesRepo
 	secretsRepo          func(userId user.UserID) *secretsrepo.SecretsRepo
 	rateLimitsRepo       func(userId user.UserID) *ratelimitsrepo.RateLimitsRepo
+	userCardsRepo        func(userId user.UserID) *usercardsrepo.UserCardsRepo
+	userRatingsRepo      func() *ratingsrepo.UserRatingsRepo
 	usersRepo            func() *usersrepo.UsersRepo
 }
 
@@ -105,6 +125,20 @@ func GetRepos(env iEnv) IRepos {
 		)
 	}
 
+	gameTimersRepo := func() *gametimersrepo.GameTimersRepo {
+		return gametimersrepo.NewGameTimersRepo(
+			env.GetQuerier(),
+			env.GetTracer("game_timers_repo"),
+		)
+	}
+
+	matchResultsRepo := func() *ratingsrepo.MatchResultsRepo {
+		return ratingsrepo.NewMatchResultsRepo(
+			env.GetQuerier(),
+			env.GetTracer("match_results_repo"),
+		)
+	}
+
 	secretsRepo := func(userId user.UserID) *secretsrepo.SecretsRepo {
 		return NewSecretsRepo(
 			env.GetQuerier(),
@@ -113,6 +147,21 @@ func GetRepos(env iEnv) IRepos {
 		)
 	}
 
+	packOpeningsRepo := func(userId user.UserID) *packopeningsrepo.PackOpeningsRepo {
+		return NewPackOpeningsRepo(
+			env.GetQuerier(),
+			env.GetTracer("pack_openings_repo"),
+			userId,
+		)
+	}
+
+	packTypesRepo := func() *packtypesrepo.PackTypesRepo {
+		return NewPackTypesRepo(
+			env.GetQuerier(),
+			env.GetTracer("pack_types_repo"),
+		)
+	}
+
 	rateLimitsRepo := func(userId user.UserID) *ratelimitsrepo.RateLimitsRepo {
 		return NewRateLimitRepo(
 			env.GetQuerier(),
@@ -121,6 +170,21 @@ func GetRepos(env iEnv) IRepos {
 		)
 	}
 
+	userCardsRepo := func(userId user.UserID) *usercardsrepo.UserCardsRepo {
+		return NewUserCardsRepo(
+			env.GetQuerier(),
+			env.GetTracer("user_cards_repo"),
+			userId,
+		)
+	}
+
+	userRatingsRepo := func() *ratingsrepo.UserRatingsRepo {
+		return ratingsrepo.NewUserRatingsRepo(
+			env.GetQuerier(),
+			env.GetTracer("user_ratings_repo"),
+		)
+	}
+
 	usersRepo := func() *usersrepo.UsersRepo {
 		return NewUserRepo(
 			env.GetQuerier(),
@@ -136,8 +200,14 @@ func GetRepos(env iEnv) IRepos {
 		decksRepo:            decksRepo,
 		gamesStateRepo:       gamesStateRepo,
 		gameStateVersionRepo: gameStateVersionRepo,
+		gameTimersRepo:       gameTimersRepo,
+		matchResultsRepo:     matchResultsRepo,
+		packOpeningsRepo:     packOpeningsRepo,
+		packTypesRepo:        packTypesRepo,
 		secretsRepo:          secretsRepo,
 		rateLimitsRepo:       rateLimitsRepo,
+		userCardsRepo:        userCardsRepo,
+		userRatingsRepo:      userRatingsRepo,
 		usersRepo:            usersRepo,
 	}
 }
@@ -170,6 +240,22 @@ func (r *Repos) GameStateVersionRepo() IGameStateVersionRepo {
 	return r.gameStateVersionRepo()
 }
 
+func (r *Repos) GameTimersRepo() IGameTimersRepo {
+	return r.gameTimersRepo()
+}
+
+func (r *Repos) MatchResultsRepo() IMatchResultsRepo {
+	return r.matchResultsRepo()
+}
+
+func (r *Repos) PackOpeningsRepo(userId user.UserID) IPackOpeningsRepo {
+	return r.packOpeningsRepo(userId)
+}
+
+func (r *Repos) PackTypesRepo() IPackTypesRepo {
+	return r.packTypesRepo()
+}
+
 func (r *Repos) SecretsRepo(userId user.UserID) ISecretsRepo {
 	return r.secretsRepo(userId)
 }
@@ -178,6 +264,14 @@ func (r *Repos) RateLimitsRepo(userId user.UserID) IRateLimitsRepo {
 	return r.rateLimitsRepo(userId)
 }
 
+func (r *Repos) UserCardsRepo(userId user.UserID) IUserCardsRepo {
+	return r.userCardsRepo(userId)
+}
+
+func (r *Repos) UserRatingsRepo() IUserRatingsRepo {
+	return r.userRatingsRepo()
+}
+
 func (r *Repos) UsersRepo() IUsersRepo {
 	return r.usersRepo()
 }
@@ -190,8 +284,14 @@ var (
 	NewDecksRepo            = decksrepo.NewDecksRepo
 	NewGameStateRepo        = gamesstaterepo.NewGameStateRepo
 	NewGameStateVersionRepo = gamestateversionsrepo.NewGameStateVersionRepo
+	NewGameTimersRepo       = gametimersrepo.NewGameTimersRepo
+	NewMatchResultsRepo     = ratingsrepo.NewMatchResultsRepo
+	NewPackOpeningsRepo     = packopeningsrepo.NewPackOpeningsRepo
+	NewPackTypesRepo        = packtypesrepo.NewPackTypesRepo
 	NewSecretsRepo          = secretsrepo.NewSecretsRepo
 	NewRateLimitRepo        = ratelimitsrepo.NewRateLimitsRepo
+	NewUserCardsRepo        = usercardsrepo.NewUserCardsRepo
+	NewUserRatingsRepo      = ratingsrepo.NewUserRatingsRepo
 	NewUserRepo             = usersrepo.NewUsersRepo
 )
 
@@ -234,6 +334,32 @@ type IDecksRepo interface {
 	Delete(ctx context.Context, deckId card.SerializableDeckID) error
 }
 
+type IUserCardsRepo interface {
+	Get(ctx context.Context, userCardId card.UserSerializableCardID) (*card.UserSerializableCard, error)
+	All(ctx context.Context) ([]*card.UserSerializableCard, error)
+	Owned(ctx context.Context) (map[card.SerializableCardID]int, error)
+	Create(ctx context.Context, userCard *card.UserSerializableCard) error
+	CreateMany(ctx context.Context, userCards []*card.UserSerializableCard) (map[card.SerializableCardID]int, error)
+	Revoke(ctx context.Context, cardIds []card.SerializableCardID) (map[card.SerializableCardID]int, error)
+	Update(ctx context.Context, userCard *card.UserSerializableCard) error
+	Delete(ctx context.Context, userCardId card.UserSerializableCardID) error
+}
+
+type IPackTypesRepo interface {
+	Get(ctx context.Context, packTypeId pack.PackTypeID) (*pack.PackType, error)
+	All(ctx context.Context) ([]*pack.PackType, error)
+	Create(ctx context.Context, packType *pack.PackType) error
+	Update(ctx context.Context, packType *pack.PackType) error
+	Delete(ctx context.Context, packTypeId pack.PackTypeID) error
+}
+
+type IPackOpeningsRepo interface {
+	Get(ctx context.Context, openingId pack.PackOpeningID) (*pack.PackOpening, error)
+	All(ctx context.Context) ([]*pack.PackOpening, error)
+	Latest(ctx context.Context, packTypeId pack.PackTypeID) (*pack.PackOpening, error)
+	Record(ctx context.Context, opening *pack.PackOpening) (map[card.SerializableCardID]int, error)
+}
+
 type ISecretsRepo interface {
 	Get(ctx context.Context, secretId secret.SecretID) (*secret.StoredSecret, error)
 	All(ctx context.Context) ([]*secret.StoredSecret, error)
@@ -253,9 +379,14 @@ type IRateLimitsRepo interface {
 type ICardsRepo interface {
 	Get(ctx context.Context, cardId card.SerializableCardID) (card.Card, error)
 	All(ctx context.Context) ([]card.Card, error)
+	GetMany(ctx context.Context, cardIds []card.SerializableCardID) ([]card.Card, error)
+	GetIDsByRarity(ctx context.Context, rarities []card.CardRarity) (map[card.CardRarity][]card.SerializableCardID, error)
 	Create(ctx context.Context, card card.Card) error
 	Update(ctx context.Context, card card.Card) error
 	Delete(ctx context.Context, cardId card.SerializableCardID) error
+	GetVersion(ctx context.Context, cardId card.SerializableCardID, version int) (card.Card, error)
+	CreateVersion(ctx context.Context, card card.Card) error
+	SaveMany(ctx context.Context, cards []card.Card) error
 }
 
 type IGameStateRepo interface {
@@ -269,8 +400,30 @@ type IGameStateRepo interface {
 type IGameStateVersionRepo interface {
 	Get(ctx context.Context, versionId game.GameStateVersionID) (*game.GameStateVersion, error)
 	Create(ctx context.Context, version *game.GameStateVersion) error
-	GetVersionsForGameState(ctx context.Context, gameStateId game.GameStateID) ([]*game.GameStateVersion, error)
+	GetVersionsPage(ctx context.Context, gameStateId game.GameStateID, limit int, offset int) ([]*game.GameStateVersion, error)
+	GetVersionByNumber(ctx context.Context, gameStateId game.GameStateID, number int) (*game.GameStateVersion, error)
 	GetLatestVersion(ctx context.Context, gameStateId game.GameStateID) (*game.GameStateVersion, error)
+	GetLatestVersionBefore(ctx context.Context, gameStateId game.GameStateID, cutoff time.Time) (*game.GameStateVersion, int, error)
+	GetVersionsBetween(ctx context.Context, gameStateId game.GameStateID, since time.Time, cutoff time.Time) ([]*game.GameStateVersion, error)
+}
+
+type IGameTimersRepo interface {
+	Schedule(ctx context.Context, timer *game.RevealTimer) error
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*game.RevealTimer, error)
+	Complete(ctx context.Context, timer *game.RevealTimer) error
+}
+
+type IUserRatingsRepo interface {
+	Get(ctx context.Context, userId user.UserID) (*rating.UserRating, error)
+	Start(ctx context.Context, userId user.UserID) error
+	Top(ctx context.Context, limit int, offset int) ([]*rating.UserRating, error)
+	ForUsers(ctx context.Context, userIds []user.UserID) ([]*rating.UserRating, error)
+}
+
+type IMatchResultsRepo interface {
+	Get(ctx context.Context, gameStateId game.GameStateID) (*rating.MatchResult, error)
+	Record(ctx context.Context, matchResult *rating.MatchResult, ratings [2]*rating.UserRating) error
+	ForUser(ctx context.Context, userId user.UserID, limit int, offset int) ([]*rating.MatchResult, error)
 }
 
 type IIntegrationsRepo interface {
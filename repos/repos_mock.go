@@ -34,6 +34,21 @@ func (m *MockRepos) DecksRepo(userId user.UserID) IDecksRepo {
 	return args.Get(0).(IDecksRepo)
 }
 
+func (m *MockRepos) UserCardsRepo(userId user.UserID) IUserCardsRepo {
+	args := m.Called(userId)
+	return args.Get(0).(IUserCardsRepo)
+}
+
+func (m *MockRepos) PackOpeningsRepo(userId user.UserID) IPackOpeningsRepo {
+	args := m.Called(userId)
+	return args.Get(0).(IPackOpeningsRepo)
+}
+
+func (m *MockRepos) PackTypesRepo() IPackTypesRepo {
+	args := m.Called()
+	return args.Get(0).(IPackTypesRepo)
+}
+
 func (m *MockRepos) SecretsRepo(userId user.UserID) ISecretsRepo {
 	args := m.Called(userId)
 	return args.Get(0).(ISecretsRepo)
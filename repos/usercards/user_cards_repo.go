@@ -0,0 +1,187 @@
+package usercards
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/db"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/user"
+	reposdomain "github.com/coopersmall/subswag/repos/domain"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// UserCardsRepo stores the cards in a user's collection, one row for every
+// copy they own
+type UserCardsRepo struct {
+	*reposdomain.StandardRepo[card.UserSerializableCardID, *card.UserSerializableCard, db.UserCard]
+}
+
+func NewUserCardsRepo(
+	querier db.IQuerier,
+	tracer apm.ITracer,
+	userId user.UserID,
+) *UserCardsRepo {
+	return &UserCardsRepo{
+		StandardRepo: reposdomain.NewStandardRepo[card.UserSerializableCardID, *card.UserSerializableCard, db.UserCard](
+			"user_card",
+			querier,
+			tracer,
+			userId,
+			convertRowToUserCard,
+			convertUserCardToRow,
+			isEmptyUserCard,
+			func(ctx context.Context, iqro db.IStandardQueriesReadOnly, id card.UserSerializableCardID) (db.UserCard, error) {
+				return iqro.GetUserCard(ctx, db.GetUserCardParams{
+					ID:     int64(id),
+					UserID: int64(userId),
+				})
+			},
+			func(ctx context.Context, iqro db.IStandardQueriesReadOnly) ([]db.UserCard, error) {
+				return iqro.GetAllUserCards(ctx, int64(userId))
+			},
+			func(ctx context.Context, iqrw db.IStandardQueriesReadWrite, c db.UserCard) (sql.Result, error) {
+				return iqrw.CreateUserCard(ctx, db.CreateUserCardParams{
+					ID:        c.ID,
+					UserID:    int64(userId),
+					CardID:    c.CardID,
+					CreatedAt: c.CreatedAt,
+					Data:      c.Data,
+				})
+			},
+			func(ctx context.Context, iqrw db.IStandardQueriesReadWrite, c db.UserCard) (sql.Result, error) {
+				return iqrw.UpdateUserCard(ctx, db.UpdateUserCardParams{
+					ID:        c.ID,
+					UserID:    int64(userId),
+					UpdatedAt: c.UpdatedAt,
+					Data:      c.Data,
+				})
+			},
+			func(ctx context.Context, iqrw db.IStandardQueriesReadWrite, id card.UserSerializableCardID) (sql.Result, error) {
+				return iqrw.DeleteUserCard(ctx, db.DeleteUserCardParams{
+					ID:     int64(id),
+					UserID: int64(userId),
+				})
+			},
+		),
+	}
+}
+
+// Owned counts the copies of each card in the collection
+func (r *UserCardsRepo) Owned(ctx context.Context) (map[card.SerializableCardID]int, error) {
+	cards, err := r.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[card.SerializableCardID]int, len(cards))
+	for _, c := range cards {
+		owned[c.CardID]++
+	}
+	return owned, nil
+}
+
+// CreateMany adds every copy in one statement, so either all of them are
+// saved or none are. It returns how many copies of each card the user owns
+// afterwards.
+func (r *UserCardsRepo) CreateMany(ctx context.Context, cards []*card.UserSerializableCard) (map[card.SerializableCardID]int, error) {
+	params := db.CreateUserCardsParams{
+		IDs:       make([]int64, len(cards)),
+		CardIDs:   make([]int64, len(cards)),
+		CreatedAt: time.Now().UTC(),
+		Data:      make([]string, len(cards)),
+	}
+	for i, c := range cards {
+		row, err := convertUserCardToRow(c)
+		if err != nil {
+			return nil, utils.NewInternalError("failed to convert to row", err)
+		}
+		params.IDs[i] = row.ID
+		params.CardIDs[i] = row.CardID
+		params.Data[i] = string(row.Data)
+	}
+	owned := map[card.SerializableCardID]int{}
+	err := r.StandardRepo.Execute(ctx, func(ctx context.Context, q db.IStandardQueriesReadWrite, userId user.UserID) error {
+		params.UserID = int64(userId)
+		rows, err := q.CreateUserCards(ctx, params)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			owned[card.SerializableCardID(row.CardID)] = int(row.Owned)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return owned, nil
+}
+
+// Revoke removes a copy of each card for every time it is listed, newest
+// copies first, and returns how many copies of each card the user owns
+// afterwards. The copies are locked, counted and removed in one statement:
+// if the user does not own enough copies of any card, nothing is removed.
+func (r *UserCardsRepo) Revoke(ctx context.Context, cardIds []card.SerializableCardID) (map[card.SerializableCardID]int, error) {
+	params := db.RevokeUserCardsParams{
+		CardIDs: make([]int64, len(cardIds)),
+	}
+	for i, cardId := range cardIds {
+		params.CardIDs[i] = int64(cardId)
+	}
+	owned := map[card.SerializableCardID]int{}
+	var short *db.RevokeUserCardsRow
+	err := r.StandardRepo.Execute(ctx, func(ctx context.Context, q db.IStandardQueriesReadWrite, userId user.UserID) error {
+		params.UserID = int64(userId)
+		rows, err := q.RevokeUserCards(ctx, params)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if row.Owned < row.Copies && short == nil {
+				short = &row
+			}
+			owned[card.SerializableCardID(row.CardID)] = int(row.Owned)
+			if row.Revoked {
+				owned[card.SerializableCardID(row.CardID)] -= int(row.Copies)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if short != nil {
+		return nil, utils.NewInvalidArgumentError(fmt.Sprintf("cannot revoke %d copies of card %d, the collection has %d", short.Copies, short.CardID, short.Owned))
+	}
+	return owned, nil
+}
+
+func isEmptyUserCard(c *card.UserSerializableCard) bool {
+	return c == nil || c.ID == 0
+}
+
+func convertRowToUserCard(result db.UserCard) (*card.UserSerializableCard, error) {
+	var c card.UserSerializableCard
+	err := utils.Unmarshal(result.Data, &c)
+	return &c, err
+}
+
+func convertUserCardToRow(
+	c *card.UserSerializableCard,
+) (db.UserCard, error) {
+	data, err := utils.Marshal(c)
+	return db.UserCard{
+		ID:        int64(c.ID),
+		UserID:    int64(c.UserID),
+		CardID:    int64(c.CardID),
+		CreatedAt: c.Metadata.CreatedAt,
+		UpdatedAt: sql.NullTime{
+			Time:  c.Metadata.UpdatedAt,
+			Valid: c.Metadata.UpdatedAt != time.Time{},
+		},
+		Data: data,
+	}, err
+}
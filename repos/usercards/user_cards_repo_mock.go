@@ -0,0 +1,67 @@
+package usercards
+
+import (
+	"context"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserCardsRepo struct {
+	mock.Mock
+}
+
+func (m *MockUserCardsRepo) Get(ctx context.Context, id card.UserSerializableCardID) (*card.UserSerializableCard, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*card.UserSerializableCard), args.Error(1)
+}
+
+func (m *MockUserCardsRepo) All(ctx context.Context) ([]*card.UserSerializableCard, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*card.UserSerializableCard), args.Error(1)
+}
+
+func (m *MockUserCardsRepo) Owned(ctx context.Context) (map[card.SerializableCardID]int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[card.SerializableCardID]int), args.Error(1)
+}
+
+func (m *MockUserCardsRepo) Create(ctx context.Context, c *card.UserSerializableCard) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockUserCardsRepo) CreateMany(ctx context.Context, cards []*card.UserSerializableCard) (map[card.SerializableCardID]int, error) {
+	args := m.Called(ctx, cards)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[card.SerializableCardID]int), args.Error(1)
+}
+
+func (m *MockUserCardsRepo) Revoke(ctx context.Context, cardIds []card.SerializableCardID) (map[card.SerializableCardID]int, error) {
+	args := m.Called(ctx, cardIds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[card.SerializableCardID]int), args.Error(1)
+}
+
+func (m *MockUserCardsRepo) Update(ctx context.Context, c *card.UserSerializableCard) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockUserCardsRepo) Delete(ctx context.Context, id card.UserSerializableCardID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,20 @@
+package usercards_test
+
+import (
+	"testing"
+
+	tt "github.com/coopersmall/subswag/testing"
+)
+
+type UserCardsRepoTestSuite struct {
+	*tt.IntegrationTest
+}
+
+func TestUserCardsRepoTestSuite(t *testing.T) {
+	config := tt.GetIntegrationSuiteConfig()
+	tt.RunIntegrationTest(t, config, func(its *tt.IntegrationTest) *UserCardsRepoTestSuite {
+		return &UserCardsRepoTestSuite{
+			IntegrationTest: its,
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package usercards_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/repos"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ctx           = context.Background()
+	userId        = user.UserID(1)
+	userCardId    = card.UserSerializableCardID(1)
+	validUser     = user.NewUser()
+	validUserCard = &card.UserSerializableCard{
+		ID: userCardId,
+		UserSerializableCardData: card.UserSerializableCardData{
+			UserID: userId,
+			CardID: 7,
+		},
+		Metadata: &domain.Metadata{
+			CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			UpdatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	repo repos.IUserCardsRepo
+)
+
+func (s *UserCardsRepoTestSuite) SetupSubTest() {
+	s.Reset()
+	repos, _ := s.GetRepos()
+	repo = repos.UserCardsRepo(userId)
+	validUser.ID = userId
+	err := repos.UsersRepo().Create(ctx, validUser)
+	assert.NoError(s.T(), err)
+}
+
+func (s *UserCardsRepoTestSuite) TestUserCardsRepo() {
+	s.Run("it works", func() {
+		err := repo.Create(ctx, validUserCard)
+		assert.NoError(s.T(), err)
+
+		result, err := repo.Get(ctx, userCardId)
+		assert.NoError(s.T(), err)
+		assert.NotNil(s.T(), result)
+		assert.Equal(s.T(), validUserCard.UserSerializableCardData, result.UserSerializableCardData)
+
+		copy2 := &card.UserSerializableCard{}
+		utils.DeepClone(validUserCard, copy2)
+		copy2.ID = 2
+
+		err = repo.Create(ctx, copy2)
+		assert.NoError(s.T(), err)
+
+		results, err := repo.All(ctx)
+		assert.NoError(s.T(), err)
+		assert.Len(s.T(), results, 2)
+
+		owned, err := repo.Owned(ctx)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), map[card.SerializableCardID]int{7: 2}, owned)
+
+		err = repo.Delete(ctx, userCardId)
+		assert.NoError(s.T(), err)
+
+		result, err = repo.Get(ctx, userCardId)
+		assert.Error(s.T(), err)
+		assert.True(s.T(), errors.Is(err, sql.ErrNoRows))
+		assert.Nil(s.T(), result)
+	})
+
+	s.Run("it creates and revokes many copies at once", func() {
+		copies := []*card.UserSerializableCard{
+			card.NewUserSerializableCard(card.UserSerializableCardData{UserID: userId, CardID: 7}),
+			card.NewUserSerializableCard(card.UserSerializableCardData{UserID: userId, CardID: 7}),
+			card.NewUserSerializableCard(card.UserSerializableCardData{UserID: userId, CardID: 9}),
+		}
+		owned, err := repo.CreateMany(ctx, copies[:2])
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), map[card.SerializableCardID]int{7: 2}, owned)
+
+		owned, err = repo.CreateMany(ctx, copies[2:])
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), map[card.SerializableCardID]int{9: 1}, owned)
+
+		owned, err = repo.Owned(ctx)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), map[card.SerializableCardID]int{7: 2, 9: 1}, owned)
+
+		owned, err = repo.Revoke(ctx, []card.SerializableCardID{7, 9})
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), map[card.SerializableCardID]int{7: 1, 9: 0}, owned)
+
+		owned, err = repo.Owned(ctx)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), map[card.SerializableCardID]int{7: 1}, owned)
+	})
+
+	s.Run("it revokes nothing when any card is short of copies", func() {
+		copies := []*card.UserSerializableCard{
+			card.NewUserSerializableCard(card.UserSerializableCardData{UserID: userId, CardID: 7}),
+			card.NewUserSerializableCard(card.UserSerializableCardData{UserID: userId, CardID: 7}),
+			card.NewUserSerializableCard(card.UserSerializableCardData{UserID: userId, CardID: 9}),
+		}
+		_, err := repo.CreateMany(ctx, copies)
+		assert.NoError(s.T(), err)
+
+		_, err = repo.Revoke(ctx, []card.SerializableCardID{7, 9, 9})
+		assert.Error(s.T(), err)
+		assert.True(s.T(), utils.IsInvalidArgumentError(err))
+
+		owned, err := repo.Owned(ctx)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), map[card.SerializableCardID]int{7: 2, 9: 1}, owned)
+	})
+
+	s.Run("it only lists the user's own cards", func() {
+		err := repo.Create(ctx, validUserCard)
+		assert.NoError(s.T(), err)
+
+		repos, _ := s.GetRepos()
+		otherUser := user.NewUser()
+		otherUser.ID = 2
+		err = repos.UsersRepo().Create(ctx, otherUser)
+		assert.NoError(s.T(), err)
+
+		results, err := repos.UserCardsRepo(otherUser.ID).All(ctx)
+		assert.NoError(s.T(), err)
+		assert.Empty(s.T(), results)
+	})
+
+	s.Run("it deletes cards when user is deleted", func() {
+		err := repo.Create(ctx, validUserCard)
+		assert.NoError(s.T(), err)
+
+		repos, _ := s.GetRepos()
+		err = repos.UsersRepo().Delete(ctx, userId)
+		assert.NoError(s.T(), err)
+
+		result, err := repo.Get(ctx, userCardId)
+		assert.Error(s.T(), err)
+		assert.Nil(s.T(), result)
+	})
+}
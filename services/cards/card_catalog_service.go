@@ -0,0 +1,202 @@
+package cards
+
+import (
+	"context"
+	"time"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/booleanexpression"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/repos"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// CardCatalogService manages the shared catalog of cards. Every change to a
+// card is saved as a new version; games pin the versions of their cards when
+// they start, so editing the catalog never changes a game in progress.
+type CardCatalogService struct {
+	logger       utils.ILogger
+	tracer       apm.ITracer
+	cardsRepo    repos.ICardsRepo
+	effectSchema *booleanexpression.Schema
+}
+
+func NewCardCatalogService(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	cardsRepo repos.ICardsRepo,
+) *CardCatalogService {
+	return &CardCatalogService{
+		logger:       logger,
+		tracer:       tracer,
+		cardsRepo:    cardsRepo,
+		effectSchema: game.EffectSnapshotSchema(),
+	}
+}
+
+func (s *CardCatalogService) GetCard(ctx context.Context, cardId card.SerializableCardID) (card.Card, error) {
+	return s.cardsRepo.Get(ctx, cardId)
+}
+
+func (s *CardCatalogService) GetAllCards(ctx context.Context) ([]card.Card, error) {
+	return s.cardsRepo.All(ctx)
+}
+
+// GetCardVersion returns the card as it was at an earlier version
+func (s *CardCatalogService) GetCardVersion(
+	ctx context.Context,
+	cardId card.SerializableCardID,
+	version int,
+) (card.Card, error) {
+	return s.cardsRepo.GetVersion(ctx, cardId, version)
+}
+
+// CreateCard adds a card to the catalog as its first version
+func (s *CardCatalogService) CreateCard(ctx context.Context, c card.Card) (card.Card, error) {
+	if err := s.validate(ctx, c); err != nil {
+		return nil, err
+	}
+	if err := s.create(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UpdateCard saves the card as its next version
+func (s *CardCatalogService) UpdateCard(ctx context.Context, c card.Card) (card.Card, error) {
+	if err := s.validate(ctx, c); err != nil {
+		return nil, err
+	}
+	current, err := s.cardsRepo.Get(ctx, c.GetID())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.update(ctx, current, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DeleteCard removes the card from the catalog. Its versions are kept for
+// the games that are still playing it.
+func (s *CardCatalogService) DeleteCard(ctx context.Context, cardId card.SerializableCardID) error {
+	return s.cardsRepo.Delete(ctx, cardId)
+}
+
+// ImportCardSet validates every card in the set and works out which cards it
+// creates and changes. Unless dryRun is set the changes are then saved in
+// one statement, so either the whole set is imported or none of it is; a set
+// with any invalid card is rejected as a whole.
+func (s *CardCatalogService) ImportCardSet(
+	ctx context.Context,
+	set *card.CardSet,
+	dryRun bool,
+) (*card.CardSetImport, error) {
+	var (
+		result *card.CardSetImport
+		err    error
+	)
+	s.tracer.Trace(ctx, "import-card-set", func(ctx context.Context, span apm.ISpan) error {
+		span.SetAttribute("cards", len(set.Cards))
+		var catalog []card.Card
+		catalog, err = s.cardsRepo.All(ctx)
+		if err != nil {
+			return err
+		}
+		result, err = card.DiffCardSet(set, catalog, s.effectSchema)
+		if err != nil {
+			return err
+		}
+		result.DryRun = dryRun
+		if dryRun {
+			return nil
+		}
+		if len(result.Invalid) > 0 {
+			err = utils.NewInvalidArgumentError("card set has invalid cards, run a dry run to see them")
+			return err
+		}
+
+		existing := make(map[card.SerializableCardID]card.Card, len(catalog))
+		for _, c := range catalog {
+			existing[c.GetID()] = c
+		}
+		incoming := make(map[card.SerializableCardID]card.Card, len(set.Cards))
+		for _, c := range set.Cards {
+			incoming[c.GetID()] = c
+		}
+		saved := make([]card.Card, 0, len(result.Created)+len(result.Updated))
+		for _, cardId := range result.Created {
+			c := incoming[cardId]
+			setFirstVersion(c)
+			saved = append(saved, c)
+		}
+		for _, update := range result.Updated {
+			c := incoming[update.CardID]
+			setNextVersion(existing[update.CardID], c)
+			saved = append(saved, c)
+		}
+		if err = s.cardsRepo.SaveMany(ctx, saved); err != nil {
+			return err
+		}
+		s.logger.Info(ctx, "imported card set", map[string]any{
+			"name":    set.Name,
+			"created": len(result.Created),
+			"updated": len(result.Updated),
+		})
+		return nil
+	})
+	return result, err
+}
+
+// validate rejects invalid cards and cards with effect lint errors, and logs
+// the lint warnings
+func (s *CardCatalogService) validate(ctx context.Context, c card.Card) error {
+	if err := card.ValidateCard(c); err != nil {
+		return err
+	}
+	if err := card.ValidateCardEffects(c, s.effectSchema); err != nil {
+		return err
+	}
+	for _, problem := range card.LintCard(c, s.effectSchema) {
+		s.logger.Warn(ctx, "card effect lint warning", map[string]any{
+			"card_id": problem.CardID,
+			"effect":  problem.Effect,
+			"message": problem.Message,
+		})
+	}
+	return nil
+}
+
+func (s *CardCatalogService) create(ctx context.Context, c card.Card) error {
+	setFirstVersion(c)
+	if err := s.cardsRepo.CreateVersion(ctx, c); err != nil {
+		return err
+	}
+	return s.cardsRepo.Create(ctx, c)
+}
+
+// update saves c as the version after current. The version is recorded
+// before the card so that a game never pins a version that was not saved.
+func (s *CardCatalogService) update(ctx context.Context, current card.Card, c card.Card) error {
+	setNextVersion(current, c)
+	if err := s.cardsRepo.CreateVersion(ctx, c); err != nil {
+		return err
+	}
+	return s.cardsRepo.Update(ctx, c)
+}
+
+func setFirstVersion(c card.Card) {
+	c.SetVersion(1, domain.NewMetadata())
+}
+
+// setNextVersion makes c the version after current, keeping when the card
+// was first created
+func setNextVersion(current card.Card, c card.Card) {
+	metadata := &domain.Metadata{UpdatedAt: time.Now().UTC()}
+	if current.GetMetadata() != nil {
+		metadata.CreatedAt = current.GetMetadata().CreatedAt
+	}
+	c.SetVersion(current.GetVersion()+1, metadata)
+}
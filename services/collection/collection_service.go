@@ -0,0 +1,212 @@
+package collection
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/repos"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// MaxCardsPerChange is the most copies a single grant or revoke can touch
+const MaxCardsPerChange = 100
+
+// CollectionService manages the cards a user owns. Every copy is stored on
+// its own, so owning three of a card is three rows, and every change is
+// published to the collections stream.
+type CollectionService struct {
+	logger        utils.ILogger
+	tracer        apm.ITracer
+	userId        user.UserID
+	userCardsRepo repos.IUserCardsRepo
+	cardsRepo     repos.ICardsRepo
+	decksRepo     repos.IDecksRepo
+	publisher     iCollectionEventsPublisher
+}
+
+func NewCollectionService(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	userId user.UserID,
+	userCardsRepo repos.IUserCardsRepo,
+	cardsRepo repos.ICardsRepo,
+	decksRepo repos.IDecksRepo,
+	publisher iCollectionEventsPublisher,
+) *CollectionService {
+	return &CollectionService{
+		logger:        logger,
+		tracer:        tracer,
+		userId:        userId,
+		userCardsRepo: userCardsRepo,
+		cardsRepo:     cardsRepo,
+		decksRepo:     decksRepo,
+		publisher:     publisher,
+	}
+}
+
+// GetCollection returns the cards the user owns that pass the filter, with
+// how many copies of each, in card ID order
+func (s *CollectionService) GetCollection(ctx context.Context, filter card.CollectionFilter) ([]*card.CollectionCard, error) {
+	owned, err := s.userCardsRepo.Owned(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(owned) == 0 {
+		return []*card.CollectionCard{}, nil
+	}
+	cards, err := s.cardsRepo.GetMany(ctx, slices.Collect(maps.Keys(owned)))
+	if err != nil {
+		return nil, err
+	}
+	collection := []*card.CollectionCard{}
+	for _, c := range cards {
+		copies := owned[c.GetID()]
+		if copies > 0 && filter.Matches(c, copies) {
+			collection = append(collection, card.NewCollectionCard(c, copies))
+		}
+	}
+	slices.SortFunc(collection, func(a, b *card.CollectionCard) int {
+		return cmp.Compare(a.Card.GetID(), b.Card.GetID())
+	})
+	return collection, nil
+}
+
+// GetCopies returns how many copies of each card the user owns
+func (s *CollectionService) GetCopies(ctx context.Context) (map[card.SerializableCardID]int, error) {
+	return s.userCardsRepo.Owned(ctx)
+}
+
+// GrantCards adds a copy to the collection for every card ID, so a card
+// listed twice is granted twice. Every card must be in the catalog; either
+// all the copies are added or none are.
+func (s *CollectionService) GrantCards(
+	ctx context.Context,
+	cardIds []card.SerializableCardID,
+	source card.CardSource,
+) ([]*card.CollectionEvent, error) {
+	var (
+		events []*card.CollectionEvent
+		err    error
+	)
+	s.tracer.Trace(ctx, "grant-cards", func(ctx context.Context, span apm.ISpan) error {
+		span.SetAttribute("cards", len(cardIds))
+		if err = validateChange(cardIds); err != nil {
+			return err
+		}
+		order, _ := card.CountCopies(cardIds)
+		var found []card.Card
+		found, err = s.cardsRepo.GetMany(ctx, order)
+		if err != nil {
+			return err
+		}
+		catalog := make(map[card.SerializableCardID]bool, len(found))
+		for _, c := range found {
+			catalog[c.GetID()] = true
+		}
+		for _, cardId := range order {
+			if !catalog[cardId] {
+				err = utils.NewInvalidArgumentError(fmt.Sprintf("card %d is not in the catalog", cardId))
+				return err
+			}
+		}
+
+		userCards := make([]*card.UserSerializableCard, len(cardIds))
+		for i, cardId := range cardIds {
+			userCards[i] = card.NewUserSerializableCard(card.UserSerializableCardData{
+				UserID: s.userId,
+				CardID: cardId,
+				Source: source,
+			})
+		}
+		var owned map[card.SerializableCardID]int
+		owned, err = s.userCardsRepo.CreateMany(ctx, userCards)
+		if err != nil {
+			return err
+		}
+
+		events = card.NewGrantEvents(s.userId, cardIds, owned, source)
+		s.publish(ctx, events...)
+		return nil
+	})
+	return events, err
+}
+
+// RevokeCards removes a copy from the collection for every card ID, newest
+// copies first. The user must own enough copies of every card; otherwise
+// nothing is removed.
+func (s *CollectionService) RevokeCards(
+	ctx context.Context,
+	cardIds []card.SerializableCardID,
+) ([]*card.CollectionEvent, error) {
+	var (
+		events []*card.CollectionEvent
+		err    error
+	)
+	s.tracer.Trace(ctx, "revoke-cards", func(ctx context.Context, span apm.ISpan) error {
+		span.SetAttribute("cards", len(cardIds))
+		if err = validateChange(cardIds); err != nil {
+			return err
+		}
+		var owned map[card.SerializableCardID]int
+		owned, err = s.userCardsRepo.Revoke(ctx, cardIds)
+		if err != nil {
+			return err
+		}
+
+		order, copies := card.CountCopies(cardIds)
+		for _, cardId := range order {
+			events = append(events, &card.CollectionEvent{
+				UserID: s.userId,
+				Type:   card.CollectionEventCardsRevoked,
+				CardID: cardId,
+				Copies: copies[cardId],
+				Owned:  owned[cardId],
+			})
+		}
+		s.publish(ctx, events...)
+		return nil
+	})
+	return events, err
+}
+
+// MissingCards lists the cards in one of the user's decks that they do not
+// own enough copies of
+func (s *CollectionService) MissingCards(ctx context.Context, deckId card.SerializableDeckID) ([]card.MissingCard, error) {
+	deck, err := s.decksRepo.Get(ctx, deckId)
+	if err != nil {
+		return nil, err
+	}
+	owned, err := s.userCardsRepo.Owned(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return card.MissingCards(deck, owned), nil
+}
+
+// publish pushes events to the collections stream. The change has already
+// been saved, so failures are logged rather than returned.
+func (s *CollectionService) publish(ctx context.Context, events ...*card.CollectionEvent) {
+	if err := s.publisher.PublishEvents(ctx, events...); err != nil {
+		s.logger.Error(ctx, "failed to publish collection events", err, nil)
+	}
+}
+
+func validateChange(cardIds []card.SerializableCardID) error {
+	if len(cardIds) == 0 {
+		return utils.NewInvalidArgumentError("no cards given")
+	}
+	if len(cardIds) > MaxCardsPerChange {
+		return utils.NewInvalidArgumentError(fmt.Sprintf("at most %d cards can be changed at once", MaxCardsPerChange))
+	}
+	return nil
+}
+
+type iCollectionEventsPublisher interface {
+	PublishEvents(ctx context.Context, events ...*card.CollectionEvent) error
+}
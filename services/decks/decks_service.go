@@ -2,25 +2,38 @@ package decks
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/coopersmall/subswag/domain"
 	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/user"
 	"github.com/coopersmall/subswag/repos"
 	"github.com/coopersmall/subswag/utils"
 )
 
 type DecksService struct {
-	logger    utils.ILogger
-	decksRepo repos.IDecksRepo
+	logger     utils.ILogger
+	userId     user.UserID
+	decksRepo  repos.IDecksRepo
+	cardsRepo  repos.ICardsRepo
+	collection iCardCollection
 }
 
 func NewDecksService(
 	logger utils.ILogger,
+	userId user.UserID,
 	decksRepo repos.IDecksRepo,
+	cardsRepo repos.ICardsRepo,
+	collection iCardCollection,
 ) *DecksService {
 	return &DecksService{
-		logger:    logger,
-		decksRepo: decksRepo,
+		logger:     logger,
+		userId:     userId,
+		decksRepo:  decksRepo,
+		cardsRepo:  cardsRepo,
+		collection: collection,
 	}
 }
 
@@ -28,34 +41,195 @@ func (s *DecksService) GetDeck(ctx context.Context, deckId card.SerializableDeck
 	return s.decksRepo.Get(ctx, deckId)
 }
 
+// GetPlayableDeck returns the deck only if it is legal in its format
+func (s *DecksService) GetPlayableDeck(ctx context.Context, deckId card.SerializableDeckID) (*card.SerializableDeck, error) {
+	deck, err := s.decksRepo.Get(ctx, deckId)
+	if err != nil {
+		return nil, err
+	}
+	legality, err := s.legality(ctx, deck)
+	if err != nil {
+		return nil, err
+	}
+	if err := legality.Err(); err != nil {
+		return nil, err
+	}
+	return deck, nil
+}
+
 func (s *DecksService) GetAllDecks(ctx context.Context) ([]*card.SerializableDeck, error) {
 	return s.decksRepo.All(ctx)
 }
 
+// ValidateDeck checks the deck against its format and returns every
+// violation. An illegal deck is not an error.
+func (s *DecksService) ValidateDeck(ctx context.Context, deckId card.SerializableDeckID) (*card.DeckLegality, error) {
+	deck, err := s.decksRepo.Get(ctx, deckId)
+	if err != nil {
+		return nil, err
+	}
+	return s.legality(ctx, deck)
+}
+
+// CreateDeck saves a new deck for the user. Its record starts empty whatever
+// data says.
 func (s *DecksService) CreateDeck(
 	ctx context.Context,
 	data card.SerializableDeckData,
-) error {
+) (*card.SerializableDeck, error) {
+	data.UserID = s.userId
+	data.GamesPlayed, data.GamesWon = 0, 0
 	deck := card.NewDeck(card.NewSerializableDeckID(), data)
-	if err := domain.Validate(deck); err != nil {
-		return err
+	if err := s.validate(ctx, deck); err != nil {
+		return nil, err
 	}
-	return s.decksRepo.Create(ctx, deck)
+	if err := s.decksRepo.Create(ctx, deck); err != nil {
+		return nil, err
+	}
+	return deck, nil
 }
 
 func (s *DecksService) UpdateDeck(
 	ctx context.Context,
 	deck *card.SerializableDeck,
 ) error {
-	if err := domain.Validate(deck); err != nil {
+	deck.UserID = s.userId
+	if deck.Metadata != nil {
+		deck.Metadata.UpdatedAt = time.Now().UTC()
+	}
+	if err := s.validate(ctx, deck); err != nil {
 		return err
 	}
 	return s.decksRepo.Update(ctx, deck)
 }
 
+// FavoriteDeck marks or unmarks the deck as a favourite. A deck that is no
+// longer legal can still be favourited.
+func (s *DecksService) FavoriteDeck(
+	ctx context.Context,
+	deckId card.SerializableDeckID,
+	favorited bool,
+) (*card.SerializableDeck, error) {
+	deck, err := s.decksRepo.Get(ctx, deckId)
+	if err != nil {
+		return nil, err
+	}
+	deck.Favorited = favorited
+	deck.Metadata.UpdatedAt = time.Now().UTC()
+	if err := s.decksRepo.Update(ctx, deck); err != nil {
+		return nil, err
+	}
+	return deck, nil
+}
+
+// CloneDeck saves a copy of the deck's cards and format as a new deck, named
+// "Copy of ..." when name is empty
+func (s *DecksService) CloneDeck(
+	ctx context.Context,
+	deckId card.SerializableDeckID,
+	name string,
+) (*card.SerializableDeck, error) {
+	deck, err := s.decksRepo.Get(ctx, deckId)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = fmt.Sprintf("Copy of %s", deck.Name)
+	}
+	return s.CreateDeck(ctx, card.SerializableDeckData{
+		CardIDs: slices.Clone(deck.CardIDs),
+		Name:    name,
+		Format:  deck.Format,
+	})
+}
+
+// ExportDeck returns the deck's shareable code
+func (s *DecksService) ExportDeck(ctx context.Context, deckId card.SerializableDeckID) (string, error) {
+	deck, err := s.decksRepo.Get(ctx, deckId)
+	if err != nil {
+		return "", err
+	}
+	return card.NewDeckCode(deck).String(), nil
+}
+
+// ImportDeck saves the deck in a code exported by any player as a new deck
+// for the user. It must be legal in its format, as when the deck is saved.
+func (s *DecksService) ImportDeck(
+	ctx context.Context,
+	code string,
+	name string,
+) (*card.SerializableDeck, error) {
+	deckCode, err := card.ParseDeckCode(code)
+	if err != nil {
+		return nil, err
+	}
+	deck := card.NewDeck(card.NewSerializableDeckID(), card.SerializableDeckData{
+		UserID:  s.userId,
+		CardIDs: deckCode.CardIDs,
+		Name:    name,
+		Format:  deckCode.Format,
+	})
+	if err := s.validate(ctx, deck); err != nil {
+		return nil, err
+	}
+	if err := s.decksRepo.Create(ctx, deck); err != nil {
+		return nil, err
+	}
+	return deck, nil
+}
+
 func (s *DecksService) DeleteDeck(
 	ctx context.Context,
 	deckId card.SerializableDeckID,
 ) error {
 	return s.decksRepo.Delete(ctx, deckId)
 }
+
+// validate rejects decks that are malformed or not legal in their format
+func (s *DecksService) validate(ctx context.Context, deck *card.SerializableDeck) error {
+	if err := domain.Validate(deck); err != nil {
+		return err
+	}
+	legality, err := s.legality(ctx, deck)
+	if err != nil {
+		return err
+	}
+	return legality.Err()
+}
+
+func (s *DecksService) legality(ctx context.Context, deck *card.SerializableDeck) (*card.DeckLegality, error) {
+	format, err := card.NewDeckFormat(deck.Format)
+	if err != nil {
+		return nil, err
+	}
+	return s.legalityIn(ctx, deck, format)
+}
+
+func (s *DecksService) legalityIn(
+	ctx context.Context,
+	deck *card.SerializableDeck,
+	format card.DeckFormat,
+) (*card.DeckLegality, error) {
+	order, _ := card.CountCopies(deck.CardIDs)
+	cards, err := s.cardsRepo.GetMany(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	catalog := make(map[card.SerializableCardID]card.Card, len(cards))
+	for _, c := range cards {
+		catalog[c.GetID()] = c
+	}
+	var owned map[card.SerializableCardID]int
+	if format.RequireOwnership {
+		owned, err = s.collection.GetCopies(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return format.ValidateDeck(deck, catalog, owned), nil
+}
+
+// iCardCollection counts the copies of each card the user owns
+type iCardCollection interface {
+	GetCopies(ctx context.Context) (map[card.SerializableCardID]int, error)
+}
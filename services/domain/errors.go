@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/coopersmall/subswag/utils"
+)
+
+// IsNotFound reports whether err means the item does not exist. Repos return
+// sql.ErrNoRows for missing rows rather than a not found error.
+func IsNotFound(err error) bool {
+	return errors.Is(err, sql.ErrNoRows) || utils.IsNotFoundError(err)
+}
@@ -0,0 +1,72 @@
+package game
+
+import (
+	"context"
+
+	"github.com/coopersmall/subswag/domain/bot"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/user"
+	servicesdomain "github.com/coopersmall/subswag/services/domain"
+)
+
+// playBots lets any bot in the game make its move for the phase the game has
+// just entered. The moves are applied to the runner, so they are committed
+// with the phase change, and the events for them are returned.
+func (s *GameRunnerService) playBots(ctx context.Context, runner *GameRunnerContext) ([]*game.GameEvent, error) {
+	events := make([]*game.GameEvent, 0)
+	var values map[card.SerializableCardID]int
+	for i := range runner.GetGameStateData().Players {
+		data := runner.GetGameStateData()
+		difficulty, ok := bot.DifficultyOf(data.Players[i].User)
+		if !ok {
+			continue
+		}
+		if values == nil {
+			cards, err := s.loadCards(ctx, data)
+			if err != nil {
+				return nil, err
+			}
+			values = make(map[card.SerializableCardID]int, len(cards))
+			for cardId, c := range cards {
+				values[cardId] = card.GetCardValue(c)
+			}
+		}
+
+		cmd, ok := bot.NewBot(difficulty, values, runner.rand()).NextCommand(data, i)
+		if !ok {
+			continue
+		}
+		if rejection := game.ValidateCommand(data, i, cmd); rejection != nil {
+			s.logger.Warn(ctx, "bot chose an illegal move", map[string]any{
+				"command": cmd.GetType(),
+				"reason":  rejection.Reason,
+			})
+			continue
+		}
+		if !applyCommand(ctx, runner.PlayerRunnerContext(i), cmd) {
+			continue
+		}
+		events = append(events, game.NewGameEvent(runner.gameState, game.GameEventActionApplied, game.ActionAppliedEventData{
+			User:    data.Players[i].User,
+			Command: cmd.GetType(),
+		}))
+	}
+	return events, nil
+}
+
+// getPlayer returns the user for a seat. Bot users are created the first time
+// a bot plays.
+func (s *GameRunnerService) getPlayer(ctx context.Context, userId user.UserID) (*user.User, error) {
+	player, err := s.usersService.GetUser(ctx, userId)
+	difficulty, isBot := bot.DifficultyOf(userId)
+	if err == nil || !isBot || !servicesdomain.IsNotFound(err) {
+		return player, err
+	}
+	botUser := bot.NewBotUser(difficulty)
+	if player, err = s.usersService.CreateUserWithId(ctx, botUser.ID, botUser.UserData); err != nil {
+		// Another game may have created the bot first
+		return s.usersService.GetUser(ctx, userId)
+	}
+	return player, nil
+}
@@ -0,0 +1,233 @@
+package game
+
+import (
+	"context"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/utils"
+)
+
+type iEffectResolver interface {
+	// Resolve runs onUse and then triggers the card's effects for effectType
+	Resolve(runner *GameRunnerContext, playerIndex int, cardId card.SerializableCardID, effectType card.CardEffectType, onUse func())
+	// ResolveStack applies the deferred effects waiting on the active stack
+	ResolveStack(runner *GameRunnerContext)
+}
+
+// EffectResolver applies card effects while a game is being run. Quick
+// effect attributes are applied as soon as their card is triggered, the rest
+// wait on the active effects stack until ResolveStack is called at the end of
+// the phase. The resolver is scoped to a single request.
+type EffectResolver struct {
+	ctx    context.Context
+	logger utils.ILogger
+	cards  map[card.SerializableCardID]card.Card
+}
+
+func NewEffectResolver(
+	ctx context.Context,
+	logger utils.ILogger,
+	cards map[card.SerializableCardID]card.Card,
+) *EffectResolver {
+	return &EffectResolver{
+		ctx:    ctx,
+		logger: logger,
+		cards:  cards,
+	}
+}
+
+func (r *EffectResolver) Resolve(
+	runner *GameRunnerContext,
+	playerIndex int,
+	cardId card.SerializableCardID,
+	effectType card.CardEffectType,
+	onUse func(),
+) {
+	onUse()
+
+	c, ok := r.cards[cardId]
+	if !ok {
+		return
+	}
+	for i, effect := range c.GetEffects(effectType) {
+		data := runner.GetGameStateData()
+		snapshot := game.NewEffectSnapshot(data, playerIndex, c, effectType)
+		triggered, err := effect.EvaluateCondition(snapshot)
+		if err != nil {
+			r.logger.Error(r.ctx, "failed to evaluate card effect condition", err, map[string]any{
+				"cardId":     cardId,
+				"effectType": effectType,
+			})
+			continue
+		}
+		if !triggered {
+			continue
+		}
+
+		r.apply(runner, playerIndex, effect, true)
+
+		data = runner.GetGameStateData()
+		effectContext := game.NewEffectContext(
+			findCardPosition(data.BoardState, cardId, data.Players[playerIndex]),
+			cardId,
+			data.Players[playerIndex].User,
+			data.GamePhase,
+		)
+		effectContext.EffectType = effectType
+		effectContext.EffectIndex = i
+		data.ActiveEffectsStack = append(data.ActiveEffectsStack, effectContext)
+		runner.UpdateState(r.ctx, data)
+	}
+}
+
+// ResolveStack pops the active effects stack, applying the non-quick
+// attributes of each effect, and moves them onto the resolved effects stack
+func (r *EffectResolver) ResolveStack(runner *GameRunnerContext) {
+	for {
+		data := runner.GetGameStateData()
+		if len(data.ActiveEffectsStack) == 0 {
+			return
+		}
+		effectContext := data.ActiveEffectsStack[len(data.ActiveEffectsStack)-1]
+		data.ActiveEffectsStack = data.ActiveEffectsStack[:len(data.ActiveEffectsStack)-1]
+		data.EffectsStack = append(data.EffectsStack, effectContext)
+		runner.UpdateState(r.ctx, data)
+
+		playerIndex, ok := data.PlayerIndex(effectContext.Activator)
+		if !ok {
+			continue
+		}
+		c, ok := r.cards[effectContext.Source]
+		if !ok {
+			continue
+		}
+		effects := c.GetEffects(effectContext.EffectType)
+		if effectContext.EffectIndex >= len(effects) {
+			continue
+		}
+		r.apply(runner, playerIndex, effects[effectContext.EffectIndex], false)
+	}
+}
+
+// apply runs the attributes of effect whose IsQuickEffect matches quick.
+// Effects act on the activating player unless ForOpponent is set.
+func (r *EffectResolver) apply(runner *GameRunnerContext, playerIndex int, effect card.CardEffect, quick bool) {
+	target := func(base card.EffectAttributeBase) int {
+		if base.ForOpponent {
+			return 1 - playerIndex
+		}
+		return playerIndex
+	}
+
+	for _, attr := range effect.GainValueEffectAttributes {
+		if attr.IsQuickEffect == quick {
+			modifyWarValue(runner, target(attr.EffectAttributeBase), attr.Amount)
+		}
+	}
+	for _, attr := range effect.LoseValueEffectAttributes {
+		if attr.IsQuickEffect == quick {
+			modifyWarValue(runner, target(attr.EffectAttributeBase), -attr.Amount)
+		}
+	}
+	for _, attr := range effect.GainPointsEffectAttributes {
+		if attr.IsQuickEffect == quick {
+			runner.PlayerRunnerContext(target(attr.EffectAttributeBase)).AddPoints(r.ctx, attr.Amount)
+		}
+	}
+	for _, attr := range effect.LosePointsEffectAttributes {
+		if attr.IsQuickEffect == quick {
+			runner.PlayerRunnerContext(target(attr.EffectAttributeBase)).SubtractPoints(r.ctx, attr.Amount)
+		}
+	}
+	for _, attr := range effect.DrawEffectAttributes {
+		if attr.IsQuickEffect == quick {
+			r.drawCards(runner, target(attr.EffectAttributeBase), attr.Amount)
+		}
+	}
+	for _, attr := range effect.DiscardEffectAttributes {
+		if attr.IsQuickEffect == quick {
+			discardCards(runner, target(attr.EffectAttributeBase), attr.Amount)
+		}
+	}
+	for _, attr := range effect.SwapPositionEffectAttributes {
+		if attr.IsQuickEffect == quick {
+			swapSelectedCards(runner)
+		}
+	}
+}
+
+// drawCards moves cards from the top of the deck into the hand. Effect draws
+// do not count towards the player's draw for the turn and do not trigger
+// further effects.
+func (r *EffectResolver) drawCards(runner *GameRunnerContext, playerIndex int, amount int) {
+	deck := runner.DeckRunnerContext(playerIndex)
+	hand := runner.HandRunnerContext(playerIndex)
+	for i := 0; i < amount; i++ {
+		if len(hand.GetHand()) >= runner.getRules().HandLimit {
+			return
+		}
+		cardId, ok := deck.Pop(r.ctx)
+		if !ok {
+			return
+		}
+		hand.AddCard(cardId)
+	}
+}
+
+// discardCards moves the most recently added cards in the hand to the
+// discard pile
+func discardCards(runner *GameRunnerContext, playerIndex int, amount int) {
+	playerState := runner.GetPlayerState(playerIndex)
+	hand := playerState.Hand
+	if amount > len(hand) {
+		amount = len(hand)
+	}
+	if amount <= 0 {
+		return
+	}
+	newHand := make([]card.SerializableCardID, len(hand)-amount)
+	copy(newHand, hand[:len(hand)-amount])
+	newDiscarded := make([]card.SerializableCardID, len(playerState.DiscardedCards), len(playerState.DiscardedCards)+amount)
+	copy(newDiscarded, playerState.DiscardedCards)
+	playerState.Hand = newHand
+	playerState.DiscardedCards = append(newDiscarded, hand[len(hand)-amount:]...)
+	runner.UpdatePlayerState(playerIndex, playerState)
+}
+
+func modifyWarValue(runner *GameRunnerContext, playerIndex int, amount int) {
+	playerState := runner.GetPlayerState(playerIndex)
+	playerState.WarValueModifier += amount
+	runner.UpdatePlayerState(playerIndex, playerState)
+}
+
+// swapSelectedCards exchanges the players' selected cards so each fights the
+// War with the other's card. Nothing happens until both have selected.
+func swapSelectedCards(runner *GameRunnerContext) {
+	p1, p2 := runner.GetPlayerState(0), runner.GetPlayerState(1)
+	if p1.SelectedCard == nil || p2.SelectedCard == nil {
+		return
+	}
+	p1.SelectedCard, p2.SelectedCard = p2.SelectedCard, p1.SelectedCard
+	runner.UpdatePlayerState(0, p1)
+	runner.UpdatePlayerState(1, p2)
+}
+
+func findCardPosition(board game.BoardState, cardId card.SerializableCardID, player game.PlayerState) game.Position {
+	for x, column := range board.Board {
+		for y, space := range column {
+			if space.Card == cardId && space.Owner == player.User {
+				return game.Position{X: x, Y: y}
+			}
+		}
+	}
+	return game.Position{}
+}
+
+type noEffectResolver struct{}
+
+func (noEffectResolver) Resolve(_ *GameRunnerContext, _ int, _ card.SerializableCardID, _ card.CardEffectType, onUse func()) {
+	onUse()
+}
+
+func (noEffectResolver) ResolveStack(_ *GameRunnerContext) {}
@@ -0,0 +1,218 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/repos"
+	streamsdomain "github.com/coopersmall/subswag/streams/domain"
+	"github.com/coopersmall/subswag/utils"
+)
+
+const (
+	spectatorPollInterval = time.Second
+	spectatorLeaseRenewal = game.SpectatorLeaseTTL / 3
+)
+
+// GameEventsService lets players follow their game live and spectators watch
+// it from behind a broadcast delay. Events are read from the game's Redis
+// stream, so watchers see moves made through any API instance.
+type GameEventsService struct {
+	logger               utils.ILogger
+	tracer               apm.ITracer
+	gameStateRepo        repos.IGameStateRepo
+	gameStateVersionRepo repos.IGameStateVersionRepo
+	spectatorsCache      iSpectatorsCache
+	gateway              iStreamTailGateway
+}
+
+func NewGameEventsService(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	gameStateRepo repos.IGameStateRepo,
+	gameStateVersionRepo repos.IGameStateVersionRepo,
+	spectatorsCache iSpectatorsCache,
+	gateway iStreamTailGateway,
+) *GameEventsService {
+	return &GameEventsService{
+		logger:               logger,
+		tracer:               tracer,
+		gameStateRepo:        gameStateRepo,
+		gameStateVersionRepo: gameStateVersionRepo,
+		spectatorsCache:      spectatorsCache,
+		gateway:              gateway,
+	}
+}
+
+// Watch calls onEvent for each event published for the game until ctx is
+// done or onEvent returns an error. Only the game's players may watch its
+// live events; everyone else spectates.
+func (s *GameEventsService) Watch(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	userId user.UserID,
+	onEvent func(ctx context.Context, event *game.GameEvent) error,
+) error {
+	gameState, err := s.gameStateRepo.Get(ctx, gameStateId)
+	if err != nil {
+		return err
+	}
+	if _, ok := gameState.PlayerIndex(userId); !ok {
+		return utils.NewPermissionDeniedError("only players can watch live events, spectators are delayed")
+	}
+	return s.gateway.Tail(ctx, game.GameEventsStream(gameStateId), func(ctx context.Context, data []byte) error {
+		var event streamsdomain.Event[*game.GameEvent]
+		if err := json.Unmarshal(data, &event); err != nil {
+			s.logger.Error(ctx, "failed to unmarshal game event", err, nil)
+			return nil
+		}
+		return onEvent(ctx, event.Data)
+	})
+}
+
+// Spectate calls onVersion with the spectator view of each of the game's
+// versions once it is older than the game's broadcast delay. Spectating
+// starts from the newest version that is old enough and ends when the last
+// version of a completed game has been sent, ctx is done or onVersion returns
+// an error. The spectator is counted for as long as they are watching.
+func (s *GameEventsService) Spectate(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	onVersion func(ctx context.Context, version game.GameStateVersionView) error,
+) error {
+	gameState, err := s.gameStateRepo.Get(ctx, gameStateId)
+	if err != nil {
+		return err
+	}
+	if gameState.Spectating.Disallowed {
+		return utils.NewPermissionDeniedError("spectators are not allowed in this game")
+	}
+	delay := gameState.Spectating.BroadcastDelay()
+
+	leaseId := game.SpectatorLeaseID(utils.NewID().String())
+	if err := s.renewLease(ctx, gameStateId, leaseId); err != nil {
+		return err
+	}
+	defer s.leave(gameStateId, leaseId)
+
+	ticker := time.NewTicker(spectatorPollInterval)
+	defer ticker.Stop()
+	var (
+		sent     = -1 // Number of the last version sent
+		sentAt   time.Time
+		renewed  = time.Now()
+		complete bool
+	)
+	for {
+		// Only the versions made since the last one sent are loaded
+		cutoff := time.Now().Add(-delay)
+		var versions []*game.GameStateVersion
+		if sent < 0 {
+			// Catch up to the game rather than replaying its history
+			var (
+				latest *game.GameStateVersion
+				due    int
+			)
+			latest, due, err = s.gameStateVersionRepo.GetLatestVersionBefore(ctx, gameStateId, cutoff)
+			if err == nil {
+				versions = []*game.GameStateVersion{latest}
+				sent = due - 2
+			} else if !utils.IsNotFoundError(err) {
+				return err
+			}
+		} else {
+			versions, err = s.gameStateVersionRepo.GetVersionsBetween(ctx, gameStateId, sentAt, cutoff)
+			if err != nil {
+				return err
+			}
+		}
+		for _, version := range versions {
+			sent++
+			if err := onVersion(ctx, game.NewSpectatorVersionView(sent, version)); err != nil {
+				return err
+			}
+			sentAt = version.Metadata.CreatedAt
+			complete = version.State.IsComplete
+		}
+		if complete {
+			// Versions can follow the first complete one, so stop only once
+			// the newest has been sent
+			var latest *game.GameStateVersion
+			latest, err = s.gameStateVersionRepo.GetLatestVersion(ctx, gameStateId)
+			if err != nil {
+				return err
+			}
+			if !latest.Metadata.CreatedAt.After(sentAt) {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			if now.Sub(renewed) >= spectatorLeaseRenewal {
+				if err := s.renewLease(ctx, gameStateId, leaseId); err != nil {
+					return err
+				}
+				renewed = now
+			}
+		}
+	}
+}
+
+// SpectatorCount returns how many users are watching the game
+func (s *GameEventsService) SpectatorCount(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+) (*game.SpectatorCount, error) {
+	if _, err := s.gameStateRepo.Get(ctx, gameStateId); err != nil {
+		return nil, err
+	}
+	spectators, err := s.spectatorsCache.GetSpectators(ctx, gameStateId)
+	if err != nil {
+		return nil, err
+	}
+	return &game.SpectatorCount{
+		GameStateID: gameStateId,
+		Count:       spectators.Count(time.Now()),
+	}, nil
+}
+
+func (s *GameEventsService) renewLease(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	leaseId game.SpectatorLeaseID,
+) error {
+	return s.spectatorsCache.UpdateSpectators(ctx, gameStateId, func(spectators *game.Spectators) error {
+		spectators.Renew(leaseId, time.Now())
+		return nil
+	})
+}
+
+// leave gives up the lease. The spectator's request context is usually done
+// by now, so a fresh one is used; the lease expires anyway if this fails.
+func (s *GameEventsService) leave(gameStateId game.GameStateID, leaseId game.SpectatorLeaseID) {
+	ctx := context.Background()
+	if err := s.spectatorsCache.UpdateSpectators(ctx, gameStateId, func(spectators *game.Spectators) error {
+		spectators.Leave(leaseId)
+		return nil
+	}); err != nil {
+		s.logger.Error(ctx, "failed to remove spectator", err, map[string]any{
+			"gameStateId": gameStateId,
+		})
+	}
+}
+
+type iStreamTailGateway interface {
+	Tail(ctx context.Context, stream string, handler func(context.Context, []byte) error) error
+}
+
+type iSpectatorsCache interface {
+	GetSpectators(ctx context.Context, gameStateId game.GameStateID) (*game.Spectators, error)
+	UpdateSpectators(ctx context.Context, gameStateId game.GameStateID, update func(*game.Spectators) error) error
+}
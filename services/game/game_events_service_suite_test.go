@@ -0,0 +1,74 @@
+package game_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain/game"
+	gamerunnerservice "github.com/coopersmall/subswag/services/game"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type GameEventsServiceTestSuite struct {
+	suite.Suite
+}
+
+func TestGameEventsServiceSuite(t *testing.T) {
+	suite.Run(t, new(GameEventsServiceTestSuite))
+}
+
+// eventsFixture is an events service backed by in-memory stores
+type eventsFixture struct {
+	service    *gamerunnerservice.GameEventsService
+	states     *fakeGameStates
+	versions   *fakeVersions
+	spectators *fakeSpectators
+}
+
+func newEventsFixture() *eventsFixture {
+	f := &eventsFixture{
+		states:     &fakeGameStates{states: make(map[game.GameStateID]*game.GameState)},
+		versions:   &fakeVersions{},
+		spectators: &fakeSpectators{spectators: make(map[game.GameStateID]*game.Spectators)},
+	}
+	f.service = gamerunnerservice.NewGameEventsService(
+		utils.NewLogger("game-events", utils.WithWriter(io.Discard)),
+		apm.NewNoopTracer(),
+		f.states,
+		f.versions,
+		f.spectators,
+		fakeStreams{},
+	)
+	return f
+}
+
+type fakeSpectators struct {
+	spectators map[game.GameStateID]*game.Spectators
+}
+
+func (f *fakeSpectators) GetSpectators(ctx context.Context, gameStateId game.GameStateID) (*game.Spectators, error) {
+	if spectators, ok := f.spectators[gameStateId]; ok {
+		return spectators, nil
+	}
+	return &game.Spectators{}, nil
+}
+
+func (f *fakeSpectators) UpdateSpectators(ctx context.Context, gameStateId game.GameStateID, update func(*game.Spectators) error) error {
+	spectators, _ := f.GetSpectators(ctx, gameStateId)
+	if err := update(spectators); err != nil {
+		return err
+	}
+	f.spectators[gameStateId] = spectators
+	return nil
+}
+
+// fakeStreams has no events to tail
+type fakeStreams struct{}
+
+func (fakeStreams) Tail(ctx context.Context, stream string, handler func(context.Context, []byte) error) error {
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,92 @@
+package game_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addVersion stores a version of the game created at createdAt
+func (f *eventsFixture) addVersion(gameState *game.GameState, createdAt time.Time) {
+	metadata := domain.NewMetadata()
+	metadata.CreatedAt = createdAt
+	f.versions.versions = append(f.versions.versions, &game.GameStateVersion{
+		ID:       game.NewGameStateVersionID(),
+		State:    copyGameState(gameState),
+		Metadata: metadata,
+	})
+}
+
+func (s *GameEventsServiceTestSuite) TestSpectate() {
+	newGame := func(f *eventsFixture) *game.GameState {
+		gameState := &game.GameState{ID: 7, Metadata: domain.NewMetadata()}
+		require.NoError(s.T(), f.states.Create(ctx, gameState))
+		return gameState
+	}
+	spectate := func(f *eventsFixture, gameState *game.GameState) []int {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		sent := make([]int, 0)
+		err := f.service.Spectate(ctx, gameState.ID, func(ctx context.Context, version game.GameStateVersionView) error {
+			sent = append(sent, version.Version)
+			return nil
+		})
+		require.NoError(s.T(), err)
+		return sent
+	}
+	// due is the newest creation time spectators may be sent yet
+	due := func() time.Time {
+		return time.Now().Add(-game.DefaultSpectatorDelay)
+	}
+
+	s.Run("should start from the newest due version and end with the completed game", func() {
+		f := newEventsFixture()
+		gameState := newGame(f)
+		due := due()
+		f.addVersion(gameState, due.Add(-2*time.Minute))
+		f.addVersion(gameState, due.Add(-time.Minute))
+		gameState.IsComplete = true
+		f.addVersion(gameState, due.Add(-time.Second))
+
+		assert.Equal(s.T(), []int{2}, spectate(f, gameState))
+	})
+
+	s.Run("should send versions as they become due", func() {
+		f := newEventsFixture()
+		gameState := newGame(f)
+		due := due()
+		f.addVersion(gameState, due.Add(-time.Minute))
+		f.addVersion(gameState, due.Add(-time.Second))
+		f.addVersion(gameState, due.Add(200*time.Millisecond))
+		gameState.IsComplete = true
+		f.addVersion(gameState, due.Add(400*time.Millisecond))
+
+		assert.Equal(s.T(), []int{1, 2, 3}, spectate(f, gameState))
+	})
+
+	s.Run("should not end before the versions that follow a completed one", func() {
+		f := newEventsFixture()
+		gameState := newGame(f)
+		due := due()
+		gameState.IsComplete = true
+		f.addVersion(gameState, due.Add(-time.Second))
+		f.addVersion(gameState, due.Add(200*time.Millisecond))
+
+		assert.Equal(s.T(), []int{0, 1}, spectate(f, gameState))
+	})
+
+	s.Run("should not let anyone watch a game that disallows spectators", func() {
+		f := newEventsFixture()
+		gameState := newGame(f)
+		f.states.states[gameState.ID].Spectating.Disallowed = true
+
+		err := f.service.Spectate(ctx, gameState.ID, func(ctx context.Context, version game.GameStateVersionView) error {
+			return nil
+		})
+		assert.Error(s.T(), err)
+	})
+}
@@ -0,0 +1,265 @@
+package game
+
+import (
+	"context"
+	"time"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/repos"
+	"github.com/coopersmall/subswag/utils"
+)
+
+const (
+	DefaultVersionsPageSize = 50
+	MaxVersionsPageSize     = 100
+)
+
+// GameReplayService reads back the version snapshots written on every
+// commit. Versions are numbered from 0 in the order they were created.
+type GameReplayService struct {
+	logger               utils.ILogger
+	tracer               apm.ITracer
+	gameStateRepo        repos.IGameStateRepo
+	gameStateVersionRepo repos.IGameStateVersionRepo
+	gameTimersRepo       repos.IGameTimersRepo
+	publisher            iGameEventsPublisher
+}
+
+func NewGameReplayService(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	gameStateRepo repos.IGameStateRepo,
+	gameStateVersionRepo repos.IGameStateVersionRepo,
+	gameTimersRepo repos.IGameTimersRepo,
+	publisher iGameEventsPublisher,
+) *GameReplayService {
+	return &GameReplayService{
+		logger:               logger,
+		tracer:               tracer,
+		gameStateRepo:        gameStateRepo,
+		gameStateVersionRepo: gameStateVersionRepo,
+		gameTimersRepo:       gameTimersRepo,
+		publisher:            publisher,
+	}
+}
+
+// ListVersions summarises a page of the versions the viewer may see, oldest
+// first
+func (s *GameReplayService) ListVersions(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	viewer user.UserID,
+	limit int,
+	offset int,
+) ([]game.GameStateVersionSummary, error) {
+	_, visible, err := s.getVisibleLatest(ctx, gameStateId, viewer)
+	if err != nil {
+		return nil, err
+	}
+	limit, offset = versionsPage(limit, offset)
+	summaries := []game.GameStateVersionSummary{}
+	if offset >= visible {
+		return summaries, nil
+	}
+	versions, err := s.gameStateVersionRepo.GetVersionsPage(ctx, gameStateId, min(limit, visible-offset), offset)
+	if err != nil {
+		return nil, err
+	}
+	for i, version := range versions {
+		summaries = append(summaries, game.NewGameStateVersionSummary(offset+i, version))
+	}
+	return summaries, nil
+}
+
+// GetVersion returns the version as the viewer may see it: a player's view if
+// they are playing in the game and the spectator view otherwise
+func (s *GameReplayService) GetVersion(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	version int,
+	viewer user.UserID,
+) (*game.GameStateVersionView, error) {
+	_, visible, err := s.getVisibleLatest(ctx, gameStateId, viewer)
+	if err != nil {
+		return nil, err
+	}
+	found, err := s.getVersion(ctx, gameStateId, version, visible)
+	if err != nil {
+		return nil, err
+	}
+	view := game.NewGameStateVersionView(version, found, viewer)
+	return &view, nil
+}
+
+// GetLatestVersion returns the newest version the viewer may see
+func (s *GameReplayService) GetLatestVersion(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	viewer user.UserID,
+) (*game.GameStateVersionView, error) {
+	latest, visible, err := s.getVisibleLatest(ctx, gameStateId, viewer)
+	if err != nil {
+		return nil, err
+	}
+	view := game.NewGameStateVersionView(visible-1, latest, viewer)
+	return &view, nil
+}
+
+// DiffVersions compares two versions as the viewer sees them, so the diff
+// never shows more than the versions themselves would
+func (s *GameReplayService) DiffVersions(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	from int,
+	to int,
+	viewer user.UserID,
+) ([]game.StateChange, error) {
+	_, visible, err := s.getVisibleLatest(ctx, gameStateId, viewer)
+	if err != nil {
+		return nil, err
+	}
+	fromVersion, err := s.getVersion(ctx, gameStateId, from, visible)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := s.getVersion(ctx, gameStateId, to, visible)
+	if err != nil {
+		return nil, err
+	}
+	return game.DiffGameViews(
+		game.NewViewFor(fromVersion.State, viewer),
+		game.NewViewFor(toVersion.State, viewer),
+	)
+}
+
+// Rollback restores the game to an earlier version. The restored state is
+// committed as a new version so the history is never rewritten. A game
+// restored into the reveal phase gets a fresh reveal window; otherwise the
+// timer for the window it was rolled back out of is cleared.
+func (s *GameReplayService) Rollback(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	version int,
+) (*game.GameState, error) {
+	var (
+		gameState *game.GameState
+		err       error
+	)
+	s.tracer.Trace(ctx, "rollback-game", func(ctx context.Context, span apm.ISpan) error {
+		span.SetAttribute("version", version)
+		if version < 0 {
+			err = utils.NewNotFoundError("game state version not found")
+			return err
+		}
+		var target *game.GameStateVersion
+		target, err = s.gameStateVersionRepo.GetVersionByNumber(ctx, gameStateId, version)
+		if err != nil {
+			return err
+		}
+
+		err = RetryOnConflict(ctx, s.gameStateRepo, gameStateId, func(current *game.GameState) error {
+			gameState = &game.GameState{
+				ID:            current.ID,
+				GameStateData: target.State.GameStateData.Clone(),
+				Version:       current.Version,
+				Metadata:      current.Metadata,
+			}
+			gameState.RevealDeadline = nil
+			if gameState.GamePhase == game.PhaseReveal && !gameState.IsComplete {
+				deadline := gameState.Rules.RevealDeadline(time.Now())
+				gameState.RevealDeadline = &deadline
+				// Written before the commit, as the runner does, so a crash
+				// in between leaves a stale timer rather than no timer
+				if err := s.gameTimersRepo.Schedule(ctx, game.NewRevealTimer(gameState)); err != nil {
+					return err
+				}
+			} else if current.RevealDeadline != nil {
+				if err := s.gameTimersRepo.Complete(ctx, &game.RevealTimer{
+					GameStateID: gameStateId,
+					RoundNumber: current.RoundNumber,
+				}); err != nil {
+					return err
+				}
+			}
+			runner := NewGameRunnerContext(gameState, s.gameStateRepo, s.gameStateVersionRepo, nil)
+			return runner.Commit(ctx)
+		})
+		if err != nil {
+			return err
+		}
+		// The restored state has already been committed, so failures to
+		// publish are logged rather than returned
+		_, count, countErr := s.gameStateVersionRepo.GetLatestVersionBefore(ctx, gameStateId, time.Now())
+		if countErr != nil {
+			s.logger.Error(ctx, "failed to count game state versions", countErr, nil)
+		}
+		event := game.NewGameEvent(gameState, game.GameEventRolledBack, game.RolledBackEventData{
+			From:    version,
+			Version: count - 1,
+		})
+		if err := s.publisher.PublishEvents(ctx, event); err != nil {
+			s.logger.Error(ctx, "failed to publish game events", err, nil)
+		}
+		s.logger.Info(ctx, "rolled back game", map[string]any{
+			"gameStateId": gameStateId,
+			"version":     version,
+		})
+		return nil
+	})
+	return gameState, err
+}
+
+// getVisibleLatest returns the newest version the viewer may see and how
+// many versions they may see, reading only the latest rows rather than the
+// whole history. Players see every version. Spectators only see versions
+// older than the game's broadcast delay until the game is complete, and
+// nothing at all if the game does not allow spectators.
+func (s *GameReplayService) getVisibleLatest(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	viewer user.UserID,
+) (*game.GameStateVersion, int, error) {
+	latest, err := s.gameStateVersionRepo.GetLatestVersion(ctx, gameStateId)
+	if err != nil {
+		return nil, 0, err
+	}
+	cutoff := latest.Metadata.CreatedAt
+	if _, ok := latest.State.PlayerIndex(viewer); !ok {
+		if latest.State.Spectating.Disallowed {
+			return nil, 0, utils.NewPermissionDeniedError("spectators are not allowed in this game")
+		}
+		if !latest.State.IsComplete {
+			cutoff = time.Now().Add(-latest.State.Spectating.BroadcastDelay())
+		}
+	}
+	visible, count, err := s.gameStateVersionRepo.GetLatestVersionBefore(ctx, gameStateId, cutoff)
+	if utils.IsNotFoundError(err) {
+		return nil, 0, utils.NewNotFoundError("no version of the game can be spectated yet")
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return visible, count, nil
+}
+
+// getVersion reads a single version, which must be one of the first visible
+func (s *GameReplayService) getVersion(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	version int,
+	visible int,
+) (*game.GameStateVersion, error) {
+	if version < 0 || version >= visible {
+		return nil, utils.NewNotFoundError("game state version not found")
+	}
+	return s.gameStateVersionRepo.GetVersionByNumber(ctx, gameStateId, version)
+}
+
+func versionsPage(limit int, offset int) (int, int) {
+	if limit <= 0 {
+		limit = DefaultVersionsPageSize
+	}
+	return min(limit, MaxVersionsPageSize), max(offset, 0)
+}
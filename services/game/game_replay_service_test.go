@@ -0,0 +1,126 @@
+package game_test
+
+import (
+	"time"
+
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spectator is not playing in any of the test games
+var spectator = user.UserID(2001)
+
+func (s *GameRunnerServiceTestSuite) TestReplay() {
+	s.Run("should page through the versions oldest first", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		gameState = s.playRound(f, gameState)
+
+		summaries, err := f.replay.ListVersions(ctx, gameState.ID, player1, 3, 4)
+		require.NoError(s.T(), err)
+		require.Len(s.T(), summaries, 3)
+		for i, summary := range summaries {
+			assert.Equal(s.T(), 4+i, summary.Version)
+			assert.Equal(s.T(), f.versions.versions[4+i].ID, summary.ID)
+		}
+		assert.Equal(s.T(), game.PhaseReveal, summaries[0].GamePhase)
+
+		summaries, err = f.replay.ListVersions(ctx, gameState.ID, player1, 5, 8)
+		require.NoError(s.T(), err)
+		assert.Len(s.T(), summaries, 2)
+
+		summaries, err = f.replay.ListVersions(ctx, gameState.ID, player1, 0, 10)
+		require.NoError(s.T(), err)
+		assert.Empty(s.T(), summaries)
+	})
+
+	s.Run("should read a single version by its number", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		gameState = s.playRound(f, gameState)
+
+		version, err := f.replay.GetVersion(ctx, gameState.ID, 4, player1)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), 4, version.Version)
+
+		latest, err := f.replay.GetLatestVersion(ctx, gameState.ID, player1)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), 9, latest.Version)
+
+		_, err = f.replay.GetVersion(ctx, gameState.ID, 10, player1)
+		assert.True(s.T(), utils.IsNotFoundError(err))
+	})
+
+	s.Run("should only show spectators the versions past the broadcast delay", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		gameState = s.playRound(f, gameState)
+		for _, version := range f.versions.versions[:3] {
+			version.Metadata.CreatedAt = time.Now().Add(-time.Hour)
+		}
+
+		latest, err := f.replay.GetLatestVersion(ctx, gameState.ID, spectator)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), 2, latest.Version)
+
+		summaries, err := f.replay.ListVersions(ctx, gameState.ID, spectator, 0, 0)
+		require.NoError(s.T(), err)
+		assert.Len(s.T(), summaries, 3)
+
+		_, err = f.replay.GetVersion(ctx, gameState.ID, 3, spectator)
+		assert.True(s.T(), utils.IsNotFoundError(err))
+	})
+}
+
+func (s *GameRunnerServiceTestSuite) TestRollback() {
+	s.Run("should commit the restored version as a new one and reopen its reveal window", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		gameState = s.playRound(f, gameState)
+		require.Equal(s.T(), game.PhaseReveal, f.versions.phases()[4])
+		require.Len(s.T(), f.timers.scheduled, 1)
+
+		restored, err := f.replay.Rollback(ctx, gameState.ID, 4)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), game.PhaseReveal, restored.GamePhase)
+		assert.Equal(s.T(), 0, restored.RoundNumber)
+		require.NotNil(s.T(), restored.RevealDeadline)
+		assert.WithinDuration(s.T(), restored.Rules.RevealDeadline(time.Now()), *restored.RevealDeadline, time.Second)
+
+		phases := f.versions.phases()
+		assert.Len(s.T(), phases, 11)
+		assert.Equal(s.T(), game.PhaseReveal, phases[10])
+		require.Len(s.T(), f.timers.scheduled, 2)
+		assert.Equal(s.T(), *restored.RevealDeadline, f.timers.scheduled[1].Deadline)
+
+		last := f.events.events[len(f.events.events)-1]
+		assert.Equal(s.T(), game.GameEventRolledBack, last.Type)
+		assert.Equal(s.T(), game.RolledBackEventData{From: 4, Version: 10}, last.Data)
+	})
+
+	s.Run("should clear the reveal timer when rolled back out of the reveal phase", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		gameState, err := f.service.AdvancePhase(ctx, gameState.ID)
+		require.NoError(s.T(), err)
+		require.Len(s.T(), f.timers.scheduled, 1)
+
+		restored, err := f.replay.Rollback(ctx, gameState.ID, 1)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), game.PhaseCardAction, restored.GamePhase)
+		assert.Nil(s.T(), restored.RevealDeadline)
+		assert.Len(s.T(), f.timers.scheduled, 1)
+		require.Len(s.T(), f.timers.completed, 1)
+		assert.Equal(s.T(), gameState.RoundNumber, f.timers.completed[0].RoundNumber)
+
+		require.NoError(s.T(), f.service.ExpireRevealTimers(ctx))
+		gameState, err = f.service.GetGame(ctx, gameState.ID)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), game.PhaseCardAction, gameState.GamePhase)
+	})
+
+	s.Run("should reject a version the game does not have", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		_, err := f.replay.Rollback(ctx, gameState.ID, 5)
+		assert.Error(s.T(), err)
+		assert.Len(s.T(), f.versions.phases(), 2)
+	})
+}
@@ -2,20 +2,54 @@ package game
 
 import (
 	"context"
+	mrand "math/rand"
+	"slices"
+	"time"
 
+	"github.com/coopersmall/subswag/apm"
 	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/bot"
 	"github.com/coopersmall/subswag/domain/card"
 	"github.com/coopersmall/subswag/domain/game"
 	"github.com/coopersmall/subswag/domain/user"
 	"github.com/coopersmall/subswag/repos"
-	"github.com/coopersmall/subswag/services"
+	"github.com/coopersmall/subswag/utils"
 )
 
 type GameRunnerService struct {
+	logger               utils.ILogger
+	tracer               apm.ITracer
 	gameStateRepo        repos.IGameStateRepo
 	gameStateVersionRepo repos.IGameStateVersionRepo
-	decksService         services.IDecksService
-	usersService         services.IUsersService
+	gameTimersRepo       repos.IGameTimersRepo
+	cardsRepo            repos.ICardsRepo
+	getDeck              func(ctx context.Context, userId user.UserID, deckId card.SerializableDeckID) (*card.SerializableDeck, error)
+	usersService         iUsersService
+	publisher            iGameEventsPublisher
+}
+
+func NewGameRunnerService(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	gameStateRepo repos.IGameStateRepo,
+	gameStateVersionRepo repos.IGameStateVersionRepo,
+	gameTimersRepo repos.IGameTimersRepo,
+	cardsRepo repos.ICardsRepo,
+	getDeck func(ctx context.Context, userId user.UserID, deckId card.SerializableDeckID) (*card.SerializableDeck, error),
+	usersService iUsersService,
+	publisher iGameEventsPublisher,
+) *GameRunnerService {
+	return &GameRunnerService{
+		logger:               logger,
+		tracer:               tracer,
+		gameStateRepo:        gameStateRepo,
+		gameStateVersionRepo: gameStateVersionRepo,
+		gameTimersRepo:       gameTimersRepo,
+		cardsRepo:            cardsRepo,
+		getDeck:              getDeck,
+		usersService:         usersService,
+		publisher:            publisher,
+	}
 }
 
 type StartGameRequest struct {
@@ -27,32 +61,68 @@ type StartGameRequest struct {
 		UserID user.UserID
 		DeckID card.SerializableDeckID
 	}
+	// Seed fixes the game's random stream, e.g. to replay a game. A random
+	// seed is used when nil.
+	Seed *int64
+	// Spectating decides who may watch the game
+	Spectating game.SpectatorSettings
+	// Rules is the preset the game is played with, classic when empty
+	Rules game.RulesPreset
 }
 
 func (s *GameRunnerService) InitializeGame(
 	ctx context.Context,
 	req StartGameRequest,
 ) (*game.GameState, error) {
+	rules, err := game.NewRules(req.Rules)
+	if err != nil {
+		return nil, err
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
 	player1, err := s.usersService.GetUser(ctx, req.Player1.UserID)
 	if err != nil {
 		return nil, err
 	}
-	player1Deck, err := s.decksService.GetDeck(ctx, req.Player1.DeckID)
+	player1Deck, err := s.getDeck(ctx, player1.ID, req.Player1.DeckID)
+	if err != nil {
+		return nil, err
+	}
+	player2, err := s.getPlayer(ctx, req.Player2.UserID)
 	if err != nil {
 		return nil, err
 	}
-	player2, err := s.usersService.GetUser(ctx, req.Player2.UserID)
+	// Bots have no decks of their own and play one lent by their opponent
+	player2DeckOwner := player2.ID
+	if bot.IsBot(player2.ID) {
+		player2DeckOwner = player1.ID
+	}
+	player2Deck, err := s.getDeck(ctx, player2DeckOwner, req.Player2.DeckID)
 	if err != nil {
 		return nil, err
 	}
-	player2Deck, err := s.decksService.GetDeck(ctx, req.Player2.DeckID)
+	rng, err := game.NewRandomRNGState()
 	if err != nil {
 		return nil, err
 	}
-	player1State := game.NewPlayerState(player1.ID, player1Deck)
-	player2State := game.NewPlayerState(player2.ID, player2Deck)
+	if req.Seed != nil {
+		rng = game.NewRNGState(*req.Seed, rng.Nonce)
+	}
+	for _, deck := range []*card.SerializableDeck{player1Deck, player2Deck} {
+		if err := rules.ValidateDeckSize(len(deck.CardIDs)); err != nil {
+			return nil, err
+		}
+	}
+	player1State := game.NewPlayerState(player1.ID, player1Deck, rules.StartingHandSize, rng.Next())
+	player2State := game.NewPlayerState(player2.ID, player2Deck, rules.StartingHandSize, rng.Next())
 
-	gameState := game.NewGameState([2]game.PlayerState{player1State, player2State})
+	gameState := game.NewGameState([2]game.PlayerState{player1State, player2State}, rules, rng)
+	gameState.Spectating = req.Spectating
+	gameState.CardVersions, err = s.pinCardVersions(ctx, player1Deck, player2Deck)
+	if err != nil {
+		return nil, err
+	}
 
 	if err := domain.Validate(gameState); err != nil {
 		return nil, err
@@ -66,9 +136,588 @@ func (s *GameRunnerService) InitializeGame(
 		return nil, err
 	}
 
+	// Setup needs nothing from the players, so the game opens in the card
+	// action phase
+	return s.advanceDonePhases(ctx, gameState)
+}
+
+func (s *GameRunnerService) GetGame(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+) (*game.GameState, error) {
+	return s.gameStateRepo.Get(ctx, gameStateId)
+}
+
+// AdvancePhase runs the transition out of the game's current phase, whether
+// or not the players are done with it, and commits a new game state version.
+// The game then moves on through any phases that need nothing more from the
+// players.
+func (s *GameRunnerService) AdvancePhase(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+) (*game.GameState, error) {
+	gameState, _, err := s.transition(ctx, gameStateId, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.advanceDonePhases(ctx, gameState)
+}
+
+// advanceDonePhases moves the game on for as long as its current phase is
+// done, committing a version at each transition. Players acting on the game
+// drive it from phase to phase this way.
+func (s *GameRunnerService) advanceDonePhases(
+	ctx context.Context,
+	gameState *game.GameState,
+) (*game.GameState, error) {
+	for gameState.IsPhaseDone() {
+		next, advanced, err := s.transition(ctx, gameState.ID, true)
+		if err != nil {
+			return nil, err
+		}
+		if !advanced {
+			return next, nil
+		}
+		gameState = next
+	}
 	return gameState, nil
 }
 
+// transition runs the transition out of the game's current phase and commits
+// it. When onlyIfDone is set the game is left as it is unless its phase is
+// done, so that concurrent callers do not advance it twice; the returned
+// bool reports whether it was advanced.
+func (s *GameRunnerService) transition(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	onlyIfDone bool,
+) (*game.GameState, bool, error) {
+	var (
+		gameState *game.GameState
+		advanced  bool
+		err       error
+	)
+	s.tracer.Trace(ctx, "advance-phase", func(ctx context.Context, span apm.ISpan) error {
+		var events []*game.GameEvent
+		err = RetryOnConflict(ctx, s.gameStateRepo, gameStateId, func(loaded *game.GameState) error {
+			gameState = loaded
+			advanced = false
+			span.SetAttribute("phase", string(gameState.GamePhase))
+			if onlyIfDone && !gameState.IsPhaseDone() {
+				return nil
+			}
+			before := gameState.GameStateData.Clone()
+			runner, err := s.newGameRunnerContext(ctx, gameState)
+			if err != nil {
+				return err
+			}
+			if err := s.advance(ctx, runner); err != nil {
+				return err
+			}
+			runner.ResolveEffects()
+			botEvents, err := s.playBots(ctx, runner)
+			if err != nil {
+				return err
+			}
+			runner.ResolveEffects()
+			if err := s.scheduleRevealTimer(ctx, gameState); err != nil {
+				return err
+			}
+			if err := runner.Commit(ctx); err != nil {
+				return err
+			}
+			events = append(game.NewGameEvents(before, gameState), botEvents...)
+			advanced = true
+			return nil
+		})
+		if err != nil || !advanced {
+			return err
+		}
+		s.publish(ctx, events...)
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return gameState, advanced, nil
+}
+
+const (
+	revealTimerLease     = 30 * time.Second
+	revealTimerBatchSize = 50
+)
+
+// ExpireRevealTimers handles every reveal window that has closed. Players who
+// have not selected a card have one selected for them and the game advances
+// to the War phase. Timers that fail are retried once their lease runs out.
+func (s *GameRunnerService) ExpireRevealTimers(ctx context.Context) error {
+	var err error
+	s.tracer.Trace(ctx, "expire-reveal-timers", func(ctx context.Context, span apm.ISpan) error {
+		var timers []*game.RevealTimer
+		timers, err = s.gameTimersRepo.ClaimDue(ctx, time.Now(), revealTimerLease, revealTimerBatchSize)
+		if err != nil {
+			return err
+		}
+		span.SetAttribute("timers", len(timers))
+		for _, timer := range timers {
+			if err := s.expireRevealTimer(ctx, timer); err != nil {
+				s.logger.Error(ctx, "failed to expire reveal timer", err, map[string]any{
+					"gameStateId": timer.GameStateID,
+					"roundNumber": timer.RoundNumber,
+				})
+				// Invalid states will not fix themselves on a retry
+				if !utils.IsInvalidStateError(err) {
+					continue
+				}
+			}
+			if err := s.gameTimersRepo.Complete(ctx, timer); err != nil {
+				s.logger.Error(ctx, "failed to complete reveal timer", err, nil)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *GameRunnerService) expireRevealTimer(ctx context.Context, timer *game.RevealTimer) error {
+	var (
+		events   []*game.GameEvent
+		revealed *game.GameState
+	)
+	err := RetryOnConflict(ctx, s.gameStateRepo, timer.GameStateID, func(gameState *game.GameState) error {
+		events = nil
+		revealed = nil
+		if timer.IsStale(gameState.GameStateData) {
+			return nil
+		}
+
+		before := gameState.GameStateData.Clone()
+		selected, err := game.ApplyAutoReveal(&gameState.GameStateData)
+		if err != nil {
+			return err
+		}
+		for _, playerIndex := range selected {
+			player := gameState.Players[playerIndex]
+			events = append(events, game.NewGameEvent(gameState, game.GameEventAutoSelected, game.AutoSelectedEventData{
+				User:     player.User,
+				Position: *player.SelectedCard,
+			}))
+		}
+
+		runner, err := s.newGameRunnerContext(ctx, gameState)
+		if err != nil {
+			return err
+		}
+		if err := s.advance(ctx, runner); err != nil {
+			return err
+		}
+		runner.ResolveEffects()
+		if err := runner.Commit(ctx); err != nil {
+			return err
+		}
+		events = append(events, game.NewGameEvents(before, gameState)...)
+		revealed = gameState
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events...)
+	if revealed == nil {
+		return nil
+	}
+	// The War and cleanup follow without waiting on the players
+	_, err = s.advanceDonePhases(ctx, revealed)
+	return err
+}
+
+// scheduleRevealTimer starts the reveal window when the game has just moved
+// into the reveal phase. The timer is written before the state is committed
+// so a crash in between leaves a stale timer rather than no timer.
+func (s *GameRunnerService) scheduleRevealTimer(ctx context.Context, gameState *game.GameState) error {
+	if gameState.GamePhase != game.PhaseReveal || gameState.RevealDeadline == nil {
+		return nil
+	}
+	return s.gameTimersRepo.Schedule(ctx, game.NewRevealTimer(gameState))
+}
+
+// ApplyCommand validates a player's command against the phase and turn rules
+// and, if it is allowed, applies it and commits the new state. Rejected
+// commands are returned as a result rather than an error.
+func (s *GameRunnerService) ApplyCommand(
+	ctx context.Context,
+	gameStateId game.GameStateID,
+	userId user.UserID,
+	cmd game.Command,
+) (*game.CommandResult, error) {
+	var (
+		result *game.CommandResult
+		err    error
+	)
+	s.tracer.Trace(ctx, "apply-command", func(ctx context.Context, span apm.ISpan) error {
+		span.SetAttribute("command", string(cmd.GetType()))
+		var before game.GameStateData
+		err = RetryOnConflict(ctx, s.gameStateRepo, gameStateId, func(gameState *game.GameState) error {
+			result = &game.CommandResult{State: gameState}
+
+			playerIndex, ok := gameState.PlayerIndex(userId)
+			if !ok {
+				result.Rejection = &game.CommandRejection{
+					Reason:  game.RejectionNotAPlayer,
+					Message: "user is not playing this game",
+				}
+				return nil
+			}
+			if rejection := game.ValidateCommand(gameState.GameStateData, playerIndex, cmd); rejection != nil {
+				span.AddEvent("command rejected")
+				result.Rejection = rejection
+				return nil
+			}
+
+			before = gameState.GameStateData.Clone()
+			runner, err := s.newGameRunnerContext(ctx, gameState)
+			if err != nil {
+				return err
+			}
+			if !applyCommand(ctx, runner.PlayerRunnerContext(playerIndex), cmd) {
+				return utils.NewInvalidStateError("command could not be applied")
+			}
+			runner.ResolveEffects()
+			if err := runner.Commit(ctx); err != nil {
+				return err
+			}
+			result.Accepted = true
+			return nil
+		})
+		if err != nil || !result.Accepted {
+			return err
+		}
+		s.publish(ctx, append(
+			[]*game.GameEvent{game.NewGameEvent(result.State, game.GameEventActionApplied, game.ActionAppliedEventData{
+				User:    userId,
+				Command: cmd.GetType(),
+			})},
+			game.NewGameEvents(before, result.State)...,
+		)...)
+		// The command may have been the last one the phase was waiting on
+		result.State, err = s.advanceDonePhases(ctx, result.State)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// publish pushes events to watchers of the game. The new state has already
+// been committed, so failures are logged rather than returned.
+func (s *GameRunnerService) publish(ctx context.Context, events ...*game.GameEvent) {
+	if err := s.publisher.PublishEvents(ctx, events...); err != nil {
+		s.logger.Error(ctx, "failed to publish game events", err, nil)
+	}
+}
+
+func applyCommand(ctx context.Context, player IPlayerRunnerContext, cmd game.Command) bool {
+	switch c := cmd.(type) {
+	case game.DrawCommand:
+		return player.DrawCard(ctx)
+	case game.SwapCommand:
+		return player.SwapCard(ctx, c.CardID, c.Position)
+	case game.DiscardCommand:
+		return player.DiscardCard(ctx, c.CardID)
+	case game.SelectRevealCommand:
+		player.SelectCard(c.Position)
+		return true
+	case game.PassCommand:
+		player.Pass()
+		return true
+	}
+	return false
+}
+
+func (s *GameRunnerService) newGameRunnerContext(
+	ctx context.Context,
+	gameState *game.GameState,
+) (*GameRunnerContext, error) {
+	cards, err := s.loadCards(ctx, gameState.GameStateData)
+	if err != nil {
+		return nil, err
+	}
+	return NewGameRunnerContext(
+		gameState,
+		s.gameStateRepo,
+		s.gameStateVersionRepo,
+		NewEffectResolver(ctx, s.logger, cards),
+	), nil
+}
+
+// pinCardVersions records the current version of every card in the decks so
+// that later changes to the catalog do not affect the game
+func (s *GameRunnerService) pinCardVersions(
+	ctx context.Context,
+	decks ...*card.SerializableDeck,
+) (map[card.SerializableCardID]int, error) {
+	versions := make(map[card.SerializableCardID]int)
+	for _, deck := range decks {
+		for _, cardId := range deck.CardIDs {
+			if _, ok := versions[cardId]; ok {
+				continue
+			}
+			c, err := s.cardsRepo.Get(ctx, cardId)
+			if err != nil {
+				return nil, err
+			}
+			versions[cardId] = c.GetVersion()
+		}
+	}
+	return versions, nil
+}
+
+// getCard returns the card as it was when the game started. Cards without a
+// pinned version, from games started before cards were versioned, are read
+// as they are now.
+func (s *GameRunnerService) getCard(
+	ctx context.Context,
+	data game.GameStateData,
+	cardId card.SerializableCardID,
+) (card.Card, error) {
+	if version := data.CardVersions[cardId]; version > 0 {
+		return s.cardsRepo.GetVersion(ctx, cardId, version)
+	}
+	return s.cardsRepo.Get(ctx, cardId)
+}
+
+// loadCards fetches every card that is in play so that effects can be
+// resolved without going back to the repo mid-action. The conditions of
+// their effects are compiled once here rather than on every evaluation.
+func (s *GameRunnerService) loadCards(
+	ctx context.Context,
+	data game.GameStateData,
+) (map[card.SerializableCardID]card.Card, error) {
+	cards := make(map[card.SerializableCardID]card.Card)
+	load := func(cardIds ...card.SerializableCardID) error {
+		for _, cardId := range cardIds {
+			if _, ok := cards[cardId]; ok || cardId == 0 {
+				continue
+			}
+			c, err := s.getCard(ctx, data, cardId)
+			if err != nil {
+				return err
+			}
+			// The effect is skipped when its condition fails to evaluate, so
+			// a bad condition should not stop the rest of the game
+			if err := c.CompileConditions(); err != nil {
+				s.logger.Warn(ctx, "failed to compile card effect conditions", map[string]any{
+					"cardId": cardId,
+					"error":  err.Error(),
+				})
+			}
+			cards[cardId] = c
+		}
+		return nil
+	}
+	for _, player := range data.Players {
+		if err := load(player.Deck...); err != nil {
+			return nil, err
+		}
+		if err := load(player.Hand...); err != nil {
+			return nil, err
+		}
+		if err := load(player.DiscardedCards...); err != nil {
+			return nil, err
+		}
+	}
+	for _, column := range data.Board {
+		for _, space := range column {
+			if err := load(space.Card); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return cards, nil
+}
+
+func (s *GameRunnerService) advance(ctx context.Context, runner *GameRunnerContext) error {
+	data := runner.GetGameStateData()
+	if data.IsComplete {
+		return utils.NewInvalidStateError("game is already complete")
+	}
+	next, ok := data.GamePhase.Next()
+	if !ok {
+		return utils.NewInvalidStateError("unknown game phase")
+	}
+
+	var err error
+	switch data.GamePhase {
+	case game.PhaseReveal:
+		err = s.revealSelectedCards(ctx, runner)
+	case game.PhaseWar:
+		err = s.resolveWar(ctx, runner)
+	case game.PhaseCleanup:
+		err = s.cleanupRound(ctx, runner)
+	}
+	if err != nil {
+		return err
+	}
+
+	data = runner.GetGameStateData()
+	if data.IsComplete {
+		return nil
+	}
+	data.GamePhase = next
+	data.RevealDeadline = nil
+	if next == game.PhaseReveal {
+		deadline := data.Rules.RevealDeadline(time.Now())
+		data.RevealDeadline = &deadline
+	}
+	runner.UpdateState(ctx, data)
+	return nil
+}
+
+// revealSelectedCards flips both players' selected cards face up. Revealed
+// cards are visible to both players.
+func (s *GameRunnerService) revealSelectedCards(ctx context.Context, runner *GameRunnerContext) error {
+	data := runner.GetGameStateData()
+	positions, err := selectedPositions(data)
+	if err != nil {
+		return err
+	}
+	for i, position := range positions {
+		space := data.Board[position.X][position.Y]
+		if space.Card == 0 || space.Revealed {
+			return utils.NewInvalidStateError("selected space has no face-down card")
+		}
+		if space.Owner != data.Players[i].User {
+			return utils.NewInvalidStateError("selected card is not owned by player")
+		}
+	}
+
+	for i, position := range positions {
+		runner.PlayerRunnerContext(i).RevealCard(ctx, position)
+	}
+
+	for i := range data.Players {
+		playerState := runner.GetPlayerState(i)
+		if playerState.RevealedCards == nil {
+			playerState.RevealedCards = make(map[game.Position]bool)
+		}
+		for _, position := range positions {
+			playerState.RevealedCards[position] = true
+			playerState.See(data.Board[position.X][position.Y].Card)
+		}
+		runner.UpdatePlayerState(i, playerState)
+	}
+	return nil
+}
+
+// resolveWar triggers the War effects of the revealed cards, then compares
+// their values and awards the winner the difference
+func (s *GameRunnerService) resolveWar(ctx context.Context, runner *GameRunnerContext) error {
+	data := runner.GetGameStateData()
+	positions, err := selectedPositions(data)
+	if err != nil {
+		return err
+	}
+	for i, position := range positions {
+		cardId := data.Board[position.X][position.Y].Card
+		runner.doFor(i)(cardId, card.CardEffectTypeWar, func() {})
+	}
+
+	// War effects may have swapped the selected cards
+	data = runner.GetGameStateData()
+	positions, err = selectedPositions(data)
+	if err != nil {
+		return err
+	}
+	result := game.WarResult{
+		RoundNumber: data.RoundNumber,
+		Positions:   positions,
+	}
+	for i, position := range positions {
+		cardId := data.Board[position.X][position.Y].Card
+		c, err := s.getCard(ctx, data, cardId)
+		if err != nil {
+			return err
+		}
+		result.Cards[i] = cardId
+		result.Values[i] = card.GetCardValue(c) + data.Players[i].WarValueModifier
+	}
+
+	winner, points := game.ResolveWar(result.Values)
+	if winner != game.NoWinner {
+		winnerId := data.Players[winner].User
+		result.Winner = &winnerId
+		result.Points = points
+		runner.PlayerRunnerContext(winner).AddPoints(ctx, points)
+		playerState := runner.GetPlayerState(winner)
+		playerState.RoundsWon++
+		runner.UpdatePlayerState(winner, playerState)
+	}
+
+	data = runner.GetGameStateData()
+	data.WarResults = append(data.WarResults, result)
+	runner.UpdateState(ctx, data)
+	return nil
+}
+
+// cleanupRound clears the spaces used in the War, resets the players for the
+// next turn and ends the game once the round limit is hit or the board is
+// empty
+func (s *GameRunnerService) cleanupRound(ctx context.Context, runner *GameRunnerContext) error {
+	data := runner.GetGameStateData()
+	positions, err := selectedPositions(data)
+	if err != nil {
+		return err
+	}
+
+	board := runner.BoardRunnerContext()
+	for _, position := range positions {
+		board.ClearSpace(ctx, position)
+	}
+
+	for i := range data.Players {
+		playerState := runner.GetPlayerState(i)
+		playerState.EmptySpaces = append(playerState.EmptySpaces, positions[:]...)
+		playerState.SelectedCard = nil
+		playerState.HasDrawnThisTurn = false
+		playerState.HasSwappedThisTurn = false
+		playerState.HasDiscardedThisTurn = false
+		playerState.HasPassedThisTurn = false
+		playerState.WarValueModifier = 0
+		runner.UpdatePlayerState(i, playerState)
+	}
+
+	data = runner.GetGameStateData()
+	data.RoundNumber++
+	if data.IsFinished() {
+		data.IsComplete = true
+		if leader := data.Leader(); leader != game.NoWinner {
+			winner, err := s.usersService.GetUser(ctx, data.Players[leader].User)
+			if err != nil {
+				return err
+			}
+			data.Winner = winner
+		}
+	}
+	runner.UpdateState(ctx, data)
+	return nil
+}
+
+func selectedPositions(data game.GameStateData) ([2]game.Position, error) {
+	var positions [2]game.Position
+	for i, player := range data.Players {
+		if player.SelectedCard == nil {
+			return positions, utils.NewInvalidStateError("player has not selected a card to reveal")
+		}
+		positions[i] = *player.SelectedCard
+	}
+	return positions, nil
+}
+
+func runWithoutEffects(_ card.SerializableCardID, _ card.CardEffectType, onUse func()) {
+	onUse()
+}
+
 type IGameRunnerContext interface {
 	GetGameStateData() game.GameStateData
 	GetPlayerState(playerIndex int) game.PlayerState
@@ -81,6 +730,24 @@ type GameRunnerContext struct {
 	gameState            *game.GameState
 	gameStateRepo        repos.IGameStateRepo
 	gameStateVersionRepo repos.IGameStateVersionRepo
+	effects              iEffectResolver
+}
+
+func NewGameRunnerContext(
+	gameState *game.GameState,
+	gameStateRepo repos.IGameStateRepo,
+	gameStateVersionRepo repos.IGameStateVersionRepo,
+	effects iEffectResolver,
+) *GameRunnerContext {
+	if effects == nil {
+		effects = noEffectResolver{}
+	}
+	return &GameRunnerContext{
+		gameState:            gameState,
+		gameStateRepo:        gameStateRepo,
+		gameStateVersionRepo: gameStateVersionRepo,
+		effects:              effects,
+	}
 }
 
 func (s *GameRunnerContext) GetGameStateData() game.GameStateData {
@@ -103,7 +770,11 @@ func (s *GameRunnerContext) UpdateState(ctx context.Context, state game.GameStat
 	s.gameState.GameStateData = state
 }
 
+// Commit saves the game as a new version. It fails with a conflict error if
+// the game has been committed by someone else since it was loaded; see
+// RetryOnConflict.
 func (s *GameRunnerContext) Commit(ctx context.Context) error {
+	s.gameState.Metadata.UpdatedAt = time.Now().UTC()
 	if err := s.gameStateRepo.Update(ctx, s.gameState); err != nil {
 		return err
 	}
@@ -113,8 +784,109 @@ func (s *GameRunnerContext) Commit(ctx context.Context) error {
 	return nil
 }
 
+// ResolveEffects applies any effects still waiting on the active stack
+func (s *GameRunnerContext) ResolveEffects() {
+	s.effects.ResolveStack(s)
+}
+
+// doFor returns the effect hook for cards used by the player at playerIndex
+func (s *GameRunnerContext) doFor(playerIndex int) func(card.SerializableCardID, card.CardEffectType, func()) {
+	return func(cardId card.SerializableCardID, effectType card.CardEffectType, onUse func()) {
+		s.effects.Resolve(s, playerIndex, cardId, effectType, onUse)
+	}
+}
+
+// BoardRunnerContext returns a board context for game-driven changes, such as
+// clearing spaces after a War, which do not trigger card effects
+func (s *GameRunnerContext) BoardRunnerContext() IBoardRunnerContext {
+	return s.boardRunnerContext(runWithoutEffects)
+}
+
+func (s *GameRunnerContext) boardRunnerContext(
+	do func(card.SerializableCardID, card.CardEffectType, func()),
+) IBoardRunnerContext {
+	return NewBoardRunnerContext(
+		func() game.BoardState {
+			return s.gameState.BoardState
+		},
+		func() game.GamePhase {
+			return s.gameState.GamePhase
+		},
+		func(ctx context.Context, board game.BoardState) {
+			s.gameState.BoardState = board
+		},
+		do,
+	)
+}
+
+func (s *GameRunnerContext) DeckRunnerContext(playerIndex int) IDecKRunnerContext {
+	return NewDeckRunnerContext(
+		playerIndex,
+		s.shuffle,
+		func() []card.SerializableCardID {
+			return s.gameState.Players[playerIndex].Deck
+		},
+		func(deck []card.SerializableCardID) {
+			s.gameState.Players[playerIndex].Deck = deck
+		},
+	)
+}
+
+func (s *GameRunnerContext) HandRunnerContext(playerIndex int) IHandRunnerContext {
+	return NewHandRunnerContext(
+		playerIndex,
+		s.getRules,
+		s.shuffle,
+		func() []card.SerializableCardID {
+			return s.gameState.Players[playerIndex].Hand
+		},
+		func(hand []card.SerializableCardID) {
+			s.gameState.Players[playerIndex].Hand = hand
+		},
+	)
+}
+
+func (s *GameRunnerContext) PlayerRunnerContext(playerIndex int) IPlayerRunnerContext {
+	return NewPlayerRunnerContext(
+		s.gameState.Players[playerIndex].User,
+		s.getRules,
+		func() IDecKRunnerContext {
+			return s.DeckRunnerContext(playerIndex)
+		},
+		func() IHandRunnerContext {
+			return s.HandRunnerContext(playerIndex)
+		},
+		func() IBoardRunnerContext {
+			return s.boardRunnerContext(s.doFor(playerIndex))
+		},
+		func() game.PlayerState {
+			return s.gameState.Players[playerIndex]
+		},
+		func(playerState game.PlayerState) {
+			s.gameState.Players[playerIndex] = playerState
+		},
+		s.doFor(playerIndex),
+	)
+}
+
+func (s *GameRunnerContext) getRules() game.Rules {
+	return s.gameState.Rules
+}
+
+// rand draws a source from the game's seeded random stream, for choices
+// that have to be replayable
+func (s *GameRunnerContext) rand() *mrand.Rand {
+	return s.gameState.RNG.Next()
+}
+
+// shuffle draws from the game's seeded random stream
+func (s *GameRunnerContext) shuffle(cardIds []card.SerializableCardID) []card.SerializableCardID {
+	return card.ShuffleCardsWithRand(cardIds, s.gameState.RNG.Next())
+}
+
 type IBoardRunnerContext interface {
 	PlaceCard(ctx context.Context, cardId card.SerializableCardID, playerId user.UserID, position game.Position) bool
+	ReplaceCard(ctx context.Context, cardId card.SerializableCardID, playerId user.UserID, position game.Position) (card.SerializableCardID, bool)
 	RevealCard(ctx context.Context, playerId user.UserID, position game.Position) bool
 	GetBoardState() game.BoardState
 	ClearSpace(ctx context.Context, position game.Position) bool
@@ -152,32 +924,56 @@ func (s *BoardRunnerContext) PlaceCard(
 	playerId user.UserID,
 	position game.Position,
 ) bool {
+	placed := false
 	onUse := func() {
 		boardState := s.getBoardState()
 		board := boardState.Board
-		if board[position.X][position.Y].Card != 0 {
+		if !boardState.Contains(position) {
 			return
 		}
-		for i, c := range board {
-			for j, d := range c {
-				if i == position.X && j == position.Y {
-					if board[i][j].Card != 0 {
-						return
-					}
-					if d.Owner != playerId {
-						return
-					}
-					d.Card = cardId
-					board[i][j] = d
-					break
-				}
-			}
+		d := board[position.X][position.Y]
+		if d.Card != 0 || d.Owner != playerId {
+			return
 		}
+		d.Card = cardId
+		board[position.X][position.Y] = d
 		boardState.Board = board
 		s.updateBoardState(ctx, boardState)
+		placed = true
 	}
 	s.do(cardId, card.CardEffectTypePlace, onUse)
-	return true
+	return placed
+}
+
+// ReplaceCard puts cardId in place of one of the player's face-down cards and
+// returns the card that was there
+func (s *BoardRunnerContext) ReplaceCard(
+	ctx context.Context,
+	cardId card.SerializableCardID,
+	playerId user.UserID,
+	position game.Position,
+) (card.SerializableCardID, bool) {
+	var (
+		replaced card.SerializableCardID
+		ok       bool
+	)
+	onUse := func() {
+		boardState := s.getBoardState()
+		if !boardState.Contains(position) {
+			return
+		}
+		d := boardState.Board[position.X][position.Y]
+		if d.Card == 0 || d.Revealed || d.Owner != playerId {
+			return
+		}
+		replaced = d.Card
+		d.Card = cardId
+		boardState.Board[position.X][position.Y] = d
+		s.updateBoardState(ctx, boardState)
+		ok = true
+	}
+	s.do(cardId, card.CardEffectTypePlace, onUse)
+	return replaced, ok
 }
 
 func (s *BoardRunnerContext) RevealCard(
@@ -212,6 +1008,9 @@ func (s *BoardRunnerContext) ClearSpace(ctx context.Context, position game.Posit
 	cardId := s.getBoardState().Board[position.X][position.Y].Card
 	onUse := func() {
 		boardState := s.getBoardState()
+		if boardState.ClearedSpaces == nil {
+			boardState.ClearedSpaces = make(map[game.Position]bool)
+		}
 		boardState.ClearedSpaces[position] = true
 		boardState.Board[position.X][position.Y] = game.BoardSpace{}
 		s.updateBoardState(ctx, boardState)
@@ -227,9 +1026,11 @@ func (s *BoardRunnerContext) IsEmptySpace(position game.Position) bool {
 
 type IPlayerRunnerContext interface {
 	GetPlayerID() user.UserID
-	DrawCard(ctx context.Context)
-	SwapCard(ctx context.Context, cardId card.SerializableCardID, position game.Position)
-	DiscardCard(ctx context.Context, cardId card.SerializableCardID)
+	DrawCard(ctx context.Context) bool
+	SwapCard(ctx context.Context, cardId card.SerializableCardID, position game.Position) bool
+	DiscardCard(ctx context.Context, cardId card.SerializableCardID) bool
+	SelectCard(position game.Position)
+	Pass()
 	RevealCard(ctx context.Context, position game.Position)
 	AddPoints(ctx context.Context, points int)
 	SubtractPoints(ctx context.Context, points int)
@@ -275,45 +1076,90 @@ func (s *PlayerRunnerContext) GetPlayerID() user.UserID {
 	return s.playerId
 }
 
-func (s *PlayerRunnerContext) DrawCard(ctx context.Context) {
-	topCard, ok := s.getDeck().Peek(ctx)
-	if !ok {
-		return
+// DrawCard draws up to the game's DrawLimit cards from the top of the deck,
+// stopping early if the hand fills up or the deck runs out
+func (s *PlayerRunnerContext) DrawCard(ctx context.Context) bool {
+	if s.getPlayerState().HasDrawnThisTurn {
+		return false
 	}
-	onUse := func() {
-		if s.getPlayerState().HasDrawnThisTurn {
-			return
+	drawn := 0
+	for drawn < s.getRules().DrawLimit {
+		topCard, ok := s.getDeck().Peek(ctx)
+		if !ok {
+			break
 		}
-		hand := s.getHand().GetHand()
-		rules := s.getRules()
-		if len(hand) >= rules.HandLimit {
-			return
+		taken := false
+		onUse := func() {
+			hand := s.getHand().GetHand()
+			rules := s.getRules()
+			if len(hand) >= rules.HandLimit {
+				return
+			}
+			s.getDeck().Pop(ctx)
+			s.getHand().AddCard(topCard)
+			playerState := s.getPlayerState()
+			playerState.LastDrawnCard = topCard
+			s.updatePlayerState(playerState)
+			taken = true
 		}
-		s.getDeck().Pop(ctx)
-		s.getHand().AddCard(topCard)
-		playerState := s.getPlayerState()
-		playerState.LastDrawnCard = topCard
-		playerState.HasDrawnThisTurn = true
-		s.updatePlayerState(playerState)
+		s.do(topCard, card.CardEffectTypeDraw, onUse)
+		if !taken {
+			break
+		}
+		drawn++
 	}
-	s.do(topCard, card.CardEffectTypeDraw, onUse)
+	if drawn == 0 {
+		return false
+	}
+	playerState := s.getPlayerState()
+	playerState.HasDrawnThisTurn = true
+	s.updatePlayerState(playerState)
+	return true
 }
 
+// SwapCard exchanges a card in the hand with one of the player's face-down
+// cards on the board
 func (s *PlayerRunnerContext) SwapCard(
 	ctx context.Context,
 	cardId card.SerializableCardID,
 	position game.Position,
-) {
+) bool {
+	swapped := false
 	onUse := func() {
 		hand := s.getHand()
+		if !slices.Contains(hand.GetHand(), cardId) {
+			return
+		}
 		hand.RemoveCard(cardId)
-		s.getBoard().PlaceCard(ctx, cardId, s.playerId, position)
+		replaced, ok := s.getBoard().ReplaceCard(ctx, cardId, s.playerId, position)
+		if !ok {
+			hand.AddCard(cardId)
+			return
+		}
+		hand.AddCard(replaced)
 		playerState := s.getPlayerState()
 		playerState.LastPlacedCard = cardId
+		playerState.See(cardId)
 		playerState.HasSwappedThisTurn = true
 		s.updatePlayerState(playerState)
+		swapped = true
 	}
 	s.do(cardId, card.CardEffectTypeSwap, onUse)
+	return swapped
+}
+
+// SelectCard chooses the face-down card the player will reveal for the War
+func (s *PlayerRunnerContext) SelectCard(position game.Position) {
+	playerState := s.getPlayerState()
+	playerState.SelectedCard = &position
+	s.updatePlayerState(playerState)
+}
+
+// Pass ends the player's card action turn without acting
+func (s *PlayerRunnerContext) Pass() {
+	playerState := s.getPlayerState()
+	playerState.HasPassedThisTurn = true
+	s.updatePlayerState(playerState)
 }
 
 func (s *PlayerRunnerContext) AddCardFromBoardToHand(
@@ -332,8 +1178,12 @@ func (s *PlayerRunnerContext) AddCardFromBoardToHand(
 	s.do(cardId, card.CardEffectTypeDraw, onUse)
 }
 
-func (s *PlayerRunnerContext) DiscardCard(ctx context.Context, cardId card.SerializableCardID) {
+func (s *PlayerRunnerContext) DiscardCard(ctx context.Context, cardId card.SerializableCardID) bool {
+	discarded := false
 	onUse := func() {
+		if !slices.Contains(s.getHand().GetHand(), cardId) {
+			return
+		}
 		s.getHand().RemoveCard(cardId)
 		playerState := s.getPlayerState()
 		newDiscarded := make([]card.SerializableCardID, len(playerState.DiscardedCards)+1)
@@ -343,8 +1193,10 @@ func (s *PlayerRunnerContext) DiscardCard(ctx context.Context, cardId card.Seria
 		playerState.LastDiscardedCard = cardId
 		playerState.HasDiscardedThisTurn = true
 		s.updatePlayerState(playerState)
+		discarded = true
 	}
 	s.do(cardId, card.CardEffectTypeDiscard, onUse)
+	return discarded
 }
 
 func (s *PlayerRunnerContext) AddDiscardedCardToHand(ctx context.Context, cardId card.SerializableCardID) {
@@ -431,17 +1283,20 @@ type IDecKRunnerContext interface {
 
 type DeckRunnerContext struct {
 	playerIdx  int
+	shuffle    func([]card.SerializableCardID) []card.SerializableCardID
 	getDeck    func() []card.SerializableCardID
 	updateDeck func(deck []card.SerializableCardID)
 }
 
 func NewDeckRunnerContext(
 	playerIdx int,
+	shuffle func([]card.SerializableCardID) []card.SerializableCardID,
 	getDeck func() []card.SerializableCardID,
 	updateDeck func(deck []card.SerializableCardID),
 ) IDecKRunnerContext {
 	return &DeckRunnerContext{
 		playerIdx:  playerIdx,
+		shuffle:    shuffle,
 		getDeck:    getDeck,
 		updateDeck: updateDeck,
 	}
@@ -537,7 +1392,7 @@ func (s *DeckRunnerContext) PushBottom(ctx context.Context, cardId card.Serializ
 
 func (s *DeckRunnerContext) Shuffle() {
 	deck := s.getDeck()
-	shuffled := card.ShuffleCards(deck)
+	shuffled := s.shuffle(deck)
 	s.updateDeck(shuffled)
 }
 
@@ -551,6 +1406,7 @@ type IHandRunnerContext interface {
 type HandRunnerContext struct {
 	playerIdx  int
 	getRules   func() game.Rules
+	shuffle    func([]card.SerializableCardID) []card.SerializableCardID
 	getHand    func() []card.SerializableCardID
 	updateHand func(hand []card.SerializableCardID)
 }
@@ -558,12 +1414,14 @@ type HandRunnerContext struct {
 func NewHandRunnerContext(
 	playerIdx int,
 	getRules func() game.Rules,
+	shuffle func([]card.SerializableCardID) []card.SerializableCardID,
 	getHand func() []card.SerializableCardID,
 	updateHand func(hand []card.SerializableCardID),
 ) IHandRunnerContext {
 	return &HandRunnerContext{
 		playerIdx:  playerIdx,
 		getRules:   getRules,
+		shuffle:    shuffle,
 		getHand:    getHand,
 		updateHand: updateHand,
 	}
@@ -585,21 +1443,30 @@ func (s *HandRunnerContext) AddCard(cardId card.SerializableCardID) {
 	s.updateHand(newHand)
 }
 
+// RemoveCard removes a single copy of cardId from the hand
 func (s *HandRunnerContext) RemoveCard(cardId card.SerializableCardID) {
 	hand := s.getHand()
-	if len(hand) == 0 {
+	i := slices.Index(hand, cardId)
+	if i == -1 {
+		return
 	}
 	newHand := make([]card.SerializableCardID, 0, len(hand)-1)
-	for _, c := range hand {
-		if c != cardId {
-			newHand = append(newHand, c)
-		}
-	}
+	newHand = append(newHand, hand[:i]...)
+	newHand = append(newHand, hand[i+1:]...)
 	s.updateHand(newHand)
 }
 
 func (s *HandRunnerContext) ShuffleHand() {
 	hand := s.getHand()
-	shuffled := card.ShuffleCards(hand)
+	shuffled := s.shuffle(hand)
 	s.updateHand(shuffled)
 }
+
+type iUsersService interface {
+	GetUser(ctx context.Context, userId user.UserID) (*user.User, error)
+	CreateUserWithId(ctx context.Context, id user.UserID, data user.UserData) (*user.User, error)
+}
+
+type iGameEventsPublisher interface {
+	PublishEvents(ctx context.Context, events ...*game.GameEvent) error
+}
@@ -0,0 +1,357 @@
+package game_test
+
+import (
+	"context"
+	"io"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/user"
+	gamerunnerservice "github.com/coopersmall/subswag/services/game"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type GameRunnerServiceTestSuite struct {
+	suite.Suite
+}
+
+func TestGameRunnerServiceSuite(t *testing.T) {
+	suite.Run(t, new(GameRunnerServiceTestSuite))
+}
+
+// runnerFixture is a game runner backed by in-memory stores, with what it
+// saved and published kept for the tests to check
+type runnerFixture struct {
+	service  *gamerunnerservice.GameRunnerService
+	replay   *gamerunnerservice.GameReplayService
+	states   *fakeGameStates
+	versions *fakeVersions
+	timers   *fakeTimers
+	events   *fakeEvents
+}
+
+func newRunnerFixture(decks ...*card.SerializableDeck) *runnerFixture {
+	f := &runnerFixture{
+		states:   &fakeGameStates{states: make(map[game.GameStateID]*game.GameState)},
+		versions: &fakeVersions{},
+		timers:   &fakeTimers{},
+		events:   &fakeEvents{},
+	}
+	cards := &fakeCards{cards: make(map[card.SerializableCardID]card.Card)}
+	users := &fakeUsers{users: make(map[user.UserID]*user.User)}
+	for _, deck := range decks {
+		u := user.NewUser()
+		u.ID = deck.UserID
+		users.users[u.ID] = u
+		for _, cardId := range deck.CardIDs {
+			cards.cards[cardId] = newNumberCard(cardId)
+		}
+	}
+	f.service = gamerunnerservice.NewGameRunnerService(
+		utils.NewLogger("game-runner", utils.WithWriter(io.Discard)),
+		apm.NewNoopTracer(),
+		f.states,
+		f.versions,
+		f.timers,
+		cards,
+		func(ctx context.Context, userId user.UserID, deckId card.SerializableDeckID) (*card.SerializableDeck, error) {
+			for _, deck := range decks {
+				if deck.ID == deckId && deck.UserID == userId {
+					return deck, nil
+				}
+			}
+			return nil, utils.NewNotFoundError("deck not found")
+		},
+		users,
+		f.events,
+	)
+	f.replay = gamerunnerservice.NewGameReplayService(
+		utils.NewLogger("game-replay", utils.WithWriter(io.Discard)),
+		apm.NewNoopTracer(),
+		f.states,
+		f.versions,
+		f.timers,
+		f.events,
+	)
+	return f
+}
+
+// newNumberCard returns a number card worth its ID modulo 100, so decks can
+// be built with known War values
+func newNumberCard(cardId card.SerializableCardID) card.Card {
+	c := &card.SerializableNumberCard{Type: card.SerializableCardTypeNumber, Number: int(cardId % 100)}
+	c.ID = cardId
+	c.Metadata = domain.NewMetadata()
+	return c
+}
+
+// newTestDeck returns a deck of count copies of a card worth value
+func newTestDeck(deckId card.SerializableDeckID, userId user.UserID, value int, count int) *card.SerializableDeck {
+	data := card.SerializableDeckData{UserID: userId, Name: "test"}
+	for i := 1; i <= count; i++ {
+		data.CardIDs = append(data.CardIDs, card.SerializableCardID(i*100+value))
+	}
+	return card.NewDeck(deckId, data)
+}
+
+// fakeGameStates keeps copies of the games, like a database would, and
+// rejects writes from an out of date version
+type fakeGameStates struct {
+	states map[game.GameStateID]*game.GameState
+}
+
+func copyGameState(gameState *game.GameState) *game.GameState {
+	copied := *gameState
+	copied.GameStateData = gameState.GameStateData.Clone()
+	metadata := *gameState.Metadata
+	copied.Metadata = &metadata
+	return &copied
+}
+
+func (f *fakeGameStates) Get(ctx context.Context, gameStateId game.GameStateID) (*game.GameState, error) {
+	gameState, ok := f.states[gameStateId]
+	if !ok {
+		return nil, utils.NewNotFoundError("game state not found")
+	}
+	return copyGameState(gameState), nil
+}
+
+func (f *fakeGameStates) All(ctx context.Context) ([]*game.GameState, error) {
+	return slices.Collect(maps.Values(f.states)), nil
+}
+
+func (f *fakeGameStates) Create(ctx context.Context, gameState *game.GameState) error {
+	f.states[gameState.ID] = copyGameState(gameState)
+	return nil
+}
+
+func (f *fakeGameStates) Update(ctx context.Context, gameState *game.GameState) error {
+	stored, ok := f.states[gameState.ID]
+	if !ok {
+		return utils.NewNotFoundError("game state not found")
+	}
+	if stored.Version != gameState.Version {
+		return utils.NewConflictError("game state was updated since it was read")
+	}
+	gameState.Version++
+	f.states[gameState.ID] = copyGameState(gameState)
+	return nil
+}
+
+func (f *fakeGameStates) Delete(ctx context.Context, gameStateId game.GameStateID) error {
+	delete(f.states, gameStateId)
+	return nil
+}
+
+type fakeVersions struct {
+	versions []*game.GameStateVersion
+}
+
+func (f *fakeVersions) Get(ctx context.Context, versionId game.GameStateVersionID) (*game.GameStateVersion, error) {
+	for _, version := range f.versions {
+		if version.ID == versionId {
+			return version, nil
+		}
+	}
+	return nil, utils.NewNotFoundError("version not found")
+}
+
+func (f *fakeVersions) Create(ctx context.Context, version *game.GameStateVersion) error {
+	f.versions = append(f.versions, &game.GameStateVersion{
+		ID:       version.ID,
+		State:    copyGameState(version.State),
+		Metadata: version.Metadata,
+	})
+	return nil
+}
+
+func (f *fakeVersions) GetVersionsPage(ctx context.Context, gameStateId game.GameStateID, limit int, offset int) ([]*game.GameStateVersion, error) {
+	if offset >= len(f.versions) {
+		return []*game.GameStateVersion{}, nil
+	}
+	return f.versions[offset:min(offset+limit, len(f.versions))], nil
+}
+
+func (f *fakeVersions) GetVersionByNumber(ctx context.Context, gameStateId game.GameStateID, number int) (*game.GameStateVersion, error) {
+	if number < 0 || number >= len(f.versions) {
+		return nil, utils.NewNotFoundError("version not found")
+	}
+	return f.versions[number], nil
+}
+
+func (f *fakeVersions) GetLatestVersion(ctx context.Context, gameStateId game.GameStateID) (*game.GameStateVersion, error) {
+	if len(f.versions) == 0 {
+		return nil, utils.NewNotFoundError("version not found")
+	}
+	return f.versions[len(f.versions)-1], nil
+}
+
+func (f *fakeVersions) GetLatestVersionBefore(ctx context.Context, gameStateId game.GameStateID, cutoff time.Time) (*game.GameStateVersion, int, error) {
+	due := 0
+	for _, version := range f.versions {
+		if !version.Metadata.CreatedAt.After(cutoff) {
+			due++
+		}
+	}
+	if due == 0 {
+		return nil, 0, utils.NewNotFoundError("version not found")
+	}
+	return f.versions[due-1], due, nil
+}
+
+func (f *fakeVersions) GetVersionsBetween(ctx context.Context, gameStateId game.GameStateID, since time.Time, cutoff time.Time) ([]*game.GameStateVersion, error) {
+	between := make([]*game.GameStateVersion, 0)
+	for _, version := range f.versions {
+		if version.Metadata.CreatedAt.After(since) && !version.Metadata.CreatedAt.After(cutoff) {
+			between = append(between, version)
+		}
+	}
+	return between, nil
+}
+
+// phases lists the phase of every committed version, oldest first
+func (f *fakeVersions) phases() []game.GamePhase {
+	phases := make([]game.GamePhase, len(f.versions))
+	for i, version := range f.versions {
+		phases[i] = version.State.GamePhase
+	}
+	return phases
+}
+
+// fakeTimers hands out every scheduled timer that has not been completed,
+// whatever its deadline
+type fakeTimers struct {
+	scheduled []*game.RevealTimer
+	completed []*game.RevealTimer
+}
+
+func (f *fakeTimers) Schedule(ctx context.Context, timer *game.RevealTimer) error {
+	f.scheduled = append(f.scheduled, timer)
+	return nil
+}
+
+func (f *fakeTimers) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*game.RevealTimer, error) {
+	due := make([]*game.RevealTimer, 0)
+	for _, timer := range f.scheduled {
+		if !slices.Contains(f.completed, timer) {
+			due = append(due, timer)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeTimers) Complete(ctx context.Context, timer *game.RevealTimer) error {
+	f.completed = append(f.completed, timer)
+	return nil
+}
+
+type fakeCards struct {
+	cards map[card.SerializableCardID]card.Card
+}
+
+func (f *fakeCards) Get(ctx context.Context, cardId card.SerializableCardID) (card.Card, error) {
+	c, ok := f.cards[cardId]
+	if !ok {
+		return nil, utils.NewNotFoundError("card not found")
+	}
+	return c, nil
+}
+
+func (f *fakeCards) All(ctx context.Context) ([]card.Card, error) {
+	return slices.Collect(maps.Values(f.cards)), nil
+}
+
+func (f *fakeCards) GetMany(ctx context.Context, cardIds []card.SerializableCardID) ([]card.Card, error) {
+	found := []card.Card{}
+	for _, cardId := range cardIds {
+		if c, ok := f.cards[cardId]; ok {
+			found = append(found, c)
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeCards) GetIDsByRarity(ctx context.Context, rarities []card.CardRarity) (map[card.CardRarity][]card.SerializableCardID, error) {
+	byRarity := map[card.CardRarity][]card.SerializableCardID{}
+	for _, c := range f.cards {
+		if rarity := card.CardData(c).Rarity; slices.Contains(rarities, rarity) {
+			byRarity[rarity] = append(byRarity[rarity], c.GetID())
+		}
+	}
+	return byRarity, nil
+}
+
+func (f *fakeCards) Create(ctx context.Context, c card.Card) error {
+	f.cards[c.GetID()] = c
+	return nil
+}
+
+func (f *fakeCards) Update(ctx context.Context, c card.Card) error {
+	f.cards[c.GetID()] = c
+	return nil
+}
+
+func (f *fakeCards) Delete(ctx context.Context, cardId card.SerializableCardID) error {
+	delete(f.cards, cardId)
+	return nil
+}
+
+func (f *fakeCards) GetVersion(ctx context.Context, cardId card.SerializableCardID, version int) (card.Card, error) {
+	return f.Get(ctx, cardId)
+}
+
+func (f *fakeCards) CreateVersion(ctx context.Context, c card.Card) error {
+	return nil
+}
+
+func (f *fakeCards) SaveMany(ctx context.Context, cards []card.Card) error {
+	for _, c := range cards {
+		f.cards[c.GetID()] = c
+	}
+	return nil
+}
+
+type fakeUsers struct {
+	users map[user.UserID]*user.User
+}
+
+func (f *fakeUsers) GetUser(ctx context.Context, userId user.UserID) (*user.User, error) {
+	u, ok := f.users[userId]
+	if !ok {
+		return nil, utils.NewNotFoundError("user not found")
+	}
+	return u, nil
+}
+
+func (f *fakeUsers) CreateUserWithId(ctx context.Context, id user.UserID, data user.UserData) (*user.User, error) {
+	u := user.NewUser()
+	u.ID = id
+	u.UserData = data
+	f.users[id] = u
+	return u, nil
+}
+
+type fakeEvents struct {
+	events []*game.GameEvent
+}
+
+func (f *fakeEvents) PublishEvents(ctx context.Context, events ...*game.GameEvent) error {
+	f.events = append(f.events, events...)
+	return nil
+}
+
+// types lists the type of every published event, oldest first
+func (f *fakeEvents) types() []game.GameEventType {
+	types := make([]game.GameEventType, len(f.events))
+	for i, event := range f.events {
+		types[i] = event.Type
+	}
+	return types
+}
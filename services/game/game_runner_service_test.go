@@ -0,0 +1,266 @@
+package game_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/user"
+	gamerunnerservice "github.com/coopersmall/subswag/services/game"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	ctx = context.Background()
+
+	// Clear of the IDs reserved for bots
+	player1 = user.UserID(1001)
+	player2 = user.UserID(1002)
+)
+
+// startGame starts a classic game between a deck of 2s and a deck of 10s
+func startGame(s *GameRunnerServiceTestSuite, rules game.RulesPreset) (*runnerFixture, *game.GameState) {
+	deck1 := newTestDeck(1, player1, 2, 20)
+	deck2 := newTestDeck(2, player2, 10, 20)
+	f := newRunnerFixture(deck1, deck2)
+	seed := int64(1)
+	req := gamerunnerservice.StartGameRequest{Seed: &seed, Rules: rules}
+	req.Player1.UserID = player1
+	req.Player1.DeckID = deck1.ID
+	req.Player2.UserID = player2
+	req.Player2.DeckID = deck2.ID
+	gameState, err := f.service.InitializeGame(ctx, req)
+	require.NoError(s.T(), err)
+	return f, gameState
+}
+
+// faceDown returns the first of the player's face-down cards
+func faceDown(s *GameRunnerServiceTestSuite, gameState *game.GameState, userId user.UserID) game.Position {
+	for x, column := range gameState.Board {
+		for y, space := range column {
+			if space.Card != 0 && !space.Revealed && space.Owner == userId {
+				return game.Position{X: x, Y: y}
+			}
+		}
+	}
+	s.FailNow("player has no face-down card")
+	return game.Position{}
+}
+
+func (s *GameRunnerServiceTestSuite) apply(f *runnerFixture, gameState *game.GameState, userId user.UserID, cmd game.Command) *game.GameState {
+	result, err := f.service.ApplyCommand(ctx, gameState.ID, userId, cmd)
+	require.NoError(s.T(), err)
+	require.True(s.T(), result.Accepted, "%+v", result.Rejection)
+	return result.State
+}
+
+func (s *GameRunnerServiceTestSuite) TestApplyCommand() {
+	s.Run("should open the game in the card action phase", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		assert.Equal(s.T(), game.PhaseCardAction, gameState.GamePhase)
+		assert.Equal(s.T(), []game.GamePhase{game.PhaseSetup, game.PhaseCardAction}, f.versions.phases())
+	})
+
+	s.Run("should play a full round as the players act", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+
+		gameState = s.apply(f, gameState, player1, game.DrawCommand{Type: game.CommandTypeDraw})
+		assert.Equal(s.T(), game.PhaseCardAction, gameState.GamePhase, "the phase waits on the other player")
+
+		gameState = s.apply(f, gameState, player2, game.PassCommand{Type: game.CommandTypePass})
+		require.Equal(s.T(), game.PhaseReveal, gameState.GamePhase)
+		require.NotNil(s.T(), gameState.RevealDeadline)
+		require.Len(s.T(), f.timers.scheduled, 1)
+		assert.Equal(s.T(), gameState.RoundNumber, f.timers.scheduled[0].RoundNumber)
+
+		selected1 := faceDown(s, gameState, player1)
+		selected2 := faceDown(s, gameState, player2)
+		gameState = s.apply(f, gameState, player1, game.SelectRevealCommand{Type: game.CommandTypeSelectReveal, Position: selected1})
+		assert.Equal(s.T(), game.PhaseReveal, gameState.GamePhase, "the phase waits on the other player")
+		gameState = s.apply(f, gameState, player2, game.SelectRevealCommand{Type: game.CommandTypeSelectReveal, Position: selected2})
+
+		assert.Equal(s.T(), game.PhaseCardAction, gameState.GamePhase)
+		assert.Equal(s.T(), 1, gameState.RoundNumber)
+		require.Len(s.T(), gameState.WarResults, 1)
+		assert.Equal(s.T(), &player2, gameState.WarResults[0].Winner)
+		assert.Equal(s.T(), 8, gameState.Players[1].Points)
+		assert.True(s.T(), gameState.ClearedSpaces[selected1])
+		assert.True(s.T(), gameState.ClearedSpaces[selected2])
+		for _, player := range gameState.Players {
+			assert.False(s.T(), player.HasActed())
+			assert.Nil(s.T(), player.SelectedCard)
+		}
+
+		assert.Equal(s.T(), []game.GamePhase{
+			game.PhaseSetup,
+			game.PhaseCardAction,
+			game.PhaseCardAction, // draw
+			game.PhaseCardAction, // pass
+			game.PhaseReveal,
+			game.PhaseReveal, // first selection
+			game.PhaseReveal, // second selection
+			game.PhaseWar,
+			game.PhaseCleanup,
+			game.PhaseCardAction,
+		}, f.versions.phases())
+		assert.Contains(s.T(), f.events.types(), game.GameEventWarResolved)
+	})
+
+	s.Run("should reject commands for another phase", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		result, err := f.service.ApplyCommand(ctx, gameState.ID, player1, game.SelectRevealCommand{
+			Type:     game.CommandTypeSelectReveal,
+			Position: faceDown(s, gameState, player1),
+		})
+		require.NoError(s.T(), err)
+		assert.False(s.T(), result.Accepted)
+		assert.Equal(s.T(), game.RejectionWrongPhase, result.Rejection.Reason)
+	})
+
+	s.Run("should finish the round when the reveal window expires", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		gameState = s.apply(f, gameState, player1, game.PassCommand{Type: game.CommandTypePass})
+		gameState = s.apply(f, gameState, player2, game.PassCommand{Type: game.CommandTypePass})
+		require.Equal(s.T(), game.PhaseReveal, gameState.GamePhase)
+
+		require.NoError(s.T(), f.service.ExpireRevealTimers(ctx))
+		gameState, err := f.service.GetGame(ctx, gameState.ID)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), game.PhaseCardAction, gameState.GamePhase)
+		assert.Equal(s.T(), 1, gameState.RoundNumber)
+		assert.Len(s.T(), gameState.WarResults, 1)
+		assert.Len(s.T(), f.timers.completed, 1)
+	})
+}
+
+// playRound has both players pass and reveal their first face-down card
+func (s *GameRunnerServiceTestSuite) playRound(f *runnerFixture, gameState *game.GameState) *game.GameState {
+	gameState = s.apply(f, gameState, player1, game.PassCommand{Type: game.CommandTypePass})
+	gameState = s.apply(f, gameState, player2, game.PassCommand{Type: game.CommandTypePass})
+	gameState = s.apply(f, gameState, player1, game.SelectRevealCommand{Type: game.CommandTypeSelectReveal, Position: faceDown(s, gameState, player1)})
+	return s.apply(f, gameState, player2, game.SelectRevealCommand{Type: game.CommandTypeSelectReveal, Position: faceDown(s, gameState, player2)})
+}
+
+func (s *GameRunnerServiceTestSuite) TestAdvancePhase() {
+	s.Run("should force the game out of its phase and open the reveal window", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		gameState, err := f.service.AdvancePhase(ctx, gameState.ID)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), game.PhaseReveal, gameState.GamePhase)
+		require.NotNil(s.T(), gameState.RevealDeadline)
+		assert.WithinDuration(s.T(), gameState.Rules.RevealDeadline(time.Now()), *gameState.RevealDeadline, time.Second)
+		assert.Len(s.T(), f.timers.scheduled, 1)
+		assert.Equal(s.T(), []game.GamePhase{game.PhaseSetup, game.PhaseCardAction, game.PhaseReveal}, f.versions.phases())
+	})
+
+	s.Run("should not reveal until both players have selected a card", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		gameState, err := f.service.AdvancePhase(ctx, gameState.ID)
+		require.NoError(s.T(), err)
+		_, err = f.service.AdvancePhase(ctx, gameState.ID)
+		assert.True(s.T(), utils.IsInvalidStateError(err), err)
+		gameState, err = f.service.GetGame(ctx, gameState.ID)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), game.PhaseReveal, gameState.GamePhase)
+	})
+
+	s.Run("should reveal the selected cards and award the War to the higher value", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		f.states.states[gameState.ID].Players[0].WarValueModifier = 9
+		selected := [2]game.Position{faceDown(s, gameState, player1), faceDown(s, gameState, player2)}
+		gameState = s.playRound(f, gameState)
+
+		require.Len(s.T(), gameState.WarResults, 1)
+		result := gameState.WarResults[0]
+		assert.Equal(s.T(), 0, result.RoundNumber)
+		assert.Equal(s.T(), selected, result.Positions)
+		assert.Equal(s.T(), [2]int{11, 10}, result.Values)
+		assert.Equal(s.T(), &player1, result.Winner)
+		assert.Equal(s.T(), 1, result.Points)
+		assert.Equal(s.T(), 1, gameState.Players[0].Points)
+		assert.Equal(s.T(), 1, gameState.Players[0].RoundsWon)
+		assert.Equal(s.T(), 0, gameState.Players[1].RoundsWon)
+
+		war := f.versions.versions[len(f.versions.versions)-3].State
+		require.Equal(s.T(), game.PhaseWar, war.GamePhase)
+		for i, position := range selected {
+			assert.True(s.T(), war.Board[position.X][position.Y].Revealed)
+			assert.True(s.T(), war.Players[i].RevealedCards[selected[1-i]], "each player sees the other's card")
+			other := selected[1-i]
+			assert.True(s.T(), war.Players[i].SeenCards[war.Board[other.X][other.Y].Card])
+		}
+	})
+
+	s.Run("should award nothing for a tied War", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		f.states.states[gameState.ID].Players[0].WarValueModifier = 8
+		gameState = s.playRound(f, gameState)
+
+		require.Len(s.T(), gameState.WarResults, 1)
+		assert.Nil(s.T(), gameState.WarResults[0].Winner)
+		assert.Equal(s.T(), 0, gameState.WarResults[0].Points)
+		for _, player := range gameState.Players {
+			assert.Equal(s.T(), 0, player.Points)
+			assert.Equal(s.T(), 0, player.RoundsWon)
+		}
+	})
+
+	s.Run("should clear the War's spaces and reset the players for the next round", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		f.states.states[gameState.ID].Players[0].WarValueModifier = 3
+		selected := [2]game.Position{faceDown(s, gameState, player1), faceDown(s, gameState, player2)}
+		gameState = s.playRound(f, gameState)
+
+		assert.Equal(s.T(), game.PhaseCardAction, gameState.GamePhase)
+		assert.Equal(s.T(), 1, gameState.RoundNumber)
+		assert.Nil(s.T(), gameState.RevealDeadline)
+		for _, position := range selected {
+			assert.Equal(s.T(), game.BoardSpace{}, gameState.Board[position.X][position.Y])
+			assert.True(s.T(), gameState.ClearedSpaces[position])
+		}
+		for _, player := range gameState.Players {
+			assert.Nil(s.T(), player.SelectedCard)
+			assert.False(s.T(), player.HasActed())
+			assert.Equal(s.T(), 0, player.WarValueModifier)
+			assert.ElementsMatch(s.T(), selected[:], player.EmptySpaces)
+		}
+	})
+
+	s.Run("should end the game at the round limit", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		f.states.states[gameState.ID].Rules.RoundLimit = 2
+		gameState = s.playRound(f, gameState)
+		require.False(s.T(), gameState.IsComplete)
+		gameState = s.playRound(f, gameState)
+
+		assert.True(s.T(), gameState.IsComplete)
+		assert.Equal(s.T(), game.PhaseCleanup, gameState.GamePhase)
+		require.NotNil(s.T(), gameState.Winner)
+		assert.Equal(s.T(), player2, gameState.Winner.ID)
+		assert.Equal(s.T(), 16, gameState.Players[1].Points)
+		// The game stays in cleanup once the round that ends it is cleared
+		phases := f.versions.phases()
+		assert.Equal(s.T(), []game.GamePhase{game.PhaseWar, game.PhaseCleanup, game.PhaseCleanup}, phases[len(phases)-3:])
+		assert.True(s.T(), f.versions.versions[len(phases)-1].State.IsComplete)
+		assert.Contains(s.T(), f.events.types(), game.GameEventGameCompleted)
+
+		_, err := f.service.AdvancePhase(ctx, gameState.ID)
+		assert.True(s.T(), utils.IsInvalidStateError(err), err)
+		result, err := f.service.ApplyCommand(ctx, gameState.ID, player1, game.PassCommand{Type: game.CommandTypePass})
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), game.RejectionGameComplete, result.Rejection.Reason)
+	})
+
+	s.Run("should be a draw when the players have won as many rounds", func() {
+		f, gameState := startGame(s, game.RulesPresetClassic)
+		stored := f.states.states[gameState.ID]
+		stored.Rules.RoundLimit = 1
+		stored.Players[0].WarValueModifier = 8
+		gameState = s.playRound(f, gameState)
+
+		assert.True(s.T(), gameState.IsComplete)
+		assert.Nil(s.T(), gameState.Winner)
+	})
+}
@@ -0,0 +1,39 @@
+package game
+
+import (
+	"context"
+
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/repos"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// maxCommitAttempts bounds how many times a change is retried when other
+// writers keep committing the game first
+const maxCommitAttempts = 5
+
+// RetryOnConflict loads the game and passes it to update, which applies a
+// change and commits it. When the commit loses a race with another writer,
+// such as the other player acting on a different API instance, the game is
+// loaded again and update is run against the fresh state. update must
+// therefore make all of its decisions from the state it is given.
+func RetryOnConflict(
+	ctx context.Context,
+	gameStateRepo repos.IGameStateRepo,
+	gameStateId game.GameStateID,
+	update func(gameState *game.GameState) error,
+) error {
+	var err error
+	for range maxCommitAttempts {
+		var gameState *game.GameState
+		gameState, err = gameStateRepo.Get(ctx, gameStateId)
+		if err != nil {
+			return err
+		}
+		err = update(gameState)
+		if !utils.IsConflictError(err) {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,368 @@
+package lobby
+
+import (
+	"context"
+	"time"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/cache"
+	"github.com/coopersmall/subswag/domain/bot"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/lobby"
+	"github.com/coopersmall/subswag/domain/user"
+	gamerunnerservice "github.com/coopersmall/subswag/services/game"
+	"github.com/coopersmall/subswag/utils"
+)
+
+const inviteCodeAttempts = 3
+
+// LobbyService pairs players into games, either from the public matchmaking
+// queue or through private lobbies joined with an invite code
+type LobbyService struct {
+	logger            utils.ILogger
+	tracer            apm.ITracer
+	lobbyCache        cache.ILobbyCache
+	getMode           func() (lobby.MatchmakingMode, error)
+	getDeck           func(ctx context.Context, userId user.UserID, deckId card.SerializableDeckID) (*card.SerializableDeck, error)
+	getRating         func(ctx context.Context, userId user.UserID) (float64, error)
+	gameRunnerService iGameRunnerService
+}
+
+func NewLobbyService(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	lobbyCache cache.ILobbyCache,
+	getMode func() (lobby.MatchmakingMode, error),
+	getDeck func(ctx context.Context, userId user.UserID, deckId card.SerializableDeckID) (*card.SerializableDeck, error),
+	getRating func(ctx context.Context, userId user.UserID) (float64, error),
+	gameRunnerService iGameRunnerService,
+) *LobbyService {
+	return &LobbyService{
+		logger:            logger,
+		tracer:            tracer,
+		lobbyCache:        lobbyCache,
+		getMode:           getMode,
+		getDeck:           getDeck,
+		getRating:         getRating,
+		gameRunnerService: gameRunnerService,
+	}
+}
+
+// JoinQueue puts the player in the matchmaking queue with their chosen deck.
+// If a match forms the game is created straight away.
+func (s *LobbyService) JoinQueue(
+	ctx context.Context,
+	userId user.UserID,
+	deckId card.SerializableDeckID,
+) (*lobby.QueueStatus, error) {
+	var (
+		status *lobby.QueueStatus
+		err    error
+	)
+	s.tracer.Trace(ctx, "join-queue", func(ctx context.Context, span apm.ISpan) error {
+		if _, err = s.getDeck(ctx, userId, deckId); err != nil {
+			return err
+		}
+		var rating float64
+		if rating, err = s.getRating(ctx, userId); err != nil {
+			return err
+		}
+		if err = s.lobbyCache.DeleteMatch(ctx, userId); err != nil {
+			return err
+		}
+
+		entry := lobby.QueueEntry{
+			UserID:     userId,
+			DeckID:     deckId,
+			Rating:     rating,
+			EnqueuedAt: time.Now(),
+		}
+		err = s.lobbyCache.UpdateQueue(ctx, func(queue *lobby.Queue) error {
+			if !queue.Add(entry) {
+				return utils.NewAlreadyExistsError("already in the queue")
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if err = s.MatchQueue(ctx); err != nil {
+			return err
+		}
+		status, err = s.QueueStatus(ctx, userId)
+		return err
+	})
+	return status, err
+}
+
+// MatchQueue starts a game for every pair the queue can make. It runs when a
+// player joins, and again on a timer as waiting players' rating windows
+// widen. Players whose game could not be created sit out the rest of the
+// round and are then put back in their old place, so one broken pair cannot
+// hold up everyone behind them.
+func (s *LobbyService) MatchQueue(ctx context.Context) error {
+	var err error
+	s.tracer.Trace(ctx, "match-queue", func(ctx context.Context, span apm.ISpan) error {
+		var mode lobby.MatchmakingMode
+		if mode, err = s.getMode(); err != nil {
+			return err
+		}
+		var held []lobby.QueueEntry
+		for {
+			var (
+				first, second lobby.QueueEntry
+				matched       bool
+			)
+			err = s.lobbyCache.UpdateQueue(ctx, func(queue *lobby.Queue) error {
+				first, second, matched = queue.Match(mode, time.Now())
+				return nil
+			})
+			if err != nil || !matched {
+				break
+			}
+			span.AddEvent("match formed")
+			var requeue []lobby.QueueEntry
+			requeue, err = s.startMatch(ctx, first, second)
+			held = append(held, requeue...)
+			if err != nil {
+				break
+			}
+		}
+		if len(held) > 0 {
+			requeueErr := s.lobbyCache.UpdateQueue(ctx, func(queue *lobby.Queue) error {
+				for _, entry := range held {
+					queue.Add(entry)
+				}
+				return nil
+			})
+			if requeueErr != nil {
+				s.logger.Error(ctx, "failed to requeue players", requeueErr, nil)
+				if err == nil {
+					err = requeueErr
+				}
+			}
+		}
+		return err
+	})
+	return err
+}
+
+// QueueStatus returns the player's place in the queue, or the game they were
+// matched into
+func (s *LobbyService) QueueStatus(
+	ctx context.Context,
+	userId user.UserID,
+) (*lobby.QueueStatus, error) {
+	match, err := s.lobbyCache.GetMatch(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if match != nil {
+		return match, nil
+	}
+	queue, err := s.lobbyCache.GetQueue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	position, ok := queue.Position(userId)
+	if !ok {
+		return nil, utils.NewNotFoundError("not in the queue")
+	}
+	return lobby.NewQueuedStatus(position), nil
+}
+
+func (s *LobbyService) LeaveQueue(ctx context.Context, userId user.UserID) error {
+	return s.lobbyCache.UpdateQueue(ctx, func(queue *lobby.Queue) error {
+		if !queue.Remove(userId) {
+			return utils.NewNotFoundError("not in the queue")
+		}
+		return nil
+	})
+}
+
+// startMatch creates the game for a matched pair and lets both players know.
+// If the game cannot be created it returns the players to put back in the
+// queue: a player whose deck is no longer playable, say because it became
+// illegal while they waited, is dropped from the queue instead.
+func (s *LobbyService) startMatch(ctx context.Context, first, second lobby.QueueEntry) ([]lobby.QueueEntry, error) {
+	var playable []lobby.QueueEntry
+	for _, entry := range []lobby.QueueEntry{first, second} {
+		_, err := s.getDeck(ctx, entry.UserID, entry.DeckID)
+		switch {
+		case err == nil:
+			playable = append(playable, entry)
+		case utils.IsInvalidArgumentError(err) || utils.IsNotFoundError(err):
+			s.logger.Warn(ctx, "dropped player with an unplayable deck from the queue", map[string]any{
+				"user_id": entry.UserID,
+				"deck_id": entry.DeckID,
+				"error":   err.Error(),
+			})
+		default:
+			s.logger.Error(ctx, "failed to check matched player's deck", err, map[string]any{
+				"user_id": entry.UserID,
+			})
+			return []lobby.QueueEntry{first, second}, nil
+		}
+	}
+	if len(playable) < 2 {
+		return playable, nil
+	}
+
+	gameState, err := s.initializeGame(ctx,
+		lobby.LobbyPlayer{UserID: first.UserID, DeckID: first.DeckID},
+		lobby.LobbyPlayer{UserID: second.UserID, DeckID: second.DeckID},
+		lobby.GameSettings{Rules: game.RulesPresetClassic},
+	)
+	if err != nil {
+		s.logger.Error(ctx, "failed to start matched game", err, map[string]any{
+			"player1": first.UserID,
+			"player2": second.UserID,
+		})
+		return playable, nil
+	}
+	if err := s.lobbyCache.SetMatch(ctx, first.UserID, lobby.NewMatchedStatus(gameState.ID, second.UserID)); err != nil {
+		return nil, err
+	}
+	return nil, s.lobbyCache.SetMatch(ctx, second.UserID, lobby.NewMatchedStatus(gameState.ID, first.UserID))
+}
+
+// CreateLobby opens a private lobby that another player can join with the
+// returned invite code. settings choose the game's rules and who may watch it.
+func (s *LobbyService) CreateLobby(
+	ctx context.Context,
+	userId user.UserID,
+	deckId card.SerializableDeckID,
+	settings lobby.GameSettings,
+) (*lobby.Lobby, error) {
+	if _, err := game.NewRules(settings.Rules); err != nil {
+		return nil, err
+	}
+	if _, err := s.getDeck(ctx, userId, deckId); err != nil {
+		return nil, err
+	}
+	var err error
+	for range inviteCodeAttempts {
+		var created *lobby.Lobby
+		created, err = lobby.NewLobby(lobby.LobbyPlayer{UserID: userId, DeckID: deckId}, settings)
+		if err != nil {
+			return nil, err
+		}
+		err = s.lobbyCache.CreateLobby(ctx, created)
+		if err == nil {
+			return created, nil
+		}
+		if !utils.IsAlreadyExistsError(err) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+func (s *LobbyService) GetLobby(ctx context.Context, inviteCode string) (*lobby.Lobby, error) {
+	found, err := s.lobbyCache.GetLobby(ctx, inviteCode)
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, utils.NewNotFoundError("lobby not found")
+	}
+	return found, nil
+}
+
+// JoinLobby seats the player as the lobby's guest and creates the game. The
+// seat is freed again if the game cannot be created.
+func (s *LobbyService) JoinLobby(
+	ctx context.Context,
+	userId user.UserID,
+	inviteCode string,
+	deckId card.SerializableDeckID,
+) (*lobby.Lobby, error) {
+	var (
+		joined *lobby.Lobby
+		err    error
+	)
+	s.tracer.Trace(ctx, "join-lobby", func(ctx context.Context, span apm.ISpan) error {
+		if _, err = s.getDeck(ctx, userId, deckId); err != nil {
+			return err
+		}
+		guest := lobby.LobbyPlayer{UserID: userId, DeckID: deckId}
+		joined, err = s.lobbyCache.UpdateLobby(ctx, inviteCode, func(l *lobby.Lobby) error {
+			return l.Join(guest)
+		})
+		if err != nil {
+			return err
+		}
+
+		var gameState *game.GameState
+		gameState, err = s.initializeGame(ctx, joined.Host, guest, joined.Settings)
+		if err != nil {
+			if _, leaveErr := s.lobbyCache.UpdateLobby(ctx, inviteCode, func(l *lobby.Lobby) error {
+				l.Guest = nil
+				return nil
+			}); leaveErr != nil {
+				s.logger.Error(ctx, "failed to free lobby seat", leaveErr, nil)
+			}
+			return err
+		}
+		joined, err = s.lobbyCache.UpdateLobby(ctx, inviteCode, func(l *lobby.Lobby) error {
+			l.GameStateID = &gameState.ID
+			return nil
+		})
+		return err
+	})
+	return joined, err
+}
+
+// CloseLobby removes a lobby. Only its host may close it.
+func (s *LobbyService) CloseLobby(ctx context.Context, userId user.UserID, inviteCode string) error {
+	found, err := s.GetLobby(ctx, inviteCode)
+	if err != nil {
+		return err
+	}
+	if found.Host.UserID != userId {
+		return utils.NewPermissionDeniedError("only the host can close the lobby")
+	}
+	return s.lobbyCache.DeleteLobby(ctx, inviteCode)
+}
+
+// StartBotGame creates a practice game against the bot of the given
+// difficulty, played with the rules preset. The bot plays botDeckId, one of
+// the player's own decks.
+func (s *LobbyService) StartBotGame(
+	ctx context.Context,
+	userId user.UserID,
+	deckId card.SerializableDeckID,
+	botDeckId card.SerializableDeckID,
+	difficulty bot.Difficulty,
+	rules game.RulesPreset,
+) (*game.GameState, error) {
+	if !difficulty.IsValid() {
+		return nil, utils.NewInvalidArgumentError("unknown bot difficulty")
+	}
+	return s.initializeGame(ctx,
+		lobby.LobbyPlayer{UserID: userId, DeckID: deckId},
+		lobby.LobbyPlayer{UserID: difficulty.UserID(), DeckID: botDeckId},
+		lobby.GameSettings{Rules: rules},
+	)
+}
+
+func (s *LobbyService) initializeGame(
+	ctx context.Context,
+	player1 lobby.LobbyPlayer,
+	player2 lobby.LobbyPlayer,
+	settings lobby.GameSettings,
+) (*game.GameState, error) {
+	req := gamerunnerservice.StartGameRequest{}
+	req.Player1.UserID = player1.UserID
+	req.Player1.DeckID = player1.DeckID
+	req.Player2.UserID = player2.UserID
+	req.Player2.DeckID = player2.DeckID
+	req.Rules = settings.Rules
+	req.Spectating = settings.Spectating
+	return s.gameRunnerService.InitializeGame(ctx, req)
+}
+
+type iGameRunnerService interface {
+	InitializeGame(ctx context.Context, req gamerunnerservice.StartGameRequest) (*game.GameState, error)
+}
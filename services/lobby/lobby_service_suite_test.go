@@ -0,0 +1,140 @@
+package lobby_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/lobby"
+	"github.com/coopersmall/subswag/domain/user"
+	gamerunnerservice "github.com/coopersmall/subswag/services/game"
+	lobbyservice "github.com/coopersmall/subswag/services/lobby"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type LobbyServiceTestSuite struct {
+	suite.Suite
+}
+
+func TestLobbyServiceSuite(t *testing.T) {
+	suite.Run(t, new(LobbyServiceTestSuite))
+}
+
+// lobbyFixture is a lobby service backed by an in-memory queue. Players in
+// illegalDecks have a deck that is no longer legal, and games with a player
+// in brokenPlayers cannot be created.
+type lobbyFixture struct {
+	service       *lobbyservice.LobbyService
+	cache         *fakeLobbyCache
+	runner        *fakeGameRunner
+	illegalDecks  map[user.UserID]bool
+	brokenPlayers map[user.UserID]bool
+}
+
+func newLobbyFixture() *lobbyFixture {
+	f := &lobbyFixture{
+		cache:         &fakeLobbyCache{queue: &lobby.Queue{}, matches: make(map[user.UserID]*lobby.QueueStatus)},
+		illegalDecks:  make(map[user.UserID]bool),
+		brokenPlayers: make(map[user.UserID]bool),
+	}
+	f.runner = &fakeGameRunner{broken: f.brokenPlayers}
+	f.service = lobbyservice.NewLobbyService(
+		utils.NewLogger("lobby", utils.WithWriter(io.Discard)),
+		apm.NewNoopTracer(),
+		f.cache,
+		func() (lobby.MatchmakingMode, error) { return lobby.MatchmakingModeFIFO, nil },
+		func(ctx context.Context, userId user.UserID, deckId card.SerializableDeckID) (*card.SerializableDeck, error) {
+			if f.illegalDecks[userId] {
+				return nil, utils.NewInvalidArgumentError("deck is not legal in the standard format")
+			}
+			return card.NewDeck(deckId, card.SerializableDeckData{UserID: userId}), nil
+		},
+		func(ctx context.Context, userId user.UserID) (float64, error) { return 1500, nil },
+		f.runner,
+	)
+	return f
+}
+
+// enqueue puts the players in the queue in the order given, without matching
+func (f *lobbyFixture) enqueue(userIds ...user.UserID) {
+	start := time.Now().Add(-time.Minute)
+	for i, userId := range userIds {
+		f.cache.queue.Add(lobby.QueueEntry{
+			UserID:     userId,
+			DeckID:     card.SerializableDeckID(userId),
+			Rating:     1500,
+			EnqueuedAt: start.Add(time.Duration(i) * time.Second),
+		})
+	}
+}
+
+// queued lists the players still waiting, oldest first
+func (f *lobbyFixture) queued() []user.UserID {
+	userIds := []user.UserID{}
+	for _, entry := range f.cache.queue.Entries {
+		userIds = append(userIds, entry.UserID)
+	}
+	return userIds
+}
+
+type fakeLobbyCache struct {
+	queue   *lobby.Queue
+	matches map[user.UserID]*lobby.QueueStatus
+}
+
+func (f *fakeLobbyCache) UpdateQueue(ctx context.Context, update func(*lobby.Queue) error) error {
+	return update(f.queue)
+}
+
+func (f *fakeLobbyCache) GetQueue(ctx context.Context) (*lobby.Queue, error) {
+	return f.queue, nil
+}
+
+func (f *fakeLobbyCache) GetMatch(ctx context.Context, userId user.UserID) (*lobby.QueueStatus, error) {
+	return f.matches[userId], nil
+}
+
+func (f *fakeLobbyCache) SetMatch(ctx context.Context, userId user.UserID, status *lobby.QueueStatus) error {
+	f.matches[userId] = status
+	return nil
+}
+
+func (f *fakeLobbyCache) DeleteMatch(ctx context.Context, userId user.UserID) error {
+	delete(f.matches, userId)
+	return nil
+}
+
+func (f *fakeLobbyCache) GetLobby(ctx context.Context, inviteCode string) (*lobby.Lobby, error) {
+	return nil, nil
+}
+
+func (f *fakeLobbyCache) CreateLobby(ctx context.Context, l *lobby.Lobby) error {
+	return nil
+}
+
+func (f *fakeLobbyCache) UpdateLobby(ctx context.Context, inviteCode string, update func(*lobby.Lobby) error) (*lobby.Lobby, error) {
+	return nil, utils.NewNotFoundError("lobby not found")
+}
+
+func (f *fakeLobbyCache) DeleteLobby(ctx context.Context, inviteCode string) error {
+	return nil
+}
+
+type fakeGameRunner struct {
+	broken map[user.UserID]bool
+	games  int
+}
+
+func (f *fakeGameRunner) InitializeGame(ctx context.Context, req gamerunnerservice.StartGameRequest) (*game.GameState, error) {
+	if f.broken[req.Player1.UserID] || f.broken[req.Player2.UserID] {
+		return nil, errors.New("failed to create game")
+	}
+	f.games++
+	return &game.GameState{ID: game.GameStateID(f.games)}, nil
+}
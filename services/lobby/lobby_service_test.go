@@ -0,0 +1,57 @@
+package lobby_test
+
+import (
+	"context"
+
+	"github.com/coopersmall/subswag/domain/lobby"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ctx = context.Background()
+
+func (s *LobbyServiceTestSuite) TestMatchQueue() {
+	s.Run("should drop a player whose deck became illegal and keep matching the rest", func() {
+		f := newLobbyFixture()
+		f.illegalDecks[1001] = true
+		f.enqueue(1001, 1002, 1003, 1004)
+
+		require.NoError(s.T(), f.service.MatchQueue(ctx))
+		assert.Equal(s.T(), 1, f.runner.games)
+		assert.Equal(s.T(), user.UserID(1004), *f.cache.matches[1003].Opponent)
+		assert.Equal(s.T(), []user.UserID{1002}, f.queued())
+		assert.Nil(s.T(), f.cache.matches[1001])
+
+		_, err := f.service.QueueStatus(ctx, 1001)
+		assert.Error(s.T(), err)
+		status, err := f.service.QueueStatus(ctx, 1002)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), lobby.NewQueuedStatus(1), status)
+	})
+
+	s.Run("should match the healthy player again on the next round", func() {
+		f := newLobbyFixture()
+		f.illegalDecks[1001] = true
+		f.enqueue(1001, 1002)
+
+		require.NoError(s.T(), f.service.MatchQueue(ctx))
+		assert.Equal(s.T(), []user.UserID{1002}, f.queued())
+
+		f.enqueue(1003)
+		require.NoError(s.T(), f.service.MatchQueue(ctx))
+		assert.Empty(s.T(), f.queued())
+		assert.Equal(s.T(), user.UserID(1003), *f.cache.matches[1002].Opponent)
+	})
+
+	s.Run("should put a pair back in its place when its game cannot be created", func() {
+		f := newLobbyFixture()
+		f.brokenPlayers[1001] = true
+		f.enqueue(1001, 1002, 1003, 1004)
+
+		require.NoError(s.T(), f.service.MatchQueue(ctx))
+		assert.Equal(s.T(), 1, f.runner.games)
+		assert.Equal(s.T(), user.UserID(1004), *f.cache.matches[1003].Opponent)
+		assert.Equal(s.T(), []user.UserID{1001, 1002}, f.queued())
+	})
+}
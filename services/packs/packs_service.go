@@ -0,0 +1,208 @@
+package packs
+
+import (
+	"context"
+	"time"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/pack"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/repos"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// maxOpenAttempts bounds how many times a pack is dealt again when another
+// pack of the same type is opened by the user at the same moment
+const maxOpenAttempts = 3
+
+// PacksService manages the booster packs admins define and opens them for a
+// user. Every opening is recorded in the user's audit log together with the
+// cards it added to their collection.
+type PacksService struct {
+	logger           utils.ILogger
+	tracer           apm.ITracer
+	userId           user.UserID
+	packTypesRepo    repos.IPackTypesRepo
+	packOpeningsRepo repos.IPackOpeningsRepo
+	cardsRepo        repos.ICardsRepo
+	publisher        iCollectionEventsPublisher
+	roll             pack.Roller
+}
+
+func NewPacksService(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	userId user.UserID,
+	packTypesRepo repos.IPackTypesRepo,
+	packOpeningsRepo repos.IPackOpeningsRepo,
+	cardsRepo repos.ICardsRepo,
+	publisher iCollectionEventsPublisher,
+) *PacksService {
+	return &PacksService{
+		logger:           logger,
+		tracer:           tracer,
+		userId:           userId,
+		packTypesRepo:    packTypesRepo,
+		packOpeningsRepo: packOpeningsRepo,
+		cardsRepo:        cardsRepo,
+		publisher:        publisher,
+		roll:             pack.CryptoRoller,
+	}
+}
+
+func (s *PacksService) GetPackType(ctx context.Context, packTypeId pack.PackTypeID) (*pack.PackType, error) {
+	return s.packTypesRepo.Get(ctx, packTypeId)
+}
+
+func (s *PacksService) GetAllPackTypes(ctx context.Context) ([]*pack.PackType, error) {
+	return s.packTypesRepo.All(ctx)
+}
+
+// CreatePackType saves a new pack type. It must be valid.
+func (s *PacksService) CreatePackType(ctx context.Context, data pack.PackTypeData) (*pack.PackType, error) {
+	packType := pack.NewPackType(data)
+	if err := packType.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.packTypesRepo.Create(ctx, packType); err != nil {
+		return nil, err
+	}
+	return packType, nil
+}
+
+// UpdatePackType changes an existing pack type. Packs already opened keep the
+// definition they were opened with in the audit log.
+func (s *PacksService) UpdatePackType(ctx context.Context, packType *pack.PackType) (*pack.PackType, error) {
+	current, err := s.packTypesRepo.Get(ctx, packType.ID)
+	if err != nil {
+		return nil, err
+	}
+	packType.Metadata = &domain.Metadata{
+		CreatedAt: current.Metadata.CreatedAt,
+		UpdatedAt: time.Now().UTC(),
+	}
+	if err := packType.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.packTypesRepo.Update(ctx, packType); err != nil {
+		return nil, err
+	}
+	return packType, nil
+}
+
+func (s *PacksService) DeletePackType(ctx context.Context, packTypeId pack.PackTypeID) error {
+	return s.packTypesRepo.Delete(ctx, packTypeId)
+}
+
+// OpenPack deals a pack of the type with a cryptographically random roll for
+// every slot and adds its cards to the user's collection. The opening and the
+// cards are saved together, so a failed opening grants nothing.
+func (s *PacksService) OpenPack(ctx context.Context, packTypeId pack.PackTypeID) (*pack.PackOpening, error) {
+	var (
+		opening *pack.PackOpening
+		err     error
+	)
+	s.tracer.Trace(ctx, "open-pack", func(ctx context.Context, span apm.ISpan) error {
+		span.SetAttribute("pack_type_id", packTypeId)
+		var packType *pack.PackType
+		packType, err = s.packTypesRepo.Get(ctx, packTypeId)
+		if err != nil {
+			return err
+		}
+		var catalog map[card.CardRarity][]card.SerializableCardID
+		catalog, err = s.cardsRepo.GetIDsByRarity(ctx, packType.Rarities())
+		if err != nil {
+			return err
+		}
+
+		var owned map[card.SerializableCardID]int
+		for range maxOpenAttempts {
+			opening, owned, err = s.open(ctx, packType, catalog)
+			if !utils.IsConflictError(err) {
+				break
+			}
+		}
+		if err != nil {
+			opening = nil
+			return err
+		}
+
+		span.SetAttribute("sequence", opening.Sequence)
+		span.SetAttribute("pity_triggered", opening.PityTriggered)
+		s.publish(ctx, card.NewGrantEvents(s.userId, opening.CardIDs(), owned, card.CardSourcePack)...)
+		return nil
+	})
+	return opening, err
+}
+
+// GetOpenings returns the audit log of every pack the user has opened,
+// newest first
+func (s *PacksService) GetOpenings(ctx context.Context) ([]*pack.PackOpening, error) {
+	return s.packOpeningsRepo.All(ctx)
+}
+
+func (s *PacksService) GetOpening(ctx context.Context, openingId pack.PackOpeningID) (*pack.PackOpening, error) {
+	return s.packOpeningsRepo.Get(ctx, openingId)
+}
+
+// GetPity returns how many packs of the type the user has opened in a row
+// without the rarity its pity rule guarantees
+func (s *PacksService) GetPity(ctx context.Context, packTypeId pack.PackTypeID) (*pack.PackPityCounter, error) {
+	packType, err := s.packTypesRepo.Get(ctx, packTypeId)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.latest(ctx, packTypeId)
+	if err != nil {
+		return nil, err
+	}
+	return pack.NewPackPityCounter(packType, latest), nil
+}
+
+// open deals the pack after the user's latest opening of the type and
+// records it, returning how many copies of each card dealt the user owns
+// afterwards. It returns a conflict error if another opening was recorded in
+// the meantime.
+func (s *PacksService) open(
+	ctx context.Context,
+	packType *pack.PackType,
+	catalog map[card.CardRarity][]card.SerializableCardID,
+) (*pack.PackOpening, map[card.SerializableCardID]int, error) {
+	previous, err := s.latest(ctx, packType.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	opening, err := packType.Open(s.userId, previous, catalog, s.roll)
+	if err != nil {
+		return nil, nil, err
+	}
+	owned, err := s.packOpeningsRepo.Record(ctx, opening)
+	if err != nil {
+		return nil, nil, err
+	}
+	return opening, owned, nil
+}
+
+// latest returns the user's last opening of the pack type, or nil if they
+// have never opened one
+func (s *PacksService) latest(ctx context.Context, packTypeId pack.PackTypeID) (*pack.PackOpening, error) {
+	previous, err := s.packOpeningsRepo.Latest(ctx, packTypeId)
+	if utils.IsNotFoundError(err) {
+		return nil, nil
+	}
+	return previous, err
+}
+
+// publish pushes events to the collections stream. The cards have already
+// been granted, so failures are logged rather than returned.
+func (s *PacksService) publish(ctx context.Context, events ...*card.CollectionEvent) {
+	if err := s.publisher.PublishEvents(ctx, events...); err != nil {
+		s.logger.Error(ctx, "failed to publish collection events", err, nil)
+	}
+}
+
+type iCollectionEventsPublisher interface {
+	PublishEvents(ctx context.Context, events ...*card.CollectionEvent) error
+}
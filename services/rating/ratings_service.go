@@ -0,0 +1,171 @@
+package rating
+
+import (
+	"context"
+	"slices"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain/bot"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/rating"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/repos"
+	servicesdomain "github.com/coopersmall/subswag/services/domain"
+	"github.com/coopersmall/subswag/utils"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 100
+
+	maxRecordAttempts = 3
+)
+
+// RatingsService rates players on finished games and serves leaderboards and
+// match history
+type RatingsService struct {
+	logger           utils.ILogger
+	tracer           apm.ITracer
+	gameStateRepo    repos.IGameStateRepo
+	userRatingsRepo  repos.IUserRatingsRepo
+	matchResultsRepo repos.IMatchResultsRepo
+	usersService     iUsersService
+}
+
+func NewRatingsService(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	gameStateRepo repos.IGameStateRepo,
+	userRatingsRepo repos.IUserRatingsRepo,
+	matchResultsRepo repos.IMatchResultsRepo,
+	usersService iUsersService,
+) *RatingsService {
+	return &RatingsService{
+		logger:           logger,
+		tracer:           tracer,
+		gameStateRepo:    gameStateRepo,
+		userRatingsRepo:  userRatingsRepo,
+		matchResultsRepo: matchResultsRepo,
+		usersService:     usersService,
+	}
+}
+
+// RecordGame rates both players on a completed game and updates their decks'
+// records, all in one write. A game is only ever recorded once, so
+// redelivered completion events are ignored. Practice games against a bot are not rated.
+func (s *RatingsService) RecordGame(ctx context.Context, gameStateId game.GameStateID) error {
+	var err error
+	s.tracer.Trace(ctx, "record-game", func(ctx context.Context, span apm.ISpan) error {
+		if _, err = s.matchResultsRepo.Get(ctx, gameStateId); err == nil {
+			span.AddEvent("already recorded")
+			return nil
+		} else if !servicesdomain.IsNotFound(err) {
+			return err
+		}
+
+		var gameState *game.GameState
+		gameState, err = s.gameStateRepo.Get(ctx, gameStateId)
+		if err != nil {
+			return err
+		}
+		for _, player := range gameState.Players {
+			if bot.IsBot(player.User) {
+				span.AddEvent("practice game against a bot")
+				return nil
+			}
+		}
+
+		// Both players are rated from the ratings they had when read. If
+		// either changes before the result is written, because another of
+		// their games was recorded first, the game is rated again.
+		for range maxRecordAttempts {
+			err = s.rateGame(ctx, gameState)
+			if !utils.IsConflictError(err) {
+				break
+			}
+		}
+		if utils.IsAlreadyExistsError(err) {
+			span.AddEvent("already recorded")
+			err = nil
+			return nil
+		}
+		return err
+	})
+	return err
+}
+
+// rateGame rates the game from both players' current ratings and writes the
+// result, the new ratings and the decks' records together
+func (s *RatingsService) rateGame(ctx context.Context, gameState *game.GameState) error {
+	result, err := rating.NewMatchResult(gameState)
+	if err != nil {
+		return err
+	}
+	var ratings [2]*rating.UserRating
+	for i, player := range result.Players {
+		if err := s.userRatingsRepo.Start(ctx, player.UserID); err != nil {
+			return err
+		}
+		if ratings[i], err = s.userRatingsRepo.Get(ctx, player.UserID); err != nil {
+			return err
+		}
+	}
+	result.Rate(ratings)
+	return s.matchResultsRepo.Record(ctx, result, ratings)
+}
+
+// GetRating returns the user's rating, or the starting rating if they have
+// not finished a game
+func (s *RatingsService) GetRating(ctx context.Context, userId user.UserID) (*rating.UserRating, error) {
+	userRating, err := s.userRatingsRepo.Get(ctx, userId)
+	if servicesdomain.IsNotFound(err) {
+		return rating.NewUserRating(userId), nil
+	}
+	return userRating, err
+}
+
+// Leaderboard ranks every rated player
+func (s *RatingsService) Leaderboard(ctx context.Context, limit int, offset int) ([]rating.LeaderboardEntry, error) {
+	limit, offset = page(limit, offset)
+	ratings, err := s.userRatingsRepo.Top(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return rating.NewLeaderboard(ratings, offset), nil
+}
+
+// FriendsLeaderboard ranks the user against their friends
+func (s *RatingsService) FriendsLeaderboard(ctx context.Context, userId user.UserID) ([]rating.LeaderboardEntry, error) {
+	u, err := s.usersService.GetUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	userIds := append(slices.Clone(u.Friends), userId)
+	ratings, err := s.userRatingsRepo.ForUsers(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+	return rating.NewLeaderboard(ratings, 0), nil
+}
+
+// MatchHistory returns the user's finished games, most recent first
+func (s *RatingsService) MatchHistory(
+	ctx context.Context,
+	userId user.UserID,
+	limit int,
+	offset int,
+) ([]*rating.MatchResult, error) {
+	limit, offset = page(limit, offset)
+	return s.matchResultsRepo.ForUser(ctx, userId, limit, offset)
+}
+
+func page(limit int, offset int) (int, int) {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	return min(limit, MaxPageSize), max(offset, 0)
+}
+
+type iUsersService interface {
+	GetUser(ctx context.Context, userId user.UserID) (*user.User, error)
+}
@@ -9,19 +9,30 @@ import (
 	"github.com/coopersmall/subswag/cache"
 	"github.com/coopersmall/subswag/domain"
 	"github.com/coopersmall/subswag/domain/apitoken"
+	"github.com/coopersmall/subswag/domain/bot"
 	"github.com/coopersmall/subswag/domain/card"
 	"github.com/coopersmall/subswag/domain/chatsession"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/lobby"
+	"github.com/coopersmall/subswag/domain/pack"
+	"github.com/coopersmall/subswag/domain/rating"
 	"github.com/coopersmall/subswag/domain/secret"
 	"github.com/coopersmall/subswag/domain/user"
 	"github.com/coopersmall/subswag/gateways"
 	"github.com/coopersmall/subswag/repos"
 	answerAssistantservice "github.com/coopersmall/subswag/services/answerassistant"
 	apitokenservice "github.com/coopersmall/subswag/services/apitoken"
+	cardsservice "github.com/coopersmall/subswag/services/cards"
 	chatsessionservice "github.com/coopersmall/subswag/services/chatsession"
 	chatsessionitemservice "github.com/coopersmall/subswag/services/chatsessionitems"
+	collectionservice "github.com/coopersmall/subswag/services/collection"
 	deckservice "github.com/coopersmall/subswag/services/decks"
 	encryptionservice "github.com/coopersmall/subswag/services/encryption"
+	gamerunnerservice "github.com/coopersmall/subswag/services/game"
+	lobbyservice "github.com/coopersmall/subswag/services/lobby"
+	packsservice "github.com/coopersmall/subswag/services/packs"
 	ratelimiterservice "github.com/coopersmall/subswag/services/ratelimiter"
+	ratingsservice "github.com/coopersmall/subswag/services/rating"
 	secretsservice "github.com/coopersmall/subswag/services/secret"
 	usersservice "github.com/coopersmall/subswag/services/user"
 	userauthenticationservice "github.com/coopersmall/subswag/services/userauthentication"
@@ -33,9 +44,17 @@ import (
 type IServices interface {
 	APITokenService(userId user.UserID) IAPITokenService
 	AnswerAssistantService(userId user.UserID) IAnswerAssistantService
+	CardCatalogService() ICardCatalogService
 	ChatSessionsService(userId user.UserID) IChatSessionsService
 	ChatSessionItemsService(userId user.UserID) IChatSessionItemsService
+	CollectionService(userId user.UserID) ICollectionService
 	DecksService(userId user.UserID) IDecksService
+	GameRunnerService() IGameRunnerService
+	GameReplayService() IGameReplayService
+	GameEventsService() IGameEventsService
+	LobbyService() ILobbyService
+	PacksService(userId user.UserID) IPacksService
+	RatingsService() IRatingsService
 	SecretsService(userId user.UserID) ISecretsService
 	AuthenticationService() IAuthenticationService
 	JWTService() IJWTService
@@ -46,9 +65,17 @@ type IServices interface {
 type Services struct {
 	apiTokenService         func(userId user.UserID) IAPITokenService
 	answerAssistantService  func(userId user.UserID) IAnswerAssistantService
+	cardCatalogService      func() ICardCatalogService
 	chatSessionsService     func(userId user.UserID) IChatSessionsService
 	chatSessionItemsService func(userId user.UserID) IChatSessionItemsService
+	collectionService       func(userId user.UserID) ICollectionService
 	decksService            func(userId user.UserID) IDecksService
+	gameRunnerService       func() IGameRunnerService
+	gameReplayService       func() IGameReplayService
+	gameEventsService       func() IGameEventsService
+	lobbyService            func() ILobbyService
+	packsService            func(userId user.UserID) IPacksService
+	ratingsService          func() IRatingsService
 	secretsService          func(userId user.UserID) ISecretsService
 	authenticationService   func() IAuthenticationService
 	jwtService              func() IJWTService
@@ -101,10 +128,45 @@ func GetServices(
 		)
 	}
 
+	newCardCatalogService := func() ICardCatalogService {
+		return cardsservice.NewCardCatalogService(
+			env.GetLogger("card-catalog-service"),
+			env.GetTracer("card-catalog-service"),
+			repos.CardsRepo(),
+		)
+	}
+
+	newCollectionService := func(userId user.UserID) ICollectionService {
+		return collectionservice.NewCollectionService(
+			env.GetLogger("collection-service"),
+			env.GetTracer("collection-service"),
+			userId,
+			repos.UserCardsRepo(userId),
+			repos.CardsRepo(),
+			repos.DecksRepo(userId),
+			publishers.CollectionEventsPublisher(),
+		)
+	}
+
+	newPacksService := func(userId user.UserID) IPacksService {
+		return packsservice.NewPacksService(
+			env.GetLogger("packs-service"),
+			env.GetTracer("packs-service"),
+			userId,
+			repos.PackTypesRepo(),
+			repos.PackOpeningsRepo(userId),
+			repos.CardsRepo(),
+			publishers.CollectionEventsPublisher(),
+		)
+	}
+
 	newDeckService := func(userId user.UserID) IDecksService {
 		return deckservice.NewDecksService(
 			env.GetLogger("deck-service"),
+			userId,
 			repos.DecksRepo(userId),
+			repos.CardsRepo(),
+			newCollectionService(userId),
 		)
 	}
 
@@ -148,12 +210,89 @@ func GetServices(
 		)
 	}
 
+	newGameRunnerService := func() IGameRunnerService {
+		return gamerunnerservice.NewGameRunnerService(
+			env.GetLogger("game-runner-service"),
+			env.GetTracer("game-runner-service"),
+			repos.GameStateRepo(),
+			repos.GameStateVersionRepo(),
+			repos.GameTimersRepo(),
+			repos.CardsRepo(),
+			func(ctx context.Context, userId user.UserID, deckId card.SerializableDeckID) (*card.SerializableDeck, error) {
+				return newDeckService(userId).GetPlayableDeck(ctx, deckId)
+			},
+			newUsersService(),
+			publishers.GameEventsPublisher(),
+		)
+	}
+
+	newGameEventsService := func() IGameEventsService {
+		return gamerunnerservice.NewGameEventsService(
+			env.GetLogger("game-events-service"),
+			env.GetTracer("game-events-service"),
+			repos.GameStateRepo(),
+			repos.GameStateVersionRepo(),
+			cache.SpectatorsCache(),
+			gateways.RedisStreamSubscriberGateway(nil),
+		)
+	}
+
+	newGameReplayService := func() IGameReplayService {
+		return gamerunnerservice.NewGameReplayService(
+			env.GetLogger("game-replay-service"),
+			env.GetTracer("game-replay-service"),
+			repos.GameStateRepo(),
+			repos.GameStateVersionRepo(),
+			repos.GameTimersRepo(),
+			publishers.GameEventsPublisher(),
+		)
+	}
+
+	newRatingsService := func() IRatingsService {
+		return ratingsservice.NewRatingsService(
+			env.GetLogger("ratings-service"),
+			env.GetTracer("ratings-service"),
+			repos.GameStateRepo(),
+			repos.UserRatingsRepo(),
+			repos.MatchResultsRepo(),
+			newUsersService(),
+		)
+	}
+
+	newLobbyService := func() ILobbyService {
+		return lobbyservice.NewLobbyService(
+			env.GetLogger("lobby-service"),
+			env.GetTracer("lobby-service"),
+			cache.LobbyCache(),
+			vars.GetMatchmakingMode,
+			func(ctx context.Context, userId user.UserID, deckId card.SerializableDeckID) (*card.SerializableDeck, error) {
+				return newDeckService(userId).GetPlayableDeck(ctx, deckId)
+			},
+			func(ctx context.Context, userId user.UserID) (float64, error) {
+				userRating, err := newRatingsService().GetRating(ctx, userId)
+				if err != nil {
+					return 0, err
+				}
+				return userRating.Rating, nil
+			},
+			newGameRunnerService(),
+		)
+	}
+
 	return &Services{
 		apiTokenService:         newAPITokenService,
 		answerAssistantService:  newAnswerAssistantService,
+		cardCatalogService:      newCardCatalogService,
 		chatSessionsService:     newChatSessionsService,
 		chatSessionItemsService: newChatSessionItemsService,
+		collectionService:       newCollectionService,
 		decksService:            newDeckService,
+		gameRunnerService:       newGameRunnerService,
+		gameReplayService:       newGameReplayService,
+		gameEventsService:       newGameEventsService,
+		lobbyService:            newLobbyService,
+		packsService:            newPacksService,
+		ratingsService:          newRatingsService,
 		jwtService:              newJWTService,
 		rsaService:              newRSAService,
 		secretsService:          newSecretsService,
@@ -165,11 +304,19 @@ func GetServices(
 var (
 	NewAPITokenService           = apitokenservice.NewAPITokenService
 	NewAnswerAssistantService    = answerAssistantservice.NewAnswerAssistantService
+	NewCardCatalogService        = cardsservice.NewCardCatalogService
 	NewChatSessionsService       = chatsessionservice.NewChatSessionsService
 	NewChatSessionItemsService   = chatsessionitemservice.NewChatSessionItemsService
+	NewCollectionService         = collectionservice.NewCollectionService
 	NewDeckService               = deckservice.NewDecksService
+	NewGameRunnerService         = gamerunnerservice.NewGameRunnerService
+	NewGameReplayService         = gamerunnerservice.NewGameReplayService
+	NewGameEventsService         = gamerunnerservice.NewGameEventsService
+	NewLobbyService              = lobbyservice.NewLobbyService
+	NewPacksService              = packsservice.NewPacksService
 	NewJWTService                = encryptionservice.NewJWTService
 	NewRateLimiterService        = ratelimiterservice.NewRateLimiterService
+	NewRatingsService            = ratingsservice.NewRatingsService
 	NewRSAService                = encryptionservice.NewRSAService
 	NewSecretService             = secretsservice.NewSecretService
 	NewUserAuthenticationService = userauthenticationservice.NewAuthenticationService
@@ -184,6 +331,10 @@ func (s *Services) AnswerAssistantService(userId user.UserID) IAnswerAssistantSe
 	return s.answerAssistantService(userId)
 }
 
+func (s *Services) CardCatalogService() ICardCatalogService {
+	return s.cardCatalogService()
+}
+
 func (s *Services) ChatSessionsService(userId user.UserID) IChatSessionsService {
 	return s.chatSessionsService(userId)
 }
@@ -192,10 +343,38 @@ func (s *Services) ChatSessionItemsService(userId user.UserID) IChatSessionItems
 	return s.chatSessionItemsService(userId)
 }
 
+func (s *Services) CollectionService(userId user.UserID) ICollectionService {
+	return s.collectionService(userId)
+}
+
 func (s *Services) DecksService(userId user.UserID) IDecksService {
 	return s.decksService(userId)
 }
 
+func (s *Services) GameRunnerService() IGameRunnerService {
+	return s.gameRunnerService()
+}
+
+func (s *Services) GameReplayService() IGameReplayService {
+	return s.gameReplayService()
+}
+
+func (s *Services) GameEventsService() IGameEventsService {
+	return s.gameEventsService()
+}
+
+func (s *Services) LobbyService() ILobbyService {
+	return s.lobbyService()
+}
+
+func (s *Services) PacksService(userId user.UserID) IPacksService {
+	return s.packsService(userId)
+}
+
+func (s *Services) RatingsService() IRatingsService {
+	return s.ratingsService()
+}
+
 func (s *Services) JWTService() IJWTService {
 	return s.jwtService()
 }
@@ -227,6 +406,7 @@ type iEnvVars interface {
 	GetSecretsRSAPublicKey() (*rsa.PublicKey, error)
 	GetSecretsRSAPrivateKey() (*rsa.PrivateKey, error)
 	GetJWTSigningKey() ([]byte, error)
+	GetMatchmakingMode() (lobby.MatchmakingMode, error)
 }
 
 type IAPITokenService interface {
@@ -241,6 +421,16 @@ type IAnswerAssistantService interface {
 	AnswerQuestion(ctx context.Context, userId user.UserID, items []chatsession.ChatSessionItem) (*chatsession.AssistantChatSessionItem, error)
 }
 
+type ICardCatalogService interface {
+	GetCard(ctx context.Context, cardId card.SerializableCardID) (card.Card, error)
+	GetAllCards(ctx context.Context) ([]card.Card, error)
+	GetCardVersion(ctx context.Context, cardId card.SerializableCardID, version int) (card.Card, error)
+	CreateCard(ctx context.Context, c card.Card) (card.Card, error)
+	UpdateCard(ctx context.Context, c card.Card) (card.Card, error)
+	DeleteCard(ctx context.Context, cardId card.SerializableCardID) error
+	ImportCardSet(ctx context.Context, set *card.CardSet, dryRun bool) (*card.CardSetImport, error)
+}
+
 type IChatSessionsService interface {
 	GetChatSession(ctx context.Context, sessionId chatsession.ChatSessionID) (*chatsession.ChatSession, error)
 	GetAllChatSessions(ctx context.Context) ([]*chatsession.ChatSession, error)
@@ -260,14 +450,82 @@ type IChatSessionItemsService interface {
 	ConvertChatSessionItemsToLLMMessages(ctx context.Context, items []chatsession.ChatSessionItem) []llms.MessageContent
 }
 
+type ICollectionService interface {
+	GetCollection(ctx context.Context, filter card.CollectionFilter) ([]*card.CollectionCard, error)
+	GetCopies(ctx context.Context) (map[card.SerializableCardID]int, error)
+	GrantCards(ctx context.Context, cardIds []card.SerializableCardID, source card.CardSource) ([]*card.CollectionEvent, error)
+	RevokeCards(ctx context.Context, cardIds []card.SerializableCardID) ([]*card.CollectionEvent, error)
+	MissingCards(ctx context.Context, deckId card.SerializableDeckID) ([]card.MissingCard, error)
+}
+
 type IDecksService interface {
 	GetDeck(ctx context.Context, deckId card.SerializableDeckID) (*card.SerializableDeck, error)
+	GetPlayableDeck(ctx context.Context, deckId card.SerializableDeckID) (*card.SerializableDeck, error)
+	ValidateDeck(ctx context.Context, deckId card.SerializableDeckID) (*card.DeckLegality, error)
 	GetAllDecks(ctx context.Context) ([]*card.SerializableDeck, error)
-	CreateDeck(ctx context.Context, data card.SerializableDeckData) error
+	CreateDeck(ctx context.Context, data card.SerializableDeckData) (*card.SerializableDeck, error)
 	UpdateDeck(ctx context.Context, deck *card.SerializableDeck) error
+	FavoriteDeck(ctx context.Context, deckId card.SerializableDeckID, favorited bool) (*card.SerializableDeck, error)
+	CloneDeck(ctx context.Context, deckId card.SerializableDeckID, name string) (*card.SerializableDeck, error)
+	ExportDeck(ctx context.Context, deckId card.SerializableDeckID) (string, error)
+	ImportDeck(ctx context.Context, code string, name string) (*card.SerializableDeck, error)
 	DeleteDeck(ctx context.Context, deckId card.SerializableDeckID) error
 }
 
+type IPacksService interface {
+	GetPackType(ctx context.Context, packTypeId pack.PackTypeID) (*pack.PackType, error)
+	GetAllPackTypes(ctx context.Context) ([]*pack.PackType, error)
+	CreatePackType(ctx context.Context, data pack.PackTypeData) (*pack.PackType, error)
+	UpdatePackType(ctx context.Context, packType *pack.PackType) (*pack.PackType, error)
+	DeletePackType(ctx context.Context, packTypeId pack.PackTypeID) error
+	OpenPack(ctx context.Context, packTypeId pack.PackTypeID) (*pack.PackOpening, error)
+	GetOpenings(ctx context.Context) ([]*pack.PackOpening, error)
+	GetOpening(ctx context.Context, openingId pack.PackOpeningID) (*pack.PackOpening, error)
+	GetPity(ctx context.Context, packTypeId pack.PackTypeID) (*pack.PackPityCounter, error)
+}
+
+type IGameRunnerService interface {
+	InitializeGame(ctx context.Context, req gamerunnerservice.StartGameRequest) (*game.GameState, error)
+	GetGame(ctx context.Context, gameStateId game.GameStateID) (*game.GameState, error)
+	AdvancePhase(ctx context.Context, gameStateId game.GameStateID) (*game.GameState, error)
+	ApplyCommand(ctx context.Context, gameStateId game.GameStateID, userId user.UserID, cmd game.Command) (*game.CommandResult, error)
+	ExpireRevealTimers(ctx context.Context) error
+}
+
+type IGameEventsService interface {
+	Watch(ctx context.Context, gameStateId game.GameStateID, userId user.UserID, onEvent func(ctx context.Context, event *game.GameEvent) error) error
+	Spectate(ctx context.Context, gameStateId game.GameStateID, onVersion func(ctx context.Context, version game.GameStateVersionView) error) error
+	SpectatorCount(ctx context.Context, gameStateId game.GameStateID) (*game.SpectatorCount, error)
+}
+
+type ILobbyService interface {
+	JoinQueue(ctx context.Context, userId user.UserID, deckId card.SerializableDeckID) (*lobby.QueueStatus, error)
+	QueueStatus(ctx context.Context, userId user.UserID) (*lobby.QueueStatus, error)
+	LeaveQueue(ctx context.Context, userId user.UserID) error
+	MatchQueue(ctx context.Context) error
+	CreateLobby(ctx context.Context, userId user.UserID, deckId card.SerializableDeckID, settings lobby.GameSettings) (*lobby.Lobby, error)
+	GetLobby(ctx context.Context, inviteCode string) (*lobby.Lobby, error)
+	JoinLobby(ctx context.Context, userId user.UserID, inviteCode string, deckId card.SerializableDeckID) (*lobby.Lobby, error)
+	CloseLobby(ctx context.Context, userId user.UserID, inviteCode string) error
+	StartBotGame(ctx context.Context, userId user.UserID, deckId card.SerializableDeckID, botDeckId card.SerializableDeckID, difficulty bot.Difficulty, rules game.RulesPreset) (*game.GameState, error)
+}
+
+type IRatingsService interface {
+	RecordGame(ctx context.Context, gameStateId game.GameStateID) error
+	GetRating(ctx context.Context, userId user.UserID) (*rating.UserRating, error)
+	Leaderboard(ctx context.Context, limit int, offset int) ([]rating.LeaderboardEntry, error)
+	FriendsLeaderboard(ctx context.Context, userId user.UserID) ([]rating.LeaderboardEntry, error)
+	MatchHistory(ctx context.Context, userId user.UserID, limit int, offset int) ([]*rating.MatchResult, error)
+}
+
+type IGameReplayService interface {
+	ListVersions(ctx context.Context, gameStateId game.GameStateID, viewer user.UserID, limit int, offset int) ([]game.GameStateVersionSummary, error)
+	GetVersion(ctx context.Context, gameStateId game.GameStateID, version int, viewer user.UserID) (*game.GameStateVersionView, error)
+	GetLatestVersion(ctx context.Context, gameStateId game.GameStateID, viewer user.UserID) (*game.GameStateVersionView, error)
+	DiffVersions(ctx context.Context, gameStateId game.GameStateID, from int, to int, viewer user.UserID) ([]game.StateChange, error)
+	Rollback(ctx context.Context, gameStateId game.GameStateID, version int) (*game.GameState, error)
+}
+
 type IJWTService interface {
 	CreateToken(ctx context.Context, userId user.UserID, signingKey []byte) (string, error)
 	CreateTokenWithID(ctx context.Context, userId user.UserID, tokenId utils.ID, signingKey []byte) (string, error)
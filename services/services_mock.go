@@ -29,11 +29,46 @@ func (m *MockServices) ChatSessionItemsService(userId user.UserID) IChatSessionI
 	return args.Get(0).(IChatSessionItemsService)
 }
 
+func (m *MockServices) CollectionService(userId user.UserID) ICollectionService {
+	args := m.Called(userId)
+	return args.Get(0).(ICollectionService)
+}
+
 func (m *MockServices) DecksService(userId user.UserID) IDecksService {
 	args := m.Called(userId)
 	return args.Get(0).(IDecksService)
 }
 
+func (m *MockServices) GameRunnerService() IGameRunnerService {
+	args := m.Called()
+	return args.Get(0).(IGameRunnerService)
+}
+
+func (m *MockServices) GameEventsService() IGameEventsService {
+	args := m.Called()
+	return args.Get(0).(IGameEventsService)
+}
+
+func (m *MockServices) GameReplayService() IGameReplayService {
+	args := m.Called()
+	return args.Get(0).(IGameReplayService)
+}
+
+func (m *MockServices) LobbyService() ILobbyService {
+	args := m.Called()
+	return args.Get(0).(ILobbyService)
+}
+
+func (m *MockServices) PacksService(userId user.UserID) IPacksService {
+	args := m.Called(userId)
+	return args.Get(0).(IPacksService)
+}
+
+func (m *MockServices) RatingsService() IRatingsService {
+	args := m.Called()
+	return args.Get(0).(IRatingsService)
+}
+
 func (m *MockServices) SecretsService(userId user.UserID) ISecretsService {
 	args := m.Called(userId)
 	return args.Get(0).(ISecretsService)
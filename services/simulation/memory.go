@@ -0,0 +1,192 @@
+package simulation
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/user"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// The in-memory stores below stand in for the repos, users service and
+// publisher the game runner needs, so games can be played without a database
+// or Redis. Only what a simulation needs is kept: versions, timers and
+// events are dropped.
+
+type memoryGameStates struct {
+	states map[game.GameStateID]*game.GameState
+}
+
+func newMemoryGameStates() *memoryGameStates {
+	return &memoryGameStates{states: make(map[game.GameStateID]*game.GameState)}
+}
+
+func (m *memoryGameStates) Get(ctx context.Context, gameStateId game.GameStateID) (*game.GameState, error) {
+	gameState, ok := m.states[gameStateId]
+	if !ok {
+		return nil, utils.NewNotFoundError("game state not found")
+	}
+	return gameState, nil
+}
+
+func (m *memoryGameStates) All(ctx context.Context) ([]*game.GameState, error) {
+	return slices.Collect(maps.Values(m.states)), nil
+}
+
+func (m *memoryGameStates) Create(ctx context.Context, gameState *game.GameState) error {
+	m.states[gameState.ID] = gameState
+	return nil
+}
+
+func (m *memoryGameStates) Update(ctx context.Context, gameState *game.GameState) error {
+	m.states[gameState.ID] = gameState
+	return nil
+}
+
+func (m *memoryGameStates) Delete(ctx context.Context, gameStateId game.GameStateID) error {
+	delete(m.states, gameStateId)
+	return nil
+}
+
+type discardedVersions struct{}
+
+func (discardedVersions) Get(ctx context.Context, versionId game.GameStateVersionID) (*game.GameStateVersion, error) {
+	return nil, utils.NewNotFoundError("versions are not kept in simulations")
+}
+
+func (discardedVersions) Create(ctx context.Context, version *game.GameStateVersion) error {
+	return nil
+}
+
+func (discardedVersions) GetVersionsPage(ctx context.Context, gameStateId game.GameStateID, limit int, offset int) ([]*game.GameStateVersion, error) {
+	return nil, nil
+}
+
+func (discardedVersions) GetVersionByNumber(ctx context.Context, gameStateId game.GameStateID, number int) (*game.GameStateVersion, error) {
+	return nil, utils.NewNotFoundError("versions are not kept in simulations")
+}
+
+func (discardedVersions) GetLatestVersion(ctx context.Context, gameStateId game.GameStateID) (*game.GameStateVersion, error) {
+	return nil, utils.NewNotFoundError("versions are not kept in simulations")
+}
+
+func (discardedVersions) GetLatestVersionBefore(ctx context.Context, gameStateId game.GameStateID, cutoff time.Time) (*game.GameStateVersion, int, error) {
+	return nil, 0, utils.NewNotFoundError("versions are not kept in simulations")
+}
+
+func (discardedVersions) GetVersionsBetween(ctx context.Context, gameStateId game.GameStateID, since time.Time, cutoff time.Time) ([]*game.GameStateVersion, error) {
+	return nil, nil
+}
+
+// discardedTimers drops reveal timers, simulated players always select a card
+type discardedTimers struct{}
+
+func (discardedTimers) Schedule(ctx context.Context, timer *game.RevealTimer) error {
+	return nil
+}
+
+func (discardedTimers) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*game.RevealTimer, error) {
+	return nil, nil
+}
+
+func (discardedTimers) Complete(ctx context.Context, timer *game.RevealTimer) error {
+	return nil
+}
+
+type memoryCards struct {
+	cards map[card.SerializableCardID]card.Card
+}
+
+func (m *memoryCards) Get(ctx context.Context, cardId card.SerializableCardID) (card.Card, error) {
+	c, ok := m.cards[cardId]
+	if !ok {
+		return nil, utils.NewNotFoundError("card not found")
+	}
+	return c, nil
+}
+
+func (m *memoryCards) All(ctx context.Context) ([]card.Card, error) {
+	return slices.Collect(maps.Values(m.cards)), nil
+}
+
+func (m *memoryCards) GetMany(ctx context.Context, cardIds []card.SerializableCardID) ([]card.Card, error) {
+	found := []card.Card{}
+	for _, cardId := range cardIds {
+		if c, ok := m.cards[cardId]; ok {
+			found = append(found, c)
+		}
+	}
+	return found, nil
+}
+
+func (m *memoryCards) GetIDsByRarity(ctx context.Context, rarities []card.CardRarity) (map[card.CardRarity][]card.SerializableCardID, error) {
+	byRarity := map[card.CardRarity][]card.SerializableCardID{}
+	for _, c := range m.cards {
+		if rarity := card.CardData(c).Rarity; slices.Contains(rarities, rarity) {
+			byRarity[rarity] = append(byRarity[rarity], c.GetID())
+		}
+	}
+	return byRarity, nil
+}
+
+func (m *memoryCards) Create(ctx context.Context, c card.Card) error {
+	m.cards[c.GetID()] = c
+	return nil
+}
+
+func (m *memoryCards) Update(ctx context.Context, c card.Card) error {
+	m.cards[c.GetID()] = c
+	return nil
+}
+
+func (m *memoryCards) Delete(ctx context.Context, cardId card.SerializableCardID) error {
+	delete(m.cards, cardId)
+	return nil
+}
+
+// GetVersion returns the card as it is; the catalog cannot change during a
+// simulation
+func (m *memoryCards) GetVersion(ctx context.Context, cardId card.SerializableCardID, version int) (card.Card, error) {
+	return m.Get(ctx, cardId)
+}
+
+func (m *memoryCards) CreateVersion(ctx context.Context, c card.Card) error {
+	return nil
+}
+
+func (m *memoryCards) SaveMany(ctx context.Context, cards []card.Card) error {
+	for _, c := range cards {
+		m.cards[c.GetID()] = c
+	}
+	return nil
+}
+
+type memoryUsers struct {
+	users map[user.UserID]*user.User
+}
+
+func (m *memoryUsers) GetUser(ctx context.Context, userId user.UserID) (*user.User, error) {
+	u, ok := m.users[userId]
+	if !ok {
+		return nil, utils.NewNotFoundError("user not found")
+	}
+	return u, nil
+}
+
+func (m *memoryUsers) CreateUserWithId(ctx context.Context, id user.UserID, data user.UserData) (*user.User, error) {
+	u := user.NewUser()
+	u.ID = id
+	u.UserData = data
+	m.users[id] = u
+	return u, nil
+}
+
+type discardedEvents struct{}
+
+func (discardedEvents) PublishEvents(ctx context.Context, events ...*game.GameEvent) error {
+	return nil
+}
@@ -0,0 +1,179 @@
+package simulation
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain/bot"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/domain/simulation"
+	"github.com/coopersmall/subswag/domain/user"
+	gamerunnerservice "github.com/coopersmall/subswag/services/game"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// maxAdvances stops a game that never completes, e.g. because an effect
+// keeps refilling the board
+const maxAdvances = 1000
+
+// players are the simulated users, one per seat
+var players = [2]user.UserID{1001, 1002}
+
+// Simulator plays games between two decks entirely in memory, through the
+// same game runner and effect engine as live games. Each seat is played by a
+// bot.
+type Simulator struct {
+	logger utils.ILogger
+	tracer apm.ITracer
+	cards  map[card.SerializableCardID]card.Card
+	values map[card.SerializableCardID]int
+	decks  [2]*card.SerializableDeck
+	bots   [2]bot.Difficulty
+	rules  game.RulesPreset
+}
+
+func NewSimulator(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	cards []card.Card,
+	decks [2]*card.SerializableDeck,
+	bots [2]bot.Difficulty,
+	rules game.RulesPreset,
+) (*Simulator, error) {
+	if _, err := game.NewRules(rules); err != nil {
+		return nil, err
+	}
+	simulator := &Simulator{
+		logger: logger,
+		tracer: tracer,
+		cards:  make(map[card.SerializableCardID]card.Card, len(cards)),
+		values: make(map[card.SerializableCardID]int, len(cards)),
+		decks:  decks,
+		bots:   bots,
+		rules:  rules,
+	}
+	for _, c := range cards {
+		simulator.cards[c.GetID()] = c
+		simulator.values[c.GetID()] = card.GetCardValue(c)
+	}
+	for i, deck := range decks {
+		if deck == nil {
+			return nil, utils.NewInvalidArgumentError("both decks are required")
+		}
+		if !bots[i].IsValid() {
+			return nil, utils.NewInvalidArgumentError("unknown bot difficulty")
+		}
+		for _, cardId := range deck.CardIDs {
+			if _, ok := simulator.cards[cardId]; !ok {
+				return nil, utils.NewInvalidArgumentError("deck " + deck.Name + " has a card that was not loaded")
+			}
+		}
+	}
+	return simulator, nil
+}
+
+// Run plays games games and reports the results. Game i is seeded with
+// seed+i, so a run can be repeated exactly. The decks swap seats every game.
+func (s *Simulator) Run(ctx context.Context, games int, seed int64) (*simulation.Report, error) {
+	report := simulation.NewReport(s.decks, s.bots)
+	gameStates := newMemoryGameStates()
+	users := &memoryUsers{users: make(map[user.UserID]*user.User)}
+	for _, userId := range players {
+		if _, err := users.CreateUserWithId(ctx, userId, user.UserData{}); err != nil {
+			return nil, err
+		}
+	}
+	runner := gamerunnerservice.NewGameRunnerService(
+		s.logger,
+		s.tracer,
+		gameStates,
+		discardedVersions{},
+		discardedTimers{},
+		&memoryCards{cards: s.cards},
+		func(ctx context.Context, userId user.UserID, deckId card.SerializableDeckID) (*card.SerializableDeck, error) {
+			for _, deck := range s.decks {
+				if deck.ID == deckId {
+					return deck, nil
+				}
+			}
+			return nil, utils.NewNotFoundError("deck not found")
+		},
+		users,
+		discardedEvents{},
+	)
+
+	for i := range games {
+		sides := [2]int{i % 2, 1 - i%2}
+		gameState, err := s.play(ctx, runner, sides, seed+int64(i))
+		// Only one game is played at a time
+		clear(gameStates.states)
+		if err != nil {
+			if !utils.IsInvalidStateError(err) {
+				return nil, err
+			}
+			s.logger.Warn(ctx, "simulated game could not be finished", map[string]any{
+				"seed":  seed + int64(i),
+				"error": err.Error(),
+			})
+			report.AddFailure()
+			continue
+		}
+		report.Add(gameState.GameStateData, sides)
+	}
+	report.Finish()
+	return report, nil
+}
+
+// play runs one game to completion. sides gives the side that sits in each
+// seat.
+func (s *Simulator) play(
+	ctx context.Context,
+	runner *gamerunnerservice.GameRunnerService,
+	sides [2]int,
+	seed int64,
+) (*game.GameState, error) {
+	req := gamerunnerservice.StartGameRequest{Seed: &seed, Rules: s.rules}
+	req.Player1.UserID = players[0]
+	req.Player1.DeckID = s.decks[sides[0]].ID
+	req.Player2.UserID = players[1]
+	req.Player2.DeckID = s.decks[sides[1]].ID
+	gameState, err := runner.InitializeGame(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	r := rand.New(rand.NewSource(seed))
+	var bots [2]*bot.Bot
+	for seat, side := range sides {
+		bots[seat] = bot.NewBot(s.bots[side], s.values, r)
+	}
+
+	for range maxAdvances {
+		if gameState.IsComplete {
+			return gameState, nil
+		}
+		acted := false
+		for seat, b := range bots {
+			cmd, ok := b.NextCommand(gameState.GameStateData, seat)
+			if !ok {
+				continue
+			}
+			result, err := runner.ApplyCommand(ctx, gameState.ID, players[seat], cmd)
+			if err != nil {
+				return nil, err
+			}
+			gameState = result.State
+			acted = acted || result.Accepted
+		}
+		// The game moves on as the players act. When neither can, e.g. with
+		// no face-down card left to reveal, it is forced on as a reveal
+		// timer would.
+		if !acted {
+			if gameState, err = runner.AdvancePhase(ctx, gameState.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, utils.NewInvalidStateError("game did not complete")
+}
@@ -0,0 +1,15 @@
+package simulation_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SimulatorTestSuite struct {
+	suite.Suite
+}
+
+func TestSimulatorSuite(t *testing.T) {
+	suite.Run(t, new(SimulatorTestSuite))
+}
@@ -0,0 +1,78 @@
+package simulation_test
+
+import (
+	"context"
+	"io"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain"
+	"github.com/coopersmall/subswag/domain/bot"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/services/simulation"
+	"github.com/coopersmall/subswag/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *SimulatorTestSuite) TestRun() {
+	numberCard := func(id card.SerializableCardID, number int) card.Card {
+		c := &card.SerializableNumberCard{Type: card.SerializableCardTypeNumber, Number: number}
+		c.ID = id
+		c.Metadata = domain.NewMetadata()
+		return c
+	}
+	cards := make([]card.Card, 0)
+	weak := card.SerializableDeckData{Name: "weak"}
+	strong := card.SerializableDeckData{Name: "strong"}
+	for i := 1; i <= 20; i++ {
+		cards = append(cards, numberCard(card.SerializableCardID(i), 1))
+		cards = append(cards, numberCard(card.SerializableCardID(100+i), 10))
+		weak.CardIDs = append(weak.CardIDs, card.SerializableCardID(i))
+		strong.CardIDs = append(strong.CardIDs, card.SerializableCardID(100+i))
+	}
+	decks := [2]*card.SerializableDeck{card.NewDeck(1, weak), card.NewDeck(2, strong)}
+
+	newSimulator := func() *simulation.Simulator {
+		simulator, err := simulation.NewSimulator(
+			utils.NewLogger("simulate", utils.WithWriter(io.Discard)),
+			apm.NewNoopTracer(),
+			cards,
+			decks,
+			[2]bot.Difficulty{bot.DifficultyMedium, bot.DifficultyEasy},
+			game.RulesPresetClassic,
+		)
+		require.NoError(s.T(), err)
+		return simulator
+	}
+
+	s.Run("should play every game to completion", func() {
+		report, err := newSimulator().Run(context.Background(), 20, 1)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), 20, report.Games)
+		assert.Equal(s.T(), 0, report.Failed)
+		assert.Equal(s.T(), 20, report.Sides[1].Wins)
+		assert.Equal(s.T(), 1.0, report.Sides[1].WinRate)
+		assert.NotEmpty(s.T(), report.Lengths)
+	})
+
+	s.Run("should repeat a run with the same seed", func() {
+		first, err := newSimulator().Run(context.Background(), 10, 7)
+		require.NoError(s.T(), err)
+		second, err := newSimulator().Run(context.Background(), 10, 7)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), first, second)
+	})
+
+	s.Run("should reject decks with cards that were not loaded", func() {
+		_, err := simulation.NewSimulator(
+			utils.NewLogger("simulate", utils.WithWriter(io.Discard)),
+			apm.NewNoopTracer(),
+			cards[:1],
+			decks,
+			[2]bot.Difficulty{bot.DifficultyMedium, bot.DifficultyMedium},
+			game.RulesPresetClassic,
+		)
+		assert.Error(s.T(), err)
+	})
+}
@@ -0,0 +1,49 @@
+package collections
+
+import (
+	"context"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/gateways"
+	publisherdomain "github.com/coopersmall/subswag/streams/publishers/domain"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// CollectionEventsPublisher publishes every change to a user's collection to
+// the shared collections stream
+type CollectionEventsPublisher struct {
+	logger utils.ILogger
+	tracer apm.ITracer
+	*publisherdomain.StandardStreamPublisher[*card.CollectionEvent]
+}
+
+func NewCollectionEventsPublisher(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	gateway gateways.IPublisherGateway,
+) *CollectionEventsPublisher {
+	return &CollectionEventsPublisher{
+		logger: logger,
+		tracer: tracer,
+		StandardStreamPublisher: publisherdomain.NewStandardStreamPublisher[*card.CollectionEvent](
+			stream,
+			logger,
+			tracer,
+			gateway,
+		),
+	}
+}
+
+func (p *CollectionEventsPublisher) PublishEvents(ctx context.Context, events ...*card.CollectionEvent) error {
+	for _, event := range events {
+		if err := p.PublishCreate(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func stream() string {
+	return card.CollectionEventsStream
+}
@@ -0,0 +1,66 @@
+package games
+
+import (
+	"context"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/gateways"
+	publisherdomain "github.com/coopersmall/subswag/streams/publishers/domain"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// GameEventsPublisher publishes each game's events to its own stream so
+// watchers only read the game they are interested in. Completion events are
+// also published to the shared completed games stream.
+type GameEventsPublisher struct {
+	logger  utils.ILogger
+	tracer  apm.ITracer
+	gateway gateways.IPublisherGateway
+}
+
+func NewGameEventsPublisher(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	gateway gateways.IPublisherGateway,
+) *GameEventsPublisher {
+	return &GameEventsPublisher{
+		logger:  logger,
+		tracer:  tracer,
+		gateway: gateway,
+	}
+}
+
+func (p *GameEventsPublisher) PublishEvents(ctx context.Context, events ...*game.GameEvent) error {
+	for _, event := range events {
+		stream := func() string {
+			return game.GameEventsStream(event.GameStateID)
+		}
+		publisher := publisherdomain.NewStandardStreamPublisher[*game.GameEvent](
+			stream,
+			p.logger,
+			p.tracer,
+			p.gateway,
+		)
+		if err := publisher.PublishCreate(ctx, event); err != nil {
+			return err
+		}
+		if event.Type == game.GameEventGameCompleted {
+			if err := p.completedGamesPublisher().PublishCreate(ctx, event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *GameEventsPublisher) completedGamesPublisher() *publisherdomain.StandardStreamPublisher[*game.GameEvent] {
+	return publisherdomain.NewStandardStreamPublisher[*game.GameEvent](
+		func() string {
+			return game.CompletedGamesStream
+		},
+		p.logger,
+		p.tracer,
+		p.gateway,
+	)
+}
@@ -4,18 +4,26 @@ import (
 	"context"
 
 	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain/card"
+	"github.com/coopersmall/subswag/domain/game"
 	usersdomain "github.com/coopersmall/subswag/domain/user"
 	"github.com/coopersmall/subswag/gateways"
+	collectionspublisher "github.com/coopersmall/subswag/streams/publishers/collections"
+	gamespublisher "github.com/coopersmall/subswag/streams/publishers/games"
 	userspublisher "github.com/coopersmall/subswag/streams/publishers/users"
 	"github.com/coopersmall/subswag/utils"
 )
 
 type IPublishers interface {
+	CollectionEventsPublisher() ICollectionEventsPublisher
+	GameEventsPublisher() IGameEventsPublisher
 	UsersPublisher() IUsersPublisher
 }
 
 type Publishers struct {
-	usersPublisher func() IUsersPublisher
+	collectionEventsPublisher func() ICollectionEventsPublisher
+	gameEventsPublisher       func() IGameEventsPublisher
+	usersPublisher            func() IUsersPublisher
 }
 
 func GetPublishers(
@@ -29,11 +37,35 @@ func GetPublishers(
 			gateways.RedisStreamPublisherGateway(),
 		)
 	}
+	newGameEventsPublisher := func() IGameEventsPublisher {
+		return gamespublisher.NewGameEventsPublisher(
+			env.GetLogger("game-events-publisher"),
+			env.GetTracer("game-events-publisher"),
+			gateways.RedisStreamPublisherGateway(),
+		)
+	}
+	newCollectionEventsPublisher := func() ICollectionEventsPublisher {
+		return collectionspublisher.NewCollectionEventsPublisher(
+			env.GetLogger("collection-events-publisher"),
+			env.GetTracer("collection-events-publisher"),
+			gateways.RedisStreamPublisherGateway(),
+		)
+	}
 	return &Publishers{
-		usersPublisher: newUserUpdateSubscriber,
+		collectionEventsPublisher: newCollectionEventsPublisher,
+		gameEventsPublisher:       newGameEventsPublisher,
+		usersPublisher:            newUserUpdateSubscriber,
 	}
 }
 
+func (p *Publishers) CollectionEventsPublisher() ICollectionEventsPublisher {
+	return p.collectionEventsPublisher()
+}
+
+func (p *Publishers) GameEventsPublisher() IGameEventsPublisher {
+	return p.gameEventsPublisher()
+}
+
 func (p *Publishers) UsersPublisher() IUsersPublisher {
 	return p.usersPublisher()
 }
@@ -43,6 +75,14 @@ type iEnv interface {
 	GetTracer(service string) apm.ITracer
 }
 
+type ICollectionEventsPublisher interface {
+	PublishEvents(ctx context.Context, events ...*card.CollectionEvent) error
+}
+
+type IGameEventsPublisher interface {
+	PublishEvents(ctx context.Context, events ...*game.GameEvent) error
+}
+
 type IUsersPublisher interface {
 	PublishUpdate(ctx context.Context, user *usersdomain.User) error
 }
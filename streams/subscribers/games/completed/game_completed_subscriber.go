@@ -0,0 +1,59 @@
+package completed
+
+import (
+	"context"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/domain/game"
+	"github.com/coopersmall/subswag/gateways"
+	"github.com/coopersmall/subswag/services"
+	subscriberdomain "github.com/coopersmall/subswag/streams/subscribers/domain"
+	"github.com/coopersmall/subswag/utils"
+)
+
+// GameCompletedSubscriber rates the players of every finished game. It reads
+// through the "ratings" consumer group so each game is handled by a single
+// instance.
+type GameCompletedSubscriber struct {
+	logger utils.ILogger
+	tracer apm.ITracer
+	*subscriberdomain.StandardStreamSubscriber[*game.GameEvent]
+	services services.IServices
+}
+
+func NewGameCompletedSubscriber(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	gateway gateways.ISubscriberGateway,
+	services services.IServices,
+) *GameCompletedSubscriber {
+	subscriber := &GameCompletedSubscriber{
+		logger:   logger,
+		tracer:   tracer,
+		services: services,
+	}
+	subscriber.StandardStreamSubscriber = subscriberdomain.NewStandardStreamSubscriber[*game.GameEvent](
+		"ratings",
+		"game-completed",
+		stream,
+		logger,
+		tracer,
+		gateway,
+		subscriber.Handle,
+	)
+	return subscriber
+}
+
+func (s *GameCompletedSubscriber) Handle(
+	ctx context.Context,
+	event *game.GameEvent,
+) error {
+	if event == nil || event.Type != game.GameEventGameCompleted {
+		return nil
+	}
+	return s.services.RatingsService().RecordGame(ctx, event.GameStateID)
+}
+
+func stream() string {
+	return game.CompletedGamesStream
+}
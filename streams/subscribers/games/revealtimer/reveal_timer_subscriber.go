@@ -0,0 +1,48 @@
+package revealtimer
+
+import (
+	"context"
+	"time"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/services"
+	"github.com/coopersmall/subswag/utils"
+)
+
+const pollInterval = time.Second
+
+// RevealTimerSubscriber polls for reveal windows that have closed. Timers are
+// stored in the database and leased while handled, so every instance can run
+// one without a round being auto-revealed twice.
+type RevealTimerSubscriber struct {
+	logger   utils.ILogger
+	tracer   apm.ITracer
+	services services.IServices
+}
+
+func NewRevealTimerSubscriber(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	services services.IServices,
+) *RevealTimerSubscriber {
+	return &RevealTimerSubscriber{
+		logger:   logger,
+		tracer:   tracer,
+		services: services,
+	}
+}
+
+func (s *RevealTimerSubscriber) Subscribe(ctx context.Context) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.services.GameRunnerService().ExpireRevealTimers(ctx); err != nil {
+				s.logger.Error(ctx, "failed to expire reveal timers", err, nil)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package matchmaking
+
+import (
+	"context"
+	"time"
+
+	"github.com/coopersmall/subswag/apm"
+	"github.com/coopersmall/subswag/services"
+	"github.com/coopersmall/subswag/utils"
+)
+
+const pollInterval = time.Second
+
+// MatchmakingSubscriber re-runs matchmaking as players wait, so players whose
+// rating windows have widened enough are paired without anyone new joining.
+// Pairs are taken from the queue in a single update, so every instance can
+// run one without a player being matched twice.
+type MatchmakingSubscriber struct {
+	logger   utils.ILogger
+	tracer   apm.ITracer
+	services services.IServices
+}
+
+func NewMatchmakingSubscriber(
+	logger utils.ILogger,
+	tracer apm.ITracer,
+	services services.IServices,
+) *MatchmakingSubscriber {
+	return &MatchmakingSubscriber{
+		logger:   logger,
+		tracer:   tracer,
+		services: services,
+	}
+}
+
+func (s *MatchmakingSubscriber) Subscribe(ctx context.Context) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.services.LobbyService().MatchQueue(ctx); err != nil {
+				s.logger.Error(ctx, "failed to match queued players", err, nil)
+			}
+		}
+	}
+}
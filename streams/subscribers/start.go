@@ -23,6 +23,9 @@ func StartSubscribers(
 
 	s := []ISubscriber{
 		subscribers.UserUpdateSubscriber(),
+		subscribers.RevealTimerSubscriber(),
+		subscribers.GameCompletedSubscriber(),
+		subscribers.MatchmakingSubscriber(),
 	}
 
 	for _, subscriber := range s {
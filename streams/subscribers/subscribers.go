@@ -6,12 +6,18 @@ import (
 	"github.com/coopersmall/subswag/apm"
 	"github.com/coopersmall/subswag/gateways"
 	"github.com/coopersmall/subswag/services"
+	completed "github.com/coopersmall/subswag/streams/subscribers/games/completed"
+	revealtimer "github.com/coopersmall/subswag/streams/subscribers/games/revealtimer"
+	matchmaking "github.com/coopersmall/subswag/streams/subscribers/lobby/matchmaking"
 	userupdate "github.com/coopersmall/subswag/streams/subscribers/users/update"
 	"github.com/coopersmall/subswag/utils"
 )
 
 type ISubscribers interface {
 	UserUpdateSubscriber() ISubscriber
+	RevealTimerSubscriber() ISubscriber
+	GameCompletedSubscriber() ISubscriber
+	MatchmakingSubscriber() ISubscriber
 }
 
 type ISubscriber interface {
@@ -19,7 +25,10 @@ type ISubscriber interface {
 }
 
 type Subscribers struct {
-	userUpdateSubscriber func() ISubscriber
+	userUpdateSubscriber    func() ISubscriber
+	revealTimerSubscriber   func() ISubscriber
+	gameCompletedSubscriber func() ISubscriber
+	matchmakingSubscriber   func() ISubscriber
 }
 
 func GetSubscribers(
@@ -35,8 +44,33 @@ func GetSubscribers(
 			services,
 		)
 	}
+	newRevealTimerSubscriber := func() ISubscriber {
+		return revealtimer.NewRevealTimerSubscriber(
+			env.GetLogger("reveal-timer"),
+			env.GetTracer("reveal-timer"),
+			services,
+		)
+	}
+	newGameCompletedSubscriber := func() ISubscriber {
+		return completed.NewGameCompletedSubscriber(
+			env.GetLogger("game-completed"),
+			env.GetTracer("game-completed"),
+			gateways.RedisStreamSubscriberGateway(nil),
+			services,
+		)
+	}
+	newMatchmakingSubscriber := func() ISubscriber {
+		return matchmaking.NewMatchmakingSubscriber(
+			env.GetLogger("matchmaking"),
+			env.GetTracer("matchmaking"),
+			services,
+		)
+	}
 	return &Subscribers{
-		userUpdateSubscriber: newUserUpdateSubscriber,
+		userUpdateSubscriber:    newUserUpdateSubscriber,
+		revealTimerSubscriber:   newRevealTimerSubscriber,
+		gameCompletedSubscriber: newGameCompletedSubscriber,
+		matchmakingSubscriber:   newMatchmakingSubscriber,
 	}
 }
 
@@ -44,6 +78,18 @@ func (s *Subscribers) UserUpdateSubscriber() ISubscriber {
 	return s.userUpdateSubscriber()
 }
 
+func (s *Subscribers) RevealTimerSubscriber() ISubscriber {
+	return s.revealTimerSubscriber()
+}
+
+func (s *Subscribers) GameCompletedSubscriber() ISubscriber {
+	return s.gameCompletedSubscriber()
+}
+
+func (s *Subscribers) MatchmakingSubscriber() ISubscriber {
+	return s.matchmakingSubscriber()
+}
+
 type iEnv interface {
 	GetLogger(name string) utils.ILogger
 	GetTracer(service string) apm.ITracer
@@ -4,6 +4,8 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"time"
+
+	"github.com/coopersmall/subswag/domain/lobby"
 )
 
 type testEnvVars struct {
@@ -23,6 +25,7 @@ type testEnvVars struct {
 	groqKey             string
 	apiURL              string
 	apiTimeout          time.Duration
+	matchmakingMode     lobby.MatchmakingMode
 }
 
 func NewTestEnvVars() (*testEnvVars, error) {
@@ -55,6 +58,7 @@ func NewTestEnvVars() (*testEnvVars, error) {
 		groqKey:             "test-groq-key",
 		apiURL:              "http://localhost:8080",
 		apiTimeout:          time.Second * 10,
+		matchmakingMode:     lobby.MatchmakingModeFIFO,
 	}, nil
 }
 
@@ -123,6 +127,10 @@ func (t *testEnvVars) GetAPITimeout() (time.Duration, error) {
 	return t.apiTimeout, nil
 }
 
+func (t *testEnvVars) GetMatchmakingMode() (lobby.MatchmakingMode, error) {
+	return t.matchmakingMode, nil
+}
+
 // Helper methods to update test environment variables
 func (t *testEnvVars) SetRedisURL(url string) {
 	t.redisURL = url
@@ -16,6 +16,7 @@ const (
 	errCodeUnauthenticated  ErrorCode = "unauthenticated"
 	errCodeUnableToHandle   ErrorCode = "unable_to_handle"
 	errCodeInvalidState     ErrorCode = "invalid_state"
+	errCodeConflict         ErrorCode = "conflict"
 	errCodeJSONMarshError   ErrorCode = "json_marsh_error"
 	errCodeMultiError       ErrorCode = "multi_error"
 )
@@ -31,6 +32,7 @@ var (
 	unauthenticated  = errorx.NewType(ns, errCodeUnauthenticated)
 	unableToHandle   = errorx.NewType(ns, errCodeUnableToHandle)
 	invalidState     = errorx.NewType(ns, errCodeInvalidState)
+	conflict         = errorx.NewType(ns, errCodeConflict)
 	jsonMarshError   = errorx.NewType(ns, errCodeJSONMarshError)
 	multiError       = errorx.NewType(ns, errCodeMultiError)
 
@@ -42,6 +44,7 @@ var (
 		errCodeUnauthenticated:  *unauthenticated,
 		errCodeUnableToHandle:   *unableToHandle,
 		errCodeInvalidState:     *invalidState,
+		errCodeConflict:         *conflict,
 		errCodeJSONMarshError:   *jsonMarshError,
 		errCodeMultiError:       *multiError,
 	}
@@ -94,6 +97,11 @@ func NewInvalidStateError(message string, causes ...error) error {
 	return invalidState.Wrap(err, message)
 }
 
+func NewConflictError(message string, causes ...error) error {
+	err := errorx.DecorateMany("cause", causes...)
+	return conflict.Wrap(err, message)
+}
+
 func NewJSONMarshError(message string, causes ...error) error {
 	err := errorx.DecorateMany("cause", causes...)
 	return jsonMarshError.Wrap(err, message)
@@ -116,6 +124,18 @@ func GetErrorCode(err error) (ErrorCode, bool) {
 	return "", false
 }
 
+func GetErrorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	if typedErr, ok := err.(*errorx.Error); ok {
+		return typedErr.Message()
+	}
+
+	return err.Error()
+}
+
 func ErrorOrNil(
 	message string,
 	fn func(message string, causes ...error) error,
@@ -159,6 +179,10 @@ func IsInvalidStateError(err error) bool {
 	return errorx.IsOfType(err, invalidState)
 }
 
+func IsConflictError(err error) bool {
+	return errorx.IsOfType(err, conflict)
+}
+
 func IsJSONMarshError(err error) bool {
 	return errorx.IsOfType(err, jsonMarshError)
 }
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// JSONChange is a single difference between two values. Path is a dot
+// separated path into their JSON form; From or To is nil when the value was
+// added or removed.
+type JSONChange struct {
+	Path string `json:"path"`
+	From any    `json:"from"`
+	To   any    `json:"to"`
+}
+
+// DiffJSON returns the structural differences between the JSON forms of two
+// values, ordered by path
+func DiffJSON(from any, to any) ([]JSONChange, error) {
+	fromValue, err := toJSONValue(from)
+	if err != nil {
+		return nil, err
+	}
+	toValue, err := toJSONValue(to)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]JSONChange, 0)
+	diffJSONValues("", fromValue, toValue, &changes)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+func toJSONValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
+func diffJSONValues(path string, from any, to any, changes *[]JSONChange) {
+	switch f := from.(type) {
+	case map[string]any:
+		t, ok := to.(map[string]any)
+		if !ok {
+			break
+		}
+		for key, value := range f {
+			diffJSONValues(joinPath(path, key), value, t[key], changes)
+		}
+		for key, value := range t {
+			if _, ok := f[key]; !ok {
+				diffJSONValues(joinPath(path, key), nil, value, changes)
+			}
+		}
+		return
+	case []any:
+		t, ok := to.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < len(f) || i < len(t); i++ {
+			var fromItem, toItem any
+			if i < len(f) {
+				fromItem = f[i]
+			}
+			if i < len(t) {
+				toItem = t[i]
+			}
+			diffJSONValues(joinPath(path, fmt.Sprint(i)), fromItem, toItem, changes)
+		}
+		return
+	}
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, JSONChange{Path: path, From: from, To: to})
+	}
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -9,20 +9,27 @@ import (
 type JSONPathQuery string
 
 func EvaluateJSONPathQuery(query JSONPathQuery, data any) (any, error) {
+	value, _, err := LookupJSONPathQuery(query, data)
+	return value, err
+}
+
+// LookupJSONPathQuery evaluates the query and reports whether it matched
+// anything. A missing value is returned as "".
+func LookupJSONPathQuery(query JSONPathQuery, data any) (any, bool, error) {
 	json, err := json.Marshal(data)
 	if err != nil {
-		return "", NewJSONMarshError("failed to marshal json while evaluating json path", err)
+		return "", false, NewJSONMarshError("failed to marshal json while evaluating json path", err)
 	}
 	result := gjson.Get(string(json), string(query))
 	if !result.Exists() {
-		return "", nil
+		return "", false, nil
 	}
 	if result.IsArray() {
 		var results []any
 		for _, r := range result.Array() {
 			results = append(results, r.Value())
 		}
-		return results, nil
+		return results, true, nil
 	}
-	return result.Value(), nil
+	return result.Value(), true, nil
 }
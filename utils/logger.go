@@ -51,6 +51,13 @@ func WithLoggingHandler(w io.Writer, exporter LogExporter) opt {
 	}
 }
 
+// WithWriter sends logs to w instead of stdout
+func WithWriter(w io.Writer) opt {
+	return func(logger *stdLogger) {
+		logger.stdLogger = slog.New(newLogHandler(w, &noopLogExporter{}))
+	}
+}
+
 func (l *stdLogger) Info(ctx context.Context, msg string, metadata map[string]any) {
 	logLevel(ctx, l.stdLogger, l.service, slog.LevelInfo, msg, nil, metadata, 2)
 }